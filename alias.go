@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// defaultAliasFile is the alias data file, relative to a checkout of this
+// repository. It is embedded at build time, so edits take effect on the
+// next build.
+const defaultAliasFile = "data/service_aliases.json"
+
+// runAlias implements `hogwash alias add|rm|list`, which curates
+// data/service_aliases.json.
+func runAlias(args []string) error {
+	fs := flag.NewFlagSet("alias", flag.ExitOnError)
+	dataPath := fs.String("data", defaultAliasFile, "Alias data file to edit")
+	fromFull := fs.String("from-full", "", "Full export of the last extraction; alias add requires the TruffleHog keyword to exist in it")
+	force := fs.Bool("force", false, "alias add: replace an existing alias of the Gitleaks keyword")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s alias [flags] add <gl-keyword> <th-keyword>\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s alias [flags] rm <gl-keyword>\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s alias [flags] list\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	verb, rest := fs.Arg(0), fs.Args()
+	if len(rest) > 0 {
+		rest = rest[1:]
+	}
+	want := map[string]int{"add": 2, "rm": 1, "list": 0}
+	if n, ok := want[verb]; !ok || len(rest) != n {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("alias: expected add <gl-keyword> <th-keyword>, rm <gl-keyword> or list, got %v", fs.Args()))
+	}
+
+	aliases, err := readAliases(*dataPath)
+	if err != nil {
+		return fmt.Errorf("alias: %w", err)
+	}
+	switch verb {
+	case "list":
+		for _, gl := range sortedKeys(aliases) {
+			fmt.Fprintf(os.Stdout, "%s → %s\n", gl, aliases[gl])
+		}
+		return nil
+	case "rm":
+		key, ok := findAlias(aliases, rest[0])
+		if !ok {
+			return fmt.Errorf("alias: %s has no alias for %q", *dataPath, rest[0])
+		}
+		logger.Info(fmt.Sprintf("Removed alias %s → %s", key, aliases[key]), "gl_keyword", key)
+		delete(aliases, key)
+	case "add":
+		if *fromFull == "" {
+			return withExitCode(exitUsage, errors.New("alias add: -from-full is required to check the TruffleHog keyword"))
+		}
+		var full combine.Export
+		if err := readJSONInput(*fromFull, &full); err != nil {
+			return fmt.Errorf("alias: %w", err)
+		}
+		gl, th, err := checkAlias(aliases, full, rest[0], rest[1], *force)
+		if err != nil {
+			return fmt.Errorf("alias: %w", err)
+		}
+		if key, ok := findAlias(aliases, gl); ok {
+			delete(aliases, key)
+		}
+		aliases[gl] = th
+		logger.Info(fmt.Sprintf("Added alias %s → %s", gl, th), "gl_keyword", gl, "th_keyword", th)
+	}
+	return writeDataFile(*dataPath, aliases)
+}
+
+// checkAlias validates a new alias against the alias file and the full
+// export of the last extraction, and returns it in canonical spelling: the
+// Gitleaks keyword lower-cased, the TruffleHog keyword as derived from its
+// detector.
+func checkAlias(aliases map[string]string, full combine.Export, gl, th string, force bool) (string, string, error) {
+	gl = strings.ToLower(strings.TrimSpace(gl))
+	thKeywords := extractedTHKeywords(full)
+	canonical, ok := thKeywords[combine.NormalizeKeyword(th)]
+	if !ok {
+		return "", "", fmt.Errorf("no TruffleHog keyword %q in the last extraction", th)
+	}
+	if combine.NormalizeKeyword(gl) == combine.NormalizeKeyword(canonical) {
+		return "", "", fmt.Errorf("%q already matches TruffleHog keyword %q exactly", gl, canonical)
+	}
+	if key, ok := findAlias(aliases, gl); ok && !force {
+		if aliases[key] == canonical {
+			return "", "", fmt.Errorf("%s → %s already exists", key, canonical)
+		}
+		return "", "", fmt.Errorf("%s is already aliased to %s (use -force to replace)", key, aliases[key])
+	}
+	return gl, canonical, nil
+}
+
+// extractedTHKeywords maps the normalized form of every TruffleHog keyword
+// in a full export to its spelling: those of matched detectors and of
+// TH-only entries.
+func extractedTHKeywords(full combine.Export) map[string]string {
+	keywords := make(map[string]string)
+	for _, svc := range full.Services {
+		for _, dir := range svc.MatchedTH {
+			k := trufflehog.DeriveKeyword(dir)
+			keywords[combine.NormalizeKeyword(k)] = k
+		}
+	}
+	for _, th := range full.THOnlyHosts {
+		keywords[combine.NormalizeKeyword(th.Keyword)] = th.Keyword
+	}
+	return keywords
+}
+
+// findAlias returns the key of aliases that spells gl, comparing normalized
+// keywords as the combiner does.
+func findAlias(aliases map[string]string, gl string) (string, bool) {
+	norm := combine.NormalizeKeyword(gl)
+	for key := range aliases {
+		if combine.NormalizeKeyword(key) == norm {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func readAliases(path string) (map[string]string, error) {
+	var aliases map[string]string
+	if err := readJSONInput(path, &aliases); err != nil {
+		return nil, err
+	}
+	if aliases == nil {
+		aliases = make(map[string]string)
+	}
+	return aliases, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestAlias(t *testing.T) {
+	dir := t.TempDir()
+	dataPath := filepath.Join(dir, "service_aliases.json")
+	if err := os.WriteFile(dataPath, []byte("{\n  \"cisco-meraki\": \"meraki\"\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	full := combine.Export{
+		Services:    []combine.Service{{Keyword: "cisco-meraki", MatchedTH: []string{"meraki"}}},
+		THOnlyHosts: []combine.THOnlyEntry{{Keyword: "sendgrid", DirName: "sendgrid", Hosts: []string{"api.sendgrid.com"}}},
+	}
+	data, _ := json.Marshal(full)
+	fullPath := filepath.Join(dir, "full.json")
+	if err := os.WriteFile(fullPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	alias := func(args ...string) error {
+		return runAlias(append([]string{"-data", dataPath, "-from-full", fullPath}, args...))
+	}
+	read := func() map[string]string {
+		aliases, err := readAliases(dataPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return aliases
+	}
+
+	if err := alias("add", "Twilio-SendGrid", "send_grid"); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"cisco-meraki": "meraki", "twilio-sendgrid": "sendgrid"}; !reflect.DeepEqual(read(), want) {
+		t.Errorf("after add: %v, want %v", read(), want)
+	}
+	for _, bad := range [][]string{
+		{"acme", "acmee"},               // typo: not an extracted TH keyword
+		{"send-grid", "sendgrid"},       // matches exactly already
+		{"cisco_meraki", "sendgrid"},    // already aliased
+		{"twilio-sendgrid", "sendgrid"}, // exists
+	} {
+		if err := alias(append([]string{"add"}, bad...)...); err == nil {
+			t.Errorf("add %v: no error", bad)
+		}
+	}
+	if err := alias("-force", "add", "cisco-meraki", "sendgrid"); err != nil {
+		t.Errorf("add -force: %v", err)
+	}
+	if err := alias("rename", "cisco-meraki"); err == nil || !strings.Contains(err.Error(), "expected add") {
+		t.Errorf("unknown verb: %v", err)
+	}
+
+	if err := alias("rm", "CISCO_MERAKI"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(dataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"twilio-sendgrid\": \"sendgrid\"\n}\n"; string(got) != want {
+		t.Errorf("after rm:\n%s\nwant:\n%s", got, want)
+	}
+	if err := alias("rm", "cisco-meraki"); err == nil {
+		t.Error("rm of a missing alias: no error")
+	}
+}
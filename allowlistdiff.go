@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"secret-detector-export/pkg/export"
+)
+
+// runAllowlistDiff implements `hogwash allowlist-diff [flags] <export.json> <deployed>`.
+func runAllowlistDiff(args []string) error {
+	fs := flag.NewFlagSet("allowlist-diff", flag.ExitOnError)
+	outPath := fs.String("out", "-", "Output file path (or - for stdout)")
+	force := fs.Bool("force", false, "Overwrite -out if it already exists")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s allowlist-diff [flags] <export.json> <deployed>\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "<deployed> is the live host allowlist: a gondolin or full export, a JSON array of hosts, or one host per line.")
+		fmt.Fprintln(fs.Output(), "A full <export.json> is reduced to gondolin with the default options first.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("allowlist-diff: expected an export and a deployed allowlist, got %d arguments", fs.NArg()))
+	}
+
+	g, err := loadGondolin(fs.Arg(0), export.DefaultOptions())
+	if err != nil {
+		return fmt.Errorf("allowlist-diff: %w", err)
+	}
+	deployed, err := readAllowlist(fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("allowlist-diff: %w", err)
+	}
+	d := export.DiffAllowlistGondolin(g, deployed)
+	if err := writeJSONOutput(*outPath, *force, false, false, d); err != nil {
+		return fmt.Errorf("allowlist-diff: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "allowlist-diff: %d new hosts, %d unjustified deployed hosts, %d changed services\n",
+		len(d.NewHosts), len(d.UnjustifiedHosts), len(d.ChangedServices))
+	return nil
+}
+
+// readAllowlist reads a deployed allowlist from path (or - for stdin).
+func readAllowlist(path string) (export.Allowlist, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return export.Allowlist{}, fmt.Errorf("read %s: %w", path, err)
+	}
+	a, err := export.ParseAllowlist(data)
+	if err != nil {
+		return export.Allowlist{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return a, nil
+}
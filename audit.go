@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// runAudit implements `hogwash audit [flags] <export.json>`.
+func runAudit(args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	list := fs.String("list", "", "JSON file of keyword → required hosts (default: embedded data/must_have_services.json)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s audit [flags] <export.json | ->\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("audit: expected exactly one input file, got %d", fs.NArg()))
+	}
+	path := fs.Arg(0)
+
+	req, err := loadAuditRequirements(*list)
+	if err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	var raw json.RawMessage
+	if err := readJSONInput(path, &raw); err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	kind, err := export.DetectKind(raw)
+	if err != nil {
+		return fmt.Errorf("audit: %s: %w", path, err)
+	}
+
+	var errs []error
+	switch kind {
+	case "gondolin":
+		var g export.Gondolin
+		if err := json.Unmarshal(raw, &g); err != nil {
+			return fmt.Errorf("audit: decode %s: %w", path, err)
+		}
+		errs = export.AuditGondolin(g, req)
+	default:
+		var e combine.Export
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return fmt.Errorf("audit: decode %s: %w", path, err)
+		}
+		errs = export.AuditFull(e, req)
+	}
+	if err := reportAudit(errs, len(req)); err != nil {
+		return fmt.Errorf("audit: %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadAuditRequirements reads an -audit-list / -list file, or returns the
+// embedded defaults when path is empty.
+func loadAuditRequirements(path string) (export.AuditRequirements, error) {
+	if path == "" {
+		return export.DefaultAuditRequirements(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read audit list: %w", err)
+	}
+	req, err := export.ParseAuditRequirements(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse audit list %s: %w", path, err)
+	}
+	return req, nil
+}
+
+// reportAudit prints audit failures to stderr and returns an error if there
+// were any.
+func reportAudit(errs []error, services int) error {
+	for _, err := range errs {
+		logger.Warn(err.Error(), "check", "audit")
+	}
+	if len(errs) > 0 {
+		return withExitCode(exitValidation, fmt.Errorf("audit failed: %d of %d must-have services missing or incomplete", len(errs), services))
+	}
+	logger.Info(fmt.Sprintf("Audit: all %d must-have services present", services), "services", services)
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"secret-detector-export/pkg/export"
+)
+
+// benchResult is the measured cost of one pattern over the corpus.
+type benchResult struct {
+	PatternID   string        `json:"pattern_id"`
+	Duration    time.Duration `json:"duration_ns"` // fastest of the runs, full corpus
+	MBPerSecond float64       `json:"mb_per_second"`
+	Matches     int           `json:"matches"`
+	HasKeywords bool          `json:"has_keywords"` // false means consumers can't pre-filter it
+}
+
+// benchPatterns times each pattern's regex over corpus, keeping the fastest
+// of runs to reduce noise, and returns results slowest first. The keyword
+// pre-filter is deliberately not applied: this measures the regex cost a
+// consumer pays whenever the filter lets a value through.
+func benchPatterns(patterns []export.ValuePattern, corpus string, runs int) ([]benchResult, error) {
+	mb := float64(len(corpus)) / (1 << 20)
+	results := make([]benchResult, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.ID, err)
+		}
+		r := benchResult{PatternID: p.ID, HasKeywords: len(p.Keywords) > 0}
+		for i := 0; i < runs; i++ {
+			start := time.Now()
+			matches := re.FindAllStringIndex(corpus, -1)
+			if d := time.Since(start); i == 0 || d < r.Duration {
+				r.Duration = d
+			}
+			r.Matches = len(matches)
+		}
+		if secs := r.Duration.Seconds(); secs > 0 {
+			r.MBPerSecond = mb / secs
+		}
+		results = append(results, r)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Duration > results[j].Duration })
+	return results, nil
+}
+
+// generatedCorpus builds roughly size bytes of config-like filler lines with
+// one synthesized sample per pattern mixed in, so every regex sees both
+// misses and hits.
+func generatedCorpus(patterns []export.ValuePattern, size int, rng *rand.Rand) string {
+	corpus, _ := generateSamples(patterns, 1, rng)
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-"
+	var b strings.Builder
+	b.Grow(size + 1024)
+	next := 0
+	for b.Len() < size {
+		if len(corpus) > 0 && rng.Intn(20) == 0 {
+			// Round-robin so every pattern's sample appears at least once
+			// in all but tiny corpora.
+			s := corpus[next%len(corpus)]
+			next++
+			fmt.Fprintf(&b, "%s_KEY=%s\n", strings.ToUpper(s.PatternID), s.Value)
+			continue
+		}
+		fmt.Fprintf(&b, "setting_%d = ", rng.Intn(1000))
+		for i, n := 0, 8+rng.Intn(56); i < n; i++ {
+			b.WriteByte(alphabet[rng.Intn(len(alphabet))])
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// readCorpus concatenates every non-binary file under path.
+func readCorpus(path string) (string, error) {
+	var b strings.Builder
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" && p != path {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if !isBinary(data) {
+			b.Write(data)
+			b.WriteByte('\n')
+		}
+		return nil
+	})
+	return b.String(), err
+}
+
+// runBench implements `hogwash bench -dataset <export.json> [flags]`.
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	dataset := fs.String("dataset", "", "Gondolin or full export whose patterns to time (required)")
+	corpusPath := fs.String("corpus", "", "File or directory to match against (default: a generated corpus with synthetic samples)")
+	corpusSize := fs.Int("corpus-size", 1<<20, "Size in bytes of the generated corpus")
+	runs := fs.Int("runs", 3, "Times each pattern is run; the fastest run is reported")
+	top := fs.Int("top", 10, "Report only the N slowest patterns (0 = all)")
+	asJSON := fs.Bool("json", false, "Write results as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s bench -dataset <export.json> [flags]\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dataset == "" {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("bench: -dataset is required"))
+	}
+	if *runs < 1 {
+		return fmt.Errorf("bench: invalid -runs %d: must be >= 1", *runs)
+	}
+
+	patterns, err := loadValuePatterns(*dataset)
+	if err != nil {
+		return fmt.Errorf("bench: %w", err)
+	}
+	var corpus string
+	if *corpusPath != "" {
+		if corpus, err = readCorpus(*corpusPath); err != nil {
+			return fmt.Errorf("bench: %w", err)
+		}
+	} else {
+		corpus = generatedCorpus(patterns, *corpusSize, rand.New(rand.NewSource(1)))
+	}
+
+	results, err := benchPatterns(patterns, corpus, *runs)
+	if err != nil {
+		return fmt.Errorf("bench: %w", err)
+	}
+	var total time.Duration
+	for _, r := range results {
+		total += r.Duration
+	}
+	if *top > 0 && len(results) > *top {
+		results = results[:*top]
+	}
+
+	if *asJSON {
+		return export.EncodeJSON(os.Stdout, results, false)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PATTERN\tTIME\tMB/S\tMATCHES\tKEYWORDS")
+	for _, r := range results {
+		kw := "yes"
+		if !r.HasKeywords {
+			kw = "no"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.1f\t%d\t%s\n", r.PatternID, r.Duration.Round(time.Microsecond), r.MBPerSecond, r.Matches, kw)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "bench: %d patterns over %.1f MB, %s total per pass\n", len(patterns), float64(len(corpus))/(1<<20), total.Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/export"
+)
+
+func TestBenchPatterns(t *testing.T) {
+	patterns := []export.ValuePattern{
+		{ID: "github-pat", Regex: `ghp_[0-9a-zA-Z]{36}`, Keywords: []string{"ghp_"}},
+		{ID: "generic", Regex: `(?i)(?:key|token)\s*=\s*"?([a-z0-9]{16,})`},
+	}
+	corpus := generatedCorpus(patterns, 64<<10, rand.New(rand.NewSource(1)))
+	if len(corpus) < 64<<10 || !strings.Contains(corpus, "ghp_") {
+		t.Fatalf("generated corpus is %d bytes, want >= 64 KiB with samples mixed in", len(corpus))
+	}
+
+	results, err := benchPatterns(patterns, corpus, 2)
+	if err != nil {
+		t.Fatalf("benchPatterns: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %+v", results)
+	}
+	if results[0].Duration < results[1].Duration {
+		t.Error("results should be sorted slowest first")
+	}
+	for _, r := range results {
+		if r.Matches == 0 {
+			t.Errorf("%s found no matches in a corpus seeded with its samples", r.PatternID)
+		}
+		if r.PatternID == "generic" && r.HasKeywords {
+			t.Error("generic has no keywords")
+		}
+	}
+
+	if _, err := benchPatterns([]export.ValuePattern{{ID: "broken", Regex: `(`}}, corpus, 1); err == nil {
+		t.Error("expected error for an invalid regex")
+	}
+}
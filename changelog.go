@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"secret-detector-export/pkg/export"
+)
+
+// runChangelog implements `hogwash changelog [flags] <old.json> <new.json>`.
+func runChangelog(args []string) error {
+	fs := flag.NewFlagSet("changelog", flag.ExitOnError)
+	title := fs.String("title", "Dataset changes "+time.Now().UTC().Format(time.DateOnly), "Heading for the release notes")
+	outPath := fs.String("out", "-", "Output file path (or - for stdout)")
+	force := fs.Bool("force", false, "Overwrite -out if it already exists")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s changelog [flags] <old.json> <new.json>\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("changelog: expected two export files, got %d", fs.NArg()))
+	}
+
+	d, err := diffFiles(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("changelog: %w", err)
+	}
+	write := func(w io.Writer) error { return export.WriteChangelog(w, d, *title) }
+	if *outPath == "-" {
+		err = write(os.Stdout)
+	} else {
+		err = writeFileAtomic(*outPath, *force, false, 0o644, write)
+	}
+	if err != nil {
+		return fmt.Errorf("changelog: %w", err)
+	}
+	return nil
+}
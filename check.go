@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// runCheck implements `hogwash check [export flags] -out <committed.json>`:
+// regenerate in memory and fail if the committed file is stale.
+func runCheck(args []string) error {
+	var cfg exportConfig
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	cfg.registerFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s check [export flags] -out <committed.json>\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Regenerates the export in memory and exits non-zero if -out is stale.\n-mode defaults to the kind of the committed file.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("check: unexpected arguments %v", fs.Args()))
+	}
+	if err := cfg.applyConfigFile(fs); err != nil {
+		return withExitCode(exitUsage, fmt.Errorf("check: %w", err))
+	}
+	if cfg.OutPath == "-" || len(cfg.Outputs) > 0 {
+		return errors.New("check: -out must name the committed export file")
+	}
+	cfg.recordFlags(fs)
+
+	var raw json.RawMessage
+	if err := readJSONInput(cfg.OutPath, &raw); err != nil {
+		return fmt.Errorf("check: %w", err)
+	}
+	kind, err := export.DetectKind(raw)
+	if err != nil {
+		return fmt.Errorf("check: %s: %w", cfg.OutPath, err)
+	}
+	if _, ok := cfg.Flags["mode"]; !ok {
+		cfg.Mode = kind
+	} else if cfg.Mode != kind {
+		return fmt.Errorf("check: -mode %s, but %s is a %s export", cfg.Mode, cfg.OutPath, kind)
+	}
+	if err := cfg.validate(); err != nil {
+		return withExitCode(exitUsage, fmt.Errorf("check: %w", err))
+	}
+	cfg.startLogging()
+
+	ctx, stop := interruptContext()
+	defer stop()
+	ctx, cancel := cfg.withTimeout(ctx)
+	defer cancel()
+	out, err := buildExport(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("check: %w", err)
+	}
+
+	var d export.Diff
+	var committedHash string
+	switch kind {
+	case "gondolin":
+		var committed export.Gondolin
+		if err := json.Unmarshal(raw, &committed); err != nil {
+			return fmt.Errorf("check: decode %s: %w", cfg.OutPath, err)
+		}
+		committedHash = committed.WithContentHash().ContentHash
+		d = export.DiffGondolin(committed, *out.gondolin)
+	default:
+		var committed combine.Export
+		if err := json.Unmarshal(raw, &committed); err != nil {
+			return fmt.Errorf("check: decode %s: %w", cfg.OutPath, err)
+		}
+		committedHash = committed.WithContentHash().ContentHash
+		d = export.DiffFull(committed, out.full)
+	}
+
+	_, hash := out.render(kind)
+	// The hash is recomputed from the committed content, so hand edits are
+	// caught even if they left content_hash alone.
+	if committedHash == hash {
+		logger.Info(fmt.Sprintf("check: %s is up to date (%s)", cfg.OutPath, hash), "path", cfg.OutPath, "content_hash", hash)
+		return nil
+	}
+	if d.Empty() {
+		logger.Info("check: services, hosts and rules match; other fields differ")
+	} else if err := export.EncodeJSON(os.Stdout, d, false); err != nil {
+		return err
+	}
+	return withExitCode(exitStale, fmt.Errorf("check: %s is stale (committed %s, regenerated %s); rerun the export to refresh it", cfg.OutPath, committedHash, hash))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCheck(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "secret-mapping.gondolin.json")
+	cfg := exportConfig{
+		THDir:          filepath.Join("testdata", "trufflehog", "pkg", "detectors"),
+		GLPath:         filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml"),
+		OutPath:        out,
+		Mode:           "gondolin",
+		PublicSuffixes: "reject",
+	}
+	if err := runExport(context.Background(), cfg); err != nil {
+		t.Fatalf("runExport: %v", err)
+	}
+	args := []string{"-trufflehog", cfg.THDir, "-gitleaks", cfg.GLPath, "-out", out}
+
+	if err := runCheck(args); err != nil {
+		t.Fatalf("check on a fresh export: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := strings.Replace(string(data), `"keyword_host_map": {`, `"keyword_host_map": {"removed": ["gone.example.com"],`, 1)
+	if err := os.WriteFile(out, []byte(stale), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runCheck(args); err == nil || !strings.Contains(err.Error(), "is stale") {
+		t.Errorf("check on an edited export = %v, want stale error", err)
+	}
+}
@@ -0,0 +1,79 @@
+//go:build js && wasm
+
+// Command matcher-wasm exposes pkg/matcher to JavaScript, so consumers such
+// as pi-gondolin.ts run the reference matching code instead of a port of it.
+//
+// Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o matcher.wasm ./cmd/matcher-wasm
+//
+// and load it through matcher.mjs, next to this file, which also documents
+// the JavaScript API. Results cross the boundary as JSON strings that the
+// shim decodes.
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"secret-detector-export/pkg/matcher"
+)
+
+func main() {
+	js.Global().Set("hogwashMatcher", js.ValueOf(map[string]any{
+		"load": js.FuncOf(load),
+	}))
+	// Keep the Go runtime alive for calls from JavaScript.
+	select {}
+}
+
+// load(datasetJSON) returns {matcher} with the bound methods, or {error}.
+func load(_ js.Value, args []js.Value) any {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return map[string]any{"error": "load: expected the export JSON as a string"}
+	}
+	m, err := matcher.Load([]byte(args[0].String()))
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	return map[string]any{"matcher": map[string]any{
+		"hostsForEnvName": stringFunc(func(name string) any {
+			return m.HostsForEnvName(name)
+		}),
+		"keywordsForEnvName": stringFunc(func(name string) any {
+			keywords, exact := m.KeywordsForEnvName(name)
+			return map[string]any{"keywords": keywords, "exact": exact}
+		}),
+		"detectSecrets": stringFunc(func(value string) any {
+			return m.DetectSecrets(value)
+		}),
+		"compileErrors": js.FuncOf(func(js.Value, []js.Value) any {
+			errs := make(map[string]string)
+			for id, err := range m.CompileErrors() {
+				errs[id] = err.Error()
+			}
+			return toJSON(errs)
+		}),
+	}}
+}
+
+// stringFunc wraps fn as a JavaScript function of one string argument that
+// returns fn's result as JSON.
+func stringFunc(fn func(string) any) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		arg := ""
+		if len(args) > 0 {
+			arg = args[0].String()
+		}
+		return toJSON(fn(arg))
+	})
+}
+
+func toJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// Only plain strings, slices and maps are marshaled here.
+		panic(err)
+	}
+	return string(data)
+}
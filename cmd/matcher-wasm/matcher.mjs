@@ -0,0 +1,55 @@
+// JavaScript shim for matcher.wasm (see main.go). Load wasm_exec.js from the
+// Go distribution first ($(go env GOROOT)/lib/wasm/wasm_exec.js, or
+// misc/wasm/ before Go 1.24); it defines globalThis.Go.
+//
+//   import "./wasm_exec.js";
+//   import { loadMatcher } from "./matcher.mjs";
+//
+//   const m = await loadMatcher(await readFile("matcher.wasm"), datasetJSON);
+//   m.hostsForEnvName("GITHUB_TOKEN");   // ["api.github.com", ...]
+//   m.keywordsForEnvName("GITHUB_TOKEN"); // { keywords: ["github"], exact: false }
+//   m.detectSecrets(value);               // [{ pattern_id, keyword, secret, start, end, policy, hosts }]
+//   m.compileErrors();                    // { [patternId]: message }
+//
+// The dataset is a gondolin or full export, as JSON text or a parsed object.
+// Results follow pkg/matcher exactly; offsets are byte offsets into the
+// UTF-8 encoding of the value.
+
+let runtime;
+
+// init starts the Go runtime once. wasm is the module's bytes, or a fetch()
+// Response (or a promise of one) for streaming compilation.
+function init(wasm) {
+  runtime ??= (async () => {
+    const go = new globalThis.Go();
+    const source = await wasm;
+    const { instance } =
+      typeof Response !== "undefined" && source instanceof Response
+        ? await WebAssembly.instantiateStreaming(source, go.importObject)
+        : await WebAssembly.instantiate(source, go.importObject);
+    // Resolves only if the Go program exits, which it never does.
+    go.run(instance);
+    return globalThis.hogwashMatcher;
+  })();
+  return runtime;
+}
+
+// loadMatcher builds a matcher for dataset. Several matchers may be loaded
+// into one runtime.
+export async function loadMatcher(wasm, dataset) {
+  const api = await init(wasm);
+  const text = typeof dataset === "string" ? dataset : JSON.stringify(dataset);
+  const { matcher, error } = api.load(text);
+  if (error) {
+    throw new Error(`hogwash matcher: ${error}`);
+  }
+  return {
+    hostsForEnvName: (name) => JSON.parse(matcher.hostsForEnvName(name)) ?? [],
+    keywordsForEnvName: (name) => {
+      const { keywords, exact } = JSON.parse(matcher.keywordsForEnvName(name));
+      return { keywords: keywords ?? [], exact };
+    },
+    detectSecrets: (value) => JSON.parse(matcher.detectSecrets(value)) ?? [],
+    compileErrors: () => JSON.parse(matcher.compileErrors()),
+  };
+}
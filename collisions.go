@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/matcher"
+)
+
+// collisionReport is what collisions finds in a corpus of env var names.
+type collisionReport struct {
+	Names      int         `json:"names"`      // distinct names read
+	Matched    int         `json:"matched"`    // names mapped to hosts, by exact name or keyword
+	Multiple   []nameMatch `json:"multiple"`   // names matching more than one keyword
+	Unexpected []nameMatch `json:"unexpected"` // names a keyword matches inside a word
+}
+
+// nameMatch is one reported name: the keywords behind the report and the
+// hosts the name would be mapped to.
+type nameMatch struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords"`
+	Hosts    []string `json:"hosts"`
+}
+
+// runCollisions implements `hogwash collisions -dataset <export.json>
+// <names-file>`.
+func runCollisions(args []string) error {
+	fs := flag.NewFlagSet("collisions", flag.ExitOnError)
+	dataset := fs.String("dataset", "", "Gondolin or full export to match against (required)")
+	asJSON := fs.Bool("json", false, "Write the report as JSON")
+	strict := fs.Bool("strict", false, "Exit with code 5 when any name matches several keywords or a keyword inside a word")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s collisions -dataset <export.json> [flags] <names-file | ->\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Matches a corpus of env var names, one per line (KEY=VALUE lines are read as\n")
+		fmt.Fprintf(fs.Output(), "KEY), and reports names that match several keywords or match a keyword only\n")
+		fmt.Fprintf(fs.Output(), "inside a word, such as \"linear\" in LINEARIZE_CACHE_URL.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dataset == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("collisions: -dataset and one names file are required"))
+	}
+
+	m, err := matcher.LoadFile(*dataset)
+	if err != nil {
+		return fmt.Errorf("collisions: %w", err)
+	}
+	var names []string
+	if path := fs.Arg(0); path == "-" {
+		names, err = readEnvNames(os.Stdin)
+	} else {
+		var f *os.File
+		if f, err = os.Open(path); err == nil {
+			names, err = readEnvNames(f)
+			f.Close()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("collisions: %w", err)
+	}
+
+	r := simulateCollisions(m, names)
+	if *asJSON {
+		if err := export.EncodeJSON(os.Stdout, r, false); err != nil {
+			return err
+		}
+	} else {
+		printCollisions(os.Stdout, r)
+	}
+	if *strict && len(r.Multiple)+len(r.Unexpected) > 0 {
+		return withExitCode(exitValidation, fmt.Errorf("collisions: %d names match several keywords, %d match a keyword inside a word", len(r.Multiple), len(r.Unexpected)))
+	}
+	return nil
+}
+
+// simulateCollisions matches every name as a consumer of the dataset would.
+// Names hitting exact_name_host_map are counted but never reported: the
+// exact entry overrides keyword matching.
+func simulateCollisions(m *matcher.Matcher, names []string) collisionReport {
+	r := collisionReport{Names: len(names), Multiple: []nameMatch{}, Unexpected: []nameMatch{}}
+	for _, name := range names {
+		keywords, exact := m.KeywordsForEnvName(name)
+		if !exact && len(keywords) == 0 {
+			continue
+		}
+		r.Matched++
+		if exact {
+			continue
+		}
+		hosts := m.HostsForEnvName(name)
+		if len(keywords) > 1 {
+			r.Multiple = append(r.Multiple, nameMatch{Name: name, Keywords: keywords, Hosts: hosts})
+		}
+		var inside []string
+		for _, kw := range keywords {
+			if !matcher.OnWordBoundary(name, kw) {
+				inside = append(inside, kw)
+			}
+		}
+		if len(inside) > 0 {
+			r.Unexpected = append(r.Unexpected, nameMatch{Name: name, Keywords: inside, Hosts: hosts})
+		}
+	}
+	return r
+}
+
+func printCollisions(w io.Writer, r collisionReport) {
+	fmt.Fprintf(w, "%d names, %d matched, %d match several keywords, %d match a keyword inside a word\n", r.Names, r.Matched, len(r.Multiple), len(r.Unexpected))
+	for _, section := range []struct {
+		title   string
+		matches []nameMatch
+	}{{"Several keywords", r.Multiple}, {"Inside a word", r.Unexpected}} {
+		if len(section.matches) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s:\n", section.title)
+		for _, nm := range section.matches {
+			fmt.Fprintf(w, "  %s: %s → %s\n", nm.Name, strings.Join(nm.Keywords, ", "), strings.Join(nm.Hosts, ", "))
+		}
+	}
+}
+
+// readEnvNames reads one env var name per line, sorted and deduplicated.
+// Blank lines and # comments are skipped; an "export " prefix and a
+// "=VALUE" suffix are dropped, so .env files work as corpora too.
+func readEnvNames(r io.Reader) ([]string, error) {
+	seen := make(map[string]bool)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if name = strings.TrimSpace(name); name != "" {
+			seen[name] = true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return sortedKeys(seen), nil
+}
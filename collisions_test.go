@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/matcher"
+)
+
+func TestSimulateCollisions(t *testing.T) {
+	names, err := readEnvNames(strings.NewReader("# corpus\nLINEARIZE_CACHE_URL\nexport GITHUB_STRIPE_TOKEN=x\n\nLINEAR_API_KEY\nDD_API_KEY\nHOME\nLINEAR_API_KEY\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"DD_API_KEY", "GITHUB_STRIPE_TOKEN", "HOME", "LINEARIZE_CACHE_URL", "LINEAR_API_KEY"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+
+	m := matcher.New(export.Gondolin{
+		SchemaVersion: export.SchemaVersion,
+		KeywordHostMap: map[string][]string{
+			"github": {"api.github.com"},
+			"stripe": {"api.stripe.com"},
+			"linear": {"api.linear.app"},
+		},
+		ExactNameHostMap: map[string][]string{"DD_API_KEY": {"api.datadoghq.com"}},
+	})
+	r := simulateCollisions(m, names)
+	if r.Names != 5 || r.Matched != 4 {
+		t.Errorf("names/matched = %d/%d, want 5/4", r.Names, r.Matched)
+	}
+	wantMultiple := []nameMatch{{Name: "GITHUB_STRIPE_TOKEN", Keywords: []string{"github", "stripe"}, Hosts: []string{"api.github.com", "api.stripe.com"}}}
+	if !reflect.DeepEqual(r.Multiple, wantMultiple) {
+		t.Errorf("multiple = %+v", r.Multiple)
+	}
+	wantUnexpected := []nameMatch{{Name: "LINEARIZE_CACHE_URL", Keywords: []string{"linear"}, Hosts: []string{"api.linear.app"}}}
+	if !reflect.DeepEqual(r.Unexpected, wantUnexpected) {
+		t.Errorf("unexpected = %+v", r.Unexpected)
+	}
+
+	var out strings.Builder
+	printCollisions(&out, r)
+	if !strings.Contains(out.String(), "Inside a word:\n  LINEARIZE_CACHE_URL: linear → api.linear.app\n") {
+		t.Errorf("report:\n%s", out.String())
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// completion enumerates subcommands, so it registers itself here rather than
+// in the map literal, which would be an initialization cycle.
+func init() {
+	subcommands["completion"] = runCompletion
+}
+
+// completionShells are the shells `completion` can emit scripts for.
+var completionShells = []string{"bash", "fish", "zsh"}
+
+// flagValues lists the accepted values of enum flags, offered after the
+// flag instead of file names.
+var flagValues = map[string][]string{
+	"log-format":          {"json", "text"},
+	"mode":                {"full", "gondolin"},
+	"normalize":           {"basic", "loose"},
+	"public-suffix-hosts": {"reject", "wildcard"},
+}
+
+// completionFlag is one default-command flag as completion scripts see it.
+type completionFlag struct {
+	name   string
+	usage  string
+	isBool bool
+	values []string
+}
+
+// completionFlags lists the default command's flags, sorted by name.
+func completionFlags() []completionFlag {
+	fs := flag.NewFlagSet("hogwash", flag.ContinueOnError)
+	var cfg exportConfig
+	var mf mainFlags
+	cfg.registerFlags(fs)
+	mf.register(fs)
+
+	var flags []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, completionFlag{
+			name:   f.Name,
+			usage:  f.Usage,
+			isBool: ok && b.IsBoolFlag(),
+			values: flagValues[f.Name],
+		})
+	})
+	return flags
+}
+
+// runCompletion implements `hogwash completion <bash|zsh|fish>`.
+func runCompletion(args []string) error {
+	fs := flag.NewFlagSet("completion", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s completion <%s>\n\n", os.Args[0], strings.Join(completionShells, "|"))
+		fmt.Fprintf(fs.Output(), "Prints a completion script for subcommands, flags and enum values:\n")
+		fmt.Fprintf(fs.Output(), "  bash: source <(hogwash completion bash)\n")
+		fmt.Fprintf(fs.Output(), "  zsh:  hogwash completion zsh > \"${fpath[1]}/_hogwash\"\n")
+		fmt.Fprintf(fs.Output(), "  fish: hogwash completion fish > ~/.config/fish/completions/hogwash.fish\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("completion: expected a shell, got %d arguments", fs.NArg()))
+	}
+
+	cmds := sortedKeys(subcommands)
+	flags := completionFlags()
+	switch fs.Arg(0) {
+	case "bash":
+		writeBashCompletion(os.Stdout, cmds, flags)
+	case "zsh":
+		writeZshCompletion(os.Stdout, cmds, flags)
+	case "fish":
+		writeFishCompletion(os.Stdout, cmds, flags)
+	default:
+		return withExitCode(exitUsage, fmt.Errorf("completion: unknown shell %q (want %s)", fs.Arg(0), strings.Join(completionShells, ", ")))
+	}
+	return nil
+}
+
+func writeBashCompletion(w io.Writer, cmds []string, flags []completionFlag) {
+	var names []string
+	for _, f := range flags {
+		names = append(names, "-"+f.name)
+	}
+	fmt.Fprintf(w, "# bash completion for hogwash\n_hogwash() {\n")
+	fmt.Fprintf(w, "\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(w, "\tif [[ $COMP_CWORD -eq 2 && ${COMP_WORDS[1]} == completion ]]; then\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n\t\treturn\n\tfi\n", strings.Join(completionShells, " "))
+	fmt.Fprintf(w, "\tcase \"$prev\" in\n")
+	for _, f := range flags {
+		if len(f.values) > 0 {
+			fmt.Fprintf(w, "\t-%s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", f.name, strings.Join(f.values, " "))
+		}
+	}
+	fmt.Fprintf(w, "\tesac\n")
+	fmt.Fprintf(w, "\tif [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n\t\treturn\n\tfi\n", strings.Join(cmds, " "))
+	fmt.Fprintf(w, "\tif [[ $cur == -* && ! \" %s \" =~ \" ${COMP_WORDS[1]} \" ]]; then\n", strings.Join(cmds, " "))
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n\t\treturn\n\tfi\n", strings.Join(names, " "))
+	fmt.Fprintf(w, "\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n}\n")
+	fmt.Fprintf(w, "complete -o filenames -F _hogwash hogwash\n")
+}
+
+func writeZshCompletion(w io.Writer, cmds []string, flags []completionFlag) {
+	esc := strings.NewReplacer(`\`, `\\`, `'`, `'\''`, `:`, `\:`, `[`, `\[`, `]`, `\]`)
+	fmt.Fprintf(w, "#compdef hogwash\n\n_hogwash() {\n")
+	fmt.Fprintf(w, "\tlocal -a subcommands flags\n")
+	fmt.Fprintf(w, "\tsubcommands=(%s)\n", strings.Join(cmds, " "))
+	fmt.Fprintf(w, "\tflags=(\n")
+	for _, f := range flags {
+		fmt.Fprintf(w, "\t\t'-%s:%s'\n", f.name, esc.Replace(f.usage))
+	}
+	fmt.Fprintf(w, "\t)\n")
+	fmt.Fprintf(w, "\tif (( CURRENT == 3 )) && [[ $words[2] == completion ]]; then\n\t\tcompadd -- %s\n\t\treturn\n\tfi\n", strings.Join(completionShells, " "))
+	fmt.Fprintf(w, "\tcase $words[CURRENT-1] in\n")
+	for _, f := range flags {
+		if len(f.values) > 0 {
+			fmt.Fprintf(w, "\t-%s) compadd -- %s; return ;;\n", f.name, strings.Join(f.values, " "))
+		}
+	}
+	fmt.Fprintf(w, "\tesac\n")
+	fmt.Fprintf(w, "\tif (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then\n\t\t_describe 'subcommand' subcommands\n\t\treturn\n\tfi\n")
+	fmt.Fprintf(w, "\tif [[ $words[CURRENT] == -* ]] && (( ! ${subcommands[(Ie)$words[2]]} )); then\n\t\t_describe 'flag' flags\n\t\treturn\n\tfi\n")
+	fmt.Fprintf(w, "\t_files\n}\n\n")
+	fmt.Fprintf(w, "if [[ $funcstack[1] == _hogwash ]]; then\n\t_hogwash \"$@\"\nelse\n\tcompdef _hogwash hogwash\nfi\n")
+}
+
+func writeFishCompletion(w io.Writer, cmds []string, flags []completionFlag) {
+	esc := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	fmt.Fprintf(w, "# fish completion for hogwash\n")
+	fmt.Fprintf(w, "complete -c hogwash -n __fish_use_subcommand -f -a '%s'\n", strings.Join(cmds, " "))
+	fmt.Fprintf(w, "complete -c hogwash -n '__fish_seen_subcommand_from completion' -f -a '%s'\n", strings.Join(completionShells, " "))
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c hogwash -n __fish_use_subcommand -o %s -d '%s'", f.name, esc.Replace(f.usage))
+		switch {
+		case len(f.values) > 0:
+			values := append([]string(nil), f.values...)
+			sort.Strings(values)
+			line += fmt.Sprintf(" -x -a '%s'", strings.Join(values, " "))
+		case !f.isBool:
+			line += " -r"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompletionScripts(t *testing.T) {
+	cmds := sortedKeys(subcommands)
+	flags := completionFlags()
+
+	var mode *completionFlag
+	for i := range flags {
+		if flags[i].name == "mode" {
+			mode = &flags[i]
+		}
+	}
+	if mode == nil || mode.isBool || strings.Join(mode.values, " ") != "full gondolin" {
+		t.Fatalf("mode flag = %+v", mode)
+	}
+
+	writers := map[string]func(*bytes.Buffer){
+		"bash": func(b *bytes.Buffer) { writeBashCompletion(b, cmds, flags) },
+		"zsh":  func(b *bytes.Buffer) { writeZshCompletion(b, cmds, flags) },
+		"fish": func(b *bytes.Buffer) { writeFishCompletion(b, cmds, flags) },
+	}
+	for shell, write := range writers {
+		var b bytes.Buffer
+		write(&b)
+		script := b.String()
+		for _, want := range []string{"completion", "scan-env", "watch-interval", "gondolin", "wildcard"} {
+			if !strings.Contains(script, want) {
+				t.Errorf("%s completion lacks %q", shell, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// defaultConfigFile is picked up from the working directory when -config
+// isn't given.
+const defaultConfigFile = "secret-mapping.toml"
+
+// applyConfigFile sets flags on fs from a TOML config file. Top-level keys
+// are flag names without the dash; arrays repeat the flag. A [profile.<name>]
+// table bundles more flags for one consumer and is applied over the
+// top-level keys when -profile names it. Flags given on the command line win.
+// With an empty -config, defaultConfigFile is used if it exists; cfg.ConfigPath
+// is left naming the file that was read.
+func (cfg *exportConfig) applyConfigFile(fs *flag.FlagSet) error {
+	path := cfg.ConfigPath
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFile); err != nil {
+			if cfg.Profile != "" {
+				return fmt.Errorf("-profile %s: no config file (-config, or ./%s)", cfg.Profile, defaultConfigFile)
+			}
+			return nil
+		}
+		path = defaultConfigFile
+	}
+
+	var values map[string]any
+	if _, err := toml.DecodeFile(path, &values); err != nil {
+		return fmt.Errorf("read config %s: %w", path, err)
+	}
+	profiles, _ := values["profile"].(map[string]any)
+	delete(values, "profile")
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if cfg.Profile != "" {
+		profile, ok := profiles[cfg.Profile].(map[string]any)
+		if !ok {
+			return fmt.Errorf("config %s: no profile %q (have: %s)", path, cfg.Profile, strings.Join(sortedKeys(profiles), ", "))
+		}
+		if err := setConfigFlags(fs, path+" profile "+cfg.Profile, profile, set); err != nil {
+			return err
+		}
+	}
+	if err := setConfigFlags(fs, path, values, set); err != nil {
+		return err
+	}
+	cfg.ConfigPath = path
+	return nil
+}
+
+// setConfigFlags sets the flags named by values' keys that aren't in set yet,
+// and adds them to set. where names the file (and profile) in errors.
+func setConfigFlags(fs *flag.FlagSet, where string, values map[string]any, set map[string]bool) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, name := range keys {
+		if name == "config" || name == "profile" {
+			return fmt.Errorf("config %s: %q cannot be set from a config file", where, name)
+		}
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("config %s: unknown flag %q", where, name)
+		}
+		if set[name] {
+			continue
+		}
+		// An array sets a repeatable flag such as -out once per element.
+		items, ok := values[name].([]any)
+		if !ok {
+			items = []any{values[name]}
+		}
+		for _, item := range items {
+			value, err := configValue(item)
+			if err != nil {
+				return fmt.Errorf("config %s: %s: %w", where, name, err)
+			}
+			if err := fs.Set(name, value); err != nil {
+				return fmt.Errorf("config %s: %s: %w", where, name, err)
+			}
+		}
+		set[name] = true
+	}
+	return nil
+}
+
+// configValue renders a TOML value the way it would be typed on the
+// command line.
+func configValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case time.Duration:
+		return v.String(), nil
+	}
+	return "", errors.New("value must be a string, boolean or number")
+}
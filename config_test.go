@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret-mapping.toml")
+	config := `
+mode = "gondolin"
+out = "dist/secret-mapping.gondolin.json"
+top = 50
+verify-dns = true
+dns-timeout = "2s"
+https-rate = 2.5
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg exportConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.registerFlags(fs)
+	if err := fs.Parse([]string{"-config", path, "-out", "override.json"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.applyConfigFile(fs); err != nil {
+		t.Fatalf("applyConfigFile: %v", err)
+	}
+	if cfg.Mode != "gondolin" || cfg.Top != 50 || !cfg.VerifyDNS || cfg.DNSTimeout != 2*time.Second || cfg.HTTPSRate != 2.5 {
+		t.Errorf("config not applied: %+v", cfg)
+	}
+	if cfg.OutPath != "override.json" {
+		t.Errorf("OutPath = %q, want the command-line value", cfg.OutPath)
+	}
+
+	if err := os.WriteFile(path, []byte(`out = ["mode=full,path=full.json", "mode=gondolin,path=gondolin.json"]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg = exportConfig{}
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.registerFlags(fs)
+	if err := fs.Parse([]string{"-config", path}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.applyConfigFile(fs); err != nil {
+		t.Fatalf("applyConfigFile with an array: %v", err)
+	}
+	if len(cfg.Outputs) != 2 || cfg.Outputs[1].Mode != "gondolin" {
+		t.Errorf("Outputs = %+v, want one per array element", cfg.Outputs)
+	}
+
+	if err := os.WriteFile(path, []byte("outt = \"typo.json\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cfg.registerFlags(fs)
+	if err := fs.Parse([]string{"-config", path}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.applyConfigFile(fs); err == nil || !strings.Contains(err.Error(), `unknown flag "outt"`) {
+		t.Errorf("applyConfigFile with unknown key = %v, want unknown flag error", err)
+	}
+}
+
+func TestConfigProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret-mapping.toml")
+	config := `
+mode = "full"
+top = 10
+out = "dist/full.json"
+
+[profile.gondolin-edge]
+mode = "gondolin"
+categories = "ai,vcs"
+out = ["mode=gondolin,path=dist/edge.json"]
+
+[profile.full-audit]
+audit = true
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	parse := func(args ...string) (exportConfig, error) {
+		var cfg exportConfig
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		cfg.registerFlags(fs)
+		if err := fs.Parse(append([]string{"-config", path}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		return cfg, cfg.applyConfigFile(fs)
+	}
+
+	cfg, err := parse("-profile", "gondolin-edge", "-categories", "ai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Mode != "gondolin" || cfg.Top != 10 || cfg.Categories != "ai" {
+		t.Errorf("profile not layered between config and command line: %+v", cfg)
+	}
+	if len(cfg.Outputs) != 1 || cfg.Outputs[0].Path != "dist/edge.json" || cfg.outPathSet {
+		t.Errorf("profile -out should replace the top-level one: %+v", cfg.Outputs)
+	}
+
+	if cfg, err = parse(); err != nil || cfg.Mode != "full" || cfg.Audit {
+		t.Errorf("without -profile: %+v, %v", cfg, err)
+	}
+	if _, err := parse("-profile", "edge"); err == nil || !strings.Contains(err.Error(), "full-audit, gondolin-edge") {
+		t.Errorf("unknown profile: %v", err)
+	}
+}
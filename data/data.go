@@ -0,0 +1,150 @@
+// Package data embeds the curated policy files that steer extraction and
+// export. They live as JSON so policy can evolve without editing Go source.
+package data
+
+import _ "embed"
+
+// DeprecatedServices maps retired service keywords (vendor shut down,
+// upstream detector removed) to the reason. They stay in the full export but
+// are left out of gondolin mode.
+//
+//go:embed deprecated_services.json
+var DeprecatedServices []byte
+
+// EnvNames lists vendor-specific env var names per service keyword, added
+// to the generated KEYWORD_API_KEY-style names.
+//
+//go:embed env_names.json
+var EnvNames []byte
+
+// EnvNamePrefixes lists vendor abbreviations env var names start with per
+// service keyword ("DD_" for datadog); each is expanded with the generated
+// suffixes.
+//
+//go:embed env_name_prefixes.json
+var EnvNamePrefixes []byte
+
+// ExactNameHostMap maps env var names where keyword-based matching doesn't
+// work (too short, too generic, no service name) to hosts.
+//
+//go:embed exact_name_host_map.json
+var ExactNameHostMap []byte
+
+// GondolinPatternDenylist lists Gitleaks rule IDs too generic for runtime
+// value matching in gondolin mode.
+//
+//go:embed gondolin_pattern_denylist.json
+var GondolinPatternDenylist []byte
+
+// HostRoles pins the role of hosts the classification heuristics get wrong.
+//
+//go:embed host_roles.json
+var HostRoles []byte
+
+// Licenses is the attribution block embedded in every export: the license
+// and copyright of each source the data is derived from.
+//
+//go:embed licenses.json
+var Licenses []byte
+
+// MatchPins restricts which TruffleHog keywords a Gitleaks keyword may bind
+// by prefix; an empty list rejects every prefix match. Edited with
+// `hogwash review`.
+//
+//go:embed match_pins.json
+var MatchPins []byte
+
+// MustHaveServices maps service keywords every release must contain to hosts
+// they must map to; the audit gate fails when one is missing.
+//
+//go:embed must_have_services.json
+var MustHaveServices []byte
+
+// PatternPolicy maps rule IDs and service categories to policy hints.
+//
+//go:embed pattern_policy.json
+var PatternPolicy []byte
+
+// PrimaryHostOverrides pins the primary host for specific services.
+//
+//go:embed primary_host_overrides.json
+var PrimaryHostOverrides []byte
+
+// RegionalHosts lists curated regional endpoints per service keyword.
+//
+//go:embed regional_hosts.json
+var RegionalHosts []byte
+
+// RuleSeverity maps rule IDs and service categories to severities.
+//
+//go:embed rule_severity.json
+var RuleSeverity []byte
+
+// ServiceAliases maps Gitleaks keywords to the TruffleHog keyword of the
+// same service where the names diverge after normalization. Edited with
+// `hogwash alias`.
+//
+//go:embed service_aliases.json
+var ServiceAliases []byte
+
+// ServiceCategories assigns a taxonomy category to service keywords.
+//
+//go:embed service_categories.json
+var ServiceCategories []byte
+
+// ServiceInfo gives service keywords a display name and an API
+// documentation link.
+//
+//go:embed service_info.json
+var ServiceInfo []byte
+
+// ServicePopularity is a most-popular-first list of service keywords.
+//
+//go:embed service_popularity.json
+var ServicePopularity []byte
+
+// THKeywordOverrides maps TruffleHog detector directory names to keywords
+// where credential-suffix stripping gets them wrong. Edited with
+// `hogwash override`.
+//
+//go:embed th_keyword_overrides.json
+var THKeywordOverrides []byte
+
+// TenantHosts lists curated tenant-scoped host templates per service
+// keyword, e.g. "{workspace}.slack.com".
+//
+//go:embed tenant_hosts.json
+var TenantHosts []byte
+
+// WellKnownHosts maps service keywords to API hosts, backfilled into
+// Gitleaks services no TruffleHog detector matches.
+//
+//go:embed well_known_hosts.json
+var WellKnownHosts []byte
+
+// Files maps each embedded file name to its contents, so a binary can report
+// which policy data it carries.
+func Files() map[string][]byte {
+	return map[string][]byte{
+		"deprecated_services.json":       DeprecatedServices,
+		"env_name_prefixes.json":         EnvNamePrefixes,
+		"env_names.json":                 EnvNames,
+		"exact_name_host_map.json":       ExactNameHostMap,
+		"gondolin_pattern_denylist.json": GondolinPatternDenylist,
+		"host_roles.json":                HostRoles,
+		"licenses.json":                  Licenses,
+		"match_pins.json":                MatchPins,
+		"must_have_services.json":        MustHaveServices,
+		"pattern_policy.json":            PatternPolicy,
+		"primary_host_overrides.json":    PrimaryHostOverrides,
+		"regional_hosts.json":            RegionalHosts,
+		"rule_severity.json":             RuleSeverity,
+		"service_aliases.json":           ServiceAliases,
+		"service_categories.json":        ServiceCategories,
+		"service_info.json":              ServiceInfo,
+		"service_popularity.json":        ServicePopularity,
+		"th_keyword_overrides.json":      THKeywordOverrides,
+		"tenant_hosts.json":              TenantHosts,
+		"well_known_hosts.json":          WellKnownHosts,
+	}
+}
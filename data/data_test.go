@@ -0,0 +1,27 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilesListsEveryEmbeddedFile(t *testing.T) {
+	names, err := filepath.Glob("*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := Files()
+	if len(files) != len(names) {
+		t.Errorf("Files() has %d entries, directory has %d JSON files", len(files), len(names))
+	}
+	for _, name := range names {
+		want, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := files[name]; !ok || string(got) != string(want) {
+			t.Errorf("Files()[%q] missing or stale", name)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeDataFile writes a curated keyword map (map[string]string or
+// map[string][]string) in the committed layout of the data files: one key
+// per line, sorted, lists on one line, trailing newline.
+func writeDataFile(path string, m any) error {
+	var entries []string
+	switch m := m.(type) {
+	case map[string]string:
+		for _, k := range sortedKeys(m) {
+			entries = append(entries, fmt.Sprintf("  %s: %s", jsonString(k), jsonString(m[k])))
+		}
+	case map[string][]string:
+		for _, k := range sortedKeys(m) {
+			items := make([]string, len(m[k]))
+			for i, v := range m[k] {
+				items[i] = jsonString(v)
+			}
+			entries = append(entries, fmt.Sprintf("  %s: [%s]", jsonString(k), strings.Join(items, ", ")))
+		}
+	default:
+		return fmt.Errorf("unsupported data file type %T", m)
+	}
+	return writeFileAtomic(path, true, false, 0o644, func(w io.Writer) error {
+		if len(entries) == 0 {
+			_, err := io.WriteString(w, "{}\n")
+			return err
+		}
+		_, err := io.WriteString(w, "{\n"+strings.Join(entries, ",\n")+"\n}\n")
+		return err
+	})
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// diffFiles loads two exports and compares them. Both must be the same kind.
+func diffFiles(oldPath, newPath string) (export.Diff, error) {
+	var oldRaw, newRaw json.RawMessage
+	if err := readJSONInput(oldPath, &oldRaw); err != nil {
+		return export.Diff{}, err
+	}
+	if err := readJSONInput(newPath, &newRaw); err != nil {
+		return export.Diff{}, err
+	}
+	oldKind, err := export.DetectKind(oldRaw)
+	if err != nil {
+		return export.Diff{}, fmt.Errorf("%s: %w", oldPath, err)
+	}
+	newKind, err := export.DetectKind(newRaw)
+	if err != nil {
+		return export.Diff{}, fmt.Errorf("%s: %w", newPath, err)
+	}
+	if oldKind != newKind {
+		return export.Diff{}, fmt.Errorf("cannot compare a %s export (%s) with a %s export (%s)", oldKind, oldPath, newKind, newPath)
+	}
+
+	if oldKind == "gondolin" {
+		var o, n export.Gondolin
+		if err := json.Unmarshal(oldRaw, &o); err != nil {
+			return export.Diff{}, fmt.Errorf("decode %s: %w", oldPath, err)
+		}
+		if err := json.Unmarshal(newRaw, &n); err != nil {
+			return export.Diff{}, fmt.Errorf("decode %s: %w", newPath, err)
+		}
+		return export.DiffGondolin(o, n), nil
+	}
+	var o, n combine.Export
+	if err := json.Unmarshal(oldRaw, &o); err != nil {
+		return export.Diff{}, fmt.Errorf("decode %s: %w", oldPath, err)
+	}
+	if err := json.Unmarshal(newRaw, &n); err != nil {
+		return export.Diff{}, fmt.Errorf("decode %s: %w", newPath, err)
+	}
+	return export.DiffFull(o, n), nil
+}
+
+// runDiff implements `hogwash diff [flags] <old.json> <new.json>`.
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	outPath := fs.String("out", "-", "Output file path (or - for stdout)")
+	force := fs.Bool("force", false, "Overwrite -out if it already exists")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s diff [flags] <old.json> <new.json>\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("diff: expected two export files, got %d", fs.NArg()))
+	}
+
+	d, err := diffFiles(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("diff: %w", err)
+	}
+	if err := writeJSONOutput(*outPath, *force, false, false, d); err != nil {
+		return fmt.Errorf("diff: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "diff: services +%d -%d, host changes %d, rules +%d -%d, changed regexes %d\n",
+		len(d.AddedServices), len(d.RemovedServices), len(d.HostChanges),
+		len(d.AddedRules), len(d.RemovedRules), len(d.ChangedRegexes))
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Exit codes let CI tell failure classes apart. Go's flag package already
+// exits 2 on unknown flags, so exitUsage matches it.
+const (
+	exitFailure    = 1 // anything not classified below
+	exitUsage      = 2 // invalid flags, arguments or config file
+	exitExtraction = 3 // -trufflehog, -gitleaks or -from-full input couldn't be read
+	exitStrict     = 4 // a -strict check failed (extraction warnings, validation, host conflicts, coverage) or extraction exceeded -max-warnings/-max-skipped
+	exitValidation = 5 // validate, lint -strict, collisions -strict, audit or -golden found problems, or the combined export is invalid
+	exitStale      = 6 // check found the committed output stale
+	exitCanceled   = 7 // interrupted, or -timeout exceeded
+)
+
+// exitCodes documents every code for -list-exit-codes.
+var exitCodes = []struct {
+	code int
+	desc string
+}{
+	{0, "success"},
+	{exitFailure, "other failure (I/O, network, encoding)"},
+	{exitUsage, "invalid flags, arguments or config file"},
+	{exitExtraction, "TruffleHog, Gitleaks or -from-full input couldn't be read or parsed"},
+	{exitStrict, "a -strict check failed (extraction warnings, validation, host conflicts, coverage) or extraction exceeded -max-warnings/-max-skipped"},
+	{exitValidation, "validate, lint -strict, collisions -strict, audit or -golden found problems, or the combined export is invalid"},
+	{exitStale, "check found the committed output stale"},
+	{exitCanceled, "interrupted (SIGINT/SIGTERM) or -timeout exceeded"},
+}
+
+// exitCodeError attaches an exit code to an error; wrapping it further with
+// %w keeps the code.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// withExitCode tags err with code. A nil err stays nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{code: code, err: err}
+}
+
+// exitCode returns the code err was tagged with, or exitFailure.
+func exitCode(err error) int {
+	var ec *exitCodeError
+	if errors.As(err, &ec) {
+		return ec.code
+	}
+	return exitFailure
+}
+
+func listExitCodes(w io.Writer) {
+	for _, c := range exitCodes {
+		fmt.Fprintf(w, "%d\t%s\n", c.code, c.desc)
+	}
+}
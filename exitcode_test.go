@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{errors.New("boom"), exitFailure},
+		{withExitCode(exitStale, errors.New("stale")), exitStale},
+		{fmt.Errorf("check: %w", withExitCode(exitUsage, errors.New("bad flag"))), exitUsage},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+	if withExitCode(exitUsage, nil) != nil {
+		t.Error("withExitCode(nil) != nil")
+	}
+}
+
+func TestRunExportCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out := filepath.Join(t.TempDir(), "out.json")
+	cfg := exportConfig{
+		THDir:   filepath.Join("testdata", "trufflehog", "pkg", "detectors"),
+		OutPath: out,
+		Mode:    "full",
+	}
+	if err := runExport(ctx, cfg); exitCode(err) != exitCanceled {
+		t.Fatalf("runExport with canceled ctx = %v (exit %d), want exit %d", err, exitCode(err), exitCanceled)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(out)); len(entries) != 0 {
+		t.Errorf("canceled run left files behind: %v", entries)
+	}
+}
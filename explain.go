@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// runExplain implements `hogwash explain -trufflehog <dir> -gitleaks <toml> <gl-keyword>`.
+func runExplain(args []string) error {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	thDir := fs.String("trufflehog", "", "Path to trufflehog/pkg/detectors/ (required)")
+	glPath := fs.String("gitleaks", "", "Path to gitleaks/config/gitleaks.toml (required)")
+	allowIPHosts := fs.Bool("allow-ip-hosts", false, "Keep IP-literal hosts, as the export flag of the same name does")
+	normalize := fs.String("normalize", string(combine.NormalizeBasic), "Keyword comparison, as the export flag of the same name: 'basic' or 'loose'")
+	asJSON := fs.Bool("json", false, "Write the trace as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s explain -trufflehog <dir> -gitleaks <toml> <gl-keyword>\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *thDir == "" || *glPath == "" {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("explain: -trufflehog and -gitleaks are required"))
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("explain: expected exactly one gitleaks keyword, got %d", fs.NArg()))
+	}
+
+	level, err := combine.ParseNormalization(*normalize)
+	if err != nil {
+		return withExitCode(exitUsage, fmt.Errorf("explain: -normalize: %w", err))
+	}
+
+	thDetectors, _, _, err := trufflehog.Extract(*thDir, trufflehog.ExtractOptions{AllowIPHosts: *allowIPHosts})
+	if err != nil {
+		return fmt.Errorf("explain: trufflehog extraction: %w", err)
+	}
+	glRules, err := gitleaks.Extract(*glPath)
+	if err != nil {
+		return fmt.Errorf("explain: gitleaks extraction: %w", err)
+	}
+
+	ex := combine.ExplainWith(fs.Arg(0), thDetectors, glRules, combine.Options{Normalization: level})
+	if *asJSON {
+		return export.EncodeJSON(os.Stdout, ex, false)
+	}
+	fmt.Fprintf(os.Stdout, "%s\n", ex.Keyword)
+	if len(ex.Rules) > 0 {
+		fmt.Fprintf(os.Stdout, "  rules: %s\n", strings.Join(ex.Rules, ", "))
+	}
+	fmt.Fprintln(os.Stdout, "  trace:")
+	for _, s := range ex.Steps {
+		fmt.Fprintf(os.Stdout, "    %s\n", s)
+	}
+	if len(ex.Detectors) > 0 {
+		fmt.Fprintln(os.Stdout, "  detectors:")
+		for _, d := range ex.Detectors {
+			mark := "-"
+			if d.Bound {
+				mark = "+"
+			}
+			fmt.Fprintf(os.Stdout, "    %s %s (%s): %s\n", mark, d.DirName, d.Keyword, d.Reason)
+		}
+	}
+	if len(ex.Hosts) > 0 {
+		fmt.Fprintf(os.Stdout, "  hosts: %s\n", strings.Join(ex.Hosts, ", "))
+	}
+	if ex.PrimaryHost != "" {
+		fmt.Fprintf(os.Stdout, "  primary host: %s\n", ex.PrimaryHost)
+	}
+	return nil
+}
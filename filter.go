@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// keywordList parses a comma-separated -only/-exclude value.
+func keywordList(s string) []string {
+	var out []string
+	for _, k := range strings.Split(s, ",") {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// filterServices applies -only and -exclude to the combined export: a
+// service or TH-only entry is kept if -only is empty or lists its keyword,
+// and -exclude doesn't. Listed keywords that match nothing are warned
+// about, since they are usually typos.
+func filterServices(full combine.Export, only, exclude string) combine.Export {
+	onlySet, excludeSet := make(map[string]bool), make(map[string]bool)
+	for _, k := range keywordList(only) {
+		onlySet[k] = true
+	}
+	for _, k := range keywordList(exclude) {
+		excludeSet[k] = true
+	}
+	seen := make(map[string]bool)
+	before := len(full.Services) + len(full.THOnlyHosts)
+	full = full.WithServices(func(keyword string) bool {
+		keyword = strings.ToLower(keyword)
+		seen[keyword] = true
+		return (len(onlySet) == 0 || onlySet[keyword]) && !excludeSet[keyword]
+	})
+	for _, flagSet := range []struct {
+		name string
+		set  map[string]bool
+	}{{"-only", onlySet}, {"-exclude", excludeSet}} {
+		for _, k := range sortedKeys(flagSet.set) {
+			if !seen[k] {
+				logger.Warn(fmt.Sprintf("%s: no service with keyword %q", flagSet.name, k), "flag", flagSet.name, "keyword", k)
+			}
+		}
+	}
+	after := len(full.Services) + len(full.THOnlyHosts)
+	logger.Info(fmt.Sprintf("Kept %d of %d services (-only/-exclude)", after, before), "kept", after, "services", before)
+	return full
+}
+
+// checkCategories reports categories that aren't curated.
+func checkCategories(categories []string) error {
+	if len(categories) == 0 {
+		return errors.New("no categories")
+	}
+	known := combine.Categories()
+	for _, c := range categories {
+		if i := sort.SearchStrings(known, c); i == len(known) || known[i] != c {
+			return fmt.Errorf("unknown category %q (known: %s)", c, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// rulesIn counts the rules of services.
+func rulesIn(services []combine.Service) int {
+	n := 0
+	for _, svc := range services {
+		n += len(svc.Rules)
+	}
+	return n
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOnlyExclude(t *testing.T) {
+	base := exportConfig{
+		THDir:          filepath.Join("testdata", "trufflehog", "pkg", "detectors"),
+		GLPath:         filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml"),
+		Mode:           "full",
+		PublicSuffixes: "reject",
+	}
+	for _, tc := range []struct {
+		only, exclude string
+		want          []string
+	}{
+		{"", "", []string{"cisco-meraki", "cloudflare"}},
+		{"Cloudflare, nosuchservice", "", []string{"cloudflare"}},
+		{"", "cloudflare", []string{"cisco-meraki"}},
+		{"cloudflare,cisco-meraki", "cisco-meraki", []string{"cloudflare"}},
+	} {
+		cfg := base
+		cfg.Only, cfg.Exclude = tc.only, tc.exclude
+		out, err := buildExport(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("-only %q -exclude %q: %v", tc.only, tc.exclude, err)
+		}
+		var got []string
+		for _, svc := range out.full.Services {
+			got = append(got, svc.Keyword)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") || out.full.Stats.TotalServices != len(tc.want) {
+			t.Errorf("-only %q -exclude %q: keywords %v, want %v", tc.only, tc.exclude, got, tc.want)
+		}
+	}
+
+	var cfg exportConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.registerFlags(fs)
+	if err := fs.Parse([]string{"-gitleaks", "gl.toml", "-only", " , "}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "invalid -only") {
+		t.Errorf("-only without keywords: validate = %v", err)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	cfg := exportConfig{
+		THDir:          filepath.Join("testdata", "trufflehog", "pkg", "detectors"),
+		GLPath:         filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml"),
+		Mode:           "gondolin",
+		PublicSuffixes: "reject",
+		Categories:     "infra",
+	}
+	out, err := buildExport(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sortedKeys(out.gondolin.KeywordHostMap); strings.Join(got, ",") != "cisco-meraki" {
+		t.Errorf("keyword_host_map keys = %v, want [cisco-meraki]", got)
+	}
+	if st := out.gondolinStats; st.ValuePatterns != 1 || st.ExcludedPatterns != 0 || strings.Join(st.Categories, ",") != "infra" {
+		t.Errorf("gondolin stats = %+v", st)
+	}
+	if len(out.full.Services) != 2 {
+		t.Errorf("full export was scoped too: %d services", len(out.full.Services))
+	}
+
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"-categories", "ai"}, "requires a gondolin output"},
+		{[]string{"-mode", "gondolin", "-categories", "ai,nosuch"}, `unknown category "nosuch"`},
+		{[]string{"-mode", "gondolin", "-categories", "AI, vcs"}, ""},
+	} {
+		var cfg exportConfig
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		cfg.registerFlags(fs)
+		if err := fs.Parse(append([]string{"-gitleaks", "gl.toml"}, tc.args...)); err != nil {
+			t.Fatal(err)
+		}
+		err := cfg.validate()
+		if tc.err == "" && err != nil || tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%v: validate = %v, want %q", tc.args, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// gapFixes names, per cause, the extractor work that would recover the
+// detectors grouped under it.
+var gapFixes = map[string]string{
+	trufflehog.GapSprintfHost: "resolve fmt.Sprintf host arguments",
+	trufflehog.GapConstURL:    "propagate constants into URL concatenation",
+	trufflehog.SkipNoURLs:     "none found: SDK, gRPC or non-HTTP verification",
+	trufflehog.SkipAllNoise:   "review the noise filters",
+	trufflehog.SkipParseError: "fix the package parse error",
+}
+
+// gapReport lists the TruffleHog detectors that yielded no hosts, grouped
+// by cause, largest group first.
+type gapReport struct {
+	Detectors    int        `json:"detectors"`     // detector directories extracted
+	WithoutHosts int        `json:"without_hosts"` // of which yielded no hosts
+	Groups       []gapGroup `json:"groups"`
+}
+
+type gapGroup struct {
+	Cause     string   `json:"cause"` // a Gap constant, or the skip reason when no finer cause is known
+	Fix       string   `json:"fix"`
+	Detectors []string `json:"detectors"`
+}
+
+// runGaps implements `hogwash gaps -trufflehog <dir>`.
+func runGaps(args []string) error {
+	fs := flag.NewFlagSet("gaps", flag.ExitOnError)
+	thDir := fs.String("trufflehog", "", "Path to trufflehog/pkg/detectors/ (required)")
+	asJSON := fs.Bool("json", false, "Write the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s gaps -trufflehog <dir> [-json]\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Lists the TruffleHog detectors that yielded no hosts, grouped by the extractor\n")
+		fmt.Fprintf(fs.Output(), "improvement that would recover them, largest group first.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *thDir == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("gaps: -trufflehog is required"))
+	}
+	detectors, skipped, _, err := trufflehog.Extract(*thDir, trufflehog.ExtractOptions{})
+	if err != nil {
+		return fmt.Errorf("gaps: trufflehog extraction: %w", err)
+	}
+	r := buildGapReport(len(detectors), skipped)
+	if *asJSON {
+		return export.EncodeJSON(os.Stdout, r, false)
+	}
+	return printGapReport(os.Stdout, r)
+}
+
+func buildGapReport(withHosts int, skipped []trufflehog.Skipped) gapReport {
+	r := gapReport{Detectors: withHosts + len(skipped), WithoutHosts: len(skipped), Groups: []gapGroup{}}
+	byCause := make(map[string][]string)
+	for _, s := range skipped {
+		cause := s.Cause
+		if cause == "" {
+			cause = s.Reason
+		}
+		byCause[cause] = append(byCause[cause], s.DirName)
+	}
+	for cause, dirs := range byCause {
+		sort.Strings(dirs)
+		r.Groups = append(r.Groups, gapGroup{Cause: cause, Fix: gapFixes[cause], Detectors: dirs})
+	}
+	sort.Slice(r.Groups, func(i, j int) bool {
+		a, b := r.Groups[i], r.Groups[j]
+		if len(a.Detectors) != len(b.Detectors) {
+			return len(a.Detectors) > len(b.Detectors)
+		}
+		return a.Cause < b.Cause
+	})
+	return r
+}
+
+func printGapReport(out io.Writer, r gapReport) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	share := 0.0
+	if r.Detectors > 0 {
+		share = 100 * float64(r.WithoutHosts) / float64(r.Detectors)
+	}
+	fmt.Fprintf(w, "Detectors:\t%d, %d without hosts (%.1f%%)\n", r.Detectors, r.WithoutHosts, share)
+	for _, g := range r.Groups {
+		fmt.Fprintf(w, "  %s:\t%d\t%s\n", g.Cause, len(g.Detectors), g.Fix)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, g := range r.Groups {
+		fmt.Fprintf(out, "\n%s:\n", g.Cause)
+		for _, dir := range g.Detectors {
+			fmt.Fprintf(out, "  %s\n", dir)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestGapReport(t *testing.T) {
+	skipped := []trufflehog.Skipped{
+		{DirName: "zeta", Reason: trufflehog.SkipNoURLs, Cause: trufflehog.GapSprintfHost},
+		{DirName: "alpha", Reason: trufflehog.SkipAllNoise, Cause: trufflehog.GapSprintfHost},
+		{DirName: "offline", Reason: trufflehog.SkipNoURLs},
+		{DirName: "base", Reason: trufflehog.SkipNoURLs, Cause: trufflehog.GapConstURL},
+	}
+	r := buildGapReport(6, skipped)
+	if r.Detectors != 10 || r.WithoutHosts != 4 {
+		t.Errorf("counts = %d/%d, want 10/4", r.Detectors, r.WithoutHosts)
+	}
+	var got []string
+	for _, g := range r.Groups {
+		got = append(got, g.Cause+"="+strings.Join(g.Detectors, ","))
+	}
+	want := []string{"sprintf_host=alpha,zeta", "const_url=base", "no_urls=offline"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groups = %v, want %v", got, want)
+	}
+
+	var out strings.Builder
+	if err := printGapReport(&out, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"10, 4 without hosts (40.0%)", "resolve fmt.Sprintf host arguments", "\nsprintf_host:\n  alpha\n  zeta\n"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("report lacks %q:\n%s", line, out.String())
+		}
+	}
+}
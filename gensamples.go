@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/samples"
+)
+
+// sample is one synthesized fake secret in the gen-samples corpus.
+type sample struct {
+	PatternID string `json:"pattern_id"`
+	Keyword   string `json:"keyword,omitempty"`
+	Value     string `json:"value"`
+}
+
+// loadValuePatterns returns the value patterns of a gondolin export, or of
+// the gondolin export derived from a full one. Full exports keep every rule
+// (no denylist), so generic patterns get samples too.
+func loadValuePatterns(path string) ([]export.ValuePattern, error) {
+	g, err := loadGondolin(path, export.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return g.ValuePatterns, nil
+}
+
+// generateSamples synthesizes n samples per pattern. Patterns the generator
+// can't satisfy are returned as failures rather than aborting the corpus.
+func generateSamples(patterns []export.ValuePattern, n int, rng *rand.Rand) ([]sample, map[string]error) {
+	out := []sample{}
+	failed := make(map[string]error)
+	for _, p := range patterns {
+		for i := 0; i < n; i++ {
+			v, err := samples.ForPattern(p, rng)
+			if err != nil {
+				failed[p.ID] = err
+				break
+			}
+			out = append(out, sample{PatternID: p.ID, Keyword: p.Keyword, Value: v})
+		}
+	}
+	return out, failed
+}
+
+// runGenSamples implements `hogwash gen-samples -dataset <export.json> [flags]`.
+func runGenSamples(args []string) error {
+	fs := flag.NewFlagSet("gen-samples", flag.ExitOnError)
+	dataset := fs.String("dataset", "", "Gondolin or full export whose patterns to sample (required)")
+	n := fs.Int("n", 1, "Samples per pattern")
+	seed := fs.Int64("seed", 1, "Random seed; the same seed and dataset give the same corpus")
+	outPath := fs.String("out", "-", "Output file path (or - for stdout)")
+	force := fs.Bool("force", false, "Overwrite -out if it already exists")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s gen-samples -dataset <export.json> [flags]\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dataset == "" {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("gen-samples: -dataset is required"))
+	}
+	if *n < 1 {
+		return fmt.Errorf("gen-samples: invalid -n %d: must be >= 1", *n)
+	}
+
+	patterns, err := loadValuePatterns(*dataset)
+	if err != nil {
+		return fmt.Errorf("gen-samples: %w", err)
+	}
+	corpus, failed := generateSamples(patterns, *n, rand.New(rand.NewSource(*seed)))
+	for _, id := range sortedKeys(failed) {
+		fmt.Fprintf(os.Stderr, "gen-samples: %s: %v\n", id, failed[id])
+	}
+	if err := writeJSONOutput(*outPath, *force, false, false, corpus); err != nil {
+		return fmt.Errorf("gen-samples: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "gen-samples: %d samples for %d patterns (%d failed)\n", len(corpus), len(patterns)-len(failed), len(failed))
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/matcher"
+)
+
+func TestGenerateSamplesAreDetected(t *testing.T) {
+	g := export.Gondolin{
+		KeywordHostMap: map[string][]string{"github": {"api.github.com"}},
+		ValuePatterns: []export.ValuePattern{
+			{ID: "github-pat", Keyword: "github", Regex: `ghp_[0-9a-zA-Z]{36}`, Keywords: []string{"ghp_"}},
+			{ID: "cloudflare-api-key", Regex: `(?i)\bcloudflare_[a-z0-9]{16}\b`, Keywords: []string{"cloudflare"}},
+			{ID: "broken", Regex: `(`},
+		},
+	}
+
+	corpus, failed := generateSamples(g.ValuePatterns, 2, rand.New(rand.NewSource(1)))
+	if len(corpus) != 4 {
+		t.Errorf("corpus has %d samples, want 4", len(corpus))
+	}
+	if len(failed) != 1 || failed["broken"] == nil {
+		t.Errorf("failed = %v, want only broken", failed)
+	}
+
+	m := matcher.New(g)
+	for _, s := range corpus {
+		found := false
+		for _, f := range m.DetectSecrets(s.Value) {
+			if f.PatternID == s.PatternID {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("sample %q for %s is not detected", s.Value, s.PatternID)
+		}
+	}
+}
@@ -2,4 +2,15 @@ module secret-detector-export
 
 go 1.22
 
-require github.com/BurntSushi/toml v1.6.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	golang.org/x/net v0.30.0
+	google.golang.org/grpc v1.67.3
+	google.golang.org/protobuf v1.35.2
+)
+
+require (
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.19.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+)
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// goldenListLimit caps how many lost services, hosts and rules -golden
+// prints per category.
+const goldenListLimit = 10
+
+// checkGolden compares the export against the golden file at path and
+// returns an error if it regressed beyond limits. A full golden file is
+// compared with full; a gondolin one with g, or with full reduced by the
+// default options when the run isn't producing gondolin output.
+func checkGolden(path string, full combine.Export, g *export.Gondolin, limits export.RegressionThresholds) error {
+	var raw json.RawMessage
+	if err := readJSONInput(path, &raw); err != nil {
+		return fmt.Errorf("-golden: %w", err)
+	}
+	kind, err := export.DetectKind(raw)
+	if err != nil {
+		return fmt.Errorf("-golden: %s: %w", path, err)
+	}
+
+	var r export.Regression
+	switch kind {
+	case "gondolin":
+		var golden export.Gondolin
+		if err := json.Unmarshal(raw, &golden); err != nil {
+			return fmt.Errorf("-golden: decode %s: %w", path, err)
+		}
+		if g == nil {
+			reduced := export.ToGondolin(full, export.DefaultOptions())
+			g = &reduced
+		}
+		r = export.CheckRegressionGondolin(golden, *g, limits)
+	default:
+		var golden combine.Export
+		if err := json.Unmarshal(raw, &golden); err != nil {
+			return fmt.Errorf("-golden: decode %s: %w", path, err)
+		}
+		r = export.CheckRegressionFull(golden, full, limits)
+	}
+
+	logger.Info(fmt.Sprintf("Golden: lost %d/%d services (%.1f%%), %d/%d hosts (%.1f%%), %d/%d rules (%.1f%%)",
+		r.Services.Lost, r.Services.Golden, r.Services.Percent,
+		r.Hosts.Lost, r.Hosts.Golden, r.Hosts.Percent,
+		r.Rules.Lost, r.Rules.Golden, r.Rules.Percent),
+		"services", r.Services, "hosts", r.Hosts, "rules", r.Rules)
+	if !r.Failed() {
+		return nil
+	}
+
+	warnLimited("removed service", r.Diff.RemovedServices)
+	var lostHosts []string
+	for _, hc := range r.Diff.HostChanges {
+		for _, h := range hc.Removed {
+			lostHosts = append(lostHosts, hc.Keyword+" → "+h)
+		}
+	}
+	warnLimited("removed host", lostHosts)
+	var lostRules []string
+	for _, rr := range r.Diff.RemovedRules {
+		lostRules = append(lostRules, rr.ID)
+	}
+	warnLimited("removed rule", lostRules)
+	for _, f := range r.Failures {
+		logger.Warn(f, "check", "golden")
+	}
+	return withExitCode(exitValidation, fmt.Errorf("-golden: export regressed against %s: %d thresholds exceeded", path, len(r.Failures)))
+}
+
+// warnLimited logs up to goldenListLimit items as warnings.
+func warnLimited(what string, items []string) {
+	warnEach(len(items), goldenListLimit, func(i int) {
+		logger.Warn(what+": "+items[i], "check", "golden")
+	})
+}
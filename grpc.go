@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"secret-detector-export/pkg/matcher"
+)
+
+// The gRPC API of serve, defined by proto/hogwash/v1/dataset.proto. It is
+// small enough (four unary methods, one server stream, flat messages) that
+// the wire protocol is implemented here over x/net's HTTP/2 instead of
+// pulling grpc-go into the binary. The generated client in proto/hogwash/v1
+// is only used to test interop.
+
+// grpcServicePath prefixes every method path of the Dataset service.
+const grpcServicePath = "/hogwash.v1.Dataset/"
+
+// maxGRPCRequest bounds request messages; the largest is a DetectValue value.
+const maxGRPCRequest = 4 << 20
+
+// grpcChunkSize is the largest StreamDataset chunk, well under the 4 MiB
+// message limit gRPC clients default to.
+const grpcChunkSize = 1 << 20
+
+// gRPC status codes the server answers with.
+const (
+	grpcOK              = 0
+	grpcInvalidArgument = 3
+	grpcResourceLimit   = 8
+	grpcUnimplemented   = 12
+	grpcInternal        = 13
+)
+
+// grpcStatus is an error carrying a gRPC status code.
+type grpcStatus struct {
+	code int
+	msg  string
+}
+
+func (e *grpcStatus) Error() string { return e.msg }
+
+func grpcErrorf(code int, format string, args ...any) error {
+	return &grpcStatus{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// grpcHandler serves the Dataset service over cleartext HTTP/2 (h2c), which
+// is what gRPC clients speak to an insecure channel.
+func (s *datasetServer) grpcHandler() http.Handler {
+	return h2c.NewHandler(http.HandlerFunc(s.serveGRPC), &http2.Server{})
+}
+
+func (s *datasetServer) serveGRPC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests only", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+
+	resp, err := s.callGRPC(r)
+	for _, msg := range resp {
+		if err != nil {
+			break
+		}
+		frame := make([]byte, 5, 5+len(msg))
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+		_, err = w.Write(append(frame, msg...))
+	}
+	code, msg := grpcOK, ""
+	if err != nil {
+		code, msg = grpcInternal, err.Error()
+		var st *grpcStatus
+		if errors.As(err, &st) {
+			code = st.code
+		}
+	}
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	w.Header().Set("Grpc-Message", grpcPercentEncode(msg))
+}
+
+// callGRPC reads the request message and dispatches on the method name. It
+// returns the response messages: one for a unary method, any number for a
+// stream.
+func (s *datasetServer) callGRPC(r *http.Request) ([][]byte, error) {
+	method, ok := strings.CutPrefix(r.URL.Path, grpcServicePath)
+	if !ok {
+		return nil, grpcErrorf(grpcUnimplemented, "unknown service in %s", r.URL.Path)
+	}
+	var header [5]byte
+	if _, err := io.ReadFull(r.Body, header[:]); err != nil {
+		return nil, grpcErrorf(grpcInvalidArgument, "read request: %v", err)
+	}
+	if header[0] != 0 {
+		return nil, grpcErrorf(grpcUnimplemented, "compressed requests are not supported")
+	}
+	n := binary.BigEndian.Uint32(header[1:])
+	if n > maxGRPCRequest {
+		return nil, grpcErrorf(grpcResourceLimit, "request of %d bytes exceeds %d", n, maxGRPCRequest)
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r.Body, body); err != nil {
+		return nil, grpcErrorf(grpcInvalidArgument, "read request: %v", err)
+	}
+	req, err := parseProto(body)
+	if err != nil {
+		return nil, grpcErrorf(grpcInvalidArgument, "decode request: %v", err)
+	}
+
+	var out protoEncoder
+	switch method {
+	case "LookupEnvName":
+		out.lookupResult(queryEnvName(s.g, req.string(1)))
+	case "LookupHost":
+		out.lookupResult(queryHost(s.g, req.string(1)))
+	case "DetectValue":
+		for _, f := range s.matcher.DetectSecrets(req.string(1)) {
+			out.message(1, encodeFinding(f))
+		}
+	case "GetDataset", "StreamDataset":
+		data, hash, err := s.datasetKind(req.varint(1))
+		if err != nil {
+			return nil, err
+		}
+		if method == "GetDataset" {
+			out.bytes(1, data)
+			out.string(2, hash)
+			break
+		}
+		var chunks [][]byte
+		for len(data) > 0 {
+			n := min(len(data), grpcChunkSize)
+			var chunk protoEncoder
+			chunk.bytes(1, data[:n])
+			if len(chunks) == 0 {
+				chunk.string(2, hash)
+			}
+			chunks = append(chunks, chunk.buf)
+			data = data[n:]
+		}
+		return chunks, nil
+	default:
+		return nil, grpcErrorf(grpcUnimplemented, "unknown method %s", method)
+	}
+	return [][]byte{out.buf}, nil
+}
+
+// datasetKind returns the served JSON and content hash for a DatasetKind.
+func (s *datasetServer) datasetKind(kind uint64) ([]byte, string, error) {
+	switch kind {
+	case 0, 1:
+		return s.gondolin, s.g.ContentHash, nil
+	case 2:
+		return s.full, s.fullHash, nil
+	}
+	return nil, "", grpcErrorf(grpcInvalidArgument, "unknown dataset kind %d", kind)
+}
+
+// lookupResult encodes a LookupResult.
+func (e *protoEncoder) lookupResult(r queryResult) {
+	e.string(1, r.Query)
+	e.bool(2, r.ExactName)
+	e.strings(3, r.Keywords)
+	e.strings(4, r.ExactNames)
+	e.strings(5, r.Hosts)
+	e.strings(6, r.PrimaryHosts)
+	e.strings(7, r.Patterns)
+	e.string(8, r.Role)
+	e.string(9, r.Region)
+	e.strings(10, r.PathPrefixes)
+}
+
+func encodeFinding(f matcher.Finding) []byte {
+	var e protoEncoder
+	e.string(1, f.PatternID)
+	e.string(2, f.Keyword)
+	e.string(3, f.Secret)
+	e.varint(4, uint64(f.Start))
+	e.varint(5, uint64(f.End))
+	e.string(6, f.Policy)
+	e.strings(7, f.Hosts)
+	return e.buf
+}
+
+// grpcPercentEncode escapes a grpc-message trailer value as the gRPC HTTP/2
+// protocol requires.
+func grpcPercentEncode(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// protoEncoder appends proto3 fields, skipping default values as proto3
+// does.
+type protoEncoder struct{ buf []byte }
+
+func (e *protoEncoder) tag(field, wire int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wire))
+}
+
+func (e *protoEncoder) varint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.tag(field, 0)
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *protoEncoder) bool(field int, v bool) {
+	if v {
+		e.varint(field, 1)
+	}
+}
+
+func (e *protoEncoder) bytes(field int, v []byte) {
+	if len(v) == 0 {
+		return
+	}
+	e.message(field, v)
+}
+
+func (e *protoEncoder) string(field int, v string) {
+	e.bytes(field, []byte(v))
+}
+
+func (e *protoEncoder) strings(field int, vs []string) {
+	for _, v := range vs {
+		e.message(field, []byte(v))
+	}
+}
+
+// message appends a length-delimited field, even an empty one (an empty
+// element of a repeated message field still counts).
+func (e *protoEncoder) message(field int, v []byte) {
+	e.tag(field, 2)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// protoFields holds the last value of each scalar or bytes field of a
+// decoded message, which is all the request messages need.
+type protoFields map[int]protoValue
+
+type protoValue struct {
+	varint uint64
+	bytes  []byte
+}
+
+func (f protoFields) string(field int) string { return string(f[field].bytes) }
+func (f protoFields) varint(field int) uint64 { return f[field].varint }
+
+// parseProto decodes the fields of a message, skipping fixed-width ones.
+func parseProto(data []byte) (protoFields, error) {
+	fields := make(protoFields)
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("bad field key")
+		}
+		data = data[n:]
+		field, wire := int(key>>3), key&7
+		var v protoValue
+		switch wire {
+		case 0:
+			v.varint, n = binary.Uvarint(data)
+			if n <= 0 {
+				return nil, fmt.Errorf("field %d: bad varint", field)
+			}
+			data = data[n:]
+		case 1, 5:
+			size := 8
+			if wire == 5 {
+				size = 4
+			}
+			if len(data) < size {
+				return nil, fmt.Errorf("field %d: truncated", field)
+			}
+			data = data[size:]
+			continue
+		case 2:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || size > uint64(len(data)-n) {
+				return nil, fmt.Errorf("field %d: truncated", field)
+			}
+			v.bytes = data[n : n+int(size)]
+			data = data[n+int(size):]
+		default:
+			return nil, fmt.Errorf("field %d: unsupported wire type %d", field, wire)
+		}
+		fields[field] = v
+	}
+	return fields, nil
+}
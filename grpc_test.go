@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+	hogwashv1 "secret-detector-export/proto/hogwash/v1"
+)
+
+func TestGRPC(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-access-token", Regex: `sk_live_([a-z0-9]{8})`}}},
+		},
+	}
+	srv, err := newDatasetServer(full.WithContentHash(), export.Options{})
+	if err != nil {
+		t.Fatalf("newDatasetServer: %v", err)
+	}
+	ts := httptest.NewServer(srv.grpcHandler())
+	defer ts.Close()
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+
+	call := func(method string, req []byte) (protoFields, string, string) {
+		t.Helper()
+		frame := make([]byte, 5, 5+len(req))
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(req)))
+		hreq, _ := http.NewRequest(http.MethodPost, ts.URL+grpcServicePath+method, bytes.NewReader(append(frame, req...)))
+		hreq.Header.Set("Content-Type", "application/grpc")
+		resp, err := client.Do(hreq)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("%s: read: %v", method, err)
+		}
+		var fields protoFields
+		if len(body) >= 5 {
+			if fields, err = parseProto(body[5:]); err != nil {
+				t.Fatalf("%s: decode: %v", method, err)
+			}
+		}
+		return fields, resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	}
+	request := func(field int, v string) []byte {
+		var e protoEncoder
+		e.string(field, v)
+		return e.buf
+	}
+
+	got, status, _ := call("LookupEnvName", request(1, "STRIPE_SECRET_KEY"))
+	if status != "0" || got.string(3) != "stripe" || got.string(5) != "api.stripe.com" || got.string(7) != "stripe-access-token" {
+		t.Errorf("LookupEnvName = %v, status %s", got, status)
+	}
+	got, _, _ = call("LookupHost", request(1, "API.stripe.com."))
+	if got.string(1) != "host=api.stripe.com" || got.string(3) != "stripe" {
+		t.Errorf("LookupHost = %v", got)
+	}
+
+	got, _, _ = call("DetectValue", request(1, "key=sk_live_abcd1234"))
+	finding, err := parseProto(got[1].bytes)
+	if err != nil || finding.string(1) != "stripe-access-token" || finding.string(3) != "abcd1234" || finding.varint(4) != 12 {
+		t.Errorf("DetectValue finding = %v (err %v)", finding, err)
+	}
+
+	var kind protoEncoder
+	kind.varint(1, 2)
+	got, _, _ = call("GetDataset", kind.buf)
+	var e combine.Export
+	if err := json.Unmarshal(got[1].bytes, &e); err != nil || got.string(2) != e.ContentHash || len(e.Services) != 1 {
+		t.Errorf("GetDataset(FULL) = %q, hash %q (err %v)", got[1].bytes, got.string(2), err)
+	}
+	got, _, _ = call("GetDataset", nil)
+	if !bytes.Equal(got[1].bytes, srv.gondolin) {
+		t.Errorf("GetDataset(UNSPECIFIED) did not return the gondolin export")
+	}
+
+	if _, status, msg := call("Nope", nil); status != "12" || msg != "unknown method Nope" {
+		t.Errorf("unknown method: status %s, message %q", status, msg)
+	}
+}
+
+// TestGRPCInterop talks to the hand-rolled server with the grpc-go client
+// generated from proto/hogwash/v1/dataset.proto.
+func TestGRPCInterop(t *testing.T) {
+	// Long rule descriptions push the full export past gRPC's 4 MiB default
+	// message limit.
+	full := combine.Export{}
+	for i := 0; i < 50; i++ {
+		keyword := fmt.Sprintf("svc%02d", i)
+		full.Services = append(full.Services, combine.Service{
+			Keyword: keyword,
+			Hosts:   []string{"api." + keyword + ".com"},
+			Rules:   []combine.Rule{{ID: keyword + "-key", Description: strings.Repeat("x", 100<<10), Regex: keyword + `_([a-z]{8})`}},
+		})
+	}
+	srv, err := newDatasetServer(full.WithContentHash(), export.Options{})
+	if err != nil {
+		t.Fatalf("newDatasetServer: %v", err)
+	}
+	if len(srv.full) <= 4<<20 {
+		t.Fatalf("full export is %d bytes, want more than 4 MiB", len(srv.full))
+	}
+	ts := httptest.NewServer(srv.grpcHandler())
+	defer ts.Close()
+	conn, err := grpc.NewClient(strings.TrimPrefix(ts.URL, "http://"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := hogwashv1.NewDatasetClient(conn)
+	ctx := context.Background()
+
+	res, err := client.LookupEnvName(ctx, &hogwashv1.LookupEnvNameRequest{Name: "SVC07_TOKEN"})
+	if err != nil || len(res.Keywords) != 1 || res.Keywords[0] != "svc07" || len(res.Hosts) != 1 || res.Hosts[0] != "api.svc07.com" {
+		t.Errorf("LookupEnvName = %v (err %v)", res, err)
+	}
+	host, err := client.LookupHost(ctx, &hogwashv1.LookupHostRequest{Host: "api.svc03.com"})
+	if err != nil || len(host.Keywords) != 1 || host.Keywords[0] != "svc03" {
+		t.Errorf("LookupHost = %v (err %v)", host, err)
+	}
+	found, err := client.DetectValue(ctx, &hogwashv1.DetectValueRequest{Value: "key=svc01_abcdefgh"})
+	if err != nil || len(found.Findings) != 1 || found.Findings[0].PatternId != "svc01-key" || found.Findings[0].Secret != "abcdefgh" || found.Findings[0].Start != 10 {
+		t.Errorf("DetectValue = %v (err %v)", found, err)
+	}
+
+	// A single message over the client's limit is refused, as documented.
+	if _, err := client.GetDataset(ctx, &hogwashv1.GetDatasetRequest{Kind: hogwashv1.DatasetKind_DATASET_KIND_FULL}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GetDataset(FULL) of %d bytes: err %v, want ResourceExhausted", len(srv.full), err)
+	}
+	got, err := client.GetDataset(ctx, &hogwashv1.GetDatasetRequest{Kind: hogwashv1.DatasetKind_DATASET_KIND_FULL}, grpc.MaxCallRecvMsgSize(64<<20))
+	if err != nil || !bytes.Equal(got.Json, srv.full) || got.ContentHash != srv.fullHash {
+		t.Errorf("GetDataset(FULL) with a raised limit: %d bytes, hash %q (err %v)", len(got.GetJson()), got.GetContentHash(), err)
+	}
+
+	stream, err := client.StreamDataset(ctx, &hogwashv1.GetDatasetRequest{Kind: hogwashv1.DatasetKind_DATASET_KIND_FULL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data []byte
+	var hash string
+	chunks := 0
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("StreamDataset chunk %d: %v", chunks, err)
+		}
+		if chunks == 0 {
+			hash = chunk.ContentHash
+		}
+		data = append(data, chunk.Json...)
+		chunks++
+	}
+	if !bytes.Equal(data, srv.full) || hash != srv.fullHash || chunks < 5 {
+		t.Errorf("StreamDataset: %d bytes in %d chunks, hash %q; want %d bytes, hash %q", len(data), chunks, hash, len(srv.full), srv.fullHash)
+	}
+
+	_, err = client.GetDataset(ctx, &hogwashv1.GetDatasetRequest{Kind: 9})
+	if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument || st.Message() != "unknown dataset kind 9" {
+		t.Errorf("GetDataset(kind 9): %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// Integration tests exercise the whole extract → combine pipeline from the
+// repository root so the testdata fixtures and the TH_ROOT/GL_PATH defaults
+// (sibling checkouts) resolve the same way they do for the CLI.
+
+func TestCombineIntegrationFixtures(t *testing.T) {
+	thRoot := filepath.Join("testdata", "trufflehog", "pkg", "detectors")
+	glPath := filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml")
+
+	thDetectors, skipped, warnings, err := trufflehog.Extract(thRoot, trufflehog.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("trufflehog.Extract: %v", err)
+	}
+	if len(skipped) != 0 {
+		t.Fatalf("unexpected skipped detectors: %v", skipped)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+
+	glRules, err := gitleaks.Extract(glPath)
+	if err != nil {
+		t.Fatalf("gitleaks.Extract: %v", err)
+	}
+
+	full := combine.Combine(thDetectors, glRules)
+	if full.Stats.ServicesWithHosts != 2 {
+		t.Fatalf("ServicesWithHosts = %d, want 2", full.Stats.ServicesWithHosts)
+	}
+	if full.Stats.MatchExact != 1 {
+		t.Fatalf("MatchExact = %d, want 1", full.Stats.MatchExact)
+	}
+	if full.Stats.MatchAlias != 1 {
+		t.Fatalf("MatchAlias = %d, want 1", full.Stats.MatchAlias)
+	}
+
+	for _, svc := range full.Services {
+		if svc.Keyword != "cloudflare" {
+			continue
+		}
+		got := svc.PathPrefixes["api.cloudflare.com"]
+		if len(got) != 1 || got[0] != "/client/v4/" {
+			t.Errorf("cloudflare path_prefixes = %v, want [/client/v4/]", svc.PathPrefixes)
+		}
+	}
+}
+
+// External integration test (opt-in).
+func TestCombineIntegrationExternal(t *testing.T) {
+	if os.Getenv("RUN_EXTERNAL_INTEGRATION") != "1" {
+		t.Skip("set RUN_EXTERNAL_INTEGRATION=1 to run against external trufflehog/gitleaks repos")
+	}
+
+	thRoot := os.Getenv("TH_ROOT")
+	if thRoot == "" {
+		thRoot = "../../trufflehog/pkg/detectors"
+	}
+	glPath := os.Getenv("GL_PATH")
+	if glPath == "" {
+		glPath = "../../gitleaks/config/gitleaks.toml"
+	}
+
+	thDetectors, _, _, err := trufflehog.Extract(thRoot, trufflehog.ExtractOptions{})
+	if err != nil {
+		t.Fatal("TruffleHog detectors not found:", err)
+	}
+	glRules, err := gitleaks.Extract(glPath)
+	if err != nil {
+		t.Fatal("Gitleaks config not found:", err)
+	}
+
+	full := combine.Combine(thDetectors, glRules)
+
+	// Sanity checks on real data
+	if full.Stats.TotalServices < 500 {
+		t.Errorf("TotalServices = %d, expected >= 500", full.Stats.TotalServices)
+	}
+	if full.Stats.ServicesWithHosts < 70 {
+		t.Errorf("ServicesWithHosts = %d, expected >= 70", full.Stats.ServicesWithHosts)
+	}
+	if full.Stats.TotalRules < 200 {
+		t.Errorf("TotalRules = %d, expected >= 200", full.Stats.TotalRules)
+	}
+	if full.Stats.RulesWithHosts < 130 {
+		t.Errorf("RulesWithHosts = %d, expected >= 130", full.Stats.RulesWithHosts)
+	}
+
+	// Check specific high-profile services have hosts
+	for _, err := range export.AuditFull(full, export.DefaultAuditRequirements()) {
+		t.Error(err)
+	}
+
+	// Verify no garbage hosts (all must be valid DNS names with dots)
+	for _, svc := range full.Services {
+		for _, h := range svc.Hosts {
+			if !trufflehog.IsValidHostname(h) {
+				t.Errorf("service %q has invalid host %q", svc.Keyword, h)
+			}
+		}
+	}
+	for _, th := range full.THOnlyHosts {
+		for _, h := range th.Hosts {
+			if !trufflehog.IsValidHostname(h) {
+				t.Errorf("TH-only %q has invalid host %q", th.Keyword, h)
+			}
+		}
+	}
+
+	// Verify all GL regex patterns are non-empty
+	for _, svc := range full.Services {
+		for _, r := range svc.Rules {
+			if r.Regex == "" {
+				t.Errorf("service %q rule %q has empty regex", svc.Keyword, r.ID)
+			}
+		}
+	}
+
+	// Check no duplicate services
+	seen := make(map[string]bool)
+	for _, svc := range full.Services {
+		if seen[svc.Keyword] {
+			t.Errorf("duplicate service keyword %q", svc.Keyword)
+		}
+		seen[svc.Keyword] = true
+	}
+}
+
+// External coverage test (opt-in).
+func TestTHKeywordDerivationCoverageExternal(t *testing.T) {
+	if os.Getenv("RUN_EXTERNAL_INTEGRATION") != "1" {
+		t.Skip("set RUN_EXTERNAL_INTEGRATION=1 to run against external trufflehog repo")
+	}
+
+	thRoot := os.Getenv("TH_ROOT")
+	if thRoot == "" {
+		thRoot = "../../trufflehog/pkg/detectors"
+	}
+
+	thDetectors, _, _, err := trufflehog.Extract(thRoot, trufflehog.ExtractOptions{})
+	if err != nil {
+		t.Fatal("TruffleHog detectors not found:", err)
+	}
+
+	// Count how many unique keywords we get
+	keywords := make(map[string][]string)
+	for _, d := range thDetectors {
+		if d.Keyword == "" {
+			t.Errorf("empty keyword for dir %q", d.DirName)
+			continue
+		}
+		keywords[d.Keyword] = append(keywords[d.Keyword], d.DirName)
+	}
+
+	t.Logf("TH detectors: %d, unique keywords: %d", len(thDetectors), len(keywords))
+
+	// Keywords should consolidate detectors (fewer keywords than detectors)
+	if len(keywords) >= len(thDetectors) {
+		t.Errorf("keyword derivation didn't consolidate: %d keywords for %d detectors",
+			len(keywords), len(thDetectors))
+	}
+
+	// Check some known consolidations
+	for keyword, dirs := range keywords {
+		if keyword == "cloudflare" && len(dirs) < 2 {
+			t.Errorf("cloudflare should consolidate multiple dirs, got %v", dirs)
+		}
+		if keyword == "discord" && len(dirs) < 2 {
+			t.Errorf("discord should consolidate multiple dirs, got %v", dirs)
+		}
+	}
+}
+
+func TestExtractorIntegration(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script extractor")
+	}
+	cfg := exportConfig{
+		GLPath:         filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml"),
+		Extractors:     []string{filepath.Join("testdata", "extractor", "acme.sh")},
+		Mode:           "full",
+		PublicSuffixes: "reject",
+	}
+	out, err := buildExport(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("buildExport: %v", err)
+	}
+	var acme *combine.Service
+	for i, svc := range out.full.Services {
+		if svc.Keyword == "acmepay" {
+			acme = &out.full.Services[i]
+		}
+	}
+	if acme == nil || len(acme.Hosts) != 1 || acme.Hosts[0] != "api.acmepay.example" || len(acme.Rules) != 1 {
+		t.Fatalf("acmepay service = %+v", acme)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/samples"
+)
+
+// runLint implements `hogwash lint [flags] <export.json>`.
+func runLint(args []string) error {
+	defaults := export.DefaultLintOptions()
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	minKeywordLen := fs.Int("min-keyword-len", defaults.MinKeywordLen, "Flag keywords shorter than this")
+	maxServices := fs.Int("max-services-per-host", defaults.MaxServicesPerHost, "Flag hosts mapped from more services than this")
+	entropySamples := fs.Int("entropy-samples", 100, "Full exports: generate this many matches per rule with an entropy threshold and flag rules none of them pass (0: skip)")
+	strict := fs.Bool("strict", false, "Exit non-zero if any issue is found (for release gates)")
+	asJSON := fs.Bool("json", false, "Write issues as JSON to stdout")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s lint [flags] <export.json | ->\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *entropySamples < 0 {
+		return withExitCode(exitUsage, fmt.Errorf("lint: invalid -entropy-samples %d: must be >= 0", *entropySamples))
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("lint: expected exactly one input file, got %d", fs.NArg()))
+	}
+	path := fs.Arg(0)
+	opts := export.LintOptions{MinKeywordLen: *minKeywordLen, MaxServicesPerHost: *maxServices}
+
+	var raw json.RawMessage
+	if err := readJSONInput(path, &raw); err != nil {
+		return fmt.Errorf("lint: %w", err)
+	}
+	kind, err := export.DetectKind(raw)
+	if err != nil {
+		return fmt.Errorf("lint: %s: %w", path, err)
+	}
+
+	var issues []export.LintIssue
+	switch kind {
+	case "gondolin":
+		var g export.Gondolin
+		if err := json.Unmarshal(raw, &g); err != nil {
+			return fmt.Errorf("lint: decode %s: %w", path, err)
+		}
+		issues = export.LintGondolin(g, opts)
+	default:
+		var e combine.Export
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return fmt.Errorf("lint: decode %s: %w", path, err)
+		}
+		issues = export.LintFull(e, opts)
+		if *entropySamples > 0 {
+			issues = append(issues, entropyIssues(e, *entropySamples)...)
+			sort.SliceStable(issues, func(i, j int) bool {
+				a, b := issues[i], issues[j]
+				return a.Check < b.Check || a.Check == b.Check && a.Subject < b.Subject
+			})
+		}
+	}
+
+	if *asJSON {
+		if issues == nil {
+			issues = []export.LintIssue{}
+		}
+		if err := export.EncodeJSON(os.Stdout, issues, false); err != nil {
+			return err
+		}
+	} else {
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stderr, "  - %s\n", issue)
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.Check]++
+	}
+	summary := fmt.Sprintf("lint: %s: %d issues", path, len(issues))
+	for _, check := range sortedKeys(counts) {
+		summary += fmt.Sprintf(", %s=%d", check, counts[check])
+	}
+	if *strict && len(issues) > 0 {
+		return withExitCode(exitValidation, fmt.Errorf("%s (strict)", summary))
+	}
+	fmt.Fprintln(os.Stderr, summary)
+	return nil
+}
+
+// entropyIssues flags rules whose entropy threshold makes them effectively
+// dead: no secret can exceed it, or none of n generated matches does.
+// Rules the generator can't satisfy are skipped.
+func entropyIssues(e combine.Export, n int) []export.LintIssue {
+	var issues []export.LintIssue
+	for _, svc := range e.Services {
+		for _, r := range svc.Rules {
+			res, err := samples.CheckEntropy(r, n)
+			if err != nil {
+				continue
+			}
+			var msg string
+			switch {
+			case res.Dead():
+				msg = fmt.Sprintf("entropy threshold %.2f can never be exceeded: secrets reach at most %.2f bits", res.Threshold, res.Bound)
+			case res.Suspect():
+				msg = fmt.Sprintf("none of %d generated matches exceeds entropy threshold %.2f (highest %.2f); the rule may never fire", res.Samples, res.Threshold, res.MaxSeen)
+			default:
+				continue
+			}
+			issues = append(issues, export.LintIssue{Check: export.LintDeadEntropy, Subject: r.ID, Message: msg})
+		}
+	}
+	return issues
+}
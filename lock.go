@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errLocked is returned by tryLock when another process holds the lock.
+var errLocked = errors.New("locked")
+
+// lockPollInterval is how often a waiting run retries the lock.
+const lockPollInterval = 100 * time.Millisecond
+
+// lockOutput takes an advisory lock on <outPath>.lock so concurrent runs
+// targeting the same -out don't interleave their writes. With wait 0 it
+// fails fast; otherwise it retries until wait elapses or ctx is done. The
+// returned func releases the lock.
+func lockOutput(ctx context.Context, outPath string, wait time.Duration) (func(), error) {
+	path := outPath + ".lock"
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		err := tryLock(f)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLocked) {
+			_ = f.Close()
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if !time.Now().Before(deadline) {
+			holder := lockHolder(path)
+			_ = f.Close()
+			if wait == 0 {
+				return nil, fmt.Errorf("%s is being written by another run%s; pass -lock-wait to wait for it", outPath, holder)
+			}
+			return nil, fmt.Errorf("%s is still being written by another run%s after waiting %s", outPath, holder, wait)
+		}
+		select {
+		case <-ctx.Done():
+			_ = f.Close()
+			return nil, checkCanceled(ctx)
+		case <-time.After(lockPollInterval):
+		}
+	}
+
+	// Record the holder for the error message of a run that finds it locked.
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return func() {
+		_ = unlock(f)
+		_ = f.Close()
+	}, nil
+}
+
+// lockHolder describes the PID recorded in the lock file, if any.
+func lockHolder(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	if pid := strings.TrimSpace(string(data)); pid != "" {
+		return " (pid " + pid + ")"
+	}
+	return ""
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// tryLock is a no-op where flock is unavailable; concurrent runs are not
+// protected there.
+func tryLock(*os.File) error { return nil }
+
+func unlock(*os.File) error { return nil }
@@ -0,0 +1,31 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockOutput(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "secret-mapping.json")
+	ctx := context.Background()
+
+	release, err := lockOutput(ctx, out, 0)
+	if err != nil {
+		t.Fatalf("first lock: %v", err)
+	}
+	if _, err := lockOutput(ctx, out, 0); err == nil || !strings.Contains(err.Error(), "being written by another run (pid") {
+		t.Fatalf("second lock = %v, want fail-fast error naming the holder", err)
+	}
+
+	time.AfterFunc(150*time.Millisecond, release)
+	release2, err := lockOutput(ctx, out, 5*time.Second)
+	if err != nil {
+		t.Fatalf("waiting lock: %v", err)
+	}
+	release2()
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/pipeline"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// logLevel is the minimum level logged: Info by default, Debug with -v
+// (details such as skipped detectors), Warn with -q.
+var logLevel = new(slog.LevelVar)
+
+// logger carries the export pipeline's stderr diagnostics. Messages are the
+// human-readable lines; attributes carry the same facts for -log-format json.
+var logger = slog.New(newTextHandler(os.Stderr, logLevel))
+
+// jsonLogs reports whether -log-format json is active, for the few places
+// that render differently in text mode.
+var jsonLogs bool
+
+// showAllWarnings disables the per-list cap of warnEach (-show-all-warnings).
+var showAllWarnings bool
+
+// setLogFormat switches logger to "text" or "json" (JSON lines with time,
+// level, msg and attributes).
+func setLogFormat(format string) {
+	jsonLogs = format == "json"
+	if jsonLogs {
+		logger = slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+	} else {
+		logger = slog.New(newTextHandler(os.Stderr, logLevel))
+	}
+}
+
+// startLogging applies -log-format, -v/-q and -show-all-warnings and
+// reports the config file in use.
+func (cfg exportConfig) startLogging() {
+	switch {
+	case cfg.Verbose:
+		logLevel.Set(slog.LevelDebug)
+	case cfg.Quiet:
+		logLevel.Set(slog.LevelWarn)
+	default:
+		logLevel.Set(slog.LevelInfo)
+	}
+	showAllWarnings = cfg.ShowAllWarnings
+	setLogFormat(cfg.LogFormat)
+	if cfg.ConfigPath != "" {
+		logger.Info("Config: "+cfg.ConfigPath, "config", cfg.ConfigPath)
+	}
+}
+
+// textHandler prints each record's message as a line and drops attributes,
+// which keeps the historic stderr layout: warnings are list items under the
+// preceding line, debug details are indented with "·", and errors are
+// prefixed "error:".
+type textHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+}
+
+func newTextHandler(w io.Writer, level slog.Leveler) *textHandler {
+	return &textHandler{mu: new(sync.Mutex), w: w, level: level}
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	prefix := ""
+	switch {
+	case r.Level >= slog.LevelError:
+		prefix = "error: "
+	case r.Level >= slog.LevelWarn:
+		prefix = "  - "
+	case r.Level < slog.LevelInfo:
+		prefix = "  · "
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintln(h.w, prefix+r.Message)
+	return err
+}
+
+func (h *textHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *textHandler) WithGroup(string) slog.Handler      { return h }
+
+// warnEach calls warn for the first limit of n items and summarizes the
+// rest. JSON logs and -show-all-warnings get every item.
+func warnEach(n, limit int, warn func(i int)) {
+	for i := 0; i < n; i++ {
+		if i == limit && !jsonLogs && !showAllWarnings {
+			logger.Warn(fmt.Sprintf("… and %d more", n-i))
+			return
+		}
+		warn(i)
+	}
+}
+
+// logSource reports what one pipeline source contributed. TruffleHog's
+// warnings are capped like other warning lists; an extractor's are not.
+func logSource(r pipeline.SourceReport) {
+	switch r.Source {
+	case "trufflehog":
+		if len(r.Skipped) > 0 {
+			reasons := make(map[string]int)
+			for _, s := range r.Skipped {
+				reasons[s.Reason]++
+			}
+			logger.Info(fmt.Sprintf("TruffleHog: skipped %d detectors (parse errors: %d, no URLs: %d, all noise: %d)", len(r.Skipped),
+				reasons[trufflehog.SkipParseError], reasons[trufflehog.SkipNoURLs], reasons[trufflehog.SkipAllNoise]),
+				"source", r.Source, "skipped", len(r.Skipped), "reasons", reasons)
+			for _, s := range r.Skipped {
+				logger.Debug("skipped "+s.String(), "source", r.Source, "dir", s.DirName, "reason", s.Reason)
+			}
+		}
+		if len(r.Warnings) > 0 {
+			shown := "showing up to 5"
+			if showAllWarnings || jsonLogs {
+				shown = "showing all"
+			}
+			logger.Info(fmt.Sprintf("TruffleHog: %d warnings (%s):", len(r.Warnings), shown), "source", r.Source, "warnings", len(r.Warnings))
+			warnEach(len(r.Warnings), 5, func(i int) {
+				logger.Warn(r.Warnings[i].Error(), "source", r.Source)
+			})
+		}
+		logger.Info(fmt.Sprintf("TruffleHog: extracted %d detectors with hosts", r.Detectors), "source", r.Source, "detectors", r.Detectors)
+	case "gitleaks":
+		logger.Info(fmt.Sprintf("Gitleaks: extracted %d rules", r.Rules), "source", r.Source, "rules", r.Rules)
+	default:
+		for _, w := range r.Warnings {
+			logger.Warn(w.Error(), "source", r.Source)
+		}
+		logger.Info(fmt.Sprintf("%s: extracted %d detectors, %d rules", r.Source, r.Detectors, r.Rules), "source", r.Source, "detectors", r.Detectors, "rules", r.Rules)
+	}
+}
+
+// logGondolinStats reports the gondolin reduction: a block in text mode,
+// one record in JSON mode.
+func logGondolinStats(st GondolinModeStats) {
+	if jsonLogs {
+		logger.Info("gondolin export",
+			"keyword_host_mappings", st.KeywordHostMappings,
+			"exact_name_mappings", st.ExactNameMappings,
+			"value_patterns", st.ValuePatterns,
+			"linked_patterns", st.LinkedPatterns,
+			"excluded_patterns", st.ExcludedPatterns,
+			"categories", st.Categories)
+		return
+	}
+	logger.Info("\n=== Gondolin Export ===")
+	logger.Info(fmt.Sprintf("Keyword→host mappings: %d", st.KeywordHostMappings))
+	logger.Info(fmt.Sprintf("Exact-name mappings:   %d", st.ExactNameMappings))
+	logger.Info(fmt.Sprintf("Value patterns:        %d (with host linkage: %d)", st.ValuePatterns, st.LinkedPatterns))
+	logger.Info(fmt.Sprintf("Excluded patterns:     %d", st.ExcludedPatterns))
+	if len(st.Categories) > 0 {
+		logger.Info("Categories:            " + strings.Join(st.Categories, ", "))
+	}
+}
+
+// logSummary reports the combined stats at the end of a run.
+func logSummary(s combine.Stats) {
+	if jsonLogs {
+		logger.Info("summary",
+			"total_services", s.TotalServices,
+			"services_with_hosts", s.ServicesWithHosts,
+			"match_exact", s.MatchExact,
+			"match_prefix", s.MatchPrefix,
+			"match_alias", s.MatchAlias,
+			"match_curated", s.MatchCurated,
+			"services_no_hosts", s.ServicesNoHosts,
+			"th_only_services", s.THOnlyServices,
+			"total_rules", s.TotalRules,
+			"rules_with_hosts", s.RulesWithHosts)
+		return
+	}
+	logger.Info("\n=== Summary ===")
+	logger.Info(fmt.Sprintf("Total services:       %d", s.TotalServices))
+	logger.Info(fmt.Sprintf("  With hosts+rules:   %d (exact:%d prefix:%d alias:%d curated:%d)", s.ServicesWithHosts, s.MatchExact, s.MatchPrefix, s.MatchAlias, s.MatchCurated))
+	logger.Info(fmt.Sprintf("  Rules only (no host):%d", s.ServicesNoHosts))
+	logger.Info(fmt.Sprintf("  Hosts only (no rule):%d", s.THOnlyServices))
+	logger.Info(fmt.Sprintf("Total GL rules:       %d (%d with hosts)", s.TotalRules, s.RulesWithHosts))
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestTextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(newTextHandler(&buf, slog.LevelInfo))
+	l.Debug("hidden")
+	l.Info("TruffleHog: 2 warnings (showing up to 5):", "warnings", 2)
+	l.Warn("bad host", "source", "trufflehog")
+	l.Error("boom")
+
+	want := "TruffleHog: 2 warnings (showing up to 5):\n  - bad host\nerror: boom\n"
+	if got := buf.String(); got != want {
+		t.Errorf("text output = %q, want %q", got, want)
+	}
+}
+
+func TestTextHandlerVerbose(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(newTextHandler(&buf, slog.LevelDebug))
+	l.Info("TruffleHog: skipped 1 detectors")
+	l.Debug("skipped foo: no Go files")
+
+	want := "TruffleHog: skipped 1 detectors\n  · skipped foo: no Go files\n"
+	if got := buf.String(); got != want {
+		t.Errorf("text output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/provenance"
+)
+
+// runManifest is the sidecar written by -manifest: what a run read, how it
+// was configured, and how long each stage took, so an old dataset can be
+// regenerated exactly.
+type runManifest struct {
+	Generator  combine.Generator `json:"generator"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt time.Time         `json:"finished_at"`
+	WorkDir    string            `json:"work_dir"`
+	Inputs     []manifestInput   `json:"inputs"`
+	Flags      map[string]string `json:"flags"` // every flag's effective value, defaults included
+	Stages     []stageTiming     `json:"stages"`
+	Outputs    []manifestOutput  `json:"outputs"`
+}
+
+// manifestInput is a file or directory the run read, named by the flag that
+// supplied it.
+type manifestInput struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`             // absolute
+	Commit string `json:"commit,omitempty"` // HEAD of the enclosing git checkout
+	Remote string `json:"remote,omitempty"` // that checkout's origin URL
+	SHA256 string `json:"sha256,omitempty"` // files outside a git checkout
+}
+
+// stageTiming is how long one pipeline stage took. Stages that didn't run
+// are not listed.
+type stageTiming struct {
+	Name    string  `json:"name"`
+	Seconds float64 `json:"seconds"`
+}
+
+type manifestOutput struct {
+	Mode        string `json:"mode"`
+	Path        string `json:"path"` // absolute, or - for stdout
+	ContentHash string `json:"content_hash"`
+}
+
+// stageClock times consecutive pipeline stages: each lap closes the stage
+// that started at the previous one.
+type stageClock struct {
+	mark   time.Time
+	stages []stageTiming
+}
+
+func newStageClock() *stageClock {
+	return &stageClock{mark: time.Now()}
+}
+
+func (c *stageClock) lap(name string) {
+	now := time.Now()
+	c.stages = append(c.stages, stageTiming{Name: name, Seconds: now.Sub(c.mark).Seconds()})
+	c.mark = now
+}
+
+// recordFlags notes the flags of fs after parsing and -config: the ones
+// explicitly set, for provenance, and every effective value, for -manifest.
+// The -sign-key path is left out of the latter, as it is of provenance.
+func (cfg *exportConfig) recordFlags(fs *flag.FlagSet) {
+	cfg.Flags = make(map[string]string)
+	fs.Visit(func(f *flag.Flag) { cfg.Flags[f.Name] = f.Value.String() })
+	cfg.EffectiveFlags = make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) { cfg.EffectiveFlags[f.Name] = f.Value.String() })
+	delete(cfg.EffectiveFlags, "sign-key")
+}
+
+// writeManifest writes the -manifest sidecar for a run whose outputs were
+// all written.
+func writeManifest(cfg exportConfig, out exportOutput, started time.Time, stages []stageTiming) error {
+	m := runManifest{
+		Generator:  generatorInfo(),
+		StartedAt:  started.UTC(),
+		FinishedAt: time.Now().UTC(),
+		Inputs:     []manifestInput{},
+		Flags:      cfg.EffectiveFlags,
+		Stages:     stages,
+	}
+	m.WorkDir, _ = os.Getwd()
+	for _, in := range []struct{ name, path string }{
+		{"config", cfg.ConfigPath},
+		{"trufflehog", cfg.THDir},
+		{"gitleaks", cfg.GLPath},
+		{"from-full", cfg.FromFull},
+		{"previous", cfg.Previous},
+		{"pattern-denylist", cfg.PatternDenylist},
+		{"popularity", cfg.Popularity},
+		{"audit-list", cfg.AuditList},
+		{"golden", cfg.Golden},
+	} {
+		if in.path != "" {
+			m.Inputs = append(m.Inputs, resolveManifestInput(in.name, in.path))
+		}
+	}
+	for _, o := range cfg.outputs() {
+		_, hash := out.render(o.Mode)
+		path := o.Path
+		if path != "-" {
+			path = absPath(path)
+		}
+		m.Outputs = append(m.Outputs, manifestOutput{Mode: o.Mode, Path: path, ContentHash: hash})
+	}
+	return writeJSONAtomic(cfg.Manifest, true, cfg.SyncDir, false, m)
+}
+
+// resolveManifestInput identifies path by the git commit of its checkout,
+// or, for a file outside one, by its sha256. Inputs were all read by the
+// run, so a failure here only leaves the identity out.
+func resolveManifestInput(name, path string) manifestInput {
+	in := manifestInput{Name: name, Path: absPath(path)}
+	if commit, remote, err := provenance.GitCommit(path); err == nil {
+		in.Commit, in.Remote = commit, remote
+		return in
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		sum := sha256.Sum256(data)
+		in.SHA256 = hex.EncodeToString(sum[:])
+	}
+	return in
+}
+
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return p
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	var cfg exportConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.registerFlags(fs)
+	err := fs.Parse([]string{
+		"-trufflehog", filepath.Join("testdata", "trufflehog", "pkg", "detectors"),
+		"-gitleaks", filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml"),
+		"-out", filepath.Join(dir, "full.json"),
+		"-manifest", filepath.Join(dir, "manifest.json"),
+		"-q",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.recordFlags(fs)
+	out, err := exportOnce(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(cfg.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m runManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Inputs) != 2 {
+		t.Fatalf("inputs = %+v, want trufflehog and gitleaks", m.Inputs)
+	}
+	for _, in := range m.Inputs {
+		if !filepath.IsAbs(in.Path) {
+			t.Errorf("input %s: path %q is not absolute", in.Name, in.Path)
+		}
+		if in.Commit == "" && in.SHA256 == "" {
+			t.Errorf("input %s: neither commit nor sha256", in.Name)
+		}
+	}
+	if m.Flags["mode"] != "full" || m.Flags["public-suffix-hosts"] != "reject" || m.Flags["q"] != "true" {
+		t.Errorf("flags = %v, want defaults and explicitly set values", m.Flags)
+	}
+	stages := make(map[string]bool)
+	for _, s := range m.Stages {
+		stages[s.Name] = true
+	}
+	for _, name := range []string{"extract trufflehog", "extract gitleaks", "combine", "write"} {
+		if !stages[name] {
+			t.Errorf("stages = %+v, missing %q", m.Stages, name)
+		}
+	}
+	if len(m.Outputs) != 1 || m.Outputs[0].ContentHash != out.full.ContentHash || !filepath.IsAbs(m.Outputs[0].Path) {
+		t.Errorf("outputs = %+v", m.Outputs)
+	}
+	if m.Generator.Version == "" || m.StartedAt.After(m.FinishedAt) {
+		t.Errorf("generator %+v, started %s, finished %s", m.Generator, m.StartedAt, m.FinishedAt)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// runMerge implements `hogwash merge [flags] <a.json> <b.json>`.
+func runMerge(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	var outPath string
+	fs.StringVar(&outPath, "out", "-", "Output file path (or - for stdout)")
+	fs.StringVar(&outPath, "o", "-", "Shorthand for -out")
+	strategy := fs.String("strategy", combine.MergePreferFirst, "Conflict resolution: prefer-first, prefer-newer, or union-hosts")
+	force := fs.Bool("force", false, "Overwrite -out if it already exists")
+	compact := fs.Bool("compact", false, "Write minified JSON and drop empty optional fields")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s merge [flags] <a.json> <b.json>\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("merge: expected exactly two full exports, got %d", fs.NArg()))
+	}
+	if !combine.IsValidMergeStrategy(*strategy) {
+		return fmt.Errorf("merge: invalid -strategy %q: must be prefer-first, prefer-newer, or union-hosts", *strategy)
+	}
+
+	var exports [2]combine.Export
+	for i, path := range fs.Args() {
+		var raw json.RawMessage
+		if err := readJSONInput(path, &raw); err != nil {
+			return fmt.Errorf("merge: %w", err)
+		}
+		if kind, err := export.DetectKind(raw); err != nil || kind != "full" {
+			return fmt.Errorf("merge: %s: not a full export (gondolin exports can't be merged; merge their full exports instead)", path)
+		}
+		if err := json.Unmarshal(raw, &exports[i]); err != nil {
+			return fmt.Errorf("merge: decode %s: %w", path, err)
+		}
+	}
+	merged, err := combine.Merge(exports[0], exports[1], *strategy)
+	if err != nil {
+		return fmt.Errorf("merge: %w", err)
+	}
+
+	var output any = merged
+	if *compact {
+		if output, err = export.PruneEmptyJSON(merged); err != nil {
+			return fmt.Errorf("merge: compact output: %w", err)
+		}
+	}
+	if err := writeJSONOutput(outPath, *force, false, *compact, output); err != nil {
+		return fmt.Errorf("merge: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "merge: %d + %d services → %d (%s)\n", len(exports[0].Services), len(exports[1].Services), len(merged.Services), *strategy)
+	return nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/pipeline"
+)
+
+// sourceError tags an extraction failure with the input it came from
+// (trufflehog, gitleaks, extractor, or from-full), for the per-source error
+// counter.
+type sourceError = pipeline.SourceError
+
+// datasetSizes are the dataset gauges exposed on /metrics. A negative
+// valuePatterns means no gondolin export was built.
+type datasetSizes struct {
+	services      int
+	hosts         int
+	rules         int
+	valuePatterns int
+}
+
+func sizesOf(full combine.Export, g *export.Gondolin) datasetSizes {
+	s := datasetSizes{services: len(full.Services) + len(full.THOnlyHosts), valuePatterns: -1}
+	hosts := make(map[string]bool)
+	for _, svc := range full.Services {
+		s.rules += len(svc.Rules)
+		for _, h := range svc.AllowedHosts() {
+			hosts[h] = true
+		}
+	}
+	for _, th := range full.THOnlyHosts {
+		for _, h := range th.Hosts {
+			hosts[h] = true
+		}
+	}
+	s.hosts = len(hosts)
+	if g != nil {
+		s.valuePatterns = len(g.ValuePatterns)
+	}
+	return s
+}
+
+// watchMetrics tracks the regenerations of -watch for -metrics-addr. It is
+// safe for concurrent use.
+type watchMetrics struct {
+	mu               sync.Mutex
+	sizes            *datasetSizes // nil until the first successful run
+	lastGeneration   time.Time
+	lastDuration     time.Duration
+	thSkipped        int
+	thWarnings       int
+	generations      map[string]int // "success" or "failure" → count
+	extractionErrors map[string]int // source → count
+}
+
+func newWatchMetrics() *watchMetrics {
+	return &watchMetrics{
+		generations: map[string]int{"success": 0, "failure": 0},
+		// Sources start at zero so a rate() over the counter works before
+		// the first failure.
+		extractionErrors: map[string]int{"trufflehog": 0, "gitleaks": 0, "from-full": 0},
+	}
+}
+
+// observe records one regeneration that started at started and ended with
+// out and err.
+func (m *watchMetrics) observe(started time.Time, out exportOutput, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.generations["failure"]++
+		var se *sourceError
+		if errors.As(err, &se) {
+			m.extractionErrors[se.Source]++
+		}
+		return
+	}
+	m.generations["success"]++
+	sizes := sizesOf(out.full, out.gondolin)
+	m.sizes = &sizes
+	m.lastGeneration = time.Now()
+	m.lastDuration = m.lastGeneration.Sub(started)
+	m.thSkipped, m.thWarnings = out.thSkipped, out.thWarnings
+}
+
+func (m *watchMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if m.sizes != nil {
+		writeDatasetMetrics(w, *m.sizes, m.lastGeneration)
+		writeMetric(w, "hogwash_generation_duration_seconds", "gauge", "Duration of the last successful regeneration.",
+			metricSample{value: m.lastDuration.Seconds()})
+		writeMetric(w, "hogwash_trufflehog_skipped_detectors", "gauge", "TruffleHog detectors skipped by the last successful regeneration.",
+			metricSample{value: float64(m.thSkipped)})
+		writeMetric(w, "hogwash_trufflehog_warnings", "gauge", "TruffleHog extraction warnings of the last successful regeneration.",
+			metricSample{value: float64(m.thWarnings)})
+	}
+	writeMetric(w, "hogwash_generations_total", "counter", "Regenerations by result.",
+		labeledSamples("result", m.generations)...)
+	writeMetric(w, "hogwash_extraction_errors_total", "counter", "Regenerations that failed to read or parse an input, by source.",
+		labeledSamples("source", m.extractionErrors)...)
+}
+
+// writeDatasetMetrics writes the gauges serve and -watch have in common.
+func writeDatasetMetrics(w io.Writer, s datasetSizes, generated time.Time) {
+	writeMetric(w, "hogwash_dataset_services", "gauge", "Services in the dataset.", metricSample{value: float64(s.services)})
+	writeMetric(w, "hogwash_dataset_hosts", "gauge", "Distinct allowed hosts in the dataset.", metricSample{value: float64(s.hosts)})
+	writeMetric(w, "hogwash_dataset_rules", "gauge", "Gitleaks rules in the dataset.", metricSample{value: float64(s.rules)})
+	if s.valuePatterns >= 0 {
+		writeMetric(w, "hogwash_dataset_value_patterns", "gauge", "Value patterns in the gondolin export.", metricSample{value: float64(s.valuePatterns)})
+	}
+	if !generated.IsZero() {
+		writeMetric(w, "hogwash_last_generation_timestamp_seconds", "gauge", "Unix time the dataset was generated.",
+			metricSample{value: float64(generated.UnixNano()) / 1e9})
+	}
+}
+
+// metricSample is one line of a metric family in the Prometheus text
+// format; labels is the already formatted {…} part, if any.
+type metricSample struct {
+	labels string
+	value  float64
+}
+
+func writeMetric(w io.Writer, name, typ, help string, samples ...metricSample) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	for _, s := range samples {
+		fmt.Fprintf(w, "%s%s %s\n", name, s.labels, strconv.FormatFloat(s.value, 'g', -1, 64))
+	}
+}
+
+// labeledSamples turns a count per label value into samples, sorted by
+// label value.
+func labeledSamples(label string, counts map[string]int) []metricSample {
+	samples := make([]metricSample, 0, len(counts))
+	for _, v := range sortedKeys(counts) {
+		samples = append(samples, metricSample{labels: fmt.Sprintf("{%s=%q}", label, v), value: float64(counts[v])})
+	}
+	return samples
+}
+
+// serveMetrics starts the -metrics-addr listener for -watch. Listener
+// errors are logged; they don't stop the watch.
+func serveMetrics(addr string, m *watchMetrics) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", m)
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	logger.Info("watch: metrics on http://"+addr+"/metrics", "addr", addr)
+	go func() {
+		if err := server.ListenAndServe(); err != nil {
+			logger.Error("watch: metrics: " + err.Error())
+		}
+	}()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+func TestWatchMetrics(t *testing.T) {
+	m := newWatchMetrics()
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return rec.Body.String()
+	}
+	if body := scrape(); strings.Contains(body, "hogwash_dataset_services") || !strings.Contains(body, `hogwash_extraction_errors_total{source="gitleaks"} 0`) {
+		t.Errorf("before the first run:\n%s", body)
+	}
+
+	full := combine.Export{
+		Services: []combine.Service{{Keyword: "stripe", Hosts: []string{"api.stripe.com", "files.stripe.com"}, Rules: make([]combine.Rule, 3)}},
+	}
+	m.observe(time.Now().Add(-2*time.Second), exportOutput{full: full, gondolin: &export.Gondolin{ValuePatterns: make([]export.ValuePattern, 2)}, thSkipped: 4}, nil)
+	extractErr := withExitCode(exitExtraction, &sourceError{Source: "gitleaks", Err: errors.New("bad toml")})
+	m.observe(time.Now(), exportOutput{}, fmt.Errorf("wrapped: %w", extractErr))
+
+	body := scrape()
+	for _, want := range []string{
+		"# TYPE hogwash_dataset_services gauge\nhogwash_dataset_services 1\n",
+		"hogwash_dataset_hosts 2\n",
+		"hogwash_dataset_rules 3\n",
+		"hogwash_dataset_value_patterns 2\n",
+		"hogwash_trufflehog_skipped_detectors 4\n",
+		"hogwash_last_generation_timestamp_seconds ",
+		`hogwash_generations_total{result="failure"} 1`,
+		`hogwash_generations_total{result="success"} 1`,
+		`hogwash_extraction_errors_total{source="gitleaks"} 1`,
+		`hogwash_extraction_errors_total{source="trufflehog"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+	if !strings.Contains(body, "hogwash_generation_duration_seconds 2") {
+		t.Errorf("duration of the successful run not reported:\n%s", body)
+	}
+}
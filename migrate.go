@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"secret-detector-export/pkg/export"
+)
+
+// runMigrate implements `hogwash migrate [flags] <gondolin.json>`.
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	to := fs.Int("to", export.SchemaVersion, "Target gondolin schema_version")
+	outPath := fs.String("out", "-", "Output file path (or - for stdout)")
+	force := fs.Bool("force", false, "Overwrite -out if it already exists")
+	compact := fs.Bool("compact", false, "Write minified JSON and drop empty optional fields")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s migrate [flags] <gondolin.json | ->\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("migrate: expected exactly one input file, got %d", fs.NArg()))
+	}
+
+	var g export.Gondolin
+	if err := readJSONInput(fs.Arg(0), &g); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	from := g.SchemaVersion
+
+	migrated, notes, err := export.Migrate(g, *to)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	for _, n := range notes {
+		fmt.Fprintf(os.Stderr, "migrate: %s\n", n)
+	}
+
+	var output any = migrated
+	if *compact {
+		if output, err = export.PruneEmptyJSON(migrated); err != nil {
+			return fmt.Errorf("migrate: compact output: %w", err)
+		}
+	}
+	if err := writeJSONOutput(*outPath, *force, false, *compact, output); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "migrate: schema_version %d → %d\n", from, migrated.SchemaVersion)
+	return nil
+}
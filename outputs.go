@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// outputSpec is one file written by an export run. Several specs share a
+// single extraction.
+type outputSpec struct {
+	Mode       string // "full" or "gondolin"; empty means -mode
+	Path       string // file path, or - for stdout
+	StatsOut   string // per-output -stats-out
+	Provenance string // per-output -provenance
+}
+
+// outputSpecKeys are the keys accepted in a -out spec, in the order
+// String renders them.
+var outputSpecKeys = []string{"mode", "path", "stats-out", "provenance"}
+
+// isOutputSpec reports whether an -out value is a key=value spec rather than
+// a plain path: it must start with one of outputSpecKeys followed by "=".
+func isOutputSpec(v string) bool {
+	for _, k := range outputSpecKeys {
+		if strings.HasPrefix(v, k+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOutputSpec parses "mode=gondolin,path=out.json[,stats-out=…][,provenance=…]".
+func parseOutputSpec(v string) (outputSpec, error) {
+	var o outputSpec
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(v, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return outputSpec{}, fmt.Errorf("invalid -out spec %q: %q is not key=value", v, part)
+		}
+		if seen[key] {
+			return outputSpec{}, fmt.Errorf("invalid -out spec %q: %s given twice", v, key)
+		}
+		seen[key] = true
+		switch key {
+		case "mode":
+			o.Mode = value
+		case "path":
+			o.Path = value
+		case "stats-out":
+			o.StatsOut = value
+		case "provenance":
+			o.Provenance = value
+		default:
+			return outputSpec{}, fmt.Errorf("invalid -out spec %q: unknown key %q (want %s)", v, key, strings.Join(outputSpecKeys, ", "))
+		}
+	}
+	if o.Path == "" {
+		return outputSpec{}, fmt.Errorf("invalid -out spec %q: path is required", v)
+	}
+	return o, nil
+}
+
+func (o outputSpec) String() string {
+	parts := []string{}
+	for i, value := range []string{o.Mode, o.Path, o.StatsOut, o.Provenance} {
+		if value != "" {
+			parts = append(parts, outputSpecKeys[i]+"="+value)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, " ")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// outFlag is the -out flag. A plain path sets the single output written in
+// -mode; mode=…,path=… specs may be repeated to write several outputs from
+// one extraction.
+type outFlag struct{ cfg *exportConfig }
+
+func (f *outFlag) String() string {
+	if f.cfg == nil {
+		return ""
+	}
+	if len(f.cfg.Outputs) == 0 {
+		return f.cfg.OutPath
+	}
+	specs := make([]string, len(f.cfg.Outputs))
+	for i, o := range f.cfg.Outputs {
+		specs[i] = o.String()
+	}
+	return strings.Join(specs, " ")
+}
+
+func (f *outFlag) Set(v string) error {
+	if !isOutputSpec(v) {
+		f.cfg.OutPath = v
+		f.cfg.outPathSet = true
+		return nil
+	}
+	o, err := parseOutputSpec(v)
+	if err != nil {
+		return err
+	}
+	f.cfg.Outputs = append(f.cfg.Outputs, o)
+	return nil
+}
+
+// outputs lists what the run writes: the -out specs with -mode filled in,
+// or the single -out/-mode/-stats-out/-provenance output.
+func (cfg exportConfig) outputs() []outputSpec {
+	if len(cfg.Outputs) == 0 {
+		return []outputSpec{{Mode: cfg.Mode, Path: cfg.OutPath, StatsOut: cfg.StatsOut, Provenance: cfg.Provenance}}
+	}
+	outputs := make([]outputSpec, len(cfg.Outputs))
+	for i, o := range cfg.Outputs {
+		if o.Mode == "" {
+			o.Mode = cfg.Mode
+		}
+		outputs[i] = o
+	}
+	return outputs
+}
+
+// wantsMode reports whether any output is written in mode.
+func (cfg exportConfig) wantsMode(mode string) bool {
+	for _, o := range cfg.outputs() {
+		if o.Mode == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// outputPaths returns the sorted file paths written, excluding stdout. Locks
+// are taken in this order so concurrent runs can't deadlock.
+func (cfg exportConfig) outputPaths() []string {
+	var paths []string
+	for _, o := range cfg.outputs() {
+		if o.Path != "-" {
+			paths = append(paths, o.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// validateOutputs checks the -out specs (or the single output) for
+// conflicts.
+func (cfg exportConfig) validateOutputs() error {
+	if len(cfg.Outputs) > 0 {
+		if cfg.outPathSet {
+			return errors.New("-out: a plain path cannot be combined with mode=…,path=… specs")
+		}
+		if cfg.StatsOut != "" || cfg.Provenance != "" {
+			return errors.New("-stats-out and -provenance apply to a single output; use stats-out= and provenance= in each -out spec")
+		}
+	}
+	seen := make(map[string]bool)
+	if cfg.StatsHistory != "" {
+		if cfg.StatsHistory == "-" || strings.HasPrefix(cfg.StatsHistory, "fd:") {
+			return fmt.Errorf("invalid -stats-history %q: must be a file", cfg.StatsHistory)
+		}
+		seen[cfg.StatsHistory] = true
+	}
+	for _, o := range cfg.outputs() {
+		if o.Mode != "full" && o.Mode != "gondolin" {
+			return fmt.Errorf("invalid -mode %q: must be 'full' or 'gondolin'", o.Mode)
+		}
+		for _, p := range []string{o.Path, o.StatsOut, o.Provenance} {
+			if p == "" {
+				continue
+			}
+			if seen[p] {
+				return fmt.Errorf("-out: %s is written more than once", p)
+			}
+			seen[p] = true
+		}
+		if n, ok := strings.CutPrefix(o.StatsOut, "fd:"); ok {
+			if fd, err := strconv.Atoi(n); err != nil || fd < 1 {
+				return fmt.Errorf("invalid -stats-out %q: fd:N needs a descriptor number >= 1", o.StatsOut)
+			}
+		}
+		if cfg.SignKey != "" && o.Path == "-" {
+			return errors.New("-sign-key requires -out to be a file")
+		}
+		if o.Provenance != "" && o.Path == "-" {
+			return errors.New("-provenance requires -out to be a file")
+		}
+	}
+	return nil
+}
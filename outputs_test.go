@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/export"
+)
+
+func TestParseOutputSpec(t *testing.T) {
+	o, err := parseOutputSpec("mode=gondolin,path=dist/g.json,stats-out=dist/g-stats.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := outputSpec{Mode: "gondolin", Path: "dist/g.json", StatsOut: "dist/g-stats.json"}
+	if o != want {
+		t.Errorf("parseOutputSpec = %+v, want %+v", o, want)
+	}
+	if got := o.String(); got != "mode=gondolin,path=dist/g.json,stats-out=dist/g-stats.json" {
+		t.Errorf("String() = %q", got)
+	}
+
+	for _, bad := range []string{"mode=full", "path=a.json,path=b.json", "path=a.json,format=yaml", "path=a.json,compact"} {
+		if _, err := parseOutputSpec(bad); err == nil {
+			t.Errorf("parseOutputSpec(%q) succeeded, want error", bad)
+		}
+	}
+	if isOutputSpec("dist/a=b.json") {
+		t.Error("a plain path containing = was taken for a spec")
+	}
+}
+
+func TestOutFlagValidation(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"-out", "mode=full,path=a.json", "-out", "mode=gondolin,path=b.json"}, ""},
+		{[]string{"-mode", "gondolin", "-out", "path=a.json", "-out", "mode=full,path=b.json"}, ""},
+		{[]string{"-out", "a.json", "-out", "mode=gondolin,path=b.json"}, "cannot be combined"},
+		{[]string{"-out", "mode=full,path=a.json", "-out", "mode=gondolin,path=a.json"}, "more than once"},
+		{[]string{"-out", "mode=full,path=a.json", "-stats-out", "stats.json"}, "in each -out spec"},
+		{[]string{"-out", "mode=slim,path=a.json"}, "invalid -mode"},
+		{[]string{"-out", "mode=full,path=-,provenance=p.json"}, "requires -out to be a file"},
+		{[]string{"-out", "a.json", "-stats-history", "a.json"}, "more than once"},
+		{[]string{"-stats-history", "fd:3"}, "invalid -stats-history"},
+	}
+	for _, tt := range tests {
+		var cfg exportConfig
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		cfg.registerFlags(fs)
+		if err := fs.Parse(append([]string{"-gitleaks", "gl.toml"}, tt.args...)); err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		err := cfg.validate()
+		if tt.err == "" && err != nil {
+			t.Errorf("%v: validate = %v", tt.args, err)
+		}
+		if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("%v: validate = %v, want error containing %q", tt.args, err, tt.err)
+		}
+	}
+}
+
+func TestRunExportMultipleOutputs(t *testing.T) {
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, "full.json")
+	gondolinPath := filepath.Join(dir, "gondolin.json")
+	gondolinStats := filepath.Join(dir, "gondolin-stats.json")
+	cfg := exportConfig{
+		THDir:          filepath.Join("testdata", "trufflehog", "pkg", "detectors"),
+		GLPath:         filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml"),
+		OutPath:        "-",
+		Mode:           "full",
+		PublicSuffixes: "reject",
+		Outputs: []outputSpec{
+			{Path: fullPath},
+			{Mode: "gondolin", Path: gondolinPath, StatsOut: gondolinStats},
+		},
+	}
+	if err := runExport(context.Background(), cfg); err != nil {
+		t.Fatalf("runExport: %v", err)
+	}
+
+	for path, want := range map[string]string{fullPath: "full", gondolinPath: "gondolin"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kind, err := export.DetectKind(data); err != nil || kind != want {
+			t.Errorf("%s: kind = %q (%v), want %q", filepath.Base(path), kind, err, want)
+		}
+	}
+
+	data, err := os.ReadFile(gondolinStats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats RunStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		t.Fatal(err)
+	}
+	g, err := loadGondolin(gondolinPath, export.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Mode != "gondolin" || stats.Gondolin == nil || stats.ContentHash != g.ContentHash {
+		t.Errorf("stats = %+v, want gondolin stats for %s", stats, g.ContentHash)
+	}
+}
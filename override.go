@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// defaultOverrideFile is the TruffleHog keyword override file, relative to
+// a checkout of this repository. Like the alias file, it is embedded at
+// build time.
+const defaultOverrideFile = "data/th_keyword_overrides.json"
+
+var overrideKeywordRE = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// runOverride implements `hogwash override [flags] set-keyword <th-dir>
+// <keyword>`, which pins the keyword of a TruffleHog detector in
+// data/th_keyword_overrides.json after previewing what the change does to
+// matching.
+func runOverride(args []string) error {
+	fs := flag.NewFlagSet("override", flag.ExitOnError)
+	dataPath := fs.String("data", defaultOverrideFile, "Override data file to edit")
+	thDir := fs.String("trufflehog", "", "Path to trufflehog/pkg/detectors/ (required)")
+	glPath := fs.String("gitleaks", "", "Path to gitleaks/config/gitleaks.toml (required)")
+	normalize := fs.String("normalize", string(combine.NormalizeBasic), "Keyword comparison, as the export flag of the same name: 'basic' or 'loose'")
+	dryRun := fs.Bool("dry-run", false, "Print the preview without writing the override file")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s override -trufflehog <dir> -gitleaks <toml> [flags] set-keyword <th-dir> <keyword>\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Prints the keyword and match changes the override causes, then writes it.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 3 || fs.Arg(0) != "set-keyword" {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("override: expected set-keyword <th-dir> <keyword>, got %v", fs.Args()))
+	}
+	if *thDir == "" || *glPath == "" {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("override: -trufflehog and -gitleaks are required for the preview"))
+	}
+	level, err := combine.ParseNormalization(*normalize)
+	if err != nil {
+		return withExitCode(exitUsage, fmt.Errorf("override: -normalize: %w", err))
+	}
+	dir, keyword := strings.ToLower(fs.Arg(1)), fs.Arg(2)
+	if !overrideKeywordRE.MatchString(keyword) {
+		return withExitCode(exitUsage, fmt.Errorf("override: keyword %q must be lower-case letters, digits and hyphens", keyword))
+	}
+
+	var overrides map[string]string
+	if err := readJSONInput(*dataPath, &overrides); err != nil {
+		return fmt.Errorf("override: %w", err)
+	}
+	if overrides == nil {
+		overrides = make(map[string]string)
+	}
+	detectors, _, _, err := trufflehog.Extract(*thDir, trufflehog.ExtractOptions{})
+	if err != nil {
+		return fmt.Errorf("override: trufflehog extraction: %w", err)
+	}
+	rules, err := gitleaks.Extract(*glPath)
+	if err != nil {
+		return fmt.Errorf("override: gitleaks extraction: %w", err)
+	}
+
+	proposed := make(map[string]string, len(overrides)+1)
+	for k, v := range overrides {
+		proposed[k] = v
+	}
+	proposed[dir] = keyword
+	preview, err := previewOverride(detectors, rules, combine.Options{Normalization: level}, dir, overrides, proposed)
+	if err != nil {
+		return fmt.Errorf("override: %w", err)
+	}
+	for _, line := range preview {
+		fmt.Fprintln(os.Stdout, line)
+	}
+	if *dryRun {
+		return nil
+	}
+	if err := writeDataFile(*dataPath, proposed); err != nil {
+		return fmt.Errorf("override: %w", err)
+	}
+	logger.Info(fmt.Sprintf("Wrote %s; rebuild to embed it", *dataPath), "path", *dataPath)
+	return nil
+}
+
+// previewOverride combines the detectors once with the current overrides
+// and once with the proposed ones, and describes the difference: the
+// detector's keyword, then every service whose TruffleHog match changed and
+// every TH-only keyword that appeared or disappeared. dir must be one of
+// the extracted detectors.
+func previewOverride(detectors []trufflehog.Detector, rules []gitleaks.Rule, opts combine.Options, dir string, current, proposed map[string]string) ([]string, error) {
+	i := slices.IndexFunc(detectors, func(d trufflehog.Detector) bool { return d.DirName == dir })
+	if i < 0 {
+		return nil, fmt.Errorf("no extracted TruffleHog detector %q", dir)
+	}
+	before, after := trufflehog.DeriveKeywordWith(dir, current), trufflehog.DeriveKeywordWith(dir, proposed)
+	if before == after {
+		return nil, fmt.Errorf("%s already derives keyword %q", dir, after)
+	}
+
+	rekey := func(overrides map[string]string) combine.Export {
+		ds := make([]trufflehog.Detector, len(detectors))
+		for i, d := range detectors {
+			d.Keyword = trufflehog.DeriveKeywordWith(d.DirName, overrides)
+			ds[i] = d
+		}
+		return combine.CombineWith(ds, rules, opts)
+	}
+	old, updated := rekey(current), rekey(proposed)
+
+	lines := []string{fmt.Sprintf("%s: keyword %s → %s", dir, before, after)}
+	type match struct {
+		typ string
+		th  []string
+	}
+	matches := func(e combine.Export) map[string]match {
+		m := make(map[string]match, len(e.Services))
+		for _, svc := range e.Services {
+			m[svc.Keyword] = match{svc.MatchType, svc.MatchedTH}
+		}
+		return m
+	}
+	oldMatches, newMatches := matches(old), matches(updated)
+	for _, kw := range sortedKeys(oldMatches) {
+		o, n := oldMatches[kw], newMatches[kw]
+		if o.typ != n.typ || !slices.Equal(o.th, n.th) {
+			lines = append(lines, fmt.Sprintf("~ %s: %s → %s", kw, describeMatch(o.typ, o.th), describeMatch(n.typ, n.th)))
+		}
+	}
+	thOnly := func(e combine.Export) []string {
+		var ks []string
+		for _, th := range e.THOnlyHosts {
+			ks = append(ks, th.Keyword+" ("+th.DirName+")")
+		}
+		sort.Strings(ks)
+		return ks
+	}
+	oldTH, newTH := thOnly(old), thOnly(updated)
+	for _, k := range oldTH {
+		if !slices.Contains(newTH, k) {
+			lines = append(lines, "- th-only "+k)
+		}
+	}
+	for _, k := range newTH {
+		if !slices.Contains(oldTH, k) {
+			lines = append(lines, "+ th-only "+k)
+		}
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "  no service matches change")
+	}
+	return lines, nil
+}
+
+func describeMatch(matchType string, dirs []string) string {
+	if matchType == "" {
+		return "unmatched"
+	}
+	return matchType + " [" + strings.Join(dirs, ", ") + "]"
+}
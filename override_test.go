@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestPreviewOverride(t *testing.T) {
+	thDir := filepath.Join("testdata", "trufflehog", "pkg", "detectors")
+	glPath := filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml")
+	detectors, _, _, err := trufflehog.Extract(thDir, trufflehog.ExtractOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := gitleaks.Extract(glPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := map[string]string{}
+	got, err := previewOverride(detectors, rules, combine.Options{}, "cloudflareapitoken", current, map[string]string{"cloudflareapitoken": "cf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"cloudflareapitoken: keyword cloudflare → cf",
+		"~ cloudflare: exact [cloudflareapitoken] → unmatched",
+		"+ th-only cf (cloudflareapitoken)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("preview =\n%q\nwant\n%q", got, want)
+	}
+	if _, err := previewOverride(detectors, rules, combine.Options{}, "meraki", current, map[string]string{"meraki": "meraki"}); err == nil {
+		t.Error("override that changes nothing: no error")
+	}
+	if _, err := previewOverride(detectors, rules, combine.Options{}, "merakki", current, map[string]string{"merakki": "meraki"}); err == nil {
+		t.Error("override of an unknown detector: no error")
+	}
+
+	dataPath := filepath.Join(t.TempDir(), "th_keyword_overrides.json")
+	if err := os.WriteFile(dataPath, []byte("{\n  \"sonarcloud\": \"sonar\"\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-data", dataPath, "-trufflehog", thDir, "-gitleaks", glPath}
+	if err := runOverride(append(args, "-dry-run", "set-keyword", "meraki", "cisco-meraki")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(dataPath); string(data) != "{\n  \"sonarcloud\": \"sonar\"\n}\n" {
+		t.Errorf("-dry-run wrote the file:\n%s", data)
+	}
+	if err := runOverride(append(args, "set-keyword", "meraki", "cisco-meraki")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(dataPath); string(data) != "{\n  \"meraki\": \"cisco-meraki\",\n  \"sonarcloud\": \"sonar\"\n}\n" {
+		t.Errorf("override file:\n%s", data)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// manifestName is the first entry of every package.
+const manifestName = "manifest.json"
+
+// PackageManifest describes a release package: what the export is and a
+// checksum for every other file in the archive.
+type PackageManifest struct {
+	Kind          string         `json:"kind"`                     // full or gondolin
+	SchemaVersion int            `json:"schema_version,omitempty"` // gondolin only
+	ContentHash   string         `json:"content_hash"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+	Generator     string         `json:"generator,omitempty"` // hogwash version that produced the export
+	Files         []PackageEntry `json:"files"`
+}
+
+// PackageEntry is one file of a package.
+type PackageEntry struct {
+	Name   string `json:"name"`
+	Role   string `json:"role"` // export, schema, checksum, provenance, signature
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// runPackage implements `hogwash package [flags] <export.json>`.
+func runPackage(args []string) error {
+	fs := flag.NewFlagSet("package", flag.ExitOnError)
+	outPath := fs.String("out", "", "Archive path (default: the export path with .tar.gz instead of .json)")
+	provPath := fs.String("provenance", "", "Provenance statement to include (default: <export>.intoto.json next to the export, if present)")
+	sigPath := fs.String("signature", "", "Detached signature to include (default: <export>.json"+signatureSuffix+" next to the export, if present)")
+	force := fs.Bool("force", false, "Overwrite -out if it already exists")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s package [flags] <export.json>\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("package: expected exactly one input file, got %d", fs.NArg()))
+	}
+	path := fs.Arg(0)
+	base := strings.TrimSuffix(filepath.Base(path), ".json")
+	stem := strings.TrimSuffix(path, ".json")
+	if *outPath == "" {
+		*outPath = stem + ".tar.gz"
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("package: %w", err)
+	}
+	m, err := describeExport(content)
+	if err != nil {
+		return withExitCode(exitValidation, fmt.Errorf("package: %s: %w", path, err))
+	}
+	schema, err := export.JSONSchema(m.Kind)
+	if err != nil {
+		return fmt.Errorf("package: %w", err)
+	}
+	schemaJSON, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("package: encode schema: %w", err)
+	}
+
+	exportName := filepath.Base(path)
+	files := []packageFile{
+		{name: exportName, role: "export", data: content},
+		{name: base + ".schema.json", role: "schema", data: append(schemaJSON, '\n')},
+		{name: exportName + ".sha256", role: "checksum", data: []byte(sha256Hex(content) + "  " + exportName + "\n")},
+	}
+	for _, opt := range []struct {
+		role, path, fallback string
+	}{
+		{"provenance", *provPath, stem + ".intoto.json"},
+		{"signature", *sigPath, path + signatureSuffix},
+	} {
+		f, err := optionalPackageFile(opt.role, opt.path, opt.fallback)
+		if err != nil {
+			return fmt.Errorf("package: %w", err)
+		}
+		if f != nil {
+			files = append(files, *f)
+		}
+	}
+
+	for _, f := range files {
+		m.Files = append(m.Files, PackageEntry{Name: f.name, Role: f.role, Size: len(f.data), SHA256: sha256Hex(f.data)})
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("package: encode manifest: %w", err)
+	}
+	files = append([]packageFile{{name: manifestName, data: append(manifest, '\n')}}, files...)
+
+	err = writeFileAtomic(*outPath, *force, false, 0o644, func(w io.Writer) error {
+		return writeTarGz(w, base, m.GeneratedAt, files)
+	})
+	if err != nil {
+		return fmt.Errorf("package: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "package: wrote %s (%s, %d files)\n", *outPath, m.Kind, len(files))
+	return nil
+}
+
+// packageFile is one archive member.
+type packageFile struct {
+	name string
+	role string
+	data []byte
+}
+
+// describeExport validates an export and returns its manifest without
+// files. Packaging an export that fails validation would publish it.
+func describeExport(content []byte) (PackageManifest, error) {
+	kind, err := export.DetectKind(content)
+	if err != nil {
+		return PackageManifest{}, err
+	}
+	m := PackageManifest{Kind: kind}
+	var errs []error
+	var generator *combine.Generator
+	switch kind {
+	case "gondolin":
+		var g export.Gondolin
+		if err := json.Unmarshal(content, &g); err != nil {
+			return PackageManifest{}, err
+		}
+		errs = export.ValidateGondolin(g, export.ValidateOptions{})
+		m.SchemaVersion, m.ContentHash, m.GeneratedAt, generator = g.SchemaVersion, g.ContentHash, g.GeneratedAt, g.Generator
+	default:
+		var e combine.Export
+		if err := json.Unmarshal(content, &e); err != nil {
+			return PackageManifest{}, err
+		}
+		errs = export.ValidateCombined(e, export.ValidateOptions{})
+		m.ContentHash, m.GeneratedAt, generator = e.ContentHash, e.GeneratedAt, e.Generator
+	}
+	if len(errs) > 0 {
+		return PackageManifest{}, fmt.Errorf("fails validation (%d problems, first: %w); run validate for details", len(errs), errs[0])
+	}
+	if generator != nil {
+		m.Generator = generator.Version
+	}
+	return m, nil
+}
+
+// optionalPackageFile reads path, or fallback when path is empty and
+// fallback exists. It returns nil when there is nothing to include.
+func optionalPackageFile(role, path, fallback string) (*packageFile, error) {
+	if path == "" {
+		if _, err := os.Stat(fallback); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		path = fallback
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", role, err)
+	}
+	return &packageFile{name: filepath.Base(path), role: role, data: data}, nil
+}
+
+// writeTarGz writes files under dir/ in a gzipped tar. Entries carry mtime
+// and no owner, so the same inputs give the same archive bytes.
+func writeTarGz(w io.Writer, dir string, mtime time.Time, files []packageFile) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    dir + "/" + f.name,
+			Mode:    0o644,
+			Size:    int64(len(f.data)),
+			ModTime: mtime.UTC().Truncate(time.Second),
+			Format:  tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunPackage(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "secret-mapping.gondolin.json")
+	cfg := exportConfig{
+		THDir:          filepath.Join("testdata", "trufflehog", "pkg", "detectors"),
+		GLPath:         filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml"),
+		OutPath:        out,
+		Mode:           "gondolin",
+		PublicSuffixes: "reject",
+		Provenance:     filepath.Join(dir, "secret-mapping.gondolin.intoto.json"),
+	}
+	if err := runExport(context.Background(), cfg); err != nil {
+		t.Fatalf("runExport: %v", err)
+	}
+
+	archive := filepath.Join(dir, "secret-mapping.gondolin.tar.gz")
+	if err := runPackage([]string{out}); err != nil {
+		t.Fatalf("runPackage: %v", err)
+	}
+	files := readTarGz(t, archive)
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	var m PackageManifest
+	if err := json.Unmarshal(files["secret-mapping.gondolin/manifest.json"], &m); err != nil {
+		t.Fatalf("manifest: %v (archive has %v)", err, names)
+	}
+	if m.Kind != "gondolin" || m.ContentHash == "" || len(m.Files) != 4 {
+		t.Fatalf("manifest = %+v", m)
+	}
+	roles := make(map[string]bool)
+	for _, f := range m.Files {
+		data, ok := files["secret-mapping.gondolin/"+f.Name]
+		if !ok || sha256Hex(data) != f.SHA256 || len(data) != f.Size {
+			t.Errorf("%s: missing or checksum mismatch", f.Name)
+		}
+		roles[f.Role] = true
+	}
+	for _, role := range []string{"export", "schema", "checksum", "provenance"} {
+		if !roles[role] {
+			t.Errorf("no %s in %+v", role, m.Files)
+		}
+	}
+
+	// Packaging is reproducible, and refuses to overwrite without -force.
+	first, _ := os.ReadFile(archive)
+	if err := runPackage([]string{out}); err == nil {
+		t.Error("runPackage overwrote an existing archive without -force")
+	}
+	if err := runPackage([]string{"-force", out}); err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := os.ReadFile(archive); !bytes.Equal(first, second) {
+		t.Error("packaging the same export twice gave different archives")
+	}
+
+	// An export that fails validation isn't packaged.
+	tampered := filepath.Join(dir, "tampered.json")
+	data, _ := os.ReadFile(out)
+	if err := os.WriteFile(tampered, bytes.Replace(data, []byte("cloudflare"), []byte("cloudfare"), 1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runPackage([]string{tampered}); err == nil {
+		t.Error("runPackage packaged an export with a stale content_hash")
+	}
+}
+
+func readTarGz(t *testing.T, path string) map[string][]byte {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = data
+	}
+}
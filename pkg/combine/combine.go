@@ -0,0 +1,411 @@
+// Package combine merges TruffleHog hosts and Gitleaks rules into a unified
+// per-service dataset, and owns the curated policy applied at combine time
+// (aliases, categories, policy hints, primary hosts, regions, host roles).
+package combine
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// --- Output types ---
+
+// Export is the full combined dataset (-mode full): the source of truth
+// other formats are derived from.
+type Export struct {
+	GeneratedAt time.Time            `json:"generated_at"`
+	ContentHash string               `json:"content_hash"`        // sha256 over everything except generated_at, generator, removed and skipped
+	Generator   *Generator           `json:"generator,omitempty"` // binary and data that produced the export
+	Licenses    []License            `json:"licenses,omitempty"`  // attribution and terms per source
+	Stats       Stats                `json:"stats"`
+	Services    []Service            `json:"services"`
+	THOnlyHosts []THOnlyEntry        `json:"th_only_hosts,omitempty"` // TH detectors with no GL match
+	GLNoHosts   []string             `json:"gl_no_hosts,omitempty"`   // GL services with no TH host
+	Removed     *Removed             `json:"removed,omitempty"`       // tombstones relative to -previous; not hashed
+	Skipped     []trufflehog.Skipped `json:"skipped,omitempty"`       // TH detectors that yielded no hosts (opt-in); not hashed
+}
+
+// Stats summarizes how services were matched across sources.
+type Stats struct {
+	TotalServices     int `json:"total_services"`      // GL services + TH-only services
+	ServicesWithHosts int `json:"services_with_hosts"` // have both regex and hosts
+	ServicesNoHosts   int `json:"services_no_hosts"`   // GL rules but no TH hosts
+	THOnlyServices    int `json:"th_only_services"`    // TH hosts but no GL rules
+	TotalRules        int `json:"total_rules"`         // GL rules total
+	RulesWithHosts    int `json:"rules_with_hosts"`
+	MatchExact        int `json:"match_exact"`
+	MatchPrefix       int `json:"match_prefix"`
+	MatchAlias        int `json:"match_alias"`
+	MatchCurated      int `json:"match_curated"` // hosts from data/well_known_hosts.json
+}
+
+// Service is a service entry in the combined output. It has:
+// - A canonical keyword (used for env var name matching)
+// - Hosts from TruffleHog (for createHttpHooks)
+// - Regex rules from Gitleaks (for value-based detection)
+type Service struct {
+	Keyword         string                    `json:"keyword"`                    // canonical service keyword
+	DisplayName     string                    `json:"display_name,omitempty"`     // curated brand name (data/service_info.json)
+	DocsURL         string                    `json:"docs_url,omitempty"`         // curated API documentation link
+	Category        string                    `json:"category,omitempty"`         // curated taxonomy (data/service_categories.json)
+	Hosts           []string                  `json:"hosts,omitempty"`            // from TruffleHog
+	PrimaryHost     string                    `json:"primary_host,omitempty"`     // single best host (see ChoosePrimaryHost)
+	PathPrefixes    map[string][]string       `json:"path_prefixes,omitempty"`    // host → API path prefixes (absent = whole host)
+	HostRoles       map[string]string         `json:"host_roles,omitempty"`       // host → api, auth, webhook, telemetry
+	RegionalHosts   []RegionalHost            `json:"regional_hosts,omitempty"`   // curated alternate endpoints (data/regional_hosts.json)
+	HostTemplates   []trufflehog.HostTemplate `json:"host_templates,omitempty"`   // tenant-scoped hosts, extracted and curated (data/tenant_hosts.json)
+	MatchType       string                    `json:"match_type,omitempty"`       // "exact", "prefix", "alias", "curated", ""
+	MatchedTH       []string                  `json:"matched_th,omitempty"`       // TH dir names that matched
+	UnresolvedHosts []string                  `json:"unresolved_hosts,omitempty"` // hosts that failed -verify-dns
+	EnvNames        []string                  `json:"env_names,omitempty"`        // likely env var names (generated plus data/env_names.json)
+	RotationURL     string                    `json:"rotation_url,omitempty"`     // howtorotate.com guide from a matched TH detector
+	Deprecated      string                    `json:"deprecated,omitempty"`       // why the service is retired (data/deprecated_services.json); excluded from gondolin
+	MergedFrom      []string                  `json:"merged_from,omitempty"`      // keywords of near-duplicate services folded into this one
+	Rules           []Rule                    `json:"rules"`                      // from Gitleaks
+}
+
+// Rule is a Gitleaks rule attached to a service.
+type Rule struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description,omitempty"`
+	Regex       string   `json:"regex"`
+	Entropy     float64  `json:"entropy,omitempty"`
+	SecretGroup int      `json:"secret_group,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Policy      string   `json:"policy,omitempty"`   // advisory hint: block, redact, forward
+	Severity    string   `json:"severity,omitempty"` // critical, high, medium, low
+	Examples    []string `json:"examples,omitempty"` // synthetic matches of Regex (-samples), never real secrets
+}
+
+// THOnlyEntry is a TruffleHog detector that has hosts but no matching GL rules.
+// These are still useful: the keyword can match env var names.
+type THOnlyEntry struct {
+	Keyword         string                    `json:"keyword"`
+	DirName         string                    `json:"dir_name"`
+	Hosts           []string                  `json:"hosts"`
+	PathPrefixes    map[string][]string       `json:"path_prefixes,omitempty"`
+	UnresolvedHosts []string                  `json:"unresolved_hosts,omitempty"` // hosts that failed -verify-dns
+	RotationURL     string                    `json:"rotation_url,omitempty"`
+	Deprecated      string                    `json:"deprecated,omitempty"`
+	HostTemplates   []trufflehog.HostTemplate `json:"host_templates,omitempty"`
+}
+
+// Combine merges TruffleHog detectors and Gitleaks rules into a unified dataset.
+//
+// The matching strategy:
+//  1. Build a keyword→hosts index from TH detectors (using trufflehog.DeriveKeyword)
+//  2. For each GL service keyword, find matching TH entries:
+//     a. Exact match on keyword (after normalization)
+//     b. Manual alias lookup
+//     c. Prefix match (GL keyword is prefix of TH keyword, len≥4)
+//  3. Services with the same hosts and near-identical keywords are
+//     consolidated into one, listing the others in merged_from
+//  4. TH detectors with no GL match go into THOnlyHosts
+func Combine(thDetectors []trufflehog.Detector, glRules []gitleaks.Rule) Export {
+	return CombineWith(thDetectors, glRules, Options{})
+}
+
+// Options tunes CombineWith. The zero value is what Combine uses.
+type Options struct {
+	// Normalization is how GL and TH keywords are compared for matching
+	// (default NormalizeBasic). Services are still grouped and exported
+	// under their basic-normalized keyword.
+	Normalization Normalization
+}
+
+// CombineWith is Combine with options.
+func CombineWith(thDetectors []trufflehog.Detector, glRules []gitleaks.Rule, opts Options) Export {
+	level := opts.Normalization
+	thByKeyword := indexTH(thDetectors, level)
+	thUsed := make(map[string]bool) // track which TH dirs are claimed
+
+	// Group GL rules by keyword
+	type glGroup struct {
+		keyword string
+		rules   []gitleaks.Rule
+	}
+	glGroupMap := make(map[string]*glGroup)
+	var glKeywords []string
+
+	for _, r := range glRules {
+		norm := NormalizeKeyword(r.Keyword)
+		if g, ok := glGroupMap[norm]; ok {
+			g.rules = append(g.rules, r)
+		} else {
+			glGroupMap[norm] = &glGroup{keyword: r.Keyword, rules: []gitleaks.Rule{r}}
+			glKeywords = append(glKeywords, norm)
+		}
+	}
+	sort.Strings(glKeywords)
+
+	thKeywordsSorted := sortedKeys(thByKeyword)
+
+	// Match GL groups to TH entries
+	var services []Service
+
+	for _, normKey := range glKeywords {
+		glg := glGroupMap[normKey]
+		matchedTH, matchType := findTHMatch(glg.keyword, thByKeyword, thKeywordsSorted, level, nil)
+
+		// Collect hosts and mark TH entries as used
+		hostSet := make(map[string]bool)
+		prefixes := trufflehog.NewPathPrefixSet()
+		var matchedNames []string
+		var rotationURL string
+		var templates []trufflehog.HostTemplate
+		for _, m := range matchedTH {
+			if entries, ok := thByKeyword[m]; ok {
+				for _, e := range entries {
+					for _, h := range e.hosts {
+						hostSet[h] = true
+					}
+					prefixes.AddAll(e.hosts, e.pathPrefixes)
+					thUsed[e.dirName] = true
+					matchedNames = append(matchedNames, e.dirName)
+					if e.rotationURL != "" && (rotationURL == "" || e.rotationURL < rotationURL) {
+						rotationURL = e.rotationURL
+					}
+					templates = append(templates, e.hostTemplates...)
+				}
+			}
+		}
+
+		hosts := sortedKeys(hostSet)
+		sort.Strings(matchedNames)
+		if matchType == "" {
+			if curated := WellKnownHostsFor(glg.keyword); len(curated) > 0 {
+				hosts = append([]string(nil), curated...)
+				matchType = MatchCurated
+			}
+		}
+
+		// Build rules
+		category := ServiceCategory(glg.keyword)
+		combinedRules := make([]Rule, len(glg.rules))
+		for i, r := range glg.rules {
+			combinedRules[i] = Rule{
+				ID:          r.ID,
+				Description: r.Description,
+				Regex:       r.Regex,
+				Entropy:     r.Entropy,
+				SecretGroup: r.SecretGroup,
+				Keywords:    r.Keywords,
+				Policy:      PolicyHint(r.ID, category),
+				Severity:    RuleSeverity(r.ID, glg.keyword, category),
+			}
+		}
+
+		info := ServiceInfoFor(glg.keyword)
+		svc := Service{
+			Keyword:       glg.keyword,
+			DisplayName:   info.DisplayName,
+			DocsURL:       info.DocsURL,
+			Category:      category,
+			Hosts:         hosts,
+			PrimaryHost:   ChoosePrimaryHost(glg.keyword, hosts),
+			PathPrefixes:  prefixes.Result(),
+			RegionalHosts: RegionalHostsFor(glg.keyword),
+			HostTemplates: trufflehog.MergeHostTemplates(TenantHostsFor(glg.keyword), templates),
+			MatchType:     matchType,
+			MatchedTH:     matchedNames,
+			EnvNames:      EnvNames(glg.keyword),
+			RotationURL:   rotationURL,
+			Deprecated:    DeprecationReason(glg.keyword),
+			Rules:         combinedRules,
+		}
+		svc.HostRoles = ClassifyHostRoles(svc.AllowedHosts(), svc.PathPrefixes)
+		services = append(services, svc)
+	}
+
+	services = consolidate(services)
+
+	// Collect TH-only entries (hosts with no GL rules)
+	var thOnly []THOnlyEntry
+	for _, d := range thDetectors {
+		if !thUsed[d.DirName] {
+			thOnly = append(thOnly, THOnlyEntry{
+				Keyword:      d.Keyword,
+				DirName:      d.DirName,
+				Hosts:        d.Hosts,
+				PathPrefixes: d.PathPrefixes,
+				RotationURL:  d.RotationURL,
+				Deprecated:   DeprecationReason(d.Keyword),
+
+				HostTemplates: trufflehog.MergeHostTemplates(TenantHostsFor(d.Keyword), d.HostTemplates),
+			})
+		}
+	}
+	sort.Slice(thOnly, func(i, j int) bool {
+		return thOnly[i].Keyword < thOnly[j].Keyword
+	})
+
+	stats, glNoHosts := summarize(services, thOnly)
+
+	export := Export{
+		GeneratedAt: time.Now().UTC(),
+		Licenses:    Licenses(),
+		Stats:       stats,
+		Services:    services,
+		THOnlyHosts: thOnly,
+		GLNoHosts:   glNoHosts,
+	}
+	return export.WithContentHash()
+}
+
+// summarize computes Stats and the sorted GLNoHosts list for a set of
+// services and TH-only entries.
+func summarize(services []Service, thOnly []THOnlyEntry) (Stats, []string) {
+	var stats Stats
+	var glNoHosts []string
+	for _, svc := range services {
+		stats.TotalRules += len(svc.Rules)
+		if len(svc.Hosts) > 0 {
+			stats.ServicesWithHosts++
+			stats.RulesWithHosts += len(svc.Rules)
+			switch svc.MatchType {
+			case "exact":
+				stats.MatchExact++
+			case "prefix":
+				stats.MatchPrefix++
+			case "alias":
+				stats.MatchAlias++
+			case MatchCurated:
+				stats.MatchCurated++
+			}
+		} else {
+			stats.ServicesNoHosts++
+			glNoHosts = append(glNoHosts, svc.Keyword)
+		}
+	}
+	stats.TotalServices = len(services) + len(thOnly)
+	stats.THOnlyServices = len(thOnly)
+	sort.Strings(glNoHosts)
+	return stats, glNoHosts
+}
+
+// findTHMatch finds TruffleHog keyword matches for a Gitleaks service keyword.
+// Returns (list of matched TH normalized keywords, match type). Each decision
+// is recorded in ex when it is non-nil (see Explain).
+func findTHMatch(glKeyword string, thByKeyword map[string][]thEntry, thKeywordsSorted []string, level Normalization, ex *Explanation) ([]string, string) {
+	glNorm := NormalizeKeywordAt(glKeyword, level)
+
+	// Strategy 1: Exact match
+	if _, ok := thByKeyword[glNorm]; ok {
+		ex.step("exact: %q is a TH keyword", glNorm)
+		return []string{glNorm}, "exact"
+	}
+	ex.step("exact: no TH keyword %q", glNorm)
+
+	// Strategy 2: Manual alias
+	if alias, ok := serviceAliasesByNorm[NormalizeKeyword(glKeyword)]; ok {
+		aliasNorm := NormalizeKeywordAt(alias, level)
+		if _, ok := thByKeyword[aliasNorm]; ok {
+			ex.step("alias: %q → %q is a TH keyword", glNorm, aliasNorm)
+			return []string{aliasNorm}, "alias"
+		}
+		ex.step("alias: %q → %q, but no TH keyword %q", glNorm, aliasNorm, aliasNorm)
+	} else {
+		ex.step("alias: no service_aliases.json entry for %q", glNorm)
+	}
+
+	// Strategy 3: Prefix match — find TH keywords that start with the GL keyword
+	// Only for keywords >= 4 chars to avoid false positives
+	if len(glNorm) < minPrefixMatchLen {
+		ex.step("prefix: skipped, %q is shorter than %d chars", glNorm, minPrefixMatchLen)
+		return nil, ""
+	}
+	matches := prefixMatchesSorted(thKeywordsSorted, glNorm)
+	if pinned, ok := matchPinsByNorm[NormalizeKeyword(glKeyword)]; ok && len(matches) > 0 {
+		matches = pinnedMatches(matches, pinned, level)
+		ex.step("prefix: match_pins.json allows only [%s]", strings.Join(pinned, ", "))
+	}
+	if len(matches) > 0 {
+		ex.step("prefix: TH keywords starting with %q: %s", glNorm, strings.Join(matches, ", "))
+		return matches, "prefix"
+	}
+	ex.step("prefix: no TH keyword starts with %q", glNorm)
+
+	return nil, ""
+}
+
+// pinnedMatches keeps the prefix matches listed in pinned.
+func pinnedMatches(matches, pinned []string, level Normalization) []string {
+	allowed := make(map[string]bool, len(pinned))
+	for _, k := range pinned {
+		allowed[NormalizeKeywordAt(k, level)] = true
+	}
+	var out []string
+	for _, m := range matches {
+		if allowed[m] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// minPrefixMatchLen is the shortest normalized GL keyword that may bind TH
+// keywords by prefix.
+const minPrefixMatchLen = 4
+
+// indexTH indexes TH detectors by keyword normalized at level.
+func indexTH(thDetectors []trufflehog.Detector, level Normalization) map[string][]thEntry {
+	thByKeyword := make(map[string][]thEntry)
+	for _, d := range thDetectors {
+		norm := NormalizeKeywordAt(d.Keyword, level)
+		thByKeyword[norm] = append(thByKeyword[norm], thEntry{
+			dirName:      d.DirName,
+			hosts:        d.Hosts,
+			pathPrefixes: d.PathPrefixes,
+			rotationURL:  d.RotationURL,
+
+			hostTemplates: d.HostTemplates,
+		})
+	}
+	return thByKeyword
+}
+
+type thEntry struct {
+	dirName      string
+	hosts        []string
+	pathPrefixes map[string][]string
+	rotationURL  string
+
+	hostTemplates []trufflehog.HostTemplate
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func prefixMatchesSorted(sorted []string, prefix string) []string {
+	start := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i] >= prefix
+	})
+	if start >= len(sorted) {
+		return nil
+	}
+	var out []string
+	for i := start; i < len(sorted); i++ {
+		k := sorted[i]
+		if !strings.HasPrefix(k, prefix) {
+			break
+		}
+		if k == prefix {
+			continue
+		}
+		out = append(out, k)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
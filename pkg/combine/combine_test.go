@@ -0,0 +1,209 @@
+package combine
+
+import (
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestCombineBasic(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "anthropic", Keyword: "anthropic", Hosts: []string{"api.anthropic.com"}},
+		{DirName: "openai", Keyword: "openai", Hosts: []string{"api.openai.com"}},
+		{DirName: "cloudflareapitoken", Keyword: "cloudflare", Hosts: []string{"api.cloudflare.com"}},
+		{DirName: "nogl", Keyword: "nogl", Hosts: []string{"api.nogl.com"}}, // no GL match
+	}
+
+	glRules := []gitleaks.Rule{
+		{ID: "anthropic-api-key", Keyword: "anthropic", Regex: `sk-ant-api03-.*`},
+		{ID: "openai-api-key", Keyword: "openai", Regex: `sk-[a-zA-Z0-9]{48}`},
+		{ID: "cloudflare-api-key", Keyword: "cloudflare", Regex: `[a-f0-9]{37}`},
+		{ID: "noth-secret", Keyword: "noth", Regex: `noth-[a-z]{10}`}, // no TH match
+	}
+
+	export := Combine(thDetectors, glRules)
+
+	// Check stats
+	if export.Stats.ServicesWithHosts != 3 {
+		t.Errorf("ServicesWithHosts = %d, want 3", export.Stats.ServicesWithHosts)
+	}
+	if export.Stats.ServicesNoHosts != 1 {
+		t.Errorf("ServicesNoHosts = %d, want 1", export.Stats.ServicesNoHosts)
+	}
+	if export.Stats.THOnlyServices != 1 {
+		t.Errorf("THOnlyServices = %d, want 1", export.Stats.THOnlyServices)
+	}
+	if export.Stats.TotalRules != 4 {
+		t.Errorf("TotalRules = %d, want 4", export.Stats.TotalRules)
+	}
+
+	// Check that anthropic has hosts
+	for _, svc := range export.Services {
+		if svc.Keyword == "anthropic" {
+			if len(svc.Hosts) == 0 {
+				t.Error("anthropic should have hosts")
+			}
+			if svc.MatchType != "exact" {
+				t.Errorf("anthropic match_type = %q, want 'exact'", svc.MatchType)
+			}
+			if len(svc.Rules) != 1 {
+				t.Errorf("anthropic rules count = %d, want 1", len(svc.Rules))
+			}
+		}
+		if svc.Keyword == "noth" {
+			if len(svc.Hosts) != 0 {
+				t.Error("noth should have no hosts")
+			}
+		}
+	}
+
+	// Check TH-only
+	if len(export.THOnlyHosts) != 1 || export.THOnlyHosts[0].Keyword != "nogl" {
+		t.Errorf("THOnlyHosts = %+v, want [{nogl ...}]", export.THOnlyHosts)
+	}
+}
+
+func TestCombineAliasMatch(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "meraki", Keyword: "meraki", Hosts: []string{"api.meraki.com"}},
+	}
+
+	glRules := []gitleaks.Rule{
+		{ID: "cisco-meraki-api-key", Keyword: "cisco-meraki", Regex: `[a-f0-9]{40}`},
+	}
+
+	export := Combine(thDetectors, glRules)
+
+	if export.Stats.ServicesWithHosts != 1 {
+		t.Errorf("ServicesWithHosts = %d, want 1", export.Stats.ServicesWithHosts)
+	}
+	if export.Stats.MatchAlias != 1 {
+		t.Errorf("MatchAlias = %d, want 1", export.Stats.MatchAlias)
+	}
+
+	svc := export.Services[0]
+	if svc.Keyword != "cisco-meraki" {
+		t.Errorf("keyword = %q, want 'cisco-meraki'", svc.Keyword)
+	}
+	if len(svc.Hosts) == 0 || svc.Hosts[0] != "api.meraki.com" {
+		t.Errorf("hosts = %v, want [api.meraki.com]", svc.Hosts)
+	}
+}
+
+func TestCombineAliasMatchNormalizedKeyword(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "meraki", Keyword: "meraki", Hosts: []string{"api.meraki.com"}},
+	}
+
+	// Same logical service as cisco-meraki but different casing to validate
+	// normalized alias lookup.
+	glRules := []gitleaks.Rule{
+		{ID: "cisco-meraki-api-key", Keyword: "Cisco-Meraki", Regex: `[a-f0-9]{40}`},
+	}
+
+	export := Combine(thDetectors, glRules)
+	if export.Stats.MatchAlias != 1 {
+		t.Fatalf("MatchAlias = %d, want 1", export.Stats.MatchAlias)
+	}
+	if len(export.Services) != 1 || len(export.Services[0].Hosts) == 0 {
+		t.Fatalf("expected alias-matched hosts, got %+v", export.Services)
+	}
+}
+
+func TestCombinePrefixMatch(t *testing.T) {
+	// Prefix fallback case:
+	// GL keyword = "foobar"
+	// TH keywords = "foobarsvc" and "foobarinternal"
+	// No exact keyword and no alias should match.
+	thDetectors := []trufflehog.Detector{
+		{DirName: "foobarsvc", Keyword: "foobarsvc", Hosts: []string{"api.foobarsvc.com"}},
+		{DirName: "foobarinternal", Keyword: "foobarinternal", Hosts: []string{"auth.foobarinternal.com"}},
+	}
+
+	glRules := []gitleaks.Rule{
+		{ID: "foobar-api-key", Keyword: "foobar", Regex: `fb-[a-z]{32}`},
+	}
+
+	export := Combine(thDetectors, glRules)
+
+	if export.Stats.ServicesWithHosts != 1 {
+		t.Errorf("ServicesWithHosts = %d, want 1", export.Stats.ServicesWithHosts)
+	}
+	if export.Stats.MatchPrefix != 1 {
+		t.Errorf("MatchPrefix = %d, want 1", export.Stats.MatchPrefix)
+	}
+
+	svc := export.Services[0]
+	if svc.MatchType != "prefix" {
+		t.Fatalf("match_type = %q, want prefix", svc.MatchType)
+	}
+	if len(svc.Hosts) != 2 {
+		t.Errorf("hosts count = %d, want 2, got %v", len(svc.Hosts), svc.Hosts)
+	}
+}
+
+func TestCombinePrefixMatchPins(t *testing.T) {
+	saved := matchPinsByNorm
+	defer func() { matchPinsByNorm = saved }()
+
+	thDetectors := []trufflehog.Detector{
+		{DirName: "foobarsvc", Keyword: "foobarsvc", Hosts: []string{"api.foobarsvc.com"}},
+		{DirName: "foobarinternal", Keyword: "foobarinternal", Hosts: []string{"auth.foobarinternal.com"}},
+	}
+	glRules := []gitleaks.Rule{{ID: "foobar-api-key", Keyword: "foobar", Regex: `fb-[a-z]{32}`}}
+
+	matchPinsByNorm = map[string][]string{"foobar": {"foobar-svc"}}
+	svc := Combine(thDetectors, glRules).Services[0]
+	if svc.MatchType != "prefix" || len(svc.MatchedTH) != 1 || svc.MatchedTH[0] != "foobarsvc" {
+		t.Errorf("pinned to foobarsvc: match %s %v", svc.MatchType, svc.MatchedTH)
+	}
+
+	matchPinsByNorm = map[string][]string{"foobar": {}}
+	export := Combine(thDetectors, glRules)
+	if svc := export.Services[0]; svc.MatchType != "" || len(svc.Hosts) != 0 || len(export.THOnlyHosts) != 2 {
+		t.Errorf("pinned to nothing: match %q, hosts %v, th-only %d", svc.MatchType, svc.Hosts, len(export.THOnlyHosts))
+	}
+}
+
+func TestCombineMultipleRulesSameService(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "slack", Keyword: "slack", Hosts: []string{"slack.com", "api.slack.com"}},
+	}
+
+	glRules := []gitleaks.Rule{
+		{ID: "slack-bot-token", Keyword: "slack", Regex: `xoxb-.*`},
+		{ID: "slack-user-token", Keyword: "slack", Regex: `xoxp-.*`},
+		{ID: "slack-app-token", Keyword: "slack", Regex: `xapp-.*`},
+	}
+
+	export := Combine(thDetectors, glRules)
+
+	if export.Stats.ServicesWithHosts != 1 {
+		t.Errorf("ServicesWithHosts = %d, want 1", export.Stats.ServicesWithHosts)
+	}
+	if export.Stats.TotalRules != 3 {
+		t.Errorf("TotalRules = %d, want 3", export.Stats.TotalRules)
+	}
+
+	svc := export.Services[0]
+	if len(svc.Rules) != 3 {
+		t.Errorf("rules count = %d, want 3", len(svc.Rules))
+	}
+}
+
+func TestCombineCarriesRotationURL(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "github", Keyword: "github", Hosts: []string{"api.github.com"}, RotationURL: "https://howtorotate.com/docs/tutorials/github/"},
+		{DirName: "nogl", Keyword: "nogl", Hosts: []string{"api.nogl.com"}, RotationURL: "https://howtorotate.com/docs/tutorials/nogl/"},
+	}
+	glRules := []gitleaks.Rule{{ID: "github-pat", Keyword: "github", Regex: `ghp_[0-9a-zA-Z]{36}`}}
+
+	export := Combine(thDetectors, glRules)
+	if len(export.Services) != 1 || export.Services[0].RotationURL != thDetectors[0].RotationURL {
+		t.Errorf("services = %+v, want github with its rotation_url", export.Services)
+	}
+	if len(export.THOnlyHosts) != 1 || export.THOnlyHosts[0].RotationURL != thDetectors[1].RotationURL {
+		t.Errorf("THOnlyHosts = %+v, want nogl with its rotation_url", export.THOnlyHosts)
+	}
+}
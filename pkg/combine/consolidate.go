@@ -0,0 +1,134 @@
+package combine
+
+import (
+	"sort"
+	"strings"
+)
+
+// minEditKeywordLen is the shortest normalized keyword consolidate treats
+// as a typo of another one edit away; shorter keywords ("aws", "gcs") are
+// too likely to be different services.
+const minEditKeywordLen = 6
+
+// consolidate folds services that are clearly the same into one: the same
+// non-empty host set and near-identical keywords (equal after loose
+// normalization, or one edit apart when at least minEditKeywordLen long).
+// The survivor (see survivorLess) takes the others' rules, hosts and
+// detectors (see unionService) and lists their keywords in merged_from.
+// Order is otherwise preserved.
+func consolidate(services []Service) []Service {
+	byHosts := make(map[string][]int)
+	for i, svc := range services {
+		if len(svc.Hosts) == 0 {
+			continue
+		}
+		hosts := append([]string(nil), svc.Hosts...)
+		sort.Strings(hosts)
+		key := strings.Join(hosts, "\n")
+		byHosts[key] = append(byHosts[key], i)
+	}
+
+	into := make(map[int]int) // service index → survivor index
+	for _, group := range byHosts {
+		for a, i := range group {
+			for _, j := range group[a+1:] {
+				if !nearIdenticalKeywords(services[i].Keyword, services[j].Keyword) {
+					continue
+				}
+				si, sj := survivorOf(into, i), survivorOf(into, j)
+				if si == sj {
+					continue
+				}
+				if survivorLess(services[sj], services[si]) {
+					si, sj = sj, si
+				}
+				into[sj] = si
+			}
+		}
+	}
+	if len(into) == 0 {
+		return services
+	}
+
+	merged := make(map[int]Service)
+	for i := range services {
+		s := survivorOf(into, i)
+		if s == i {
+			continue
+		}
+		base, ok := merged[s]
+		if !ok {
+			base = services[s]
+		}
+		other := services[i]
+		base = unionService(base, other)
+		base.MergedFrom = append(append(base.MergedFrom, other.Keyword), other.MergedFrom...)
+		merged[s] = base
+	}
+	var out []Service
+	for i, svc := range services {
+		if _, gone := into[i]; gone {
+			continue
+		}
+		if m, ok := merged[i]; ok {
+			sort.Strings(m.MergedFrom)
+			svc = m
+		}
+		out = append(out, svc)
+	}
+	return out
+}
+
+func survivorOf(into map[int]int, i int) int {
+	for {
+		next, ok := into[i]
+		if !ok {
+			return i
+		}
+		i = next
+	}
+}
+
+// survivorLess reports whether a should survive rather than b: an exact
+// TruffleHog match names the service canonically, then more rules, then
+// the shorter and alphabetically first keyword.
+func survivorLess(a, b Service) bool {
+	if ea, eb := a.MatchType == "exact", b.MatchType == "exact"; ea != eb {
+		return ea
+	}
+	if len(a.Rules) != len(b.Rules) {
+		return len(a.Rules) > len(b.Rules)
+	}
+	if len(a.Keyword) != len(b.Keyword) {
+		return len(a.Keyword) < len(b.Keyword)
+	}
+	return a.Keyword < b.Keyword
+}
+
+// nearIdenticalKeywords reports whether a and b name the same service.
+func nearIdenticalKeywords(a, b string) bool {
+	na, nb := NormalizeKeywordAt(a, NormalizeLoose), NormalizeKeywordAt(b, NormalizeLoose)
+	if na == nb {
+		return true
+	}
+	return min(len(na), len(nb)) >= minEditKeywordLen && withinOneEdit(na, nb)
+}
+
+// withinOneEdit reports whether a and b differ by at most one inserted,
+// deleted or substituted byte.
+func withinOneEdit(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(b)-len(a) > 1 {
+		return false
+	}
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+	if len(a) == len(b) {
+		return a[min(i+1, len(a)):] == b[min(i+1, len(b)):]
+	}
+	return a[i:] == b[i+1:]
+}
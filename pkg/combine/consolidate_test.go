@@ -0,0 +1,53 @@
+package combine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConsolidate(t *testing.T) {
+	services := []Service{
+		{Keyword: "mailgun", Hosts: []string{"api.mailgun.net"}, MatchType: "exact", MatchedTH: []string{"mailgun"}, Rules: []Rule{{ID: "mailgun-private-api-token"}}},
+		{Keyword: "mailguns", Hosts: []string{"api.mailgun.net"}, Rules: []Rule{{ID: "mailgun-pub-key"}}},
+		{Keyword: "mail-gun", Hosts: []string{"api.mailgun.net"}, Rules: []Rule{{ID: "mailgun-private-api-token"}, {ID: "mailgun-signing-key"}}},
+		{Keyword: "sendgrid", Hosts: []string{"api.sendgrid.com"}, MatchType: "exact", Rules: []Rule{{ID: "sendgrid-api-token"}}},
+		{Keyword: "sendgrd", Hosts: []string{"api.sendgrid.com"}, Rules: []Rule{{ID: "sendgrid-typo"}}},
+		// Same hosts, different services.
+		{Keyword: "github", Hosts: []string{"api.github.com"}, Rules: []Rule{{ID: "github-pat"}}},
+		{Keyword: "github-app", Hosts: []string{"api.github.com"}, Rules: []Rule{{ID: "github-app-token"}}},
+		// Near-identical keywords, different hosts.
+		{Keyword: "pusher", Hosts: []string{"api.pusher.com"}, Rules: []Rule{{ID: "pusher"}}},
+		{Keyword: "pushes", Hosts: []string{"api.pushes.example"}, Rules: []Rule{{ID: "pushes"}}},
+		// No hosts: nothing to compare.
+		{Keyword: "age", Rules: []Rule{{ID: "age-secret-key"}}},
+		{Keyword: "ages", Rules: []Rule{{ID: "age-other"}}},
+	}
+	got := consolidate(services)
+
+	var keywords []string
+	for _, svc := range got {
+		keywords = append(keywords, svc.Keyword)
+	}
+	want := []string{"mailgun", "sendgrid", "github", "github-app", "pusher", "pushes", "age", "ages"}
+	if !reflect.DeepEqual(keywords, want) {
+		t.Fatalf("keywords = %v, want %v", keywords, want)
+	}
+
+	mailgun := got[0]
+	var ids []string
+	for _, r := range mailgun.Rules {
+		ids = append(ids, r.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"mailgun-private-api-token", "mailgun-pub-key", "mailgun-signing-key"}) {
+		t.Errorf("mailgun rules = %v", ids)
+	}
+	if !reflect.DeepEqual(mailgun.MergedFrom, []string{"mail-gun", "mailguns"}) || !reflect.DeepEqual(mailgun.MatchedTH, []string{"mailgun"}) {
+		t.Errorf("mailgun = %+v", mailgun)
+	}
+	if sendgrid := got[1]; !reflect.DeepEqual(sendgrid.MergedFrom, []string{"sendgrd"}) || len(sendgrid.Rules) != 2 {
+		t.Errorf("sendgrid = %+v", sendgrid)
+	}
+	if got[2].MergedFrom != nil || services[0].MergedFrom != nil {
+		t.Error("merged_from set on an untouched service or the input")
+	}
+}
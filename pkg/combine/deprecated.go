@@ -0,0 +1,32 @@
+package combine
+
+import (
+	"encoding/json"
+
+	"secret-detector-export/data"
+)
+
+// deprecatedServices maps normalized keywords of retired services to the
+// reason they were retired.
+var deprecatedServices = mustLoadDeprecatedServices()
+
+func mustLoadDeprecatedServices() map[string]string {
+	var m map[string]string
+	if err := json.Unmarshal(data.DeprecatedServices, &m); err != nil {
+		panic("invalid embedded deprecated_services.json: " + err.Error())
+	}
+	byNorm := make(map[string]string, len(m))
+	for k, v := range m {
+		if v == "" {
+			panic("invalid embedded deprecated_services.json: " + k + " has no reason")
+		}
+		byNorm[NormalizeKeyword(k)] = v
+	}
+	return byNorm
+}
+
+// DeprecationReason returns why a keyword's service is curated as
+// deprecated, or "" when it isn't.
+func DeprecationReason(keyword string) string {
+	return deprecatedServices[NormalizeKeyword(keyword)]
+}
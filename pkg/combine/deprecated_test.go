@@ -0,0 +1,29 @@
+package combine
+
+import (
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestCombineMarksDeprecatedServices(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "rockset", Keyword: "rockset", Hosts: []string{"api.rs2.usw2.rockset.com"}},
+		{DirName: "glitch", Keyword: "glitch", Hosts: []string{"api.glitch.com"}},
+	}
+	glRules := []gitleaks.Rule{
+		{ID: "rockset-api-key", Keyword: "rockset", Regex: `[a-zA-Z0-9]{64}`},
+		{ID: "stripe-access-token", Keyword: "stripe", Regex: `sk_live_[a-z]+`},
+	}
+
+	export := Combine(thDetectors, glRules)
+	for _, svc := range export.Services {
+		if deprecated := svc.Deprecated != ""; deprecated != (svc.Keyword == "rockset") {
+			t.Errorf("%s deprecated = %q", svc.Keyword, svc.Deprecated)
+		}
+	}
+	if len(export.THOnlyHosts) != 1 || export.THOnlyHosts[0].Deprecated == "" {
+		t.Errorf("THOnlyHosts = %+v, want glitch marked deprecated", export.THOnlyHosts)
+	}
+}
@@ -0,0 +1,114 @@
+package combine
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"secret-detector-export/data"
+)
+
+// envNameSuffixes are appended to a service's keyword to generate the env
+// var names it is most likely stored under.
+var envNameSuffixes = []string{"_ACCESS_TOKEN", "_API_KEY", "_API_TOKEN", "_KEY", "_SECRET", "_SECRET_KEY", "_TOKEN"}
+
+// envNameRe matches a portable env var name.
+var envNameRe = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// envNameAdditions maps normalized keywords to curated vendor-specific names.
+var envNameAdditions = mustLoadEnvNames()
+
+// envNamePrefixes maps normalized keywords to curated abbreviations names
+// start with, such as "DD_" for datadog.
+var envNamePrefixes = mustLoadEnvNamePrefixes()
+
+func mustLoadEnvNames() map[string][]string {
+	var m map[string][]string
+	if err := json.Unmarshal(data.EnvNames, &m); err != nil {
+		panic("invalid embedded env_names.json: " + err.Error())
+	}
+	byNorm := make(map[string][]string, len(m))
+	for k, names := range m {
+		for _, n := range names {
+			if !envNameRe.MatchString(n) {
+				panic("invalid embedded env_names.json: bad name for " + k + ": " + n)
+			}
+		}
+		byNorm[NormalizeKeyword(k)] = names
+	}
+	return byNorm
+}
+
+func mustLoadEnvNamePrefixes() map[string][]string {
+	var m map[string][]string
+	if err := json.Unmarshal(data.EnvNamePrefixes, &m); err != nil {
+		panic("invalid embedded env_name_prefixes.json: " + err.Error())
+	}
+	byNorm := make(map[string][]string, len(m))
+	for k, prefixes := range m {
+		for _, p := range prefixes {
+			if !strings.HasSuffix(p, "_") || !envNameRe.MatchString(p) {
+				panic("invalid embedded env_name_prefixes.json: bad prefix for " + k + ": " + p)
+			}
+		}
+		byNorm[NormalizeKeyword(k)] = prefixes
+	}
+	return byNorm
+}
+
+// EnvNames returns the likely env var names for a service, sorted: the
+// keyword upper-cased with non-alphanumerics as "_", and each prefix from
+// data/env_name_prefixes.json, plus each of envNameSuffixes; and the curated
+// additions from data/env_names.json. Generated names that wouldn't be valid
+// env var names (a keyword starting with a digit) are left out.
+func EnvNames(keyword string) []string {
+	set := make(map[string]bool)
+	if base := envNameBase(keyword); base != "" && envNameRe.MatchString(base) {
+		for _, suffix := range envNameSuffixes {
+			set[base+suffix] = true
+		}
+	}
+	for _, prefix := range envNamePrefixes[NormalizeKeyword(keyword)] {
+		for _, suffix := range envNameSuffixes {
+			set[strings.TrimSuffix(prefix, "_")+suffix] = true
+		}
+	}
+	for _, n := range envNameAdditions[NormalizeKeyword(keyword)] {
+		set[n] = true
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	return sortedKeys(set)
+}
+
+// envNameBase upper-cases keyword and turns runs of other characters into
+// a single "_".
+func envNameBase(keyword string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToUpper(keyword) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			sep = false
+		} else {
+			sep = true
+		}
+	}
+	return b.String()
+}
+
+// unionEnvNames merges two sorted name lists.
+func unionEnvNames(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	set := make(map[string]bool, len(a)+len(b))
+	for _, n := range append(append([]string(nil), a...), b...) {
+		set[n] = true
+	}
+	return sortedKeys(set)
+}
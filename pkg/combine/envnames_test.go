@@ -0,0 +1,37 @@
+package combine
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestEnvNames(t *testing.T) {
+	got := EnvNames("new-relic")
+	for _, want := range []string{"NEW_RELIC_API_KEY", "NEW_RELIC_TOKEN", "NEW_RELIC_LICENSE_KEY"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("EnvNames(new-relic) = %v, missing %s", got, want)
+		}
+	}
+	if !slices.IsSorted(got) {
+		t.Errorf("EnvNames(new-relic) = %v, not sorted", got)
+	}
+
+	// Curated names only: a generated 1PASSWORD_TOKEN is not a valid name.
+	if got := EnvNames("1password"); got != nil {
+		t.Errorf("EnvNames(1password) = %v, want nil", got)
+	}
+	want := []string{"AWS_ACCESS_KEY_ID", "AWS_ACCESS_TOKEN", "AWS_API_KEY", "AWS_API_TOKEN", "AWS_KEY", "AWS_SECRET", "AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY", "AWS_SESSION_TOKEN", "AWS_TOKEN"}
+	if got := EnvNames("aws"); !reflect.DeepEqual(got, want) {
+		t.Errorf("EnvNames(aws) = %v, want %v", got, want)
+	}
+}
+
+func TestEnvNamesPrefixes(t *testing.T) {
+	got := EnvNames("datadog")
+	for _, want := range []string{"DATADOG_API_KEY", "DD_API_KEY", "DD_APP_KEY", "DD_TOKEN"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("EnvNames(datadog) = %v, missing %s", got, want)
+		}
+	}
+}
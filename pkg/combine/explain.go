@@ -0,0 +1,158 @@
+package combine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// Explanation traces how Combine binds TruffleHog detectors to one Gitleaks
+// keyword. It is produced by the same matching code Combine runs, so it
+// cannot drift from the real behavior.
+type Explanation struct {
+	Keyword     string             `json:"keyword"`
+	Normalized  string             `json:"normalized"`
+	Rules       []string           `json:"rules,omitempty"` // GL rule IDs grouped under the keyword
+	Steps       []string           `json:"steps"`           // matching strategies in the order tried
+	MatchType   string             `json:"match_type,omitempty"`
+	Hosts       []string           `json:"hosts,omitempty"`
+	PrimaryHost string             `json:"primary_host,omitempty"`
+	Detectors   []DetectorDecision `json:"detectors,omitempty"`
+}
+
+// DetectorDecision says why one TH detector was or wasn't bound.
+type DetectorDecision struct {
+	DirName string `json:"dir_name"`
+	Keyword string `json:"keyword"` // derived keyword, normalized
+	Bound   bool   `json:"bound"`
+	Reason  string `json:"reason"`
+}
+
+func (e *Explanation) step(format string, args ...any) {
+	if e != nil {
+		e.Steps = append(e.Steps, fmt.Sprintf(format, args...))
+	}
+}
+
+// Explain traces the matching of glKeyword against thDetectors. Detector
+// decisions cover every detector that was bound or plausibly could have
+// been: keywords related to the GL keyword by substring or alias. The
+// keyword need not be used by any rule in glRules; the trace then shows
+// what would happen if it were.
+func Explain(glKeyword string, thDetectors []trufflehog.Detector, glRules []gitleaks.Rule) Explanation {
+	return ExplainWith(glKeyword, thDetectors, glRules, Options{})
+}
+
+// ExplainWith is Explain for CombineWith's options.
+func ExplainWith(glKeyword string, thDetectors []trufflehog.Detector, glRules []gitleaks.Rule, opts Options) Explanation {
+	level := opts.Normalization
+	glNorm := NormalizeKeywordAt(glKeyword, level)
+	ex := Explanation{Keyword: glKeyword, Normalized: glNorm}
+	if level == NormalizeLoose {
+		ex.step("normalize: %q → %q (loose: lower-case, strip - _ . and spaces, plural s, number words)", glKeyword, glNorm)
+	} else {
+		ex.step("normalize: %q → %q (lower-case, strip - and _)", glKeyword, glNorm)
+	}
+
+	basic := NormalizeKeyword(glKeyword)
+	for _, r := range glRules {
+		if NormalizeKeyword(r.Keyword) == basic {
+			ex.Rules = append(ex.Rules, r.ID)
+		}
+	}
+	if len(ex.Rules) == 0 {
+		ex.step("gitleaks: no rules use %q; tracing as if one did", basic)
+	} else {
+		ex.step("gitleaks: %d rules grouped under %q", len(ex.Rules), basic)
+	}
+
+	thByKeyword := indexTH(thDetectors, level)
+	matched, matchType := findTHMatch(glKeyword, thByKeyword, sortedKeys(thByKeyword), level, &ex)
+	curated := WellKnownHostsFor(glKeyword)
+	if matchType == "" && len(curated) > 0 {
+		ex.step("curated: no TH detector bound; data/well_known_hosts.json lists %s", strings.Join(curated, ", "))
+		matchType = MatchCurated
+	}
+	ex.MatchType = matchType
+	if matchType == "" {
+		ex.step("result: no TH detector bound; the service is exported without hosts")
+	}
+
+	bound := make(map[string]bool, len(matched))
+	for _, m := range matched {
+		bound[m] = true
+	}
+	aliasNorm := ""
+	if alias, ok := serviceAliasesByNorm[basic]; ok {
+		aliasNorm = NormalizeKeywordAt(alias, level)
+	}
+
+	_, pinned := matchPinsByNorm[basic]
+
+	hostSet := make(map[string]bool)
+	for _, d := range thDetectors {
+		norm := NormalizeKeywordAt(d.Keyword, level)
+		dd := DetectorDecision{DirName: d.DirName, Keyword: norm, Bound: bound[norm]}
+		if dd.Bound {
+			for _, h := range d.Hosts {
+				hostSet[h] = true
+			}
+			dd.Reason = boundReason(matchType, glNorm)
+		} else if dd.Reason = unboundReason(norm, glNorm, aliasNorm, matchType, pinned); dd.Reason == "" {
+			continue // unrelated detector
+		}
+		ex.Detectors = append(ex.Detectors, dd)
+	}
+	sort.Slice(ex.Detectors, func(i, j int) bool {
+		if ex.Detectors[i].Bound != ex.Detectors[j].Bound {
+			return ex.Detectors[i].Bound
+		}
+		return ex.Detectors[i].DirName < ex.Detectors[j].DirName
+	})
+
+	ex.Hosts = sortedKeys(hostSet)
+	if matchType == MatchCurated {
+		ex.Hosts = curated
+	}
+	ex.PrimaryHost = ChoosePrimaryHost(glKeyword, ex.Hosts)
+	if matchType != "" {
+		ex.step("result: %s match, %d detectors, %d hosts", matchType, len(matched), len(ex.Hosts))
+	}
+	return ex
+}
+
+func boundReason(matchType, glNorm string) string {
+	switch matchType {
+	case "exact":
+		return fmt.Sprintf("keyword equals %q", glNorm)
+	case "alias":
+		return fmt.Sprintf("service_aliases.json maps %q to this keyword", glNorm)
+	default:
+		return fmt.Sprintf("keyword starts with %q", glNorm)
+	}
+}
+
+// unboundReason explains why a related detector wasn't bound, or returns ""
+// when the detector is unrelated to the GL keyword.
+func unboundReason(norm, glNorm, aliasNorm, matchType string, pinned bool) string {
+	switch {
+	case norm == "":
+		return ""
+	case norm == aliasNorm:
+		return fmt.Sprintf("alias target of %q, but the exact match took precedence", glNorm)
+	case strings.HasPrefix(norm, glNorm) && len(glNorm) < minPrefixMatchLen:
+		return fmt.Sprintf("starts with %q, but prefix matching needs at least %d chars", glNorm, minPrefixMatchLen)
+	case strings.HasPrefix(norm, glNorm) && pinned && matchType != "exact" && matchType != "alias":
+		return fmt.Sprintf("starts with %q, but match_pins.json doesn't allow it", glNorm)
+	case strings.HasPrefix(norm, glNorm):
+		return fmt.Sprintf("starts with %q, but prefix matching only runs when exact and alias lookups fail (matched by %s)", glNorm, matchType)
+	case strings.Contains(norm, glNorm):
+		return fmt.Sprintf("contains %q but doesn't start with it", glNorm)
+	case strings.Contains(glNorm, norm):
+		return fmt.Sprintf("is a substring of %q; only TH keywords that extend the GL keyword are prefix-matched", glNorm)
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package combine
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestExplain(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "cloudflareapitoken", Keyword: "cloudflare", Hosts: []string{"api.cloudflare.com"}},
+		{DirName: "cloudflarecakey", Keyword: "cloudflareca", Hosts: []string{"ca.cloudflare.com"}},
+		{DirName: "meraki", Keyword: "meraki", Hosts: []string{"api.meraki.com"}},
+		{DirName: "openai", Keyword: "openai", Hosts: []string{"api.openai.com"}},
+	}
+	glRules := []gitleaks.Rule{
+		{ID: "cloudflare-api-key", Keyword: "cloudflare"},
+		{ID: "cloudflare-global-api-key", Keyword: "Cloudflare"},
+	}
+
+	ex := Explain("cloudflare", thDetectors, glRules)
+	if ex.MatchType != "exact" || !reflect.DeepEqual(ex.Hosts, []string{"api.cloudflare.com"}) {
+		t.Errorf("Explain(cloudflare) = %+v", ex)
+	}
+	if !reflect.DeepEqual(ex.Rules, []string{"cloudflare-api-key", "cloudflare-global-api-key"}) {
+		t.Errorf("Rules = %v", ex.Rules)
+	}
+	wantDetectors := []DetectorDecision{
+		{DirName: "cloudflareapitoken", Keyword: "cloudflare", Bound: true, Reason: `keyword equals "cloudflare"`},
+		{DirName: "cloudflarecakey", Keyword: "cloudflareca", Reason: `starts with "cloudflare", but prefix matching only runs when exact and alias lookups fail (matched by exact)`},
+	}
+	if !reflect.DeepEqual(ex.Detectors, wantDetectors) {
+		t.Errorf("Detectors = %+v", ex.Detectors)
+	}
+
+	// Alias lookups are traced, including the failed exact match before them.
+	ex = Explain("Cisco_Meraki", thDetectors, nil)
+	if ex.MatchType != "alias" || ex.Normalized != "ciscomeraki" {
+		t.Errorf("Explain(Cisco_Meraki) = %+v", ex)
+	}
+	wantSteps := []string{
+		`normalize: "Cisco_Meraki" → "ciscomeraki" (lower-case, strip - and _)`,
+		`gitleaks: no rules use "ciscomeraki"; tracing as if one did`,
+		`exact: no TH keyword "ciscomeraki"`,
+		`alias: "ciscomeraki" → "meraki" is a TH keyword`,
+		`result: alias match, 1 detectors, 1 hosts`,
+	}
+	if !reflect.DeepEqual(ex.Steps, wantSteps) {
+		t.Errorf("Steps = %q", ex.Steps)
+	}
+
+	// Short keywords never prefix-match.
+	ex = Explain("ope", thDetectors, nil)
+	if ex.MatchType != "" || len(ex.Detectors) != 1 || ex.Detectors[0].Bound {
+		t.Errorf("Explain(ope) = %+v", ex)
+	}
+}
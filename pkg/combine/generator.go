@@ -0,0 +1,11 @@
+package combine
+
+// Generator identifies the binary and embedded data that produced an export,
+// so a dataset discrepancy can be traced to a build. Like generated_at, it is
+// metadata and excluded from content_hash.
+type Generator struct {
+	Version   string            `json:"version"`
+	Commit    string            `json:"commit,omitempty"` // VCS revision, "-dirty" if built from a modified tree
+	GoVersion string            `json:"go_version"`
+	DataFiles map[string]string `json:"data_files,omitempty"` // embedded data file → content digest
+}
@@ -0,0 +1,38 @@
+package combine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"secret-detector-export/pkg/jsonstream"
+)
+
+// contentHashPrefix identifies the digest algorithm so we can change it later
+// without consumers misinterpreting old values.
+const contentHashPrefix = "sha256:"
+
+// HashJSON returns the prefixed SHA-256 of v's JSON encoding. encoding/json
+// emits struct fields in declaration order and map keys sorted, so the result
+// is deterministic for our export types. The encoding is streamed into the
+// hash rather than held in memory.
+func HashJSON(v any) string {
+	h := sha256.New()
+	if err := jsonstream.Encode(h, v, ""); err != nil {
+		// Export types only contain JSON-safe values; failure is a programming error.
+		panic("hash export: " + err.Error())
+	}
+	return contentHashPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// WithContentHash returns a copy of e with ContentHash set to the digest of
+// its content, excluding GeneratedAt, Generator, Removed, Skipped and the
+// hash itself.
+func (e Export) WithContentHash() Export {
+	generatedAt, generator, removed, skipped := e.GeneratedAt, e.Generator, e.Removed, e.Skipped
+	e.GeneratedAt, e.Generator, e.Removed, e.Skipped = time.Time{}, nil, nil, nil
+	e.ContentHash = ""
+	e.ContentHash = HashJSON(e)
+	e.GeneratedAt, e.Generator, e.Removed, e.Skipped = generatedAt, generator, removed, skipped
+	return e
+}
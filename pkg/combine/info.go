@@ -0,0 +1,44 @@
+package combine
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"secret-detector-export/data"
+)
+
+// ServiceInfo is curated presentation metadata for a service keyword.
+type ServiceInfo struct {
+	DisplayName string `json:"display_name,omitempty"` // brand spelling, e.g. "New Relic"
+	DocsURL     string `json:"docs_url,omitempty"`     // API documentation entry point
+}
+
+// serviceInfo maps normalized keywords to curated display names and docs
+// links. Merged into services at combine time.
+var serviceInfo = mustLoadServiceInfo()
+
+func mustLoadServiceInfo() map[string]ServiceInfo {
+	var m map[string]ServiceInfo
+	if err := json.Unmarshal(data.ServiceInfo, &m); err != nil {
+		panic("invalid embedded service_info.json: " + err.Error())
+	}
+	byNorm := make(map[string]ServiceInfo, len(m))
+	for k, v := range m {
+		if v.DisplayName == "" {
+			panic("invalid embedded service_info.json: " + k + " has no display_name")
+		}
+		if v.DocsURL != "" {
+			if u, err := url.Parse(v.DocsURL); err != nil || u.Scheme != "https" || u.Host == "" {
+				panic("invalid embedded service_info.json: " + k + " has a bad docs_url: " + v.DocsURL)
+			}
+		}
+		byNorm[NormalizeKeyword(k)] = v
+	}
+	return byNorm
+}
+
+// ServiceInfoFor returns the curated display name and docs link for a
+// keyword; both are empty when the keyword isn't curated.
+func ServiceInfoFor(keyword string) ServiceInfo {
+	return serviceInfo[NormalizeKeyword(keyword)]
+}
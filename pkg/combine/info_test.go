@@ -0,0 +1,27 @@
+package combine
+
+import (
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestServiceInfoFor(t *testing.T) {
+	if got := ServiceInfoFor("NEW_RELIC").DisplayName; got != "New Relic" {
+		t.Errorf("ServiceInfoFor(NEW_RELIC).DisplayName = %q, want New Relic", got)
+	}
+	if got := ServiceInfoFor("unknown-service"); got != (ServiceInfo{}) {
+		t.Errorf("ServiceInfoFor(unknown-service) = %+v, want zero", got)
+	}
+}
+
+func TestCombineAddsServiceInfo(t *testing.T) {
+	thDetectors := []trufflehog.Detector{{DirName: "newrelic", Keyword: "newrelic", Hosts: []string{"api.newrelic.com"}}}
+	glRules := []gitleaks.Rule{{ID: "new-relic-user-api-key", Keyword: "newrelic", Regex: `NRAK-[a-z0-9]{27}`}}
+
+	svc := Combine(thDetectors, glRules).Services[0]
+	if svc.DisplayName != "New Relic" || svc.DocsURL == "" {
+		t.Errorf("newrelic display_name = %q, docs_url = %q", svc.DisplayName, svc.DocsURL)
+	}
+}
@@ -0,0 +1,112 @@
+package combine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"secret-detector-export/data"
+)
+
+// serviceAliasesByNorm maps a normalized Gitleaks keyword to a
+// TruffleHog-derived keyword for cases where the names diverge after
+// normalization (data/service_aliases.json).
+var serviceAliasesByNorm = mustLoadServiceAliases()
+
+func mustLoadServiceAliases() map[string]string {
+	var m map[string]string
+	if err := json.Unmarshal(data.ServiceAliases, &m); err != nil {
+		panic("invalid embedded service_aliases.json: " + err.Error())
+	}
+	byNorm := make(map[string]string, len(m))
+	for k, v := range m {
+		byNorm[NormalizeKeyword(k)] = v
+	}
+	return byNorm
+}
+
+// matchPinsByNorm maps a normalized Gitleaks keyword to the TruffleHog
+// keywords it may bind by prefix (data/match_pins.json). A pinned keyword
+// with an empty list binds nothing by prefix. Exact and alias matches are
+// not affected.
+var matchPinsByNorm = mustLoadMatchPins()
+
+func mustLoadMatchPins() map[string][]string {
+	var m map[string][]string
+	if err := json.Unmarshal(data.MatchPins, &m); err != nil {
+		panic("invalid embedded match_pins.json: " + err.Error())
+	}
+	byNorm := make(map[string][]string, len(m))
+	for k, v := range m {
+		byNorm[NormalizeKeyword(k)] = v
+	}
+	return byNorm
+}
+
+// NormalizeKeyword strips hyphens/underscores for fuzzy comparison.
+func NormalizeKeyword(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, "-", "")
+	s = strings.ReplaceAll(s, "_", "")
+	return s
+}
+
+// Normalization selects how keywords are compared when Gitleaks services
+// are matched to TruffleHog detectors.
+type Normalization string
+
+const (
+	// NormalizeBasic is NormalizeKeyword: lower case, "-" and "_" stripped.
+	NormalizeBasic Normalization = "basic"
+	// NormalizeLoose also strips "." and spaces, drops a plural "s"
+	// ("databricks" → "databrick") and writes a number word at either end
+	// as a digit ("authzero" → "auth0", "onepassword" → "1password").
+	NormalizeLoose Normalization = "loose"
+)
+
+// ParseNormalization returns the level named s.
+func ParseNormalization(s string) (Normalization, error) {
+	switch n := Normalization(s); n {
+	case NormalizeBasic, NormalizeLoose:
+		return n, nil
+	}
+	return "", fmt.Errorf("unknown normalization %q: must be %q or %q", s, NormalizeBasic, NormalizeLoose)
+}
+
+// numberWords are spelled-out digits NormalizeLoose rewrites, indexed by
+// their value.
+var numberWords = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+
+// minPluralLen is the shortest keyword NormalizeLoose strips a plural "s"
+// from, so "aws" and "gcs" stay intact.
+const minPluralLen = 5
+
+// NormalizeKeywordAt normalizes s at the given level; the zero value is
+// NormalizeBasic. The transformations are applied to both sides of a
+// comparison, so keywords only need to agree after normalization, not
+// read well.
+func NormalizeKeywordAt(s string, level Normalization) string {
+	s = NormalizeKeyword(s)
+	if level != NormalizeLoose {
+		return s
+	}
+	s = strings.NewReplacer(".", "", " ", "").Replace(s)
+	if len(s) >= minPluralLen && strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss") &&
+		!strings.HasSuffix(s, "us") && !strings.HasSuffix(s, "is") {
+		s = s[:len(s)-1]
+	}
+	for digit, word := range numberWords {
+		if len(s) > len(word) && strings.HasPrefix(s, word) {
+			s = strconv.Itoa(digit) + s[len(word):]
+			break
+		}
+	}
+	for digit, word := range numberWords {
+		if len(s) > len(word) && strings.HasSuffix(s, word) {
+			s = s[:len(s)-len(word)] + strconv.Itoa(digit)
+			break
+		}
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+package combine
+
+import (
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestNormalizeKeyword(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"cloudflare", "cloudflare"},
+		{"Cloudflare", "cloudflare"},
+		{"new-relic", "newrelic"},
+		{"hubspot_apikey", "hubspotapikey"},
+		{"GITHUB", "github"},
+		{"cisco-meraki", "ciscomeraki"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := NormalizeKeyword(tt.input)
+			if got != tt.want {
+				t.Errorf("NormalizeKeyword(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeKeywordLoose(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"databricks", "databrick"},
+		{"Databrick", "databrick"},
+		{"aws", "aws"},           // too short for plural stripping
+		{"status", "status"},     // -us is not a plural
+		{"analysis", "analysis"}, // nor -is
+		{"express", "express"},   // nor -ss
+		{"authzero", "auth0"},
+		{"auth0", "auth0"},
+		{"onepassword", "1password"},
+		{"one", "one"}, // a number word alone stays a word
+		{"Frame.io", "frameio"},
+		{"new relic", "newrelic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeKeywordAt(tt.input, NormalizeLoose); got != tt.want {
+				t.Errorf("NormalizeKeywordAt(%q, loose) = %q, want %q", tt.input, got, tt.want)
+			}
+			if got, want := NormalizeKeywordAt(tt.input, NormalizeBasic), NormalizeKeyword(tt.input); got != want {
+				t.Errorf("NormalizeKeywordAt(%q, basic) = %q, want %q", tt.input, got, want)
+			}
+		})
+	}
+}
+
+func TestCombineLooseNormalizationMatches(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "databricktoken", Keyword: "databrick", Hosts: []string{"accounts.cloud.databricks.com"}},
+		{DirName: "authzero", Keyword: "authzero", Hosts: []string{"auth0.com"}},
+		{DirName: "onepassword", Keyword: "onepassword", Hosts: []string{"my.1password.com"}},
+	}
+	glRules := []gitleaks.Rule{
+		{ID: "databricks-api-token", Keyword: "databricks", Regex: `dapi[a-h0-9]{32}`},
+		{ID: "auth0-client-secret", Keyword: "auth0", Regex: `[a-zA-Z0-9_-]{64}`},
+		{ID: "1password-secret-key", Keyword: "1password", Regex: `A3-[A-Z0-9]{6}`},
+	}
+
+	basic := Combine(thDetectors, glRules)
+	if basic.Stats.ServicesWithHosts != 0 {
+		t.Fatalf("basic normalization matched %d services, want 0", basic.Stats.ServicesWithHosts)
+	}
+
+	loose := CombineWith(thDetectors, glRules, Options{Normalization: NormalizeLoose})
+	if loose.Stats.ServicesWithHosts != 3 || len(loose.THOnlyHosts) != 0 {
+		t.Fatalf("loose normalization: %+v, want all 3 services matched", loose.Stats)
+	}
+	for _, svc := range loose.Services {
+		if svc.MatchType != "exact" {
+			t.Errorf("%s match_type = %q, want exact", svc.Keyword, svc.MatchType)
+		}
+	}
+	if got := loose.Services[0].Keyword; got != "1password" {
+		t.Errorf("services keep their Gitleaks keyword, got %q first", got)
+	}
+}
+
+func TestParseNormalization(t *testing.T) {
+	if n, err := ParseNormalization("loose"); err != nil || n != NormalizeLoose {
+		t.Errorf("ParseNormalization(loose) = %q, %v", n, err)
+	}
+	if _, err := ParseNormalization("fuzzy"); err == nil {
+		t.Error("ParseNormalization(fuzzy) succeeded")
+	}
+}
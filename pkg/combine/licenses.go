@@ -0,0 +1,58 @@
+package combine
+
+import (
+	"encoding/json"
+
+	"secret-detector-export/data"
+)
+
+// License credits one source an export is derived from and states the terms
+// that part is redistributed under. The block is content, not metadata: it
+// is covered by content_hash.
+type License struct {
+	Source     string `json:"source"`
+	Repository string `json:"repository"`
+	License    string `json:"license"` // SPDX identifier of the source project
+	Copyright  string `json:"copyright"`
+	Covers     string `json:"covers"` // which export fields derive from the source
+	Notice     string `json:"notice"`
+}
+
+// licenses is the curated attribution block, loaded from
+// data/licenses.json.
+var licenses = mustLoadLicenses()
+
+func mustLoadLicenses() []License {
+	var l []License
+	if err := json.Unmarshal(data.Licenses, &l); err != nil {
+		panic("invalid embedded licenses.json: " + err.Error())
+	}
+	for _, e := range l {
+		if e.Source == "" || e.License == "" || e.Copyright == "" {
+			panic("invalid embedded licenses.json: source, license and copyright are required")
+		}
+	}
+	return l
+}
+
+// Licenses returns the attribution block Combine embeds in exports.
+func Licenses() []License {
+	return append([]License(nil), licenses...)
+}
+
+// mergeLicenses returns first's entries followed by second's for sources
+// first doesn't credit.
+func mergeLicenses(first, second []License) []License {
+	seen := make(map[string]bool, len(first))
+	out := append([]License(nil), first...)
+	for _, l := range first {
+		seen[l.Source] = true
+	}
+	for _, l := range second {
+		if !seen[l.Source] {
+			seen[l.Source] = true
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package combine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLicenses(t *testing.T) {
+	got := make(map[string]License)
+	for _, l := range Licenses() {
+		got[l.Source] = l
+	}
+	if l := got["gitleaks"]; l.License != "MIT" || l.Copyright == "" {
+		t.Errorf("gitleaks license = %+v, want MIT with a copyright line", l)
+	}
+	if l := got["trufflehog"]; l.Notice == "" || l.Copyright == "" {
+		t.Errorf("trufflehog license = %+v, want a notice and a copyright line", l)
+	}
+
+	full := Combine(nil, nil)
+	if !reflect.DeepEqual(full.Licenses, Licenses()) {
+		t.Errorf("Combine licenses = %+v", full.Licenses)
+	}
+	stripped := full
+	stripped.Licenses = nil
+	if stripped.WithContentHash().ContentHash == full.ContentHash {
+		t.Error("licenses are not covered by content_hash")
+	}
+}
+
+func TestMergeLicenses(t *testing.T) {
+	public, internal := mergeFixtures()
+	public.Licenses = Licenses()
+	internal.Licenses = []License{{Source: "gitleaks", License: "other"}, {Source: "acme-internal", License: "proprietary"}}
+	merged, err := Merge(public, internal, MergePreferNewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(Licenses(), internal.Licenses[1])
+	if !reflect.DeepEqual(merged.Licenses, want) {
+		t.Errorf("merged licenses = %+v, want %+v", merged.Licenses, want)
+	}
+}
@@ -0,0 +1,162 @@
+package combine
+
+import (
+	"fmt"
+	"sort"
+
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// Merge strategies decide which side wins when both exports define the same
+// service (by normalized keyword) or TH-only detector (by dir name).
+const (
+	MergePreferFirst = "prefer-first" // keep the first export's entry
+	MergePreferNewer = "prefer-newer" // keep the entry from the export with the later generated_at
+	MergeUnionHosts  = "union-hosts"  // keep the first entry, adding the other's hosts and rules
+)
+
+// IsValidMergeStrategy reports whether s is a known merge strategy.
+func IsValidMergeStrategy(s string) bool {
+	switch s {
+	case MergePreferFirst, MergePreferNewer, MergeUnionHosts:
+		return true
+	}
+	return false
+}
+
+// Merge layers two full exports, e.g. an internal dataset over the public
+// one. Entries present in only one export are kept as-is; conflicts are
+// resolved by strategy, and near-duplicate services are consolidated (see
+// consolidate). Stats, GLNoHosts and the content hash are
+// recomputed, and generated_at is the later of the two.
+func Merge(first, second Export, strategy string) (Export, error) {
+	if !IsValidMergeStrategy(strategy) {
+		return Export{}, fmt.Errorf("unknown merge strategy %q (want %s, %s, or %s)", strategy, MergePreferFirst, MergePreferNewer, MergeUnionHosts)
+	}
+	// With prefer-newer, a strictly newer second export wins; otherwise the
+	// first does, so ties are deterministic.
+	secondWins := strategy == MergePreferNewer && second.GeneratedAt.After(first.GeneratedAt)
+
+	services := make(map[string]Service, len(first.Services)+len(second.Services))
+	for _, svc := range first.Services {
+		services[NormalizeKeyword(svc.Keyword)] = svc
+	}
+	for _, svc := range second.Services {
+		key := NormalizeKeyword(svc.Keyword)
+		prev, ok := services[key]
+		switch {
+		case !ok || secondWins:
+			services[key] = svc
+		case strategy == MergeUnionHosts:
+			services[key] = unionService(prev, svc)
+		}
+	}
+
+	thOnly := make(map[string]THOnlyEntry, len(first.THOnlyHosts)+len(second.THOnlyHosts))
+	for _, th := range first.THOnlyHosts {
+		thOnly[th.DirName] = th
+	}
+	for _, th := range second.THOnlyHosts {
+		prev, ok := thOnly[th.DirName]
+		switch {
+		case !ok || secondWins:
+			thOnly[th.DirName] = th
+		case strategy == MergeUnionHosts:
+			prev.Hosts, prev.PathPrefixes = unionHosts(prev.Hosts, prev.PathPrefixes, th.Hosts, th.PathPrefixes)
+			if prev.RotationURL == "" {
+				prev.RotationURL = th.RotationURL
+			}
+			prev.HostTemplates = trufflehog.MergeHostTemplates(prev.HostTemplates, th.HostTemplates)
+			thOnly[th.DirName] = prev
+		}
+	}
+
+	merged := Export{GeneratedAt: first.GeneratedAt, Licenses: mergeLicenses(first.Licenses, second.Licenses)}
+	if second.GeneratedAt.After(first.GeneratedAt) {
+		merged.GeneratedAt = second.GeneratedAt
+	}
+	for _, key := range sortedKeys(services) {
+		merged.Services = append(merged.Services, services[key])
+	}
+	merged.Services = consolidate(merged.Services)
+	for _, dir := range sortedKeys(thOnly) {
+		merged.THOnlyHosts = append(merged.THOnlyHosts, thOnly[dir])
+	}
+	sort.SliceStable(merged.THOnlyHosts, func(i, j int) bool {
+		return merged.THOnlyHosts[i].Keyword < merged.THOnlyHosts[j].Keyword
+	})
+	merged.Stats, merged.GLNoHosts = summarize(merged.Services, merged.THOnlyHosts)
+	return merged.WithContentHash(), nil
+}
+
+// unionService adds other's hosts, rules (by ID), regional hosts and matched
+// detectors to base, then re-derives the primary host and host roles.
+func unionService(base, other Service) Service {
+	base.Hosts, base.PathPrefixes = unionHosts(base.Hosts, base.PathPrefixes, other.Hosts, other.PathPrefixes)
+
+	ruleIDs := make(map[string]bool, len(base.Rules))
+	for _, r := range base.Rules {
+		ruleIDs[r.ID] = true
+	}
+	base.Rules = append([]Rule(nil), base.Rules...)
+	for _, r := range other.Rules {
+		if !ruleIDs[r.ID] {
+			base.Rules = append(base.Rules, r)
+		}
+	}
+
+	regionSeen := make(map[string]bool, len(base.RegionalHosts))
+	for _, rh := range base.RegionalHosts {
+		regionSeen[rh.Host] = true
+	}
+	base.RegionalHosts = append([]RegionalHost(nil), base.RegionalHosts...)
+	for _, rh := range other.RegionalHosts {
+		if !regionSeen[rh.Host] {
+			base.RegionalHosts = append(base.RegionalHosts, rh)
+		}
+	}
+
+	base.EnvNames = unionEnvNames(base.EnvNames, other.EnvNames)
+	base.HostTemplates = trufflehog.MergeHostTemplates(base.HostTemplates, other.HostTemplates)
+
+	matched := make(map[string]bool)
+	for _, m := range append(append([]string(nil), base.MatchedTH...), other.MatchedTH...) {
+		matched[m] = true
+	}
+	base.MatchedTH = sortedKeys(matched)
+	if base.MatchType == "" {
+		base.MatchType = other.MatchType
+	}
+	if base.Category == "" {
+		base.Category = other.Category
+	}
+	if base.DisplayName == "" {
+		base.DisplayName, base.DocsURL = other.DisplayName, other.DocsURL
+	}
+	if base.RotationURL == "" {
+		base.RotationURL = other.RotationURL
+	}
+	if base.Deprecated == "" {
+		base.Deprecated = other.Deprecated
+	}
+
+	base.PrimaryHost = ChoosePrimaryHost(base.Keyword, base.Hosts)
+	base.HostRoles = ClassifyHostRoles(base.AllowedHosts(), base.PathPrefixes)
+	return base
+}
+
+// unionHosts merges two host lists with their path prefixes. A host that
+// either side forwards unscoped stays unscoped.
+func unionHosts(aHosts []string, aPrefixes map[string][]string, bHosts []string, bPrefixes map[string][]string) ([]string, map[string][]string) {
+	set := make(map[string]bool, len(aHosts)+len(bHosts))
+	for _, h := range aHosts {
+		set[h] = true
+	}
+	for _, h := range bHosts {
+		set[h] = true
+	}
+	prefixes := trufflehog.NewPathPrefixSet()
+	prefixes.AddAll(aHosts, aPrefixes)
+	prefixes.AddAll(bHosts, bPrefixes)
+	return sortedKeys(set), prefixes.Result()
+}
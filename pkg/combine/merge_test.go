@@ -0,0 +1,93 @@
+package combine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mergeFixtures() (Export, Export) {
+	public := Export{
+		GeneratedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Services: []Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com"}, MatchType: "exact", Rules: []Rule{{ID: "stripe-access-token", Regex: `sk_live_\w+`}}},
+			{Keyword: "noth", Rules: []Rule{{ID: "noth-secret", Regex: `noth-\w+`}}},
+		},
+		THOnlyHosts: []THOnlyEntry{{Keyword: "nogl", DirName: "nogl", Hosts: []string{"api.nogl.com"}}},
+	}
+	internal := Export{
+		GeneratedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Services: []Service{
+			{Keyword: "Stripe", Hosts: []string{"stripe.internal.example.com"}, Rules: []Rule{{ID: "stripe-access-token", Regex: `changed`}, {ID: "stripe-internal", Regex: `sk_int_\w+`}}},
+			{Keyword: "acme", Hosts: []string{"api.acme.example.com"}, Rules: []Rule{{ID: "acme-token", Regex: `acme_\w+`}}},
+		},
+		THOnlyHosts: []THOnlyEntry{{Keyword: "nogl", DirName: "nogl", Hosts: []string{"eu.nogl.com"}}},
+	}
+	return public, internal
+}
+
+func TestMergePreferFirst(t *testing.T) {
+	public, internal := mergeFixtures()
+	got, err := Merge(public, internal, MergePreferFirst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keywords []string
+	for _, svc := range got.Services {
+		keywords = append(keywords, svc.Keyword)
+	}
+	if !reflect.DeepEqual(keywords, []string{"acme", "noth", "stripe"}) {
+		t.Errorf("keywords = %v", keywords)
+	}
+	if hosts := got.Services[2].Hosts; !reflect.DeepEqual(hosts, []string{"api.stripe.com"}) {
+		t.Errorf("stripe hosts = %v, want first export's", hosts)
+	}
+	if !got.GeneratedAt.Equal(internal.GeneratedAt) {
+		t.Errorf("GeneratedAt = %v, want the later one", got.GeneratedAt)
+	}
+	want := Stats{TotalServices: 4, ServicesWithHosts: 2, ServicesNoHosts: 1, THOnlyServices: 1, TotalRules: 3, RulesWithHosts: 2, MatchExact: 1}
+	if got.Stats != want {
+		t.Errorf("Stats = %+v, want %+v", got.Stats, want)
+	}
+	if !reflect.DeepEqual(got.GLNoHosts, []string{"noth"}) {
+		t.Errorf("GLNoHosts = %v", got.GLNoHosts)
+	}
+	if got.ContentHash == "" || got.ContentHash != got.WithContentHash().ContentHash {
+		t.Error("content hash not recomputed")
+	}
+}
+
+func TestMergePreferNewer(t *testing.T) {
+	public, internal := mergeFixtures()
+	got, _ := Merge(public, internal, MergePreferNewer)
+	if got.Services[2].Keyword != "Stripe" || got.THOnlyHosts[0].Hosts[0] != "eu.nogl.com" {
+		t.Errorf("prefer-newer kept older entries: %+v", got)
+	}
+	// Order of arguments doesn't matter: the newer export still wins.
+	swapped, _ := Merge(internal, public, MergePreferNewer)
+	if swapped.Services[2].Keyword != "Stripe" {
+		t.Errorf("prefer-newer (swapped) stripe = %+v", swapped.Services[2])
+	}
+}
+
+func TestMergeUnionHosts(t *testing.T) {
+	public, internal := mergeFixtures()
+	got, _ := Merge(public, internal, MergeUnionHosts)
+	stripe := got.Services[2]
+	if !reflect.DeepEqual(stripe.Hosts, []string{"api.stripe.com", "stripe.internal.example.com"}) {
+		t.Errorf("stripe hosts = %v", stripe.Hosts)
+	}
+	if len(stripe.Rules) != 2 || stripe.Rules[0].Regex != `sk_live_\w+` || stripe.Rules[1].ID != "stripe-internal" {
+		t.Errorf("stripe rules = %+v, want first's version of shared IDs plus new ones", stripe.Rules)
+	}
+	if stripe.PrimaryHost != "api.stripe.com" {
+		t.Errorf("stripe primary host = %q", stripe.PrimaryHost)
+	}
+	if !reflect.DeepEqual(got.THOnlyHosts[0].Hosts, []string{"api.nogl.com", "eu.nogl.com"}) {
+		t.Errorf("nogl hosts = %v", got.THOnlyHosts[0].Hosts)
+	}
+
+	if _, err := Merge(public, internal, "last-wins"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
@@ -0,0 +1,90 @@
+package combine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"secret-detector-export/data"
+)
+
+// Policy hints tell the consumer what to do with a value that matches a
+// pattern. They are advisory; Gondolin makes the final decision.
+const (
+	PolicyBlock   = "block"   // never let the value leave the sandbox
+	PolicyRedact  = "redact"  // replace the value before it is forwarded
+	PolicyForward = "forward" // forward to the service's hosts only
+)
+
+type patternPolicyFile struct {
+	Rules      map[string]string `json:"rules"`
+	Categories map[string]string `json:"categories"`
+}
+
+// serviceCategories assigns curated categories to service keywords. Keys are
+// normalized at load time.
+var serviceCategories = mustLoadServiceCategories()
+
+// patternPolicy maps rule IDs and categories to policy hints. A rule ID entry
+// takes precedence over its service's category entry.
+var patternPolicy = mustLoadPatternPolicy()
+
+func mustLoadServiceCategories() map[string]string {
+	var m map[string]string
+	if err := json.Unmarshal(data.ServiceCategories, &m); err != nil {
+		panic("invalid embedded service_categories.json: " + err.Error())
+	}
+	byNorm := make(map[string]string, len(m))
+	for k, v := range m {
+		byNorm[NormalizeKeyword(k)] = v
+	}
+	return byNorm
+}
+
+func mustLoadPatternPolicy() patternPolicyFile {
+	var p patternPolicyFile
+	if err := json.Unmarshal(data.PatternPolicy, &p); err != nil {
+		panic("invalid embedded pattern_policy.json: " + err.Error())
+	}
+	for _, m := range []map[string]string{p.Rules, p.Categories} {
+		for k, v := range m {
+			if !IsValidPolicy(v) {
+				panic(fmt.Sprintf("invalid embedded pattern_policy.json: %q has unknown policy %q", k, v))
+			}
+		}
+	}
+	return p
+}
+
+func IsValidPolicy(p string) bool {
+	switch p {
+	case PolicyBlock, PolicyRedact, PolicyForward:
+		return true
+	}
+	return false
+}
+
+// ServiceCategory returns the curated category for a keyword, or "".
+func ServiceCategory(keyword string) string {
+	return serviceCategories[NormalizeKeyword(keyword)]
+}
+
+// Categories returns the curated categories, sorted.
+func Categories() []string {
+	seen := make(map[string]bool)
+	for _, c := range serviceCategories {
+		seen[c] = true
+	}
+	return sortedKeys(seen)
+}
+
+// PolicyHint returns the policy hint for a rule, preferring a rule-ID entry
+// over the category entry. Returns "" when neither is curated.
+func PolicyHint(ruleID, category string) string {
+	if p, ok := patternPolicy.Rules[ruleID]; ok {
+		return p
+	}
+	if category != "" {
+		return patternPolicy.Categories[category]
+	}
+	return ""
+}
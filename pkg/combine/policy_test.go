@@ -0,0 +1,51 @@
+package combine
+
+import (
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+)
+
+func TestPolicyHint(t *testing.T) {
+	tests := []struct {
+		ruleID, category, want string
+	}{
+		{"private-key", "", PolicyBlock},                 // rule entry, no category
+		{"slack-webhook-url", "messaging", PolicyRedact}, // rule entry beats category
+		{"slack-bot-token", "messaging", PolicyForward},  // category fallback
+		{"some-unknown-rule", "", ""},                    // nothing curated
+		{"some-unknown-rule", "no-such-category", ""},    // unknown category
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ruleID+"/"+tt.category, func(t *testing.T) {
+			if got := PolicyHint(tt.ruleID, tt.category); got != tt.want {
+				t.Errorf("PolicyHint(%q, %q) = %q, want %q", tt.ruleID, tt.category, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombineAssignsCategoryAndPolicy(t *testing.T) {
+	glRules := []gitleaks.Rule{
+		{ID: "stripe-access-token", Keyword: "stripe", Regex: `sk_live_[a-z]+`},
+		{ID: "noth-secret", Keyword: "noth", Regex: `noth-[a-z]{10}`},
+	}
+
+	export := Combine(nil, glRules)
+	for _, svc := range export.Services {
+		switch svc.Keyword {
+		case "stripe":
+			if svc.Category != "payments" {
+				t.Errorf("stripe category = %q, want payments", svc.Category)
+			}
+			if svc.Rules[0].Policy != PolicyRedact {
+				t.Errorf("stripe rule policy = %q, want %q", svc.Rules[0].Policy, PolicyRedact)
+			}
+		case "noth":
+			if svc.Category != "" || svc.Rules[0].Policy != "" {
+				t.Errorf("noth category/policy = %q/%q, want empty", svc.Category, svc.Rules[0].Policy)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package combine
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"secret-detector-export/data"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// primaryHostOverrides pins the primary host for services where the
+// heuristic in ChoosePrimaryHost picks the wrong endpoint.
+var primaryHostOverrides = mustLoadPrimaryHostOverrides()
+
+func mustLoadPrimaryHostOverrides() map[string]string {
+	var m map[string]string
+	if err := json.Unmarshal(data.PrimaryHostOverrides, &m); err != nil {
+		panic("invalid embedded primary_host_overrides.json: " + err.Error())
+	}
+	byNorm := make(map[string]string, len(m))
+	for k, v := range m {
+		host, err := trufflehog.NormalizeCuratedHost(v)
+		if err != nil {
+			panic("invalid embedded primary_host_overrides.json: " + err.Error())
+		}
+		byNorm[NormalizeKeyword(k)] = host
+	}
+	return byNorm
+}
+
+// ChoosePrimaryHost picks the single host a consumer should allow when it can
+// only allow one per secret. A curated override wins if it is one of the
+// service's hosts; otherwise concrete hosts are ranked by:
+//  1. "api." prefix, then any "api" label, then everything else
+//  2. fewer DNS labels (api.stripe.com over api.eu.stripe.com)
+//  3. alphabetical
+//
+// Returns "" when there are no concrete (non-wildcard) hosts.
+func ChoosePrimaryHost(keyword string, hosts []string) string {
+	if override, ok := primaryHostOverrides[NormalizeKeyword(keyword)]; ok {
+		for _, h := range hosts {
+			if h == override {
+				return h
+			}
+		}
+	}
+
+	var candidates []string
+	for _, h := range hosts {
+		if !strings.HasPrefix(h, "*.") {
+			candidates = append(candidates, h)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if ra, rb := apiHostRank(a), apiHostRank(b); ra != rb {
+			return ra < rb
+		}
+		if la, lb := strings.Count(a, "."), strings.Count(b, "."); la != lb {
+			return la < lb
+		}
+		return a < b
+	})
+	return candidates[0]
+}
+
+func apiHostRank(host string) int {
+	if strings.HasPrefix(host, "api.") {
+		return 0
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "api" || strings.HasPrefix(label, "api-") || strings.HasSuffix(label, "-api") {
+			return 1
+		}
+	}
+	return 2
+}
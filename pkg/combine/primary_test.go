@@ -0,0 +1,29 @@
+package combine
+
+import "testing"
+
+func TestChoosePrimaryHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyword string
+		hosts   []string
+		want    string
+	}{
+		{"api prefix wins", "stripe", []string{"dashboard.stripe.com", "api.stripe.com"}, "api.stripe.com"},
+		{"fewer labels wins", "example", []string{"api.eu.example.com", "api.example.com"}, "api.example.com"},
+		{"api label ranks above plain", "foo", []string{"foo.com", "eu.api.foo.com"}, "eu.api.foo.com"},
+		{"alphabetical tie-break", "foo", []string{"b.foo.com", "a.foo.com"}, "a.foo.com"},
+		{"wildcards are never primary", "aws", []string{"*.amazonaws.com"}, ""},
+		{"override wins when present", "slack", []string{"api.slack.com", "slack.com"}, "slack.com"},
+		{"override ignored when absent", "slack", []string{"hooks.slack.com", "api.slack.com"}, "api.slack.com"},
+		{"no hosts", "age", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChoosePrimaryHost(tt.keyword, tt.hosts); got != tt.want {
+				t.Errorf("ChoosePrimaryHost(%q, %v) = %q, want %q", tt.keyword, tt.hosts, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package combine
+
+// WithoutHosts returns a copy of e with every service and TH-only host for
+// which drop returns true removed. Derived fields (primary host, path
+// prefixes, host roles, stats, gl_no_hosts, content hash) are updated; TH-only
+// entries left without hosts are removed. Curated regional hosts are kept.
+func (e Export) WithoutHosts(drop func(host string) bool) Export {
+	services := make([]Service, len(e.Services))
+	for i, svc := range e.Services {
+		kept := keepHosts(svc.Hosts, drop)
+		if len(kept) != len(svc.Hosts) {
+			svc.Hosts = kept
+			svc.PathPrefixes = keepPrefixes(svc.PathPrefixes, kept)
+			svc.PrimaryHost = ChoosePrimaryHost(svc.Keyword, kept)
+			svc.HostRoles = ClassifyHostRoles(svc.AllowedHosts(), svc.PathPrefixes)
+		}
+		services[i] = svc
+	}
+	var thOnly []THOnlyEntry
+	for _, th := range e.THOnlyHosts {
+		th.Hosts = keepHosts(th.Hosts, drop)
+		if len(th.Hosts) == 0 {
+			continue
+		}
+		th.PathPrefixes = keepPrefixes(th.PathPrefixes, th.Hosts)
+		thOnly = append(thOnly, th)
+	}
+	e.Services, e.THOnlyHosts = services, thOnly
+	e.Stats, e.GLNoHosts = summarize(e.Services, e.THOnlyHosts)
+	return e.WithContentHash()
+}
+
+// WithServices returns a copy of e with only the services and TH-only
+// entries for whose keyword keep returns true. Stats, gl_no_hosts and the
+// content hash are updated.
+func (e Export) WithServices(keep func(keyword string) bool) Export {
+	var services []Service
+	for _, svc := range e.Services {
+		if keep(svc.Keyword) {
+			services = append(services, svc)
+		}
+	}
+	var thOnly []THOnlyEntry
+	for _, th := range e.THOnlyHosts {
+		if keep(th.Keyword) {
+			thOnly = append(thOnly, th)
+		}
+	}
+	e.Services, e.THOnlyHosts = services, thOnly
+	e.Stats, e.GLNoHosts = summarize(e.Services, e.THOnlyHosts)
+	return e.WithContentHash()
+}
+
+// WithUnresolvedHosts returns a copy of e with unresolved_hosts set on every
+// service and TH-only entry that has a host for which dead returns true.
+// Hosts stay in place; consumers decide what to do with them.
+func (e Export) WithUnresolvedHosts(dead func(host string) bool) Export {
+	services := make([]Service, len(e.Services))
+	for i, svc := range e.Services {
+		svc.UnresolvedHosts = selectHosts(svc.Hosts, dead)
+		services[i] = svc
+	}
+	thOnly := make([]THOnlyEntry, len(e.THOnlyHosts))
+	for i, th := range e.THOnlyHosts {
+		th.UnresolvedHosts = selectHosts(th.Hosts, dead)
+		thOnly[i] = th
+	}
+	e.Services, e.THOnlyHosts = services, thOnly
+	return e.WithContentHash()
+}
+
+func keepHosts(hosts []string, drop func(string) bool) []string {
+	return selectHosts(hosts, func(h string) bool { return !drop(h) })
+}
+
+func selectHosts(hosts []string, pred func(string) bool) []string {
+	var out []string
+	for _, h := range hosts {
+		if pred(h) {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+func keepPrefixes(prefixes map[string][]string, hosts []string) map[string][]string {
+	var out map[string][]string
+	for _, h := range hosts {
+		if ps, ok := prefixes[h]; ok {
+			if out == nil {
+				out = make(map[string][]string)
+			}
+			out[h] = ps
+		}
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+package combine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func pruneFixture() Export {
+	return Export{
+		Services: []Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com", "old.stripe.com"}, PrimaryHost: "old.stripe.com",
+				PathPrefixes: map[string][]string{"old.stripe.com": {"/v1"}}, MatchType: "exact", Rules: []Rule{{ID: "stripe-access-token"}}},
+			{Keyword: "gone", Hosts: []string{"api.gone.example"}, MatchType: "exact", Rules: []Rule{{ID: "gone-key"}}},
+		},
+		THOnlyHosts: []THOnlyEntry{{Keyword: "nogl", DirName: "nogl", Hosts: []string{"api.nogl.example"}}},
+	}
+}
+
+func TestWithoutHosts(t *testing.T) {
+	dead := map[string]bool{"old.stripe.com": true, "api.gone.example": true, "api.nogl.example": true}
+	got := pruneFixture().WithoutHosts(func(h string) bool { return dead[h] })
+
+	stripe := got.Services[0]
+	if !reflect.DeepEqual(stripe.Hosts, []string{"api.stripe.com"}) || stripe.PathPrefixes != nil || stripe.PrimaryHost != "api.stripe.com" {
+		t.Errorf("stripe = %+v", stripe)
+	}
+	if got.Services[1].Hosts != nil || len(got.THOnlyHosts) != 0 {
+		t.Errorf("hosts left after pruning: %+v / %+v", got.Services[1], got.THOnlyHosts)
+	}
+	if !reflect.DeepEqual(got.GLNoHosts, []string{"gone"}) || got.Stats.ServicesWithHosts != 1 || got.Stats.THOnlyServices != 0 {
+		t.Errorf("stats not recomputed: %+v, gl_no_hosts %v", got.Stats, got.GLNoHosts)
+	}
+	if got.ContentHash == "" {
+		t.Error("content hash not set")
+	}
+}
+
+func TestWithServices(t *testing.T) {
+	got := pruneFixture().WithServices(func(k string) bool { return k != "stripe" })
+	if len(got.Services) != 1 || got.Services[0].Keyword != "gone" || len(got.THOnlyHosts) != 1 {
+		t.Errorf("services = %+v, th_only = %+v", got.Services, got.THOnlyHosts)
+	}
+	if got.Stats.TotalServices != 2 || got.Stats.TotalRules != 1 || got.ContentHash == "" {
+		t.Errorf("stats = %+v, content hash %q", got.Stats, got.ContentHash)
+	}
+}
+
+func TestWithUnresolvedHosts(t *testing.T) {
+	got := pruneFixture().WithUnresolvedHosts(func(h string) bool { return h == "old.stripe.com" })
+	if !reflect.DeepEqual(got.Services[0].UnresolvedHosts, []string{"old.stripe.com"}) || len(got.Services[0].Hosts) != 2 {
+		t.Errorf("stripe = %+v", got.Services[0])
+	}
+	if got.Services[1].UnresolvedHosts != nil || got.THOnlyHosts[0].UnresolvedHosts != nil {
+		t.Error("unexpected annotations")
+	}
+}
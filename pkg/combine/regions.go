@@ -0,0 +1,66 @@
+package combine
+
+import (
+	"encoding/json"
+	"sort"
+
+	"secret-detector-export/data"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// RegionalHost is a curated alternate endpoint for a service, e.g. the EU
+// instance of an API whose TruffleHog verifier only targets the US one.
+type RegionalHost struct {
+	Host   string `json:"host"`
+	Region string `json:"region"`
+}
+
+// regionalHosts maps service keywords to curated regional endpoints. Merged
+// into services at combine time.
+var regionalHosts = mustLoadRegionalHosts()
+
+func mustLoadRegionalHosts() map[string][]RegionalHost {
+	var m map[string][]RegionalHost
+	if err := json.Unmarshal(data.RegionalHosts, &m); err != nil {
+		panic("invalid embedded regional_hosts.json: " + err.Error())
+	}
+	byNorm := make(map[string][]RegionalHost, len(m))
+	for k, v := range m {
+		for i, rh := range v {
+			host, err := trufflehog.NormalizeCuratedHost(rh.Host)
+			if err != nil || rh.Region == "" || trufflehog.IsNoiseHost(host, false) {
+				panic("invalid embedded regional_hosts.json: bad entry for " + k + ": " + rh.Host)
+			}
+			v[i].Host = host
+		}
+		sort.Slice(v, func(i, j int) bool { return v[i].Host < v[j].Host })
+		byNorm[NormalizeKeyword(k)] = v
+	}
+	return byNorm
+}
+
+// RegionalHostsFor returns the curated regional endpoints for a keyword.
+func RegionalHostsFor(keyword string) []RegionalHost {
+	return regionalHosts[NormalizeKeyword(keyword)]
+}
+
+// HostsWithRegional returns hosts followed by any regional hosts not already
+// present, preserving order. Regional hosts are alternates to the primary
+// ones, so a service without hosts gets none.
+func HostsWithRegional(hosts []string, regional []RegionalHost) []string {
+	if len(hosts) == 0 || len(regional) == 0 {
+		return hosts
+	}
+	seen := make(map[string]bool, len(hosts))
+	out := append([]string(nil), hosts...)
+	for _, h := range hosts {
+		seen[h] = true
+	}
+	for _, rh := range regional {
+		if !seen[rh.Host] {
+			seen[rh.Host] = true
+			out = append(out, rh.Host)
+		}
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package combine
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestHostsWithRegional(t *testing.T) {
+	got := HostsWithRegional(
+		[]string{"api.datadoghq.com", "api.datadoghq.eu"},
+		[]RegionalHost{{Host: "api.datadoghq.eu", Region: "eu1"}, {Host: "api.us3.datadoghq.com", Region: "us3"}},
+	)
+	want := []string{"api.datadoghq.com", "api.datadoghq.eu", "api.us3.datadoghq.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HostsWithRegional = %v, want %v", got, want)
+	}
+	if got := HostsWithRegional(nil, []RegionalHost{{Host: "api.datadoghq.eu", Region: "eu1"}}); len(got) != 0 {
+		t.Errorf("HostsWithRegional without primary hosts = %v, want none", got)
+	}
+}
+
+func TestCombineAddsRegionalHosts(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "datadogtoken", Keyword: "datadog", Hosts: []string{"api.datadoghq.com"}},
+	}
+	glRules := []gitleaks.Rule{
+		{ID: "datadog-access-token", Keyword: "datadog", Regex: `[a-f0-9]{40}`},
+	}
+
+	export := Combine(thDetectors, glRules)
+	svc := export.Services[0]
+	if len(svc.RegionalHosts) == 0 {
+		t.Fatal("datadog should have curated regional hosts")
+	}
+	if len(svc.Hosts) != 1 {
+		t.Errorf("regional hosts must not be mixed into Hosts, got %v", svc.Hosts)
+	}
+}
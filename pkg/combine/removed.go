@@ -0,0 +1,34 @@
+package combine
+
+// Removed is the tombstone section of an export regenerated with
+// -previous: what the previous export had and this one doesn't, so
+// consumers can revoke allowlist entries instead of only no longer adding
+// them. It describes the transition, not the dataset, so it is left out of
+// content_hash.
+type Removed struct {
+	Previous string           `json:"previous"` // content_hash of the previous export
+	Services []RemovedService `json:"services,omitempty"`
+	Hosts    []RemovedHost    `json:"hosts,omitempty"` // hosts lost by services that remain
+	Rules    []RemovedRule    `json:"rules,omitempty"`
+}
+
+// RemovedService is a keyword that no longer exists, with the hosts it
+// mapped to.
+type RemovedService struct {
+	Keyword string   `json:"keyword"`
+	Hosts   []string `json:"hosts,omitempty"`
+	Reason  string   `json:"reason,omitempty"` // when determinable
+}
+
+// RemovedHost is a host a remaining keyword no longer maps to.
+type RemovedHost struct {
+	Keyword string `json:"keyword"`
+	Host    string `json:"host"`
+}
+
+// RemovedRule is a rule or value pattern that no longer exists.
+type RemovedRule struct {
+	ID      string `json:"id"`
+	Keyword string `json:"keyword,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
@@ -0,0 +1,123 @@
+package combine
+
+import (
+	"encoding/json"
+	"strings"
+
+	"secret-detector-export/data"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// Host roles describe what a host does with a forwarded secret. Consumers
+// apply different policies per role, e.g. an OAuth token endpoint exchanges
+// the secret rather than using it to call an API.
+const (
+	HostRoleAPI       = "api"
+	HostRoleAuth      = "auth"
+	HostRoleWebhook   = "webhook"
+	HostRoleTelemetry = "telemetry"
+)
+
+// hostRoleOverrides pins the role of hosts the heuristics misclassify.
+var hostRoleOverrides = mustLoadHostRoleOverrides()
+
+func mustLoadHostRoleOverrides() map[string]string {
+	var m map[string]string
+	if err := json.Unmarshal(data.HostRoles, &m); err != nil {
+		panic("invalid embedded host_roles.json: " + err.Error())
+	}
+	byHost := make(map[string]string, len(m))
+	for host, role := range m {
+		if !IsValidHostRole(role) {
+			panic("invalid embedded host_roles.json: unknown role " + role + " for " + host)
+		}
+		norm, err := trufflehog.NormalizeCuratedHost(host)
+		if err != nil {
+			panic("invalid embedded host_roles.json: " + err.Error())
+		}
+		byHost[norm] = role
+	}
+	return byHost
+}
+
+func IsValidHostRole(role string) bool {
+	switch role {
+	case HostRoleAPI, HostRoleAuth, HostRoleWebhook, HostRoleTelemetry:
+		return true
+	}
+	return false
+}
+
+var (
+	authLabels      = map[string]bool{"auth": true, "oauth": true, "oauth2": true, "login": true, "accounts": true, "account": true, "id": true, "identity": true, "sso": true, "token": true}
+	webhookLabels   = map[string]bool{"hooks": true, "hook": true, "webhook": true, "webhooks": true}
+	telemetryLabels = map[string]bool{"intake": true, "ingest": true, "telemetry": true, "collector": true, "metrics": true, "logs": true, "events": true, "otlp": true, "traces": true, "trace": true}
+)
+
+// ClassifyHostRole assigns a role to a host using, in order: the curated
+// override file, the host's subdomain labels, then its API path prefixes.
+// Anything unrecognized is an API host.
+func ClassifyHostRole(host string, pathPrefixes []string) string {
+	if role, ok := hostRoleOverrides[host]; ok {
+		return role
+	}
+
+	// Only subdomain labels are meaningful; the registrable domain
+	// ("id.me", "auth0.com") says nothing about the endpoint's purpose.
+	labels := strings.Split(strings.TrimPrefix(host, "*."), ".")
+	if len(labels) > 2 {
+		labels = labels[:len(labels)-2]
+	} else {
+		labels = nil
+	}
+	for _, label := range labels {
+		for _, part := range strings.Split(label, "-") {
+			switch {
+			case webhookLabels[part]:
+				return HostRoleWebhook
+			case authLabels[part]:
+				return HostRoleAuth
+			case telemetryLabels[part]:
+				return HostRoleTelemetry
+			}
+		}
+	}
+
+	// A host that takes webhook URLs under any of its prefixes receives
+	// secrets embedded in URLs, whatever else it serves.
+	for _, p := range pathPrefixes {
+		if isWebhookPrefix(p) {
+			return HostRoleWebhook
+		}
+	}
+	for _, p := range pathPrefixes {
+		if lower := strings.ToLower(p); strings.Contains(lower, "/oauth") || strings.Contains(lower, "/token/") {
+			return HostRoleAuth
+		}
+	}
+
+	return HostRoleAPI
+}
+
+// isWebhookPrefix reports whether a path prefix has a webhook segment, as in
+// /api/webhooks/ or /services/hooks/.
+func isWebhookPrefix(prefix string) bool {
+	for _, seg := range strings.Split(strings.ToLower(prefix), "/") {
+		if webhookLabels[seg] {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyHostRoles returns host → role for every host.
+func ClassifyHostRoles(hosts []string, pathPrefixes map[string][]string) map[string]string {
+	if len(hosts) == 0 {
+		return nil
+	}
+	roles := make(map[string]string, len(hosts))
+	for _, h := range hosts {
+		roles[h] = ClassifyHostRole(h, pathPrefixes[h])
+	}
+	return roles
+}
@@ -0,0 +1,55 @@
+package combine
+
+import (
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestClassifyHostRole(t *testing.T) {
+	tests := []struct {
+		host     string
+		prefixes []string
+		want     string
+	}{
+		{"api.stripe.com", nil, HostRoleAPI},
+		{"auth.example.com", nil, HostRoleAuth},
+		{"oauth2.example.com", nil, HostRoleAuth},
+		{"login.example.com", nil, HostRoleAuth},
+		{"hooks.example.com", nil, HostRoleWebhook},
+		{"http-intake.logs.example.com", nil, HostRoleTelemetry},
+		{"browser-intake.example.com", nil, HostRoleTelemetry},
+		{"discord.com", []string{"/api/webhooks/"}, HostRoleWebhook},
+		{"discord.com", []string{"/api/v10/", "/api/webhooks/"}, HostRoleWebhook}, // webhook paths win on mixed hosts
+		{"api.example.com", []string{"/oauth/", "/v1/hook/"}, HostRoleWebhook},
+		{"outlook.office.com", []string{"/webhook/"}, HostRoleWebhook},
+		{"api.example.com", []string{"/oauth/"}, HostRoleAuth},
+		{"id.me", nil, HostRoleAPI},         // registrable domain labels are ignored
+		{"api.auth0.com", nil, HostRoleAPI}, // "auth0" is a brand, not a role label
+		{"*.amazonaws.com", nil, HostRoleAPI},
+		{"*.ingest.sentry.io", nil, HostRoleTelemetry},
+		{"hooks.slack.com", nil, HostRoleWebhook}, // curated override
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := ClassifyHostRole(tt.host, tt.prefixes); got != tt.want {
+				t.Errorf("ClassifyHostRole(%q, %v) = %q, want %q", tt.host, tt.prefixes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombineClassifiesHostRoles(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "example", Keyword: "example", Hosts: []string{"api.example.com", "auth.example.com"}},
+	}
+	glRules := []gitleaks.Rule{{ID: "example-api-key", Keyword: "example", Regex: `ex_[a-z]{16}`}}
+
+	export := Combine(thDetectors, glRules)
+	roles := export.Services[0].HostRoles
+	if roles["api.example.com"] != HostRoleAPI || roles["auth.example.com"] != HostRoleAuth {
+		t.Errorf("combined HostRoles = %v", roles)
+	}
+}
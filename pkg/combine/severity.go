@@ -0,0 +1,74 @@
+package combine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"secret-detector-export/data"
+)
+
+// Severities rank how bad a leak of a matching secret is, most severe first.
+const (
+	SeverityCritical = "critical" // account-wide or irrevocable access
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+)
+
+// defaultSeverity applies to rules neither curated nor caught by a heuristic.
+const defaultSeverity = SeverityMedium
+
+// cloudRootKeyProviders are the cloud platforms whose keys can act for a
+// whole account.
+var cloudRootKeyProviders = map[string]bool{"alibaba": true, "aws": true, "azure": true, "gcp": true}
+
+type ruleSeverityFile struct {
+	Rules      map[string]string `json:"rules"`
+	Categories map[string]string `json:"categories"`
+}
+
+// ruleSeverity maps rule IDs and categories to severities.
+var ruleSeverity = mustLoadRuleSeverity()
+
+func mustLoadRuleSeverity() ruleSeverityFile {
+	var f ruleSeverityFile
+	if err := json.Unmarshal(data.RuleSeverity, &f); err != nil {
+		panic("invalid embedded rule_severity.json: " + err.Error())
+	}
+	for _, m := range []map[string]string{f.Rules, f.Categories} {
+		for k, v := range m {
+			if !IsValidSeverity(v) {
+				panic(fmt.Sprintf("invalid embedded rule_severity.json: %q has unknown severity %q", k, v))
+			}
+		}
+	}
+	return f
+}
+
+func IsValidSeverity(s string) bool {
+	switch s {
+	case SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow:
+		return true
+	}
+	return false
+}
+
+// RuleSeverity returns the severity of a rule. A curated rule-ID entry wins;
+// then webhook URLs are medium and cloud provider keys critical; then the
+// curated category entry; otherwise medium.
+func RuleSeverity(ruleID, keyword, category string) string {
+	if s, ok := ruleSeverity.Rules[ruleID]; ok {
+		return s
+	}
+	if strings.Contains(ruleID, "webhook") {
+		return SeverityMedium
+	}
+	if cloudRootKeyProviders[NormalizeKeyword(keyword)] {
+		return SeverityCritical
+	}
+	if s, ok := ruleSeverity.Categories[category]; ok {
+		return s
+	}
+	return defaultSeverity
+}
@@ -0,0 +1,39 @@
+package combine
+
+import (
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+)
+
+func TestRuleSeverity(t *testing.T) {
+	tests := []struct {
+		ruleID, keyword, category, want string
+	}{
+		{"private-key", "private-key", "crypto", SeverityCritical},           // rule entry
+		{"sentry-access-token", "sentry", "monitoring", SeverityLow},         // rule entry beats category
+		{"aws-access-token", "aws", "cloud", SeverityCritical},               // cloud provider root key
+		{"slack-webhook-url", "slack", "messaging", SeverityMedium},          // webhook URL
+		{"azure-webhook-url", "azure", "cloud", SeverityMedium},              // webhook beats cloud provider
+		{"netlify-access-token", "netlify", "cloud", SeverityHigh},           // category fallback
+		{"some-unknown-rule", "unknown", "", SeverityMedium},                 // nothing curated
+		{"some-unknown-rule", "unknown", "no-such-category", SeverityMedium}, // unknown category
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ruleID+"/"+tt.category, func(t *testing.T) {
+			if got := RuleSeverity(tt.ruleID, tt.keyword, tt.category); got != tt.want {
+				t.Errorf("RuleSeverity(%q, %q, %q) = %q, want %q", tt.ruleID, tt.keyword, tt.category, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombineAssignsSeverity(t *testing.T) {
+	glRules := []gitleaks.Rule{{ID: "aws-access-token", Keyword: "aws", Regex: `AKIA[0-9A-Z]{16}`}}
+
+	rule := Combine(nil, glRules).Services[0].Rules[0]
+	if rule.Severity != SeverityCritical {
+		t.Errorf("aws-access-token severity = %q, want %q", rule.Severity, SeverityCritical)
+	}
+}
@@ -0,0 +1,65 @@
+package combine
+
+import (
+	"encoding/json"
+
+	"secret-detector-export/data"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// tenantHosts maps service keywords to curated tenant host templates. They
+// name the placeholder ("{workspace}" rather than "{tenant}") and cover
+// services whose detectors build the host from an argument the extractor
+// can't see.
+var tenantHosts = mustLoadTenantHosts()
+
+func mustLoadTenantHosts() map[string][]trufflehog.HostTemplate {
+	var m map[string][]string
+	if err := json.Unmarshal(data.TenantHosts, &m); err != nil {
+		panic("invalid embedded tenant_hosts.json: " + err.Error())
+	}
+	byNorm := make(map[string][]trufflehog.HostTemplate, len(m))
+	for k, templates := range m {
+		var parsed []trufflehog.HostTemplate
+		for _, s := range templates {
+			t, ok := trufflehog.ParseHostTemplate(s)
+			if !ok {
+				panic("invalid embedded tenant_hosts.json: bad template for " + k + ": " + s)
+			}
+			parsed = append(parsed, t)
+		}
+		byNorm[NormalizeKeyword(k)] = trufflehog.MergeHostTemplates(parsed, nil)
+	}
+	return byNorm
+}
+
+// TenantHostsFor returns the curated host templates for a keyword.
+func TenantHostsFor(keyword string) []trufflehog.HostTemplate {
+	return tenantHosts[NormalizeKeyword(keyword)]
+}
+
+// HostsWithTemplates returns hosts followed by the wildcard of every
+// template not already present, preserving order.
+func HostsWithTemplates(hosts []string, templates []trufflehog.HostTemplate) []string {
+	if len(templates) == 0 {
+		return hosts
+	}
+	seen := make(map[string]bool, len(hosts))
+	out := append([]string(nil), hosts...)
+	for _, h := range hosts {
+		seen[h] = true
+	}
+	for _, t := range templates {
+		if !seen[t.Wildcard] {
+			seen[t.Wildcard] = true
+			out = append(out, t.Wildcard)
+		}
+	}
+	return out
+}
+
+// AllowedHosts returns every host a secret of the service may go to: its
+// hosts, then curated regional hosts, then host template wildcards.
+func (s Service) AllowedHosts() []string {
+	return HostsWithTemplates(HostsWithRegional(s.Hosts, s.RegionalHosts), s.HostTemplates)
+}
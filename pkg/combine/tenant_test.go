@@ -0,0 +1,37 @@
+package combine
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestCombineHostTemplates(t *testing.T) {
+	thDetectors := []trufflehog.Detector{{
+		DirName:       "zendeskapi",
+		Keyword:       "zendesk",
+		Hosts:         []string{"*.zendesk.com"},
+		HostTemplates: []trufflehog.HostTemplate{{Template: "{tenant}.zendesk.com", Wildcard: "*.zendesk.com"}},
+	}}
+	glRules := []gitleaks.Rule{
+		{ID: "zendesk-secret-key", Keyword: "zendesk", Regex: `[a-z0-9]{40}`},
+		{ID: "slack-bot-token", Keyword: "slack", Regex: `xoxb-[0-9a-z-]+`},
+	}
+
+	for _, svc := range Combine(thDetectors, glRules).Services {
+		switch svc.Keyword {
+		case "zendesk":
+			// The curated placeholder name wins over the extracted "{tenant}".
+			want := []trufflehog.HostTemplate{{Template: "{subdomain}.zendesk.com", Wildcard: "*.zendesk.com"}}
+			if !reflect.DeepEqual(svc.HostTemplates, want) {
+				t.Errorf("zendesk host_templates = %+v, want %+v", svc.HostTemplates, want)
+			}
+		case "slack":
+			if len(svc.Hosts) != 0 || !reflect.DeepEqual(svc.AllowedHosts(), []string{"*.slack.com"}) {
+				t.Errorf("slack hosts = %v, allowed = %v; want curated wildcard only in allowed", svc.Hosts, svc.AllowedHosts())
+			}
+		}
+	}
+}
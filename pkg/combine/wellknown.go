@@ -0,0 +1,41 @@
+package combine
+
+import (
+	"encoding/json"
+	"sort"
+
+	"secret-detector-export/data"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// MatchCurated is the match type of services whose hosts were backfilled
+// from data/well_known_hosts.json because no TruffleHog detector matched.
+const MatchCurated = "curated"
+
+// wellKnownHosts maps normalized keywords to curated API hosts.
+var wellKnownHosts = mustLoadWellKnownHosts()
+
+func mustLoadWellKnownHosts() map[string][]string {
+	var m map[string][]string
+	if err := json.Unmarshal(data.WellKnownHosts, &m); err != nil {
+		panic("invalid embedded well_known_hosts.json: " + err.Error())
+	}
+	byNorm := make(map[string][]string, len(m))
+	for k, hosts := range m {
+		for i, h := range hosts {
+			host, err := trufflehog.NormalizeCuratedHost(h)
+			if err != nil || trufflehog.IsNoiseHost(host, false) || trufflehog.IsPublicSuffix(host) {
+				panic("invalid embedded well_known_hosts.json: bad host for " + k + ": " + h)
+			}
+			hosts[i] = host
+		}
+		sort.Strings(hosts)
+		byNorm[NormalizeKeyword(k)] = hosts
+	}
+	return byNorm
+}
+
+// WellKnownHostsFor returns the curated hosts for a keyword.
+func WellKnownHostsFor(keyword string) []string {
+	return wellKnownHosts[NormalizeKeyword(keyword)]
+}
@@ -0,0 +1,45 @@
+package combine
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestCombineBackfillsWellKnownHosts(t *testing.T) {
+	thDetectors := []trufflehog.Detector{
+		{DirName: "pulumi", Keyword: "pulumi", Hosts: []string{"api.pulumi.example"}},
+	}
+	glRules := []gitleaks.Rule{
+		{ID: "planetscale-api-token", Keyword: "planetscale", Regex: `pscale_tkn_[a-z0-9]{43}`},
+		{ID: "pulumi-api-token", Keyword: "pulumi", Regex: `pul-[a-f0-9]{40}`},
+	}
+
+	export := Combine(thDetectors, glRules)
+	for _, svc := range export.Services {
+		switch svc.Keyword {
+		case "planetscale":
+			if svc.MatchType != MatchCurated || !reflect.DeepEqual(svc.Hosts, []string{"api.planetscale.com"}) {
+				t.Errorf("planetscale match_type = %q, hosts = %v; want curated api.planetscale.com", svc.MatchType, svc.Hosts)
+			}
+			if svc.PrimaryHost != "api.planetscale.com" {
+				t.Errorf("planetscale primary_host = %q", svc.PrimaryHost)
+			}
+		case "pulumi":
+			// A TruffleHog match wins over the curated entry.
+			if svc.MatchType != "exact" || !reflect.DeepEqual(svc.Hosts, []string{"api.pulumi.example"}) {
+				t.Errorf("pulumi match_type = %q, hosts = %v", svc.MatchType, svc.Hosts)
+			}
+		}
+	}
+	if export.Stats.MatchCurated != 1 || export.Stats.ServicesWithHosts != 2 || len(export.GLNoHosts) != 0 {
+		t.Errorf("stats = %+v, gl_no_hosts = %v", export.Stats, export.GLNoHosts)
+	}
+
+	ex := Explain("planetscale", thDetectors, glRules)
+	if ex.MatchType != MatchCurated || !reflect.DeepEqual(ex.Hosts, []string{"api.planetscale.com"}) {
+		t.Errorf("Explain(planetscale) = %+v", ex)
+	}
+}
@@ -0,0 +1,81 @@
+// Package dataset loads published exports for Go consumers: it decodes
+// them into the typed structs of pkg/combine and pkg/export, checks the
+// kind and schema version, and validates the invariants `hogwash validate`
+// checks (non-empty compiling regexes, valid hosts, consistent maps), so
+// consumers neither copy the struct definitions nor skip validation.
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// ErrSchemaVersion is returned (wrapped) for a gondolin export whose
+// schema_version this package doesn't know. Exports of an older supported
+// version load as is; export.Migrate upgrades them.
+var ErrSchemaVersion = errors.New("unsupported schema_version")
+
+// ValidationError lists every invariant a decoded export breaks.
+type ValidationError struct {
+	Kind     string // "full" or "gondolin"
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s export: %d problems (first: %v)", e.Kind, len(e.Problems), e.Problems[0])
+}
+
+func (e *ValidationError) Unwrap() []error { return e.Problems }
+
+// LoadCombined reads a full (combined) export from r and validates it.
+func LoadCombined(r io.Reader) (combine.Export, error) {
+	var e combine.Export
+	if err := decode(r, "full", &e); err != nil {
+		return combine.Export{}, err
+	}
+	if errs := export.ValidateCombined(e, export.ValidateOptions{}); len(errs) > 0 {
+		return combine.Export{}, &ValidationError{Kind: "full", Problems: errs}
+	}
+	return e, nil
+}
+
+// LoadGondolin reads a gondolin export from r, checks that its
+// schema_version is supported, and validates it.
+func LoadGondolin(r io.Reader) (export.Gondolin, error) {
+	var g export.Gondolin
+	if err := decode(r, "gondolin", &g); err != nil {
+		return export.Gondolin{}, err
+	}
+	if g.SchemaVersion < 1 || g.SchemaVersion > export.SchemaVersion {
+		return export.Gondolin{}, fmt.Errorf("%w %d (supported: 1..%d)", ErrSchemaVersion, g.SchemaVersion, export.SchemaVersion)
+	}
+	if errs := export.ValidateGondolin(g, export.ValidateOptions{}); len(errs) > 0 {
+		return export.Gondolin{}, &ValidationError{Kind: "gondolin", Problems: errs}
+	}
+	return g, nil
+}
+
+// decode reads all of r and unmarshals it into v after checking that it is
+// an export of kind.
+func decode(r io.Reader, kind string, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	got, err := export.DetectKind(data)
+	if err != nil {
+		return err
+	}
+	if got != kind {
+		return fmt.Errorf("expected a %s export, got %s", kind, got)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %s export: %w", kind, err)
+	}
+	return nil
+}
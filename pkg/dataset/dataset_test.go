@@ -0,0 +1,75 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+func testFull() combine.Export {
+	return combine.Export{
+		Services: []combine.Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-access-token", Regex: `sk_live_[a-z0-9]+`}}},
+		},
+	}.WithContentHash()
+}
+
+func encode(t *testing.T, v any) *bytes.Reader {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(data)
+}
+
+func TestLoadCombined(t *testing.T) {
+	full := testFull()
+	got, err := LoadCombined(encode(t, full))
+	if err != nil || len(got.Services) != 1 || got.ContentHash != full.ContentHash {
+		t.Fatalf("LoadCombined = %+v, %v", got, err)
+	}
+
+	bad := testFull()
+	bad.Services[0].Rules[0].Regex = ""
+	bad.Services[0].Hosts = []string{"localhost"}
+	_, err = LoadCombined(encode(t, bad))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Kind != "full" || len(verr.Problems) < 2 {
+		t.Errorf("invalid export: err = %v", err)
+	}
+
+	if _, err := LoadCombined(encode(t, export.ToGondolin(full, export.Options{}))); err == nil || !strings.Contains(err.Error(), "expected a full export, got gondolin") {
+		t.Errorf("gondolin input: err = %v", err)
+	}
+}
+
+func TestLoadGondolin(t *testing.T) {
+	g := export.ToGondolin(testFull(), export.Options{})
+	got, err := LoadGondolin(encode(t, g))
+	if err != nil || got.KeywordHostMap["stripe"][0] != "api.stripe.com" {
+		t.Fatalf("LoadGondolin = %+v, %v", got, err)
+	}
+
+	future := g
+	future.SchemaVersion = export.SchemaVersion + 1
+	if _, err := LoadGondolin(encode(t, future)); !errors.Is(err, ErrSchemaVersion) {
+		t.Errorf("future schema: err = %v", err)
+	}
+
+	tampered := g
+	tampered.KeywordHostMap = map[string][]string{"stripe": {"evil.example.com"}}
+	var verr *ValidationError
+	if _, err := LoadGondolin(encode(t, tampered)); !errors.As(err, &verr) || !strings.Contains(verr.Error(), "content_hash") {
+		t.Errorf("tampered export: err = %v", err)
+	}
+
+	if _, err := LoadGondolin(strings.NewReader(`{"services": []}`)); err == nil {
+		t.Error("full input: no error")
+	}
+}
@@ -0,0 +1,184 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// Allowlist is a deployed host allowlist. Keywords is set when it was
+// deployed as an export, so per-service changes can be told apart.
+type Allowlist struct {
+	Hosts    []string
+	Keywords map[string][]string // keyword (or exact env name) → hosts; nil for a plain list
+}
+
+// ParseAllowlist reads a deployed allowlist: a gondolin or full export, a
+// JSON array of hosts, or a plain list with one host per line (blank lines
+// and # comments ignored). Hosts are lower-cased.
+func ParseAllowlist(data []byte) (Allowlist, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		kind, err := DetectKind(trimmed)
+		if err != nil {
+			return Allowlist{}, err
+		}
+		var hosts map[string][]string
+		if kind == "gondolin" {
+			var g Gondolin
+			if err := json.Unmarshal(trimmed, &g); err != nil {
+				return Allowlist{}, fmt.Errorf("decode gondolin export: %w", err)
+			}
+			hosts = gondolinHosts(g)
+		} else {
+			var e combine.Export
+			if err := json.Unmarshal(trimmed, &e); err != nil {
+				return Allowlist{}, fmt.Errorf("decode full export: %w", err)
+			}
+			hosts = fullSnapshot(e).hosts
+		}
+		a := Allowlist{Keywords: hosts}
+		for _, hs := range hosts {
+			a.Hosts = append(a.Hosts, hs...)
+		}
+		a.Hosts = normalizeHostList(a.Hosts)
+		return a, nil
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		var hosts []string
+		if err := json.Unmarshal(trimmed, &hosts); err != nil {
+			return Allowlist{}, fmt.Errorf("decode host array: %w", err)
+		}
+		return Allowlist{Hosts: normalizeHostList(hosts)}, nil
+	}
+	var hosts []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			hosts = append(hosts, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Allowlist{}, err
+	}
+	return Allowlist{Hosts: normalizeHostList(hosts)}, nil
+}
+
+func normalizeHostList(hosts []string) []string {
+	seen := make(map[string]bool, len(hosts))
+	var out []string
+	for _, h := range hosts {
+		h = strings.ToLower(h)
+		if !seen[h] {
+			seen[h] = true
+			out = append(out, h)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// gondolinHosts maps keywords and exact env names to the hosts they justify.
+func gondolinHosts(g Gondolin) map[string][]string {
+	hosts := make(map[string][]string, len(g.KeywordHostMap)+len(g.ExactNameHostMap))
+	for k, hs := range g.KeywordHostMap {
+		hosts[k] = hs
+	}
+	for name, hs := range g.ExactNameHostMap {
+		hosts[name] = append(hosts[name], hs...)
+	}
+	return hosts
+}
+
+// AllowlistDiff is what deploying an export would change in a live host
+// allowlist. Hosts are compared literally, so a deployed "*.example.com"
+// doesn't cover an exported "api.example.com". Every list is sorted.
+type AllowlistDiff struct {
+	NewHosts         []JustifiedHost `json:"new_hosts,omitempty"`         // exported but not deployed
+	UnjustifiedHosts []string        `json:"unjustified_hosts,omitempty"` // deployed but mapped from no service
+	// ChangedServices lists services whose hosts differ from the deployed
+	// ones. Against a plain list only additions can be attributed.
+	ChangedServices []HostChange `json:"changed_services,omitempty"`
+}
+
+// JustifiedHost is a host and the services whose secrets may go to it.
+type JustifiedHost struct {
+	Host     string   `json:"host"`
+	Keywords []string `json:"keywords"`
+}
+
+// Empty reports whether deploying the export would change nothing.
+func (d AllowlistDiff) Empty() bool {
+	return len(d.NewHosts) == 0 && len(d.UnjustifiedHosts) == 0 && len(d.ChangedServices) == 0
+}
+
+// DiffAllowlistFull compares a full export with a deployed allowlist.
+func DiffAllowlistFull(e combine.Export, deployed Allowlist) AllowlistDiff {
+	return diffAllowlist(fullSnapshot(e).hosts, deployed)
+}
+
+// DiffAllowlistGondolin compares a gondolin export with a deployed
+// allowlist. Hosts of exact_name_host_map entries count as justified.
+func DiffAllowlistGondolin(g Gondolin, deployed Allowlist) AllowlistDiff {
+	return diffAllowlist(gondolinHosts(g), deployed)
+}
+
+func diffAllowlist(exported map[string][]string, deployed Allowlist) AllowlistDiff {
+	var d AllowlistDiff
+	isDeployed := make(map[string]bool, len(deployed.Hosts))
+	for _, h := range deployed.Hosts {
+		isDeployed[h] = true
+	}
+	justifiedBy := make(map[string][]string)
+	for _, k := range sortedMapKeys(exported) {
+		for _, h := range exported[k] {
+			justifiedBy[h] = append(justifiedBy[h], k)
+		}
+	}
+	for _, h := range sortedMapKeys(justifiedBy) {
+		if !isDeployed[h] {
+			d.NewHosts = append(d.NewHosts, JustifiedHost{Host: h, Keywords: justifiedBy[h]})
+		}
+	}
+	for _, h := range deployed.Hosts {
+		if _, ok := justifiedBy[h]; !ok {
+			d.UnjustifiedHosts = append(d.UnjustifiedHosts, h)
+		}
+	}
+
+	if deployed.Keywords == nil {
+		for _, k := range sortedMapKeys(exported) {
+			var added []string
+			for _, h := range exported[k] {
+				if !isDeployed[h] {
+					added = append(added, h)
+				}
+			}
+			if len(added) > 0 {
+				sort.Strings(added)
+				d.ChangedServices = append(d.ChangedServices, HostChange{Keyword: k, Added: added})
+			}
+		}
+		return d
+	}
+	keywords := make(map[string]bool, len(exported)+len(deployed.Keywords))
+	for k := range exported {
+		keywords[k] = true
+	}
+	for k := range deployed.Keywords {
+		keywords[k] = true
+	}
+	for _, k := range sortedMapKeys(keywords) {
+		added, removed := diffStrings(deployed.Keywords[k], exported[k])
+		if len(added) > 0 || len(removed) > 0 {
+			d.ChangedServices = append(d.ChangedServices, HostChange{Keyword: k, Added: added, Removed: removed})
+		}
+	}
+	return d
+}
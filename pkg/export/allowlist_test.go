@@ -0,0 +1,83 @@
+package export
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestParseAllowlist(t *testing.T) {
+	want := []string{"api.stripe.com", "hooks.slack.com"}
+	for name, data := range map[string]string{
+		"plain": "# deployed 2026-10-01\nAPI.Stripe.com\n\nhooks.slack.com # webhooks\napi.stripe.com\n",
+		"array": `["hooks.slack.com", "api.stripe.com"]`,
+	} {
+		a, err := ParseAllowlist([]byte(data))
+		if err != nil || !reflect.DeepEqual(a.Hosts, want) || a.Keywords != nil {
+			t.Errorf("%s: ParseAllowlist = %+v, %v", name, a, err)
+		}
+	}
+
+	g := Gondolin{SchemaVersion: SchemaVersion, KeywordHostMap: map[string][]string{"stripe": {"api.stripe.com"}}, ExactNameHostMap: map[string][]string{"DD_API_KEY": {"api.datadoghq.com"}}}
+	data, _ := json.Marshal(g)
+	a, err := ParseAllowlist(data)
+	if err != nil || !reflect.DeepEqual(a.Hosts, []string{"api.datadoghq.com", "api.stripe.com"}) || len(a.Keywords) != 2 {
+		t.Errorf("gondolin: ParseAllowlist = %+v, %v", a, err)
+	}
+	if _, err := ParseAllowlist([]byte(`{"hosts": []}`)); err == nil {
+		t.Error("unknown JSON object: no error")
+	}
+}
+
+func TestDiffAllowlist(t *testing.T) {
+	e := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com", "files.stripe.com"}},
+			{Keyword: "slack", Hosts: []string{"hooks.slack.com", "slack.com"}},
+		},
+		THOnlyHosts: []combine.THOnlyEntry{{Keyword: "slackbot", Hosts: []string{"slack.com"}}},
+	}
+
+	plain := Allowlist{Hosts: []string{"api.stripe.com", "hooks.slack.com", "legacy.example.com"}}
+	got := DiffAllowlistFull(e, plain)
+	want := AllowlistDiff{
+		NewHosts: []JustifiedHost{
+			{Host: "files.stripe.com", Keywords: []string{"stripe"}},
+			{Host: "slack.com", Keywords: []string{"slack", "slackbot"}},
+		},
+		UnjustifiedHosts: []string{"legacy.example.com"},
+		ChangedServices: []HostChange{
+			{Keyword: "slack", Added: []string{"slack.com"}},
+			{Keyword: "slackbot", Added: []string{"slack.com"}},
+			{Keyword: "stripe", Added: []string{"files.stripe.com"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plain list:\n%+v\nwant\n%+v", got, want)
+	}
+
+	g := Gondolin{
+		KeywordHostMap:   map[string][]string{"stripe": {"api.stripe.com", "files.stripe.com"}, "slack": {"hooks.slack.com"}},
+		ExactNameHostMap: map[string][]string{"SLACK_BOT_TOKEN": {"slack.com"}},
+	}
+	deployed := Allowlist{
+		Hosts:    []string{"api.stripe.com", "hooks.slack.com", "slack.com"},
+		Keywords: map[string][]string{"stripe": {"api.stripe.com"}, "slack": {"hooks.slack.com"}, "SLACK_BOT_TOKEN": {"slack.com"}, "gone": {"slack.com"}},
+	}
+	got = DiffAllowlistGondolin(g, deployed)
+	if len(got.UnjustifiedHosts) != 0 || len(got.NewHosts) != 1 || got.NewHosts[0].Host != "files.stripe.com" {
+		t.Errorf("gondolin: hosts = %+v", got)
+	}
+	wantChanged := []HostChange{
+		{Keyword: "gone", Removed: []string{"slack.com"}},
+		{Keyword: "stripe", Added: []string{"files.stripe.com"}},
+	}
+	if !reflect.DeepEqual(got.ChangedServices, wantChanged) {
+		t.Errorf("gondolin: changed services = %+v, want %+v", got.ChangedServices, wantChanged)
+	}
+	if (AllowlistDiff{}).Empty() != true || got.Empty() {
+		t.Error("Empty")
+	}
+}
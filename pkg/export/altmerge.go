@@ -0,0 +1,102 @@
+package export
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// MergedPattern is an entry of the opt-in merged_patterns section: several
+// value patterns of one service folded into a single alternation, so a
+// consumer runs one regex where it would have run len(Rules). Each rule's
+// alternative is wrapped in its own capture group; whichever MatchGroup
+// participated in a match names the rule, and its metadata (policy,
+// severity, …) stays in value_patterns under the same ID.
+//
+// Alternation is leftmost-first: where two rules of the service would match
+// at the same position, only the earlier one in Rules is reported.
+type MergedPattern struct {
+	Keyword  string       `json:"keyword"`
+	Regex    string       `json:"regex"`
+	Keywords []string     `json:"keywords,omitempty"` // union of the rules' pre-filter keywords; absent when any rule has none
+	Rules    []MergedRule `json:"rules"`
+}
+
+// MergedRule maps one alternative of a MergedPattern back to its rule.
+type MergedRule struct {
+	ID          string `json:"id"`
+	MatchGroup  int    `json:"match_group"`  // capture group spanning the rule's alternative
+	SecretGroup int    `json:"secret_group"` // capture group holding the secret; equals MatchGroup for whole matches
+}
+
+// MergePatterns folds the linked value patterns of each service that share
+// a structure (the same leading flag group, and all or none of them having
+// keyword pre-filters) into alternations. Patterns with more than one
+// capture group, unlinked patterns, and groups of one are left out; they are
+// evaluated from value_patterns as usual.
+func MergePatterns(patterns []ValuePattern) []MergedPattern {
+	type groupKey struct {
+		keyword, flags string
+		hasKeywords    bool
+	}
+	groups := make(map[groupKey][]ValuePattern)
+	var order []groupKey
+	for _, p := range patterns {
+		if p.Keyword == "" {
+			continue
+		}
+		re, err := regexp.Compile(p.Regex)
+		if err != nil || re.NumSubexp() > 1 || (re.NumSubexp() == 1 && p.SecretGroup != 1) {
+			continue
+		}
+		k := groupKey{p.Keyword, leadingFlagsRe.FindString(p.Regex), len(p.Keywords) > 0}
+		if groups[k] == nil {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], p)
+	}
+
+	var out []MergedPattern
+	for _, k := range order {
+		members := groups[k]
+		if len(members) < 2 {
+			continue
+		}
+		m := MergedPattern{Keyword: k.keyword, Regex: k.flags}
+		keywords := make(map[string]bool)
+		group := 0
+		for i, p := range members {
+			if i > 0 {
+				m.Regex += "|"
+			}
+			m.Regex += "(" + p.Regex[len(k.flags):] + ")"
+			group++
+			r := MergedRule{ID: p.ID, MatchGroup: group, SecretGroup: group}
+			if p.SecretGroup == 1 {
+				group++
+				r.SecretGroup = group
+			}
+			m.Rules = append(m.Rules, r)
+			for _, kw := range p.Keywords {
+				keywords[kw] = true
+			}
+		}
+		if k.hasKeywords {
+			for kw := range keywords {
+				m.Keywords = append(m.Keywords, kw)
+			}
+			sort.Strings(m.Keywords)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// mergedPatternsEqual reports whether a and b are the same merged_patterns,
+// treating empty and missing keyword lists (as left by -compact) alike.
+func mergedPatternsEqual(a, b []MergedPattern) bool {
+	return slices.EqualFunc(a, b, func(x, y MergedPattern) bool {
+		return x.Keyword == y.Keyword && x.Regex == y.Regex &&
+			slices.Equal(x.Keywords, y.Keywords) && slices.Equal(x.Rules, y.Rules)
+	})
+}
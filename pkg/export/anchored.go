@@ -0,0 +1,25 @@
+package export
+
+import "regexp"
+
+// AnchoredRegex returns expr anchored to a whole value: start, optional
+// whitespace, expr, optional whitespace, end. Gitleaks patterns are tuned to
+// find secrets inside file content; matched against a standalone value such
+// as an env var, the anchored form rejects values that merely contain a
+// match. A leading global flag group stays in front so PatternFlags still
+// describes the result, and no capture group is added. It returns "" when
+// the anchored form doesn't compile or numbers its capture groups
+// differently, so secret_group would point elsewhere.
+func AnchoredRegex(expr string) string {
+	flags := leadingFlagsRe.FindString(expr)
+	anchored := flags + `^\s*(?:` + expr[len(flags):] + `)\s*$`
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return ""
+	}
+	are, err := regexp.Compile(anchored)
+	if err != nil || are.NumSubexp() != re.NumSubexp() {
+		return ""
+	}
+	return anchored
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestAnchoredRegex(t *testing.T) {
+	tests := []struct {
+		expr, want string
+		matches    []string
+		rejects    []string
+	}{
+		{
+			`ghp_[0-9a-zA-Z]{4}`, `^\s*(?:ghp_[0-9a-zA-Z]{4})\s*$`,
+			[]string{"ghp_abcd", "  ghp_abcd\n"},
+			[]string{"xghp_abcd", "ghp_abcd trailing", "export TOKEN=ghp_abcd"},
+		},
+		{
+			`(?i)sk_(live|test)_([0-9a-z]{4})|rk_x`, `(?i)^\s*(?:sk_(live|test)_([0-9a-z]{4})|rk_x)\s*$`,
+			[]string{"SK_LIVE_ABCD", "rk_x"},
+			[]string{"rk_xy"},
+		},
+	}
+	for _, tt := range tests {
+		got := AnchoredRegex(tt.expr)
+		if got != tt.want {
+			t.Errorf("AnchoredRegex(%q) = %q, want %q", tt.expr, got, tt.want)
+			continue
+		}
+		re := regexp.MustCompile(got)
+		if re.NumSubexp() != regexp.MustCompile(tt.expr).NumSubexp() {
+			t.Errorf("%q: capture groups changed", got)
+		}
+		for _, s := range tt.matches {
+			if !re.MatchString(s) {
+				t.Errorf("%q does not match %q", got, s)
+			}
+		}
+		for _, s := range tt.rejects {
+			if re.MatchString(s) {
+				t.Errorf("%q matches %q", got, s)
+			}
+		}
+	}
+	// Forms that don't survive the wrapping are dropped rather than exported.
+	for _, expr := range []string{`ab(c`, `a)(b`, `\Qab`} {
+		if got := AnchoredRegex(expr); got != "" {
+			t.Errorf("AnchoredRegex(%q) = %q, want none", expr, got)
+		}
+	}
+	if f := DerivePatternFlags(AnchoredRegex(`(?i)abc`)); f == nil || !f.CaseInsensitive || !f.AnchoredStart || !f.AnchoredEnd {
+		t.Errorf("flags of the anchored form = %+v", f)
+	}
+}
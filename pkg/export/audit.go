@@ -0,0 +1,89 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"secret-detector-export/data"
+	"secret-detector-export/pkg/combine"
+)
+
+// AuditRequirements maps service keywords that must be present to hosts
+// each must map to. An empty host list only requires the service to have
+// some host.
+type AuditRequirements map[string][]string
+
+// DefaultAuditRequirements returns the embedded data/must_have_services.json.
+func DefaultAuditRequirements() AuditRequirements {
+	req, err := ParseAuditRequirements(data.MustHaveServices)
+	if err != nil {
+		panic("invalid embedded must_have_services.json: " + err.Error())
+	}
+	return req
+}
+
+// ParseAuditRequirements decodes a JSON object of keyword → required hosts.
+func ParseAuditRequirements(data []byte) (AuditRequirements, error) {
+	var req AuditRequirements
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// AuditFull checks a full export against req. Services and TH-only entries
+// are matched by normalized keyword; curated regional hosts count.
+func AuditFull(e combine.Export, req AuditRequirements) []error {
+	hosts := make(map[string][]string)
+	for _, svc := range e.Services {
+		norm := combine.NormalizeKeyword(svc.Keyword)
+		hosts[norm] = append(hosts[norm], svc.AllowedHosts()...)
+	}
+	for _, th := range e.THOnlyHosts {
+		norm := combine.NormalizeKeyword(th.Keyword)
+		hosts[norm] = append(hosts[norm], th.Hosts...)
+	}
+	return audit(hosts, req)
+}
+
+// AuditGondolin checks a gondolin export's keyword_host_map against req.
+func AuditGondolin(g Gondolin, req AuditRequirements) []error {
+	hosts := make(map[string][]string)
+	for keyword, hs := range g.KeywordHostMap {
+		norm := combine.NormalizeKeyword(keyword)
+		hosts[norm] = append(hosts[norm], hs...)
+	}
+	return audit(hosts, req)
+}
+
+// audit reports every missing service or host, sorted by keyword.
+func audit(hosts map[string][]string, req AuditRequirements) []error {
+	var errs []error
+	for _, keyword := range sortedMapKeys(req) {
+		have, ok := hosts[combine.NormalizeKeyword(keyword)]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("missing service %q", keyword))
+			continue
+		case len(have) == 0:
+			errs = append(errs, fmt.Errorf("service %q has no hosts", keyword))
+			continue
+		}
+		set := make(map[string]bool, len(have))
+		for _, h := range have {
+			set[h] = true
+		}
+		var missing []string
+		for _, h := range req[keyword] {
+			if !set[h] {
+				missing = append(missing, h)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(have)
+			errs = append(errs, fmt.Errorf("service %q: missing hosts %v (has %v)", keyword, missing, have))
+		}
+	}
+	return errs
+}
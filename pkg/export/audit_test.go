@@ -0,0 +1,61 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestAuditFull(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com"}},
+			{Keyword: "data-dog", Hosts: []string{"api.datadoghq.com"}, RegionalHosts: []combine.RegionalHost{{Host: "api.datadoghq.eu"}}},
+			{Keyword: "slack"},
+			{Keyword: "openai", Hosts: []string{"api.openai.com"}},
+		},
+		THOnlyHosts: []combine.THOnlyEntry{{Keyword: "sentry", Hosts: []string{"sentry.io"}}},
+	}
+	req := AuditRequirements{
+		"stripe":  {"api.stripe.com"},
+		"datadog": {"api.datadoghq.eu"},
+		"sentry":  {"sentry.io"},
+		"slack":   {"slack.com"},
+		"github":  {"api.github.com"},
+		"openai":  {"api.openai.com", "chat.openai.com"},
+	}
+
+	var got []string
+	for _, err := range AuditFull(full, req) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`missing service "github"`,
+		`service "openai": missing hosts [chat.openai.com] (has [api.openai.com])`,
+		`service "slack" has no hosts`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuditFull = %q, want %q", got, want)
+	}
+}
+
+func TestAuditGondolin(t *testing.T) {
+	g := Gondolin{KeywordHostMap: map[string][]string{"stripe": {"api.stripe.com"}}}
+	if errs := AuditGondolin(g, AuditRequirements{"stripe": nil}); len(errs) != 0 {
+		t.Errorf("AuditGondolin = %v, want none", errs)
+	}
+	if errs := AuditGondolin(g, AuditRequirements{"github": nil}); len(errs) != 1 {
+		t.Errorf("AuditGondolin = %v, want one missing service", errs)
+	}
+}
+
+func TestDefaultAuditRequirements(t *testing.T) {
+	req := DefaultAuditRequirements()
+	if hosts := req["anthropic"]; len(hosts) != 1 || hosts[0] != "api.anthropic.com" {
+		t.Errorf("DefaultAuditRequirements()[anthropic] = %v", hosts)
+	}
+	if _, err := ParseAuditRequirements([]byte(`["not", "a", "map"]`)); err == nil {
+		t.Error("ParseAuditRequirements accepted a JSON array")
+	}
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// keywordBloomFalsePositiveRate sizes the keyword_bloom filter.
+const keywordBloomFalsePositiveRate = 0.01
+
+// KeywordBloom is a Bloom filter over the lower-cased pre-filter keywords of
+// all value patterns. A value in which no window of one of Lengths tests
+// positive contains none of the keywords, so only patterns without keywords
+// can match it.
+//
+// Element s sets bits (h1 + i*h2) mod M for i in 0..K-1, in uint32
+// arithmetic, where h1 is the 32-bit FNV-1a hash of s's UTF-8 bytes and h2
+// the same hash continued from h1 instead of the FNV offset basis. Bit j is
+// Bits[j/8] >> (j%8) & 1.
+type KeywordBloom struct {
+	Bits    []byte `json:"bits"`    // base64 in JSON
+	M       int    `json:"m"`       // number of bits
+	K       int    `json:"k"`       // number of hash functions
+	Lengths []int  `json:"lengths"` // distinct keyword lengths in bytes, ascending
+}
+
+// NewKeywordBloom builds a filter over keywords (lower-cased here) sized for
+// a false positive rate of fpRate. It returns nil when there are no
+// keywords.
+func NewKeywordBloom(keywords []string, fpRate float64) *KeywordBloom {
+	set := make(map[string]bool, len(keywords))
+	for _, kw := range keywords {
+		if kw != "" {
+			set[strings.ToLower(kw)] = true
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+
+	n := float64(len(set))
+	m := int(math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	m = (m + 7) / 8 * 8
+	k := max(1, int(math.Round(float64(m)/n*math.Ln2)))
+	b := &KeywordBloom{Bits: make([]byte, m/8), M: m, K: k}
+
+	lengths := make(map[int]bool)
+	for kw := range set {
+		lengths[len(kw)] = true
+		h1, h2 := bloomHashes(kw)
+		for i := 0; i < k; i++ {
+			j := (h1 + uint32(i)*h2) % uint32(m)
+			b.Bits[j/8] |= 1 << (j % 8)
+		}
+	}
+	for l := range lengths {
+		b.Lengths = append(b.Lengths, l)
+	}
+	sort.Ints(b.Lengths)
+	return b
+}
+
+// MayContain reports whether s (already lower-cased) may be one of the
+// keywords. False means it certainly isn't.
+func (b *KeywordBloom) MayContain(s string) bool {
+	if b.M <= 0 || len(b.Bits)*8 < b.M {
+		return true // malformed: never rule anything out
+	}
+	h1, h2 := bloomHashes(s)
+	for i := 0; i < b.K; i++ {
+		j := (h1 + uint32(i)*h2) % uint32(b.M)
+		if b.Bits[j/8]&(1<<(j%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MayContainAny reports whether the lower-cased value may contain one of
+// the keywords, by testing every window of each keyword length.
+func (b *KeywordBloom) MayContainAny(lower string) bool {
+	for _, l := range b.Lengths {
+		for i := 0; i+l <= len(lower); i++ {
+			if b.MayContain(lower[i : i+l]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bloomHashes returns the two FNV-1a hashes combined by KeywordBloom.
+func bloomHashes(s string) (h1, h2 uint32) {
+	const offset, prime = 2166136261, 16777619
+	h1 = offset
+	for i := 0; i < len(s); i++ {
+		h1 = (h1 ^ uint32(s[i])) * prime
+	}
+	h2 = h1
+	for i := 0; i < len(s); i++ {
+		h2 = (h2 ^ uint32(s[i])) * prime
+	}
+	return h1, h2
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestKeywordBloom(t *testing.T) {
+	// FNV-1a 32 test vector, so other implementations can check their hash.
+	if h1, _ := bloomHashes("a"); h1 != 0xe40c292c {
+		t.Fatalf("h1(a) = %#x, want 0xe40c292c", h1)
+	}
+
+	var keywords []string
+	for i := 0; i < 500; i++ {
+		keywords = append(keywords, fmt.Sprintf("KW%03d_", i))
+	}
+	keywords = append(keywords, "ghp_", "sk_live_")
+	b := NewKeywordBloom(keywords, 0.01)
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded KeywordBloom
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, kw := range keywords {
+		if !decoded.MayContain(strings.ToLower(kw)) {
+			t.Fatalf("false negative for %q", kw)
+		}
+	}
+	if want := []int{4, 6, 8}; fmt.Sprint(decoded.Lengths) != fmt.Sprint(want) {
+		t.Errorf("Lengths = %v, want %v", decoded.Lengths, want)
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if decoded.MayContain(fmt.Sprintf("zz%04d", i)) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 300 {
+		t.Errorf("%d/10000 false positives, want about 1%%", falsePositives)
+	}
+
+	if !decoded.MayContainAny("token=ghp_0123") {
+		t.Error("MayContainAny missed an embedded keyword")
+	}
+	if decoded.MayContainAny("x") {
+		t.Error("MayContainAny matched a value shorter than every keyword")
+	}
+	if NewKeywordBloom(nil, 0.01) != nil {
+		t.Error("NewKeywordBloom without keywords != nil")
+	}
+}
+
+func TestToGondolinKeywordBloom(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{{
+		Keyword: "github",
+		Hosts:   []string{"api.github.com"},
+		Rules:   []combine.Rule{{ID: "github-pat", Regex: `ghp_[0-9a-zA-Z]{36}`, Keywords: []string{"GHP_"}}},
+	}}}
+	g := ToGondolin(full, Options{})
+	if g.KeywordBloom == nil || !g.KeywordBloom.MayContainAny("export token=ghp_abc") {
+		t.Fatalf("keyword_bloom = %+v", g.KeywordBloom)
+	}
+	if errs := ValidateGondolin(g, ValidateOptions{}); len(errs) != 0 {
+		t.Errorf("ValidateGondolin: %v", errs)
+	}
+
+	g.KeywordBloom.Bits = make([]byte, len(g.KeywordBloom.Bits))
+	errs := ValidateGondolin(g.WithContentHash(), ValidateOptions{})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "keyword_bloom") {
+		t.Errorf("ValidateGondolin with an empty filter = %v, want one keyword_bloom error", errs)
+	}
+}
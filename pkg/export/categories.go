@@ -0,0 +1,27 @@
+package export
+
+import "secret-detector-export/pkg/combine"
+
+// CategoryServices returns the services whose category is in categories,
+// for category-scoped gondolin exports (-categories). Services of exports
+// that predate the category field fall back to the curated category. The
+// input slice is not modified; an empty categories set returns it unchanged.
+func CategoryServices(services []combine.Service, categories map[string]bool) []combine.Service {
+	if len(categories) == 0 {
+		return services
+	}
+	var out []combine.Service
+	for _, svc := range services {
+		if categories[serviceCategory(svc)] {
+			out = append(out, svc)
+		}
+	}
+	return out
+}
+
+func serviceCategory(svc combine.Service) string {
+	if svc.Category != "" {
+		return svc.Category
+	}
+	return combine.ServiceCategory(svc.Keyword)
+}
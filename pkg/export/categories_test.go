@@ -0,0 +1,43 @@
+package export
+
+import (
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestCategoryServices(t *testing.T) {
+	services := []combine.Service{
+		{Keyword: "openai", Category: "ai"},
+		{Keyword: "github"}, // no category field: curated "vcs"
+		{Keyword: "cloudflare", Category: "cloud"},
+	}
+	got := CategoryServices(services, map[string]bool{"ai": true, "vcs": true})
+	if len(got) != 2 || got[0].Keyword != "openai" || got[1].Keyword != "github" {
+		t.Errorf("CategoryServices = %+v", got)
+	}
+	if got := CategoryServices(services, nil); len(got) != 3 {
+		t.Errorf("no categories: %d services, want 3", len(got))
+	}
+}
+
+func TestToGondolinCategories(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{
+		{Keyword: "cohere", Category: "ai", Hosts: []string{"api.cohere.com"}, Rules: []combine.Rule{{ID: "cohere-api-token", Regex: `co_[a-z]{40}`}}},
+		{Keyword: "cloudflare", Category: "cloud", Hosts: []string{"api.cloudflare.com"}, Rules: []combine.Rule{{ID: "cloudflare-api-key", Regex: `cf_[a-z]{40}`}}},
+	}}
+	g := ToGondolin(full, Options{Categories: map[string]bool{"ai": true}})
+	if len(g.KeywordHostMap) != 1 || g.KeywordHostMap["cohere"] == nil {
+		t.Errorf("keyword_host_map = %v, want only cohere (no aws override)", g.KeywordHostMap)
+	}
+	if len(g.ExactNameHostMap) != 1 || g.ExactNameHostMap["CO_API_KEY"] == nil {
+		t.Errorf("exact_name_host_map = %v, want only CO_API_KEY", g.ExactNameHostMap)
+	}
+	if len(g.ValuePatterns) != 1 || g.ValuePatterns[0].ID != "cohere-api-token" {
+		t.Errorf("value_patterns = %+v", g.ValuePatterns)
+	}
+
+	if all := ToGondolin(full, Options{}); all.KeywordHostMap["aws"] == nil || len(all.ExactNameHostMap) != len(exactNameHostMap) {
+		t.Error("unscoped export lost the curated overrides")
+	}
+}
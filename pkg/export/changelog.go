@@ -0,0 +1,114 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteChangelog renders d as Markdown release notes under a level-2 title.
+// Sections with nothing to report are omitted; an empty diff renders a single
+// "No changes." line so release notes are never blank.
+func WriteChangelog(w io.Writer, d Diff, title string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", title)
+
+	if d.Empty() {
+		b.WriteString("No changes.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	hostChanges := make(map[string]HostChange, len(d.HostChanges))
+	for _, hc := range d.HostChanges {
+		hostChanges[hc.Keyword] = hc
+	}
+	added := make(map[string]bool, len(d.AddedServices))
+	removed := make(map[string]bool, len(d.RemovedServices))
+
+	section := func(heading string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "### %s\n\n", heading)
+		for _, l := range lines {
+			fmt.Fprintf(&b, "- %s\n", l)
+		}
+		b.WriteString("\n")
+	}
+
+	var lines []string
+	for _, k := range d.AddedServices {
+		added[k] = true
+		lines = append(lines, withHosts(code(k), hostChanges[k].Added))
+	}
+	section(fmt.Sprintf("New services (%d)", len(d.AddedServices)), lines)
+
+	lines = nil
+	for _, k := range d.RemovedServices {
+		removed[k] = true
+		lines = append(lines, code(k))
+	}
+	section(fmt.Sprintf("Removed services (%d)", len(d.RemovedServices)), lines)
+
+	var gained, lost []string
+	for _, hc := range d.HostChanges {
+		if added[hc.Keyword] || removed[hc.Keyword] {
+			continue
+		}
+		if len(hc.Added) > 0 {
+			gained = append(gained, code(hc.Keyword)+": "+codeList(hc.Added))
+		}
+		if len(hc.Removed) > 0 {
+			lost = append(lost, code(hc.Keyword)+": "+codeList(hc.Removed))
+		}
+	}
+	section("New hosts", gained)
+	section("Removed hosts", lost)
+
+	section(fmt.Sprintf("New rules (%d)", len(d.AddedRules)), ruleLines(d.AddedRules))
+	section(fmt.Sprintf("Removed rules (%d)", len(d.RemovedRules)), ruleLines(d.RemovedRules))
+
+	lines = nil
+	for _, rc := range d.ChangedRegexes {
+		lines = append(lines, ruleLine(RuleRef{ID: rc.ID, Keyword: rc.Keyword}))
+	}
+	section(fmt.Sprintf("Changed regexes (%d)", len(d.ChangedRegexes)), lines)
+
+	_, err := io.WriteString(w, strings.TrimSuffix(b.String(), "\n"))
+	return err
+}
+
+func ruleLines(refs []RuleRef) []string {
+	lines := make([]string, 0, len(refs))
+	for _, r := range refs {
+		lines = append(lines, ruleLine(r))
+	}
+	return lines
+}
+
+func ruleLine(r RuleRef) string {
+	if r.Keyword == "" {
+		return code(r.ID)
+	}
+	return code(r.ID) + " (" + r.Keyword + ")"
+}
+
+func withHosts(s string, hosts []string) string {
+	if len(hosts) == 0 {
+		return s
+	}
+	return s + " — " + codeList(hosts)
+}
+
+func codeList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, it := range items {
+		quoted[i] = code(it)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func code(s string) string {
+	return "`" + s + "`"
+}
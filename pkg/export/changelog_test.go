@@ -0,0 +1,43 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteChangelog(t *testing.T) {
+	d := Diff{
+		Kind:            "full",
+		AddedServices:   []string{"fresh"},
+		RemovedServices: []string{"gone"},
+		HostChanges: []HostChange{
+			{Keyword: "fresh", Added: []string{"api.fresh.io"}},
+			{Keyword: "gone", Removed: []string{"api.gone.com"}},
+			{Keyword: "stripe", Added: []string{"files.stripe.com"}},
+		},
+		RemovedRules:   []RuleRef{{ID: "gone-key", Keyword: "gone"}},
+		ChangedRegexes: []RegexChange{{ID: "jwt", Old: "a", New: "b"}},
+	}
+
+	var b strings.Builder
+	if err := WriteChangelog(&b, d, "Dataset 2026-03-01"); err != nil {
+		t.Fatalf("WriteChangelog: %v", err)
+	}
+	want := "## Dataset 2026-03-01\n\n" +
+		"### New services (1)\n\n- `fresh` — `api.fresh.io`\n\n" +
+		"### Removed services (1)\n\n- `gone`\n\n" +
+		"### New hosts\n\n- `stripe`: `files.stripe.com`\n\n" +
+		"### Removed rules (1)\n\n- `gone-key` (gone)\n\n" +
+		"### Changed regexes (1)\n\n- `jwt`\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteChangelog =\n%s\nwant\n%s", got, want)
+	}
+
+	b.Reset()
+	if err := WriteChangelog(&b, Diff{}, "Nothing"); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != "## Nothing\n\nNo changes.\n" {
+		t.Errorf("empty changelog = %q", got)
+	}
+}
@@ -0,0 +1,199 @@
+package export
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// Coverage summarizes how well an export covers services, for reviewing a
+// dataset rather than a single run (see combine.Stats for the latter).
+type Coverage struct {
+	Services             int                `json:"services"`
+	DistinctHosts        int                `json:"distinct_hosts"`
+	HostsPerService      Distribution       `json:"hosts_per_service"`
+	RulesPerCategory     map[string]int     `json:"rules_per_category"` // "" category is reported as "uncategorized"
+	Patterns             int                `json:"patterns"`
+	PatternsWithKeywords int                `json:"patterns_with_keywords"`
+	KeywordRatio         float64            `json:"keyword_ratio"` // share of patterns a consumer can pre-filter
+	RE2Compatible        int                `json:"re2_compatible"`
+	RE2Ratio             float64            `json:"re2_ratio"` // share of patterns Go's regexp (RE2) compiles
+	TopByHosts           []ServiceHostCount `json:"top_by_hosts,omitempty"`
+	RegistrableDomains   int                `json:"registrable_domains"` // distinct eTLD+1 of all hosts
+	TopDomains           []DomainHostCount  `json:"top_domains,omitempty"`
+}
+
+// Distribution describes a set of counts.
+type Distribution struct {
+	Min     int      `json:"min"`
+	Median  int      `json:"median"`
+	Max     int      `json:"max"`
+	Mean    float64  `json:"mean"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Bucket counts values in a labeled range.
+type Bucket struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// ServiceHostCount is a service and how many hosts it maps to.
+type ServiceHostCount struct {
+	Keyword string `json:"keyword"`
+	Hosts   int    `json:"hosts"`
+}
+
+// DomainHostCount groups hosts by registrable domain (eTLD+1).
+type DomainHostCount struct {
+	Domain   string `json:"domain"`
+	Hosts    int    `json:"hosts"`
+	Services int    `json:"services"`
+}
+
+// uncategorized labels rules whose service has no curated category.
+const uncategorized = "uncategorized"
+
+// CoverageFull computes coverage for a full export. TH-only entries count as
+// services without rules. top limits TopByHosts (0 omits it).
+func CoverageFull(e combine.Export, top int) Coverage {
+	hosts := make(map[string][]string)
+	var patterns []ValuePattern
+	categories := make(map[string]int)
+	for _, svc := range e.Services {
+		hosts[svc.Keyword] = svc.AllowedHosts()
+		category := svc.Category
+		if category == "" {
+			category = uncategorized
+		}
+		for _, r := range svc.Rules {
+			categories[category]++
+			patterns = append(patterns, ValuePattern{ID: r.ID, Regex: r.Regex, Keywords: r.Keywords})
+		}
+	}
+	for _, th := range e.THOnlyHosts {
+		hosts[th.Keyword] = append(hosts[th.Keyword], th.Hosts...)
+	}
+	return coverage(hosts, patterns, categories, top)
+}
+
+// CoverageGondolin computes coverage for a gondolin export, where services
+// are keyword_host_map entries and categories are looked up from the curated
+// list by each pattern's keyword.
+func CoverageGondolin(g Gondolin, top int) Coverage {
+	categories := make(map[string]int)
+	for _, p := range g.ValuePatterns {
+		category := combine.ServiceCategory(p.Keyword)
+		if category == "" {
+			category = uncategorized
+		}
+		categories[category]++
+	}
+	return coverage(g.KeywordHostMap, g.ValuePatterns, categories, top)
+}
+
+func coverage(hosts map[string][]string, patterns []ValuePattern, categories map[string]int, top int) Coverage {
+	c := Coverage{Services: len(hosts), Patterns: len(patterns), RulesPerCategory: categories}
+
+	distinct := make(map[string]bool)
+	counts := make([]int, 0, len(hosts))
+	ranked := make([]ServiceHostCount, 0, len(hosts))
+	for keyword, hs := range hosts {
+		for _, h := range hs {
+			distinct[h] = true
+		}
+		counts = append(counts, len(hs))
+		ranked = append(ranked, ServiceHostCount{Keyword: keyword, Hosts: len(hs)})
+	}
+	c.DistinctHosts = len(distinct)
+	domains := groupByDomain(hosts)
+	c.RegistrableDomains = len(domains)
+	c.HostsPerService = distribution(counts, []int{0, 1, 2, 5, 10})
+
+	for _, p := range patterns {
+		if len(p.Keywords) > 0 {
+			c.PatternsWithKeywords++
+		}
+		if _, err := regexp.Compile(p.Regex); err == nil {
+			c.RE2Compatible++
+		}
+	}
+	if c.Patterns > 0 {
+		c.KeywordRatio = float64(c.PatternsWithKeywords) / float64(c.Patterns)
+		c.RE2Ratio = float64(c.RE2Compatible) / float64(c.Patterns)
+	}
+
+	if top > 0 {
+		sort.Slice(ranked, func(i, j int) bool {
+			if ranked[i].Hosts != ranked[j].Hosts {
+				return ranked[i].Hosts > ranked[j].Hosts
+			}
+			return ranked[i].Keyword < ranked[j].Keyword
+		})
+		c.TopByHosts = ranked[:min(top, len(ranked))]
+		sort.Slice(domains, func(i, j int) bool {
+			if domains[i].Hosts != domains[j].Hosts {
+				return domains[i].Hosts > domains[j].Hosts
+			}
+			return domains[i].Domain < domains[j].Domain
+		})
+		c.TopDomains = domains[:min(top, len(domains))]
+	}
+	return c
+}
+
+// groupByDomain counts distinct hosts and services per registrable domain.
+func groupByDomain(hosts map[string][]string) []DomainHostCount {
+	domainHosts := make(map[string]map[string]bool)
+	domainServices := make(map[string]map[string]bool)
+	for keyword, hs := range hosts {
+		for _, h := range hs {
+			d := trufflehog.RegistrableDomain(h)
+			if domainHosts[d] == nil {
+				domainHosts[d] = make(map[string]bool)
+				domainServices[d] = make(map[string]bool)
+			}
+			domainHosts[d][h] = true
+			domainServices[d][keyword] = true
+		}
+	}
+	out := make([]DomainHostCount, 0, len(domainHosts))
+	for d, hs := range domainHosts {
+		out = append(out, DomainHostCount{Domain: d, Hosts: len(hs), Services: len(domainServices[d])})
+	}
+	return out
+}
+
+// distribution summarizes counts into buckets bounded by the ascending upper
+// limits in bounds: 0, 1, 2, 3-5, 6-10, and 11+ for bounds {0, 1, 2, 5, 10}.
+func distribution(counts []int, bounds []int) Distribution {
+	var d Distribution
+	lo := 0
+	for _, hi := range bounds {
+		label := strconv.Itoa(lo)
+		if hi > lo {
+			label += "-" + strconv.Itoa(hi)
+		}
+		d.Buckets = append(d.Buckets, Bucket{Label: label})
+		lo = hi + 1
+	}
+	d.Buckets = append(d.Buckets, Bucket{Label: strconv.Itoa(lo) + "+"})
+	if len(counts) == 0 {
+		return d
+	}
+
+	sorted := append([]int(nil), counts...)
+	sort.Ints(sorted)
+	d.Min, d.Max, d.Median = sorted[0], sorted[len(sorted)-1], sorted[len(sorted)/2]
+	sum := 0
+	for _, n := range sorted {
+		sum += n
+		i := sort.SearchInts(bounds, n) // first bound >= n
+		d.Buckets[i].Count++
+	}
+	d.Mean = float64(sum) / float64(len(sorted))
+	return d
+}
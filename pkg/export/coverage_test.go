@@ -0,0 +1,55 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestCoverageFull(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "stripe", Category: "payments", Hosts: []string{"api.stripe.com", "files.stripe.com"}, Rules: []combine.Rule{
+				{ID: "stripe-access-token", Regex: `sk_live_\w+`, Keywords: []string{"sk_live_"}},
+				{ID: "stripe-lookahead", Regex: `sk_(?=live)`},
+			}},
+			{Keyword: "age", Rules: []combine.Rule{{ID: "age-secret-key", Regex: `AGE-SECRET-KEY-1\w+`, Keywords: []string{"age-secret-key"}}}},
+		},
+		THOnlyHosts: []combine.THOnlyEntry{{Keyword: "nogl", Hosts: []string{"api.nogl.com", "api.stripe.com"}}},
+	}
+
+	c := CoverageFull(full, 2)
+	if c.Services != 3 || c.DistinctHosts != 3 || c.Patterns != 3 {
+		t.Errorf("counts = %+v", c)
+	}
+	if c.PatternsWithKeywords != 2 || c.RE2Compatible != 2 {
+		t.Errorf("keywords/re2 = %d/%d, want 2/2", c.PatternsWithKeywords, c.RE2Compatible)
+	}
+	if want := map[string]int{"payments": 2, "uncategorized": 1}; !reflect.DeepEqual(c.RulesPerCategory, want) {
+		t.Errorf("RulesPerCategory = %v, want %v", c.RulesPerCategory, want)
+	}
+	wantTop := []ServiceHostCount{{Keyword: "nogl", Hosts: 2}, {Keyword: "stripe", Hosts: 2}}
+	if !reflect.DeepEqual(c.TopByHosts, wantTop) {
+		t.Errorf("TopByHosts = %v, want %v", c.TopByHosts, wantTop)
+	}
+	wantDomains := []DomainHostCount{{Domain: "stripe.com", Hosts: 2, Services: 2}, {Domain: "nogl.com", Hosts: 1, Services: 1}}
+	if c.RegistrableDomains != 2 || !reflect.DeepEqual(c.TopDomains, wantDomains) {
+		t.Errorf("domains = %d %v, want 2 %v", c.RegistrableDomains, c.TopDomains, wantDomains)
+	}
+	d := c.HostsPerService
+	if d.Min != 0 || d.Median != 2 || d.Max != 2 {
+		t.Errorf("HostsPerService = %+v", d)
+	}
+}
+
+func TestDistributionBuckets(t *testing.T) {
+	d := distribution([]int{0, 1, 3, 5, 6, 42}, []int{0, 1, 2, 5, 10})
+	want := []Bucket{{"0", 1}, {"1", 1}, {"2", 0}, {"3-5", 2}, {"6-10", 1}, {"11+", 1}}
+	if !reflect.DeepEqual(d.Buckets, want) {
+		t.Errorf("Buckets = %v, want %v", d.Buckets, want)
+	}
+	if d.Min != 0 || d.Max != 42 || d.Median != 5 || d.Mean != 57.0/6 {
+		t.Errorf("distribution = %+v", d)
+	}
+}
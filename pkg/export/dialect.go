@@ -0,0 +1,233 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// posixClassesJS spells the ASCII classes Go accepts as [[:name:]] in JS.
+var posixClassesJS = map[string]string{
+	"alnum":  `0-9A-Za-z`,
+	"alpha":  `A-Za-z`,
+	"ascii":  `\x00-\x7F`,
+	"blank":  `\t `,
+	"cntrl":  `\x00-\x1F\x7F`,
+	"digit":  `0-9`,
+	"graph":  `!-~`,
+	"lower":  `a-z`,
+	"print":  ` -~`,
+	"punct":  `!-\/:-@\[-\x60{-~`,
+	"space":  `\t\n\v\f\r `,
+	"upper":  `A-Z`,
+	"word":   `0-9A-Za-z_`,
+	"xdigit": `0-9A-Fa-f`,
+}
+
+// TranslateJS rewrites a Go regex for JavaScript's RegExp, without the u
+// flag and with the flags of DerivePatternFlags passed separately: the
+// leading flag group is dropped, (?P<name> becomes (?<name>, \A and \z
+// become lookarounds, \Q…\E is escaped, \x{…} becomes \xHH or \uHHHH, and
+// POSIX classes are expanded, and \s and \S are spelled out because JS's
+// \s also matches \v and Unicode spaces. Constructs JS can't express this
+// way (scoped or mid-regex flags, ungreedy mode, Unicode classes, code
+// points past U+FFFF) are an error.
+func TranslateJS(expr string) (string, error) {
+	m := leadingFlagsRe.FindStringSubmatch(expr)
+	body := expr
+	if m != nil {
+		if strings.Contains(m[1], "U") {
+			return "", fmt.Errorf("ungreedy flag %s", m[0])
+		}
+		body = expr[len(m[0]):]
+	}
+	return translateJS(body)
+}
+
+func translateJS(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		end := atomEnd(s, i)
+		tok := s[i:end]
+		switch s[i] {
+		case '\\':
+			out, err := escapeJS(tok, false)
+			if err != nil {
+				return "", err
+			}
+			tok = out
+		case '[':
+			out, err := classJS(tok)
+			if err != nil {
+				return "", err
+			}
+			tok = out
+		case '(':
+			open, ok := groupBody(tok)
+			switch {
+			case !ok:
+				return "", fmt.Errorf("inline flag group %s", tok)
+			case strings.HasPrefix(open, "(?P<"):
+				open = "(?<" + open[len("(?P<"):]
+			case strings.HasPrefix(open, "(?") && open != "(?:" && !strings.HasPrefix(open, "(?<"):
+				return "", fmt.Errorf("scoped flag group %s…)", open)
+			}
+			inner, err := translateJS(tok[len(groupOpening(tok)) : len(tok)-1])
+			if err != nil {
+				return "", err
+			}
+			tok = open + inner + ")"
+		}
+		b.WriteString(tok)
+		i = end
+	}
+	return b.String(), nil
+}
+
+// escapeJS translates one escape token, inside a class or not.
+func escapeJS(tok string, inClass bool) (string, error) {
+	if len(tok) < 2 {
+		return tok, nil
+	}
+	switch c := tok[1]; {
+	case c == 'A' && !inClass:
+		return `(?<![\s\S])`, nil
+	case c == 'z' && !inClass:
+		return `(?![\s\S])`, nil
+	case c == 'Q':
+		lit := strings.TrimSuffix(tok[2:], `\E`)
+		var b strings.Builder
+		for _, r := range lit {
+			if r < 0x80 && strings.ContainsRune(`\^$.|?*+()[]{}/-`, r) {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		return b.String(), nil
+	case c == 'x' && strings.HasPrefix(tok, `\x{`):
+		n, err := strconv.ParseUint(strings.TrimSuffix(tok[3:], "}"), 16, 32)
+		switch {
+		case err != nil:
+			return "", fmt.Errorf("escape %s: %w", tok, err)
+		case n <= 0xFF:
+			return fmt.Sprintf(`\x%02X`, n), nil
+		case n <= 0xFFFF:
+			return fmt.Sprintf(`\u%04X`, n), nil
+		}
+		return "", fmt.Errorf("code point %s past U+FFFF", tok)
+	case c == 'p' || c == 'P':
+		return "", fmt.Errorf("Unicode class %s", tok)
+	case c == 'C':
+		return "", fmt.Errorf("byte escape %s", tok)
+	case c == 'a':
+		return `\x07`, nil
+	case c == 's' && inClass:
+		return `\t\n\f\r `, nil
+	case c == 's':
+		return `[\t\n\f\r ]`, nil
+	case c == 'S' && inClass:
+		return `\x00-\x08\x0B\x0E-\x1F!-\uFFFF`, nil
+	case c == 'S':
+		return `[^\t\n\f\r ]`, nil
+	case c >= '0' && c <= '7':
+		return "", fmt.Errorf("octal escape %s", tok)
+	}
+	return tok, nil
+}
+
+// classJS translates a bracketed class token.
+func classJS(class string) (string, error) {
+	var b strings.Builder
+	b.WriteByte('[')
+	i := 1
+	if strings.HasPrefix(class[i:], "^") {
+		b.WriteByte('^')
+		i++
+	}
+	if strings.HasPrefix(class[i:], "]") {
+		b.WriteString(`\]`) // literal ] first in a Go class; JS would end the class
+		i++
+	}
+	for i < len(class) {
+		switch {
+		case class[i] == '\\':
+			end := escapeEnd(class, i)
+			out, err := escapeJS(class[i:end], true)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(out)
+			i = end
+		case strings.HasPrefix(class[i:], "[:") && strings.Contains(class[i:], ":]"):
+			end := i + strings.Index(class[i:], ":]") + 2
+			name := class[i+2 : end-2]
+			spelled, ok := posixClassesJS[name]
+			if !ok {
+				return "", fmt.Errorf("POSIX class [:%s:]", name)
+			}
+			b.WriteString(spelled)
+			i = end
+		case class[i] == '[':
+			b.WriteString(`\[`)
+			i++
+		default:
+			b.WriteByte(class[i])
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+// TranslateRE2 rewrites a Go regex for RE2 releases that predate the
+// (?<name>…) group syntax, spelling named groups (?P<name>…). Everything
+// else Go accepts, RE2 does too.
+func TranslateRE2(expr string) string {
+	var b strings.Builder
+	for i := 0; i < len(expr); {
+		end := atomEnd(expr, i)
+		tok := expr[i:end]
+		if open, ok := groupBody(tok); ok {
+			inner := TranslateRE2(tok[len(open) : len(tok)-1])
+			if strings.HasPrefix(open, "(?<") {
+				open = "(?P<" + open[len("(?<"):]
+			}
+			tok = open + inner + ")"
+		}
+		b.WriteString(tok)
+		i = end
+	}
+	return b.String()
+}
+
+// setDialects fills p's dialect variants from p.Regex and p.AnchoredRegex:
+// each is set only when it differs from its source, and JSIncompatible
+// explains a missing JS form.
+func (p *ValuePattern) setDialects() {
+	p.RegexRE2, p.RegexJS, p.JSIncompatible = "", "", ""
+	p.AnchoredRegexRE2, p.AnchoredRegexJS = "", ""
+	if re2 := TranslateRE2(p.Regex); re2 != p.Regex {
+		p.RegexRE2 = re2
+	}
+	js, err := TranslateJS(p.Regex)
+	switch {
+	case err != nil:
+		p.JSIncompatible = err.Error()
+		return
+	case js != p.Regex:
+		p.RegexJS = js
+	}
+	if p.AnchoredRegex == "" {
+		return
+	}
+	if re2 := TranslateRE2(p.AnchoredRegex); re2 != p.AnchoredRegex {
+		p.AnchoredRegexRE2 = re2
+	}
+	js, err = TranslateJS(p.AnchoredRegex)
+	switch {
+	case err != nil:
+		p.RegexJS = ""
+		p.JSIncompatible = "anchored_regex: " + err.Error()
+	case js != p.AnchoredRegex:
+		p.AnchoredRegexJS = js
+	}
+}
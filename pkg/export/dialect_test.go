@@ -0,0 +1,88 @@
+package export
+
+import "testing"
+
+func TestTranslateJS(t *testing.T) {
+	tests := []struct {
+		expr, want, err string
+	}{
+		{`ghp_[0-9a-zA-Z]{36}`, `ghp_[0-9a-zA-Z]{36}`, ""},
+		{`(?i)sk_(?P<key>[a-z]{4})`, `sk_(?<key>[a-z]{4})`, ""},
+		{`\Atok_\Q.a+\E\z`, `(?<![\s\S])tok_\.a\+(?![\s\S])`, ""},
+		{`[[:alnum:]\x{2D}]\x{20AC}`, `[0-9A-Za-z\x2D]\u20AC`, ""},
+		{`[]a]\a`, `[\]a]\x07`, ""},
+		{`(?:a|(b))`, `(?:a|(b))`, ""},
+		{`key\s*=\s*(\S+)`, `key[\t\n\f\r ]*=[\t\n\f\r ]*([^\t\n\f\r ]+)`, ""},
+		{`[\s:][\S]`, `[\t\n\f\r :][\x00-\x08\x0B\x0E-\x1F!-\uFFFF]`, ""},
+		{`a(?i:b)`, "", "scoped flag group (?i:…)"},
+		{`a(?i)b`, "", "inline flag group (?i)"},
+		{`(?U)a+`, "", "ungreedy flag (?U)"},
+		{`\p{Greek}`, "", `Unicode class \p{Greek}`},
+		{`\x{1F600}`, "", `code point \x{1F600} past U+FFFF`},
+		{`[[:^alpha:]]`, "", "POSIX class [:^alpha:]"},
+	}
+	for _, tt := range tests {
+		got, err := TranslateJS(tt.expr)
+		switch {
+		case tt.err != "":
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("TranslateJS(%q) error = %v, want %q", tt.expr, err, tt.err)
+			}
+		case err != nil:
+			t.Errorf("TranslateJS(%q): %v", tt.expr, err)
+		case got != tt.want:
+			t.Errorf("TranslateJS(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestTranslateRE2(t *testing.T) {
+	tests := []struct{ expr, want string }{
+		{`ghp_[a-z]{4}`, `ghp_[a-z]{4}`},
+		{`(?i)x(?<key>a(?<inner>b))`, `(?i)x(?P<key>a(?P<inner>b))`},
+		{`(?P<key>a)[(?<]`, `(?P<key>a)[(?<]`},
+	}
+	for _, tt := range tests {
+		if got := TranslateRE2(tt.expr); got != tt.want {
+			t.Errorf("TranslateRE2(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestSetDialects(t *testing.T) {
+	p := ValuePattern{Regex: `ghp_[a-z]{4}`}
+	p.setDialects()
+	if p.RegexRE2 != "" || p.RegexJS != "" || p.JSIncompatible != "" {
+		t.Errorf("portable regex got variants: %+v", p)
+	}
+	p = ValuePattern{Regex: `(?i)a(?<key>b)`}
+	p.setDialects()
+	if p.RegexRE2 != `(?i)a(?P<key>b)` || p.RegexJS != `a(?<key>b)` {
+		t.Errorf("variants = %q, %q", p.RegexRE2, p.RegexJS)
+	}
+	// Whitespace shorthands from upstream, as simplification used to emit
+	// them, get a JS form so the TypeScript consumer doesn't match \v or
+	// Unicode spaces.
+	p = ValuePattern{Regex: `token\s+([^\s]{32})`}
+	p.setDialects()
+	if p.RegexJS != `token[\t\n\f\r ]+([^\t\n\f\r ]{32})` {
+		t.Errorf("regex_js for \\s pattern = %q", p.RegexJS)
+	}
+	// The anchored form gets its own spellings: no leading flags and no \s
+	// for JS, (?P< groups for RE2.
+	p = ValuePattern{Regex: `(?i)tok_(?<key>[a-z]{4})`}
+	p.AnchoredRegex = AnchoredRegex(p.Regex)
+	p.setDialects()
+	if p.AnchoredRegexJS != `^[\t\n\f\r ]*(?:tok_(?<key>[a-z]{4}))[\t\n\f\r ]*$` {
+		t.Errorf("anchored_regex_js = %q", p.AnchoredRegexJS)
+	}
+	if p.AnchoredRegexRE2 != `(?i)^\s*(?:tok_(?P<key>[a-z]{4}))\s*$` {
+		t.Errorf("anchored_regex_re2 = %q", p.AnchoredRegexRE2)
+	}
+	p = ValuePattern{Regex: `a(?i:b)`}
+	p.AnchoredRegex = AnchoredRegex(p.Regex)
+	p.setDialects()
+	if p.RegexJS != "" || p.AnchoredRegexJS != "" || p.JSIncompatible == "" {
+		t.Errorf("untranslatable regex: regex_js %q, js_incompatible %q", p.RegexJS, p.JSIncompatible)
+	}
+}
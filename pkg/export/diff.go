@@ -0,0 +1,162 @@
+package export
+
+import (
+	"sort"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// Diff is a structured comparison of two exports of the same kind. Services
+// are identified by keyword and rules by ID; every list is sorted.
+type Diff struct {
+	Kind            string        `json:"kind"` // "full" or "gondolin"
+	AddedServices   []string      `json:"added_services,omitempty"`
+	RemovedServices []string      `json:"removed_services,omitempty"`
+	HostChanges     []HostChange  `json:"host_changes,omitempty"` // includes added and removed services
+	AddedRules      []RuleRef     `json:"added_rules,omitempty"`
+	RemovedRules    []RuleRef     `json:"removed_rules,omitempty"`
+	ChangedRegexes  []RegexChange `json:"changed_regexes,omitempty"`
+}
+
+// HostChange lists the hosts a keyword gained or lost.
+type HostChange struct {
+	Keyword string   `json:"keyword"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// RuleRef identifies a rule and the service it belongs to. Gondolin
+// patterns without host linkage have an empty keyword.
+type RuleRef struct {
+	ID      string `json:"id"`
+	Keyword string `json:"keyword,omitempty"`
+}
+
+// RegexChange records a rule whose regex differs between the two exports.
+type RegexChange struct {
+	ID      string `json:"id"`
+	Keyword string `json:"keyword,omitempty"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// Empty reports whether the two exports had no differences.
+func (d Diff) Empty() bool {
+	return len(d.AddedServices) == 0 && len(d.RemovedServices) == 0 && len(d.HostChanges) == 0 &&
+		len(d.AddedRules) == 0 && len(d.RemovedRules) == 0 && len(d.ChangedRegexes) == 0
+}
+
+// snapshot is the kind-independent view both export formats reduce to.
+type snapshot struct {
+	hosts map[string][]string // keyword → hosts (every known keyword has an entry)
+	rules map[string]RegexChange
+}
+
+func fullSnapshot(e combine.Export) snapshot {
+	s := snapshot{hosts: make(map[string][]string), rules: make(map[string]RegexChange)}
+	for _, svc := range e.Services {
+		s.hosts[svc.Keyword] = svc.AllowedHosts()
+		for _, r := range svc.Rules {
+			s.rules[r.ID] = RegexChange{ID: r.ID, Keyword: svc.Keyword, New: r.Regex}
+		}
+	}
+	for _, th := range e.THOnlyHosts {
+		s.hosts[th.Keyword] = th.Hosts
+	}
+	return s
+}
+
+func gondolinSnapshot(g Gondolin) snapshot {
+	s := snapshot{hosts: make(map[string][]string, len(g.KeywordHostMap)), rules: make(map[string]RegexChange)}
+	for k, hosts := range g.KeywordHostMap {
+		s.hosts[k] = hosts
+	}
+	for _, p := range g.ValuePatterns {
+		s.rules[p.ID] = RegexChange{ID: p.ID, Keyword: p.Keyword, New: p.Regex}
+	}
+	return s
+}
+
+// DiffFull compares two full exports.
+func DiffFull(before, after combine.Export) Diff {
+	d := diffSnapshots(fullSnapshot(before), fullSnapshot(after))
+	d.Kind = "full"
+	return d
+}
+
+// DiffGondolin compares two gondolin exports. Services are keyword_host_map
+// keys and rules are value_patterns.
+func DiffGondolin(before, after Gondolin) Diff {
+	d := diffSnapshots(gondolinSnapshot(before), gondolinSnapshot(after))
+	d.Kind = "gondolin"
+	return d
+}
+
+func diffSnapshots(before, after snapshot) Diff {
+	var d Diff
+	for _, k := range sortedMapKeys(after.hosts) {
+		if _, ok := before.hosts[k]; !ok {
+			d.AddedServices = append(d.AddedServices, k)
+		}
+	}
+	for _, k := range sortedMapKeys(before.hosts) {
+		if _, ok := after.hosts[k]; !ok {
+			d.RemovedServices = append(d.RemovedServices, k)
+		}
+	}
+
+	keywords := make(map[string]bool, len(before.hosts)+len(after.hosts))
+	for k := range before.hosts {
+		keywords[k] = true
+	}
+	for k := range after.hosts {
+		keywords[k] = true
+	}
+	for _, k := range sortedMapKeys(keywords) {
+		added, removed := diffStrings(before.hosts[k], after.hosts[k])
+		if len(added) > 0 || len(removed) > 0 {
+			d.HostChanges = append(d.HostChanges, HostChange{Keyword: k, Added: added, Removed: removed})
+		}
+	}
+
+	for _, id := range sortedMapKeys(after.rules) {
+		nr := after.rules[id]
+		or, ok := before.rules[id]
+		switch {
+		case !ok:
+			d.AddedRules = append(d.AddedRules, RuleRef{ID: id, Keyword: nr.Keyword})
+		case or.New != nr.New:
+			d.ChangedRegexes = append(d.ChangedRegexes, RegexChange{ID: id, Keyword: nr.Keyword, Old: or.New, New: nr.New})
+		}
+	}
+	for _, id := range sortedMapKeys(before.rules) {
+		if _, ok := after.rules[id]; !ok {
+			d.RemovedRules = append(d.RemovedRules, RuleRef{ID: id, Keyword: before.rules[id].Keyword})
+		}
+	}
+	return d
+}
+
+// diffStrings returns the sorted elements only in after (added) and only in
+// before (removed).
+func diffStrings(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, s := range before {
+		inBefore[s] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, s := range after {
+		inAfter[s] = true
+		if !inBefore[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range before {
+		if !inAfter[s] {
+			removed = append(removed, s)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
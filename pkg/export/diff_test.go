@@ -0,0 +1,68 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestDiffFull(t *testing.T) {
+	before := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-access-token", Regex: `sk_live_[a-z]+`}}},
+			{Keyword: "gone", Hosts: []string{"api.gone.com"}, Rules: []combine.Rule{{ID: "gone-key", Regex: `gone_[a-z]+`}}},
+		},
+	}
+	after := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com", "files.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-access-token", Regex: `sk_live_[a-z0-9]+`}}},
+			{Keyword: "fresh", Rules: []combine.Rule{{ID: "fresh-key", Regex: `fresh_[a-z]+`}}},
+		},
+		THOnlyHosts: []combine.THOnlyEntry{{Keyword: "nogl", Hosts: []string{"api.nogl.com"}}},
+	}
+
+	got := DiffFull(before, after)
+	want := Diff{
+		Kind:            "full",
+		AddedServices:   []string{"fresh", "nogl"},
+		RemovedServices: []string{"gone"},
+		HostChanges: []HostChange{
+			{Keyword: "gone", Removed: []string{"api.gone.com"}},
+			{Keyword: "nogl", Added: []string{"api.nogl.com"}},
+			{Keyword: "stripe", Added: []string{"files.stripe.com"}},
+		},
+		AddedRules:     []RuleRef{{ID: "fresh-key", Keyword: "fresh"}},
+		RemovedRules:   []RuleRef{{ID: "gone-key", Keyword: "gone"}},
+		ChangedRegexes: []RegexChange{{ID: "stripe-access-token", Keyword: "stripe", Old: `sk_live_[a-z]+`, New: `sk_live_[a-z0-9]+`}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffFull =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if d := DiffFull(after, after); !d.Empty() {
+		t.Errorf("diff of identical exports = %+v, want empty", d)
+	}
+}
+
+func TestDiffGondolin(t *testing.T) {
+	before := Gondolin{
+		KeywordHostMap: map[string][]string{"stripe": {"api.stripe.com"}},
+		ValuePatterns:  []ValuePattern{{ID: "stripe-access-token", Keyword: "stripe", Regex: `a`}},
+	}
+	after := Gondolin{
+		KeywordHostMap: map[string][]string{"stripe": {"api.stripe.com"}},
+		ValuePatterns:  []ValuePattern{{ID: "stripe-access-token", Keyword: "stripe", Regex: `b`}, {ID: "jwt", Regex: `ey`}},
+	}
+
+	got := DiffGondolin(before, after)
+	if got.Kind != "gondolin" || len(got.HostChanges) != 0 || len(got.AddedServices) != 0 {
+		t.Errorf("DiffGondolin = %+v", got)
+	}
+	if !reflect.DeepEqual(got.AddedRules, []RuleRef{{ID: "jwt"}}) {
+		t.Errorf("AddedRules = %+v", got.AddedRules)
+	}
+	if len(got.ChangedRegexes) != 1 || got.ChangedRegexes[0].Old != "a" || got.ChangedRegexes[0].New != "b" {
+		t.Errorf("ChangedRegexes = %+v", got.ChangedRegexes)
+	}
+}
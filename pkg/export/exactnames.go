@@ -0,0 +1,45 @@
+package export
+
+import (
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// deriveExactNames adds to exact the env var names of services that keyword
+// matching would miss: those in a service's env_names whose normalized form
+// doesn't contain the service's keyword, such as DD_API_KEY for datadog or
+// HF_TOKEN for huggingface. A derived name maps to the service's
+// keyword_host_map hosts, so services left out of it derive nothing.
+// Curated entries in exact win, and a name derived from two services is left
+// out as ambiguous. It returns derived name → service keyword.
+func deriveExactNames(services []combine.Service, keywordHosts, exact map[string][]string) map[string]string {
+	sources := make(map[string]string)
+	ambiguous := make(map[string]bool)
+	for _, svc := range services {
+		if _, ok := keywordHosts[svc.Keyword]; !ok {
+			continue
+		}
+		norm := combine.NormalizeKeyword(svc.Keyword)
+		for _, name := range svc.EnvNames {
+			if _, curated := exact[name]; curated || strings.Contains(combine.NormalizeKeyword(name), norm) {
+				continue
+			}
+			if other, ok := sources[name]; ok && other != svc.Keyword {
+				ambiguous[name] = true
+				continue
+			}
+			sources[name] = svc.Keyword
+		}
+	}
+	for name := range ambiguous {
+		delete(sources, name)
+	}
+	for name, keyword := range sources {
+		exact[name] = append([]string(nil), keywordHosts[keyword]...)
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return sources
+}
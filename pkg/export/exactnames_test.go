@@ -0,0 +1,43 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestDeriveExactNames(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{
+		{Keyword: "datadog", Hosts: []string{"api.datadoghq.com"}, EnvNames: combine.EnvNames("datadog")},
+		{Keyword: "huggingface", Hosts: []string{"huggingface.co"}, EnvNames: []string{"HF_TOKEN", "HUGGINGFACE_TOKEN", "SHARED_TOKEN"}},
+		{Keyword: "acme", Hosts: []string{"api.acme.example"}, EnvNames: []string{"SHARED_TOKEN"}},
+		{Keyword: "nohosts", EnvNames: []string{"NH_TOKEN"}},
+	}}
+
+	g := ToGondolin(full, Options{DeriveExactNames: true})
+	for name, keyword := range map[string]string{"DD_APP_KEY": "datadog", "DD_TOKEN": "datadog", "DD_API_KEY": "", "HF_TOKEN": ""} {
+		if g.ExactNameSources[name] != keyword {
+			t.Errorf("exact_name_sources[%s] = %q, want %q", name, g.ExactNameSources[name], keyword)
+		}
+	}
+	// DD_API_KEY is curated: the curated hosts stay.
+	if want := exactNameHostMap["DD_API_KEY"]; !reflect.DeepEqual(g.ExactNameHostMap["DD_API_KEY"], want) {
+		t.Errorf("DD_API_KEY = %v, want curated %v", g.ExactNameHostMap["DD_API_KEY"], want)
+	}
+	if got := g.ExactNameHostMap["DD_TOKEN"]; !reflect.DeepEqual(got, []string{"api.datadoghq.com"}) {
+		t.Errorf("DD_TOKEN = %v", got)
+	}
+	for _, name := range []string{"DATADOG_API_KEY", "HUGGINGFACE_TOKEN", "SHARED_TOKEN", "NH_TOKEN"} {
+		if _, ok := g.ExactNameHostMap[name]; ok {
+			t.Errorf("%s derived: keyword-matched, ambiguous or without hosts", name)
+		}
+	}
+	if errs := ValidateGondolin(g, ValidateOptions{}); len(errs) != 0 {
+		t.Errorf("validate: %v", errs)
+	}
+
+	if g := ToGondolin(full, Options{}); g.ExactNameSources != nil || g.ExactNameHostMap["DD_TOKEN"] != nil {
+		t.Errorf("without DeriveExactNames: sources %v", g.ExactNameSources)
+	}
+}
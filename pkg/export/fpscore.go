@@ -0,0 +1,91 @@
+package export
+
+import (
+	"math"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// Weights of the false-positive score components; they sum to 1.
+const (
+	fpWeightNoEntropy  = 0.25 // no entropy threshold to reject low-randomness values
+	fpWeightUnanchored = 0.20 // no anchor or word boundary
+	fpWeightPrefix     = 0.30 // short or missing fixed prefix
+	fpWeightKeywords   = 0.25 // missing or short pre-filter keywords
+)
+
+// Lengths at which a fixed prefix or a keyword stops adding to the score.
+const (
+	fpSpecificPrefixLen  = 6
+	fpSpecificKeywordLen = 6
+	fpGenericKeywordLen  = 2
+)
+
+// FalsePositiveScore rates how prone a pattern is to match things that
+// aren't secrets, from 0 (a long fixed prefix, anchored, entropy-checked,
+// specific keywords) to 1 (none of those). It is rounded to two decimals.
+// A regex that doesn't parse scores as having no prefix and no anchors.
+func FalsePositiveScore(expr string, entropy float64, keywords []string) float64 {
+	score := 0.0
+	if entropy <= 0 {
+		score += fpWeightNoEntropy
+	}
+
+	prefixLen := 0
+	if re, err := syntax.Parse(expr, syntax.Perl); err == nil {
+		re = re.Simplify()
+		if !hasOp(re, isAssertion) {
+			score += fpWeightUnanchored
+		}
+		prefixLen, _ = literalPrefix(re)
+	} else {
+		score += fpWeightUnanchored
+	}
+	score += fpWeightPrefix * clamp01(1-float64(prefixLen)/fpSpecificPrefixLen)
+
+	shortest := -1
+	for _, kw := range keywords {
+		if n := utf8.RuneCountInString(strings.TrimSpace(kw)); n > 0 && (shortest < 0 || n < shortest) {
+			shortest = n
+		}
+	}
+	if shortest < 0 {
+		score += fpWeightKeywords
+	} else {
+		score += fpWeightKeywords * clamp01(float64(fpSpecificKeywordLen-shortest)/(fpSpecificKeywordLen-fpGenericKeywordLen))
+	}
+	return math.Round(score*100) / 100
+}
+
+// literalPrefix counts the literal runes every match of re starts with,
+// looking through zero-width assertions and capture groups. Case-folded
+// literals count: they still pin the text. complete reports whether all of
+// re is fixed text, so a concatenation can keep counting past it.
+func literalPrefix(re *syntax.Regexp) (n int, complete bool) {
+	if isAssertion(re) {
+		return 0, true
+	}
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune), true
+	case syntax.OpEmptyMatch:
+		return 0, true
+	case syntax.OpCapture:
+		return literalPrefix(re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			k, c := literalPrefix(sub)
+			n += k
+			if !c {
+				return n, false
+			}
+		}
+		return n, true
+	}
+	return 0, false
+}
+
+func clamp01(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
@@ -0,0 +1,27 @@
+package export
+
+import "testing"
+
+func TestFalsePositiveScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		entropy  float64
+		keywords []string
+		want     float64
+	}{
+		{"specific", `\b(ghp_[0-9a-zA-Z]{36})\b`, 3, []string{"ghp_"}, 0.23},
+		{"long prefix", `\bsk_live_[0-9a-z]{24}`, 3.5, []string{"sk_live_"}, 0},
+		{"case-folded prefix in group", `(?i)\b(xoxb-[0-9a-z-]+)`, 3, []string{"xoxb-"}, 0.11},
+		{"generic", `[a-f0-9]{32}`, 0, nil, 1},
+		{"short keyword, no entropy", `\bkey=([a-z0-9]{20})`, 0, []string{"key"}, 0.54},
+		{"unparsable", `(`, 3, []string{"longkeyword"}, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FalsePositiveScore(tt.expr, tt.entropy, tt.keywords); got != tt.want {
+				t.Errorf("FalsePositiveScore(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"fmt"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// RegressionThresholds bound how much an export may shrink relative to a
+// golden one, as percentages of the golden counts. Additions never fail.
+type RegressionThresholds struct {
+	MaxServiceLoss float64 // services removed
+	MaxHostLoss    float64 // keyword→host mappings removed
+	MaxRuleLoss    float64 // rules removed
+}
+
+// DefaultRegressionThresholds tolerates the churn of a normal upstream bump
+// while catching an extractor that silently stops finding hosts.
+func DefaultRegressionThresholds() RegressionThresholds {
+	return RegressionThresholds{MaxServiceLoss: 2, MaxHostLoss: 5, MaxRuleLoss: 2}
+}
+
+// Loss counts how many of the golden export's items are gone.
+type Loss struct {
+	Golden  int     `json:"golden"`
+	Lost    int     `json:"lost"`
+	Percent float64 `json:"percent"`
+}
+
+func newLoss(golden, lost int) Loss {
+	l := Loss{Golden: golden, Lost: lost}
+	if golden > 0 {
+		l.Percent = 100 * float64(lost) / float64(golden)
+	}
+	return l
+}
+
+// Regression compares an export against a golden one.
+type Regression struct {
+	Diff     Diff     `json:"diff"`
+	Services Loss     `json:"services"`
+	Hosts    Loss     `json:"hosts"`
+	Rules    Loss     `json:"rules"`
+	Failures []string `json:"failures,omitempty"` // thresholds exceeded
+}
+
+// Failed reports whether any threshold was exceeded.
+func (r Regression) Failed() bool { return len(r.Failures) > 0 }
+
+// CheckRegressionFull compares a full export against a golden full export.
+func CheckRegressionFull(golden, current combine.Export, t RegressionThresholds) Regression {
+	r := regression(fullSnapshot(golden), fullSnapshot(current), t)
+	r.Diff.Kind = "full"
+	return r
+}
+
+// CheckRegressionGondolin compares a gondolin export against a golden one.
+func CheckRegressionGondolin(golden, current Gondolin, t RegressionThresholds) Regression {
+	r := regression(gondolinSnapshot(golden), gondolinSnapshot(current), t)
+	r.Diff.Kind = "gondolin"
+	return r
+}
+
+func regression(golden, current snapshot, t RegressionThresholds) Regression {
+	r := Regression{Diff: diffSnapshots(golden, current)}
+
+	goldenHosts, lostHosts := 0, 0
+	for _, hosts := range golden.hosts {
+		goldenHosts += len(hosts)
+	}
+	for _, hc := range r.Diff.HostChanges {
+		lostHosts += len(hc.Removed)
+	}
+	r.Services = newLoss(len(golden.hosts), len(r.Diff.RemovedServices))
+	r.Hosts = newLoss(goldenHosts, lostHosts)
+	r.Rules = newLoss(len(golden.rules), len(r.Diff.RemovedRules))
+
+	check := func(name string, l Loss, max float64) {
+		if l.Percent > max {
+			r.Failures = append(r.Failures, fmt.Sprintf("%s loss %.1f%% (%d of %d) exceeds %.1f%%", name, l.Percent, l.Lost, l.Golden, max))
+		}
+	}
+	check("service", r.Services, t.MaxServiceLoss)
+	check("host", r.Hosts, t.MaxHostLoss)
+	check("rule", r.Rules, t.MaxRuleLoss)
+	return r
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestCheckRegressionFull(t *testing.T) {
+	golden := combine.Export{Services: []combine.Service{
+		{Keyword: "stripe", Hosts: []string{"api.stripe.com", "files.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-key", Regex: "sk_"}}},
+		{Keyword: "github", Hosts: []string{"api.github.com", "github.com"}, Rules: []combine.Rule{{ID: "github-pat", Regex: "ghp_"}}},
+	}}
+	current := combine.Export{Services: []combine.Service{
+		{Keyword: "stripe", Hosts: []string{"api.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-key", Regex: "sk_"}}},
+		{Keyword: "github", Hosts: []string{"api.github.com", "github.com"}, Rules: []combine.Rule{{ID: "github-pat", Regex: "ghp_"}}},
+		{Keyword: "openai", Hosts: []string{"api.openai.com"}},
+	}}
+
+	r := CheckRegressionFull(golden, current, DefaultRegressionThresholds())
+	if want := (Loss{Golden: 4, Lost: 1, Percent: 25}); r.Hosts != want {
+		t.Errorf("Hosts = %+v, want %+v", r.Hosts, want)
+	}
+	if want := []string{"host loss 25.0% (1 of 4) exceeds 5.0%"}; !reflect.DeepEqual(r.Failures, want) {
+		t.Errorf("Failures = %q, want %q", r.Failures, want)
+	}
+
+	lenient := RegressionThresholds{MaxServiceLoss: 0, MaxHostLoss: 25, MaxRuleLoss: 0}
+	if r := CheckRegressionFull(golden, current, lenient); r.Failed() {
+		t.Errorf("loss at the threshold failed: %q", r.Failures)
+	}
+}
+
+func TestCheckRegressionGondolin(t *testing.T) {
+	golden := Gondolin{
+		KeywordHostMap: map[string][]string{"stripe": {"api.stripe.com"}, "github": {"api.github.com"}},
+		ValuePatterns:  []ValuePattern{{ID: "stripe-key"}, {ID: "github-pat"}},
+	}
+	current := Gondolin{KeywordHostMap: map[string][]string{"stripe": {"api.stripe.com"}}}
+
+	r := CheckRegressionGondolin(golden, current, DefaultRegressionThresholds())
+	if len(r.Failures) != 3 {
+		t.Errorf("Failures = %q, want service, host and rule loss", r.Failures)
+	}
+	if r.Diff.Kind != "gondolin" || !reflect.DeepEqual(r.Diff.RemovedServices, []string{"github"}) {
+		t.Errorf("Diff = %+v", r.Diff)
+	}
+}
@@ -0,0 +1,423 @@
+// Package export derives consumer-facing formats from a combined dataset:
+// the slim gondolin runtime export, its schema migrations, validation, and
+// JSON encoding helpers shared by the CLI.
+package export
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"secret-detector-export/data"
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// --- Gondolin-specific output types ---
+
+// SchemaVersion is the schema emitted by ToGondolin. Fields added since v1
+// are optional, so v1 readers can consume newer exports unchanged. The
+// Gondolin type (and JSONSchema, generated from it) is the field list; the
+// upgrades and downgrades in migrate.go record what each version adds.
+const SchemaVersion = 2
+
+// Gondolin is the slim, purpose-built dataset for Gondolin's
+// secret-aware env forwarding. It contains only what pi-gondolin.ts needs:
+//   - keyword_host_map:   keyword substring → API hosts (for env var name matching)
+//   - primary_host_map:   keyword → the one host to allow when only one is possible
+//   - path_prefixes:      host → API path prefixes forwarding can be scoped to
+//   - host_regions:       regional endpoint host → region (hosts are also in keyword_host_map)
+//   - host_roles:         host → api, auth, webhook, or telemetry
+//   - host_templates:     wildcard host → tenant template, e.g. "*.slack.com" → "{workspace}.slack.com"
+//   - exact_name_host_map: full env var name → API hosts (for oddballs like DD_API_KEY)
+//   - value_patterns:     Gitleaks regexes for value-based secret detection
+type Gondolin struct {
+	SchemaVersion    int                 `json:"schema_version"`
+	GeneratedAt      time.Time           `json:"generated_at"`
+	ContentHash      string              `json:"content_hash,omitempty"` // sha256 over everything except generated_at and generator (v2+)
+	Generator        *combine.Generator  `json:"generator,omitempty"`    // binary and data that produced the export
+	Licenses         []combine.License   `json:"licenses,omitempty"`     // attribution and terms per source
+	KeywordHostMap   map[string][]string `json:"keyword_host_map"`
+	PrimaryHostMap   map[string]string   `json:"primary_host_map,omitempty"` // keyword → single best host
+	PathPrefixes     map[string][]string `json:"path_prefixes,omitempty"`    // host → API path prefixes (absent = whole host)
+	HostRegions      map[string]string   `json:"host_regions,omitempty"`     // regional host → region
+	HostRoles        map[string]string   `json:"host_roles,omitempty"`       // host → api, auth, webhook, telemetry
+	HostTemplates    map[string]string   `json:"host_templates,omitempty"`   // wildcard host → tenant template
+	Wildcards        string              `json:"wildcards,omitempty"`        // how wildcard hosts were treated: expand or both (absent: keep)
+	ServiceWeights   map[string]float64  `json:"service_weights,omitempty"`  // keyword → popularity weight; value_patterns are ordered by it
+	Matching         *Matching           `json:"matching,omitempty"`         // how the name maps apply to env var names (absent: substring)
+	ExactNameHostMap map[string][]string `json:"exact_name_host_map"`
+	ExactNameSources map[string]string   `json:"exact_name_sources,omitempty"` // derived exact name → service keyword whose env_names it came from
+	ValuePatterns    []ValuePattern      `json:"value_patterns"`
+	MergedPatterns   []MergedPattern     `json:"merged_patterns,omitempty"` // opt-in alternations over value_patterns of one service
+	KeywordBloom     *KeywordBloom       `json:"keyword_bloom,omitempty"`   // pre-check over value_patterns[].keywords
+	NameTrie         *NameTrie           `json:"name_trie,omitempty"`       // opt-in packed lookup of the two name maps
+	Removed          *combine.Removed    `json:"removed,omitempty"`         // tombstones relative to -previous; not hashed
+}
+
+// ValuePattern is a regex-based secret detection rule from Gitleaks,
+// stripped to the fields Gondolin actually needs.
+type ValuePattern struct {
+	ID      string `json:"id"`
+	Keyword string `json:"keyword,omitempty"` // links to keyword_host_map (present only if hosts exist)
+	Regex   string `json:"regex"`
+	// AnchoredRegex is Regex anchored to the whole value (see
+	// AnchoredRegex), for matching standalone values like env vars.
+	AnchoredRegex string `json:"anchored_regex,omitempty"`
+	// RegexRE2 and RegexJS are Regex spelled for RE2 and for JavaScript's
+	// RegExp (see TranslateRE2, TranslateJS), and AnchoredRegexRE2 and
+	// AnchoredRegexJS the same for AnchoredRegex, each present only where
+	// the spelling differs. JSIncompatible says why a pattern has no JS form.
+	RegexRE2         string        `json:"regex_re2,omitempty"`
+	RegexJS          string        `json:"regex_js,omitempty"`
+	AnchoredRegexRE2 string        `json:"anchored_regex_re2,omitempty"`
+	AnchoredRegexJS  string        `json:"anchored_regex_js,omitempty"`
+	JSIncompatible   string        `json:"js_incompatible,omitempty"`
+	Keywords         []string      `json:"keywords,omitempty"`     // pre-filter hints (skip regex if none match as substring)
+	SecretGroup      int           `json:"secret_group,omitempty"` // which capture group holds the secret value
+	Flags            *PatternFlags `json:"flags,omitempty"`        // compile hints derived from the regex
+	Policy           string        `json:"policy,omitempty"`       // advisory hint: block, redact, forward
+	Severity         string        `json:"severity,omitempty"`     // critical, high, medium, low
+	// FalsePositiveScore rates how prone the pattern is to match non-secrets,
+	// 0 (absent) to 1; see FalsePositiveScore.
+	FalsePositiveScore float64 `json:"false_positive_score,omitempty"`
+}
+
+// exactNameHostMap contains env var names where keyword-based matching doesn't
+// work (name too short, too generic, or doesn't contain the service name).
+//
+// Loaded from data/exact_name_host_map.json so policy data can evolve without
+// editing Go source.
+var exactNameHostMap = mustLoadExactNameHostMap()
+
+// Options controls policy applied when deriving the gondolin export.
+// The zero value applies no pattern exclusions.
+type Options struct {
+	PatternDenylist  map[string]bool    // rule IDs excluded from value_patterns
+	Top              int                // keep only the N highest-ranked services (0 = all)
+	Popularity       []string           // most-popular-first keywords used to rank services for Top
+	Weights          map[string]float64 // normalized keyword → popularity weight (see ParsePopularityWeights); ranks Top and orders value_patterns
+	Categories       map[string]bool    // keep only services in these categories (nil = all; see CategoryServices)
+	RequireHosts     bool               // leave out value patterns without host linkage
+	MinKeywordLen    int                // leave out keyword_host_map keywords shorter than this (0 = no minimum)
+	NameTrie         bool               // add the name_trie section
+	MergePatterns    bool               // add the merged_patterns section
+	SimplifyRegexes  bool               // shrink value pattern regexes (see SimplifyRegex)
+	Wildcards        string             // WildcardsKeep (or ""), WildcardsExpand, or WildcardsBoth
+	DeriveExactNames bool               // add env_names that keyword matching misses to exact_name_host_map (see deriveExactNames)
+	URLCredentials   bool               // add generated value patterns for user:password@host URLs (see URLCredentialPatterns)
+	KeywordMatch     string             // KeywordMatchSubstring (or ""), KeywordMatchWord, or KeywordMatchPrefix; declared in matching
+	KeywordRegexes   bool               // add matching.keyword_regexes
+}
+
+// DefaultOptions returns the options used by the CLI when no custom
+// policy files are given. The embedded data/gondolin_pattern_denylist.json
+// lists Gitleaks rule IDs that are too generic for runtime value matching
+// (they fire on arbitrary high-entropy strings); they stay in the full export
+// but are dropped from gondolin mode unless a custom denylist is supplied.
+// Value pattern regexes are simplified, and exact names are derived from
+// service env_names.
+func DefaultOptions() Options {
+	denylist, err := ParsePatternDenylist(data.GondolinPatternDenylist)
+	if err != nil {
+		panic("invalid embedded gondolin_pattern_denylist.json: " + err.Error())
+	}
+	var popularity []string
+	if err := json.Unmarshal(data.ServicePopularity, &popularity); err != nil {
+		panic("invalid embedded service_popularity.json: " + err.Error())
+	}
+	return Options{PatternDenylist: denylist, Popularity: popularity, SimplifyRegexes: true, DeriveExactNames: true}
+}
+
+// ParsePatternDenylist decodes a JSON array of rule IDs into a lookup set.
+func ParsePatternDenylist(data []byte) (map[string]bool, error) {
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+	m := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		m[id] = true
+	}
+	return m, nil
+}
+
+// keywordHostMapOverrides lets us explicitly add or remove runtime keyword
+// mappings when upstream detector host data is misleading or missing.
+var keywordHostMapOverrides = map[string][]string{
+	// AWS credentials are common; ensure value- and name-based detection can map
+	// to a canonical AWS domain even if extractor linkage is absent.
+	"aws": {"sts.amazonaws.com", "*.amazonaws.com"},
+}
+
+var keywordHostMapDenylist = map[string]bool{
+	// TruffleHog's private key detector reports crt.sh as a verification endpoint,
+	// but mapping generic private key vars to certificate transparency logs is
+	// not useful for env forwarding.
+	"private-key": true,
+}
+
+// activeServices returns services without the deprecated ones.
+func activeServices(services []combine.Service) []combine.Service {
+	var out []combine.Service
+	for _, svc := range services {
+		if svc.Deprecated == "" {
+			out = append(out, svc)
+		}
+	}
+	return out
+}
+
+func mustLoadExactNameHostMap() map[string][]string {
+	var m map[string][]string
+	if err := json.Unmarshal(data.ExactNameHostMap, &m); err != nil {
+		panic("invalid embedded exact_name_host_map.json: " + err.Error())
+	}
+	for name, hosts := range m {
+		for i, h := range hosts {
+			host, err := trufflehog.NormalizeCuratedHost(h)
+			if err != nil {
+				panic("invalid embedded exact_name_host_map.json: " + name + ": " + err.Error())
+			}
+			hosts[i] = host
+		}
+	}
+	return m
+}
+
+// ToGondolin transforms a full combine.Export into the slim Gondolin format.
+// Deprecated services are left out. With opts.Categories, curated keyword
+// overrides outside them and exact names whose hosts no kept service
+// forwards to are left out too. When opts.Top cuts services, overrides
+// apply only to kept services and exact names follow the same host rule. opts.Wildcards rewrites wildcard hosts in
+// both name maps; keywords left without hosts are dropped.
+func ToGondolin(full combine.Export, opts Options) Gondolin {
+	var concrete []string
+	if opts.Wildcards != "" && opts.Wildcards != WildcardsKeep {
+		concrete = concreteHosts(full)
+	}
+	candidates := activeServices(CategoryServices(full.Services, opts.Categories))
+	full.Services = TopServicesWeighted(candidates, opts.Top, opts.Popularity, opts.Weights)
+	// trimmed: -top cut services, so curated overrides and exact names only
+	// apply where a kept service forwards (see below).
+	trimmed := len(full.Services) < len(candidates)
+
+	// Build keyword → hosts map from services that have hosts
+	keywordHosts := make(map[string][]string)
+	// Track which keywords have hosts for linking value patterns
+	hasHosts := make(map[string]bool)
+	prefixes := trufflehog.NewPathPrefixSet()
+	hostRegions := make(map[string]string)
+	hostTemplates := make(map[string]string)
+
+	for _, svc := range full.Services {
+		if keywordHostMapDenylist[svc.Keyword] || len(svc.Keyword) < opts.MinKeywordLen {
+			continue
+		}
+		hosts := applyWildcardPolicy(svc.AllowedHosts(), opts.Wildcards, concrete)
+		if len(hosts) > 0 {
+			keywordHosts[svc.Keyword] = hosts
+			hasHosts[combine.NormalizeKeyword(svc.Keyword)] = true
+			prefixes.AddAll(hosts, svc.PathPrefixes)
+		}
+		if len(svc.Hosts) > 0 {
+			for _, rh := range svc.RegionalHosts {
+				hostRegions[rh.Host] = rh.Region
+			}
+		}
+		if opts.Wildcards != WildcardsExpand {
+			for _, t := range svc.HostTemplates {
+				hostTemplates[t.Wildcard] = t.Template
+			}
+		}
+	}
+
+	// When -top cut services, overrides only apply to kept services so the
+	// cut holds; a -top that cuts nothing changes nothing.
+	var kept map[string]bool
+	if trimmed {
+		kept = make(map[string]bool, len(full.Services))
+		for _, svc := range full.Services {
+			kept[combine.NormalizeKeyword(svc.Keyword)] = true
+		}
+	}
+	for keyword, hosts := range keywordHostMapOverrides {
+		if len(opts.Categories) > 0 && !opts.Categories[combine.ServiceCategory(keyword)] || len(keyword) < opts.MinKeywordLen {
+			continue
+		}
+		if trimmed && !kept[combine.NormalizeKeyword(keyword)] {
+			continue
+		}
+		if hosts = applyWildcardPolicy(hosts, opts.Wildcards, concrete); len(hosts) == 0 {
+			continue
+		}
+		keywordHosts[keyword] = hosts
+		hasHosts[combine.NormalizeKeyword(keyword)] = true
+		prefixes.AddAll(hosts, nil)
+	}
+
+	pathPrefixes := prefixes.Result()
+	primaryHosts := make(map[string]string, len(keywordHosts))
+	hostRoles := make(map[string]string)
+	for keyword, hosts := range keywordHosts {
+		if primary := combine.ChoosePrimaryHost(keyword, hosts); primary != "" {
+			primaryHosts[keyword] = primary
+		}
+		for h, role := range combine.ClassifyHostRoles(hosts, pathPrefixes) {
+			hostRoles[h] = role
+		}
+	}
+
+	var serviceWeights map[string]float64
+	if len(opts.Weights) > 0 {
+		serviceWeights = make(map[string]float64)
+		for _, svc := range full.Services {
+			if w := opts.Weights[combine.NormalizeKeyword(svc.Keyword)]; w > 0 {
+				serviceWeights[svc.Keyword] = w
+			}
+		}
+		for keyword := range keywordHosts {
+			if w := opts.Weights[combine.NormalizeKeyword(keyword)]; w > 0 {
+				serviceWeights[keyword] = w
+			}
+		}
+	}
+
+	// Build value patterns from all GL rules
+	var patterns []ValuePattern
+	patternWeights := make(map[string]float64) // rule ID → its service's weight
+	for _, svc := range full.Services {
+		for _, r := range svc.Rules {
+			if opts.PatternDenylist[r.ID] {
+				continue
+			}
+			expr, group, _ := ExportedRegex(r, opts.SimplifyRegexes)
+			p := ValuePattern{
+				ID:            r.ID,
+				Regex:         expr,
+				AnchoredRegex: AnchoredRegex(expr),
+				Keywords:      r.Keywords,
+				SecretGroup:   group,
+				Flags:         DerivePatternFlags(r.Regex),
+				Policy:        r.Policy,
+				Severity:      r.Severity,
+
+				FalsePositiveScore: FalsePositiveScore(r.Regex, r.Entropy, r.Keywords),
+			}
+			p.setDialects()
+			// Only link keyword if there's a host mapping for it
+			if hasHosts[combine.NormalizeKeyword(svc.Keyword)] {
+				p.Keyword = svc.Keyword
+			} else if opts.RequireHosts {
+				continue
+			}
+			patternWeights[p.ID] = serviceWeights[svc.Keyword]
+			patterns = append(patterns, p)
+		}
+	}
+
+	if opts.URLCredentials {
+		for _, p := range URLCredentialPatterns(keywordHosts) {
+			if opts.PatternDenylist[p.ID] {
+				continue
+			}
+			patternWeights[p.ID] = serviceWeights[p.Keyword]
+			patterns = append(patterns, p)
+		}
+	}
+
+	// Sort patterns by service weight (heaviest first, so consumers
+	// evaluate hot services first), then keyword (empty last), then ID
+	sort.Slice(patterns, func(i, j int) bool {
+		if wi, wj := patternWeights[patterns[i].ID], patternWeights[patterns[j].ID]; wi != wj {
+			return wi > wj
+		}
+		ki, kj := patterns[i].Keyword, patterns[j].Keyword
+		if ki == "" && kj != "" {
+			return false
+		}
+		if ki != "" && kj == "" {
+			return true
+		}
+		if ki != kj {
+			return ki < kj
+		}
+		return patterns[i].ID < patterns[j].ID
+	})
+
+	// Copy exact name map (so we don't expose the package var)
+	exactMap := make(map[string][]string, len(exactNameHostMap))
+	for k, v := range exactNameHostMap {
+		if (len(opts.Categories) > 0 || trimmed) && !anyKeptHost(v, keywordHosts) {
+			continue
+		}
+		if v = applyWildcardPolicy(v, opts.Wildcards, concrete); len(v) == 0 {
+			continue
+		}
+		exactMap[k] = v
+	}
+	var exactSources map[string]string
+	if opts.DeriveExactNames {
+		exactSources = deriveExactNames(full.Services, keywordHosts, exactMap)
+	}
+
+	export := Gondolin{
+		SchemaVersion:    SchemaVersion,
+		GeneratedAt:      full.GeneratedAt,
+		Generator:        full.Generator,
+		Licenses:         full.Licenses,
+		KeywordHostMap:   keywordHosts,
+		PrimaryHostMap:   primaryHosts,
+		PathPrefixes:     pathPrefixes,
+		HostRegions:      hostRegions,
+		HostRoles:        hostRoles,
+		HostTemplates:    hostTemplates,
+		ExactNameHostMap: exactMap,
+		ExactNameSources: exactSources,
+		ValuePatterns:    patterns,
+		ServiceWeights:   serviceWeights,
+	}
+	if opts.Wildcards != WildcardsKeep {
+		export.Wildcards = opts.Wildcards
+	}
+	mode, _ := ParseKeywordMatch(opts.KeywordMatch)
+	export.Matching = NewMatching(mode, keywordHosts, opts.KeywordRegexes)
+	var keywords []string
+	for _, p := range patterns {
+		keywords = append(keywords, p.Keywords...)
+	}
+	export.KeywordBloom = NewKeywordBloom(keywords, keywordBloomFalsePositiveRate)
+	if opts.NameTrie {
+		trie := BuildNameTrie(keywordHosts, exactMap)
+		export.NameTrie = &trie
+	}
+	if opts.MergePatterns {
+		export.MergedPatterns = MergePatterns(patterns)
+	}
+	return export.WithContentHash()
+}
+
+// anyKeptHost reports whether some keyword_host_map entry lists one of hosts.
+func anyKeptHost(hosts []string, keywordHosts map[string][]string) bool {
+	for _, kept := range keywordHosts {
+		for _, k := range kept {
+			for _, h := range hosts {
+				if h == k {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// CountLinkedPatterns returns how many patterns link to a keyword_host_map entry.
+func CountLinkedPatterns(patterns []ValuePattern) int {
+	n := 0
+	for _, p := range patterns {
+		if p.Keyword != "" {
+			n++
+		}
+	}
+	return n
+}
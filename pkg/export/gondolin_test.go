@@ -0,0 +1,423 @@
+package export
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestToGondolinExport(t *testing.T) {
+	full := combine.Export{
+		GeneratedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Stats:       combine.Stats{TotalServices: 3, ServicesWithHosts: 1, ServicesNoHosts: 1, THOnlyServices: 1},
+		Services: []combine.Service{
+			{
+				Keyword:   "stripe",
+				Hosts:     []string{"api.stripe.com"},
+				MatchType: "exact",
+				MatchedTH: []string{"stripe"},
+				Rules: []combine.Rule{
+					{
+						ID:          "stripe-access-token",
+						Description: "A Stripe secret key",
+						Regex:       `(sk_(?:test|live)_[a-zA-Z0-9]{24,99})`,
+						Entropy:     3.5,
+						Keywords:    []string{"sk_test", "sk_live"},
+					},
+				},
+			},
+			{
+				Keyword: "age",
+				Rules: []combine.Rule{
+					{
+						ID:          "age-secret-key",
+						Description: "An age secret key",
+						Regex:       `AGE-SECRET-KEY-1[0-9A-Z]{58}`,
+						Entropy:     3.0,
+						SecretGroup: 1,
+						Keywords:    []string{"age-secret-key-"},
+					},
+				},
+			},
+		},
+		THOnlyHosts: []combine.THOnlyEntry{
+			{Keyword: "abstract", DirName: "abstract", Hosts: []string{"exchange-rates.abstractapi.com"}},
+		},
+		GLNoHosts: []string{"age"},
+	}
+
+	gondolin := ToGondolin(full, Options{})
+
+	// Schema version
+	if gondolin.SchemaVersion != SchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", gondolin.SchemaVersion, SchemaVersion)
+	}
+
+	// Timestamp preserved
+	if !gondolin.GeneratedAt.Equal(full.GeneratedAt) {
+		t.Errorf("GeneratedAt mismatch")
+	}
+
+	// keyword_host_map should include stripe plus policy overrides (aws)
+	if len(gondolin.KeywordHostMap) != 2 {
+		t.Fatalf("KeywordHostMap length = %d, want 2", len(gondolin.KeywordHostMap))
+	}
+	if hosts, ok := gondolin.KeywordHostMap["stripe"]; !ok || len(hosts) != 1 || hosts[0] != "api.stripe.com" {
+		t.Errorf("KeywordHostMap[stripe] = %v, want [api.stripe.com]", hosts)
+	}
+	if hosts, ok := gondolin.KeywordHostMap["aws"]; !ok || len(hosts) != 2 || hosts[0] != "sts.amazonaws.com" || hosts[1] != "*.amazonaws.com" {
+		t.Errorf("KeywordHostMap[aws] = %v, want [sts.amazonaws.com *.amazonaws.com]", hosts)
+	}
+
+	// exact_name_host_map should match the hardcoded map
+	if len(gondolin.ExactNameHostMap) != len(exactNameHostMap) {
+		t.Errorf("ExactNameHostMap length = %d, want %d", len(gondolin.ExactNameHostMap), len(exactNameHostMap))
+	}
+	for k, v := range exactNameHostMap {
+		got, ok := gondolin.ExactNameHostMap[k]
+		if !ok {
+			t.Errorf("ExactNameHostMap missing key %q", k)
+			continue
+		}
+		if len(got) != len(v) {
+			t.Errorf("ExactNameHostMap[%s] length = %d, want %d", k, len(got), len(v))
+		}
+	}
+
+	// Value patterns: 2 total (one from stripe, one from age)
+	if len(gondolin.ValuePatterns) != 2 {
+		t.Fatalf("ValuePatterns length = %d, want 2", len(gondolin.ValuePatterns))
+	}
+
+	// Stripe pattern should have keyword linked
+	var stripePattern, agePattern *ValuePattern
+	for i := range gondolin.ValuePatterns {
+		switch gondolin.ValuePatterns[i].ID {
+		case "stripe-access-token":
+			stripePattern = &gondolin.ValuePatterns[i]
+		case "age-secret-key":
+			agePattern = &gondolin.ValuePatterns[i]
+		}
+	}
+
+	if stripePattern == nil {
+		t.Fatal("missing stripe-access-token pattern")
+	}
+	if stripePattern.Keyword != "stripe" {
+		t.Errorf("stripe pattern keyword = %q, want 'stripe'", stripePattern.Keyword)
+	}
+	if stripePattern.Regex == "" {
+		t.Error("stripe pattern regex is empty")
+	}
+	if len(stripePattern.Keywords) != 2 {
+		t.Errorf("stripe pattern keywords = %v, want [sk_test sk_live]", stripePattern.Keywords)
+	}
+
+	// Age pattern should NOT have keyword (no hosts)
+	if agePattern == nil {
+		t.Fatal("missing age-secret-key pattern")
+	}
+	if agePattern.Keyword != "" {
+		t.Errorf("age pattern keyword = %q, want empty (no hosts)", agePattern.Keyword)
+	}
+	if agePattern.SecretGroup != 1 {
+		t.Errorf("age pattern secret_group = %d, want 1", agePattern.SecretGroup)
+	}
+	if stripePattern.Flags != nil {
+		t.Errorf("stripe pattern flags = %+v, want nil (no flags or anchors)", stripePattern.Flags)
+	}
+
+	// No bloat fields should be present (description, entropy, match_type, matched_th, th_only, gl_no_hosts)
+	// These are enforced by the type system — Gondolin simply doesn't have those fields.
+
+	// Patterns with host linkage
+	linked := CountLinkedPatterns(gondolin.ValuePatterns)
+	if linked != 1 {
+		t.Errorf("linked patterns = %d, want 1 (only stripe)", linked)
+	}
+}
+
+func TestToGondolinExportSorting(t *testing.T) {
+	full := combine.Export{
+		GeneratedAt: time.Now(),
+		Services: []combine.Service{
+			{
+				Keyword: "zebra",
+				Rules: []combine.Rule{
+					{ID: "zebra-key", Regex: `zebra_[a-z]+`},
+				},
+			},
+			{
+				Keyword: "alpha",
+				Hosts:   []string{"api.alpha.com"},
+				Rules: []combine.Rule{
+					{ID: "alpha-key", Regex: `alpha_[a-z]+`, Keywords: []string{"alpha_"}},
+				},
+			},
+		},
+	}
+
+	gondolin := ToGondolin(full, Options{})
+
+	// Patterns with keywords sort first, then by keyword, then by ID
+	if len(gondolin.ValuePatterns) != 2 {
+		t.Fatalf("len = %d, want 2", len(gondolin.ValuePatterns))
+	}
+	if gondolin.ValuePatterns[0].ID != "alpha-key" {
+		t.Errorf("first pattern = %q, want alpha-key (has host linkage, sorts first)", gondolin.ValuePatterns[0].ID)
+	}
+	if gondolin.ValuePatterns[1].ID != "zebra-key" {
+		t.Errorf("second pattern = %q, want zebra-key (no host linkage, sorts last)", gondolin.ValuePatterns[1].ID)
+	}
+}
+
+func TestToGondolinExportPatternDenylist(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{
+				Keyword: "generic",
+				Rules: []combine.Rule{
+					{ID: "generic-api-key", Regex: `(?i)key=[a-z0-9]{16,}`},
+				},
+			},
+			{
+				Keyword: "github",
+				Hosts:   []string{"api.github.com"},
+				Rules: []combine.Rule{
+					{ID: "github-pat", Regex: `ghp_[0-9a-zA-Z]{36}`},
+				},
+			},
+		},
+	}
+
+	gondolin := ToGondolin(full, DefaultOptions())
+	if len(gondolin.ValuePatterns) != 1 || gondolin.ValuePatterns[0].ID != "github-pat" {
+		t.Fatalf("ValuePatterns = %+v, want only github-pat", gondolin.ValuePatterns)
+	}
+
+	// The zero value keeps everything.
+	gondolin = ToGondolin(full, Options{})
+	if len(gondolin.ValuePatterns) != 2 {
+		t.Fatalf("ValuePatterns length = %d, want 2 without denylist", len(gondolin.ValuePatterns))
+	}
+}
+
+func TestToGondolinQualityThresholds(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "age", Rules: []combine.Rule{{ID: "age-secret-key", Regex: `AGE-SECRET-KEY-1[0-9A-Z]{58}`}}},
+			{Keyword: "npm", Hosts: []string{"registry.npmjs.org"}, Rules: []combine.Rule{{ID: "npm-access-token", Regex: `npm_[a-z0-9]{36}`}}},
+			{Keyword: "github", Hosts: []string{"api.github.com"}, Rules: []combine.Rule{{ID: "github-pat", Regex: `ghp_[0-9a-zA-Z]{36}`}}},
+		},
+	}
+
+	g := ToGondolin(full, Options{RequireHosts: true})
+	if len(g.ValuePatterns) != 2 || g.ValuePatterns[0].ID != "github-pat" || g.ValuePatterns[1].ID != "npm-access-token" {
+		t.Errorf("-require-hosts: value_patterns = %+v", g.ValuePatterns)
+	}
+
+	g = ToGondolin(full, Options{MinKeywordLen: 4})
+	if g.KeywordHostMap["npm"] != nil || g.KeywordHostMap["aws"] != nil || g.KeywordHostMap["github"] == nil {
+		t.Errorf("-min-keyword-len: keyword_host_map = %v", g.KeywordHostMap)
+	}
+	if len(g.ValuePatterns) != 3 || g.ValuePatterns[2].ID != "npm-access-token" || g.ValuePatterns[2].Keyword != "" {
+		t.Errorf("-min-keyword-len: npm pattern should stay, unlinked: %+v", g.ValuePatterns)
+	}
+
+	g = ToGondolin(full, Options{MinKeywordLen: 4, RequireHosts: true})
+	if len(g.ValuePatterns) != 1 || g.ValuePatterns[0].ID != "github-pat" {
+		t.Errorf("both: value_patterns = %+v", g.ValuePatterns)
+	}
+}
+
+func TestParsePatternDenylist(t *testing.T) {
+	got, err := ParsePatternDenylist([]byte(`["jwt", "generic-api-key"]`))
+	if err != nil {
+		t.Fatalf("ParsePatternDenylist: %v", err)
+	}
+	if len(got) != 2 || !got["jwt"] || !got["generic-api-key"] {
+		t.Errorf("ParsePatternDenylist = %v, want {jwt, generic-api-key}", got)
+	}
+
+	if _, err := ParsePatternDenylist([]byte(`{"jwt": true}`)); err == nil {
+		t.Error("expected error for non-array denylist")
+	}
+}
+
+func TestContentHashIgnoresGeneratedAtAndGenerator(t *testing.T) {
+	full := combine.Export{
+		GeneratedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Services: []combine.Service{
+			{Keyword: "github", Hosts: []string{"api.github.com"}, Rules: []combine.Rule{{ID: "github-pat", Regex: `ghp_[0-9a-zA-Z]{36}`}}},
+		},
+	}
+	later := full
+	later.GeneratedAt = full.GeneratedAt.Add(24 * time.Hour)
+	later.Generator = &combine.Generator{Version: "v1.2.3", Commit: "abc123"}
+
+	a := full.WithContentHash()
+	b := later.WithContentHash()
+	if a.ContentHash == "" || a.ContentHash != b.ContentHash {
+		t.Fatalf("combined hashes differ across generated_at: %q vs %q", a.ContentHash, b.ContentHash)
+	}
+	if !a.GeneratedAt.Equal(full.GeneratedAt) {
+		t.Error("WithContentHash must preserve GeneratedAt")
+	}
+	if again := a.WithContentHash(); again.ContentHash != a.ContentHash {
+		t.Errorf("rehashing changed the hash: %q vs %q", again.ContentHash, a.ContentHash)
+	}
+
+	ga := ToGondolin(full, Options{})
+	gb := ToGondolin(later, Options{})
+	if ga.ContentHash == "" || ga.ContentHash != gb.ContentHash {
+		t.Fatalf("gondolin hashes differ across generated_at: %q vs %q", ga.ContentHash, gb.ContentHash)
+	}
+
+	changed := full
+	changed.Services = []combine.Service{
+		{Keyword: "github", Hosts: []string{"api.github.com", "uploads.github.com"}, Rules: full.Services[0].Rules},
+	}
+	if gc := ToGondolin(changed, Options{}); gc.ContentHash == ga.ContentHash {
+		t.Error("gondolin hash should change when hosts change")
+	}
+}
+
+func TestToGondolinExportPrimaryHostMap(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "stripe", Hosts: []string{"dashboard.stripe.com", "api.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-access-token", Regex: `sk_live_[a-z]+`}}},
+		},
+	}
+
+	gondolin := ToGondolin(full, Options{})
+	if got := gondolin.PrimaryHostMap["stripe"]; got != "api.stripe.com" {
+		t.Errorf("PrimaryHostMap[stripe] = %q, want api.stripe.com", got)
+	}
+	// Policy overrides get a primary host too (from the curated overrides file).
+	if got := gondolin.PrimaryHostMap["aws"]; got != "sts.amazonaws.com" {
+		t.Errorf("PrimaryHostMap[aws] = %q, want sts.amazonaws.com", got)
+	}
+}
+
+func TestToGondolinExportPolicy(t *testing.T) {
+	full := combine.Combine(nil, []gitleaks.Rule{
+		{ID: "stripe-access-token", Keyword: "stripe", Regex: `sk_live_[a-z]+`},
+	})
+
+	gondolin := ToGondolin(full, Options{})
+	for _, p := range gondolin.ValuePatterns {
+		if p.ID == "stripe-access-token" && p.Policy != combine.PolicyRedact {
+			t.Errorf("gondolin stripe policy = %q, want %q", p.Policy, combine.PolicyRedact)
+		}
+	}
+}
+
+func TestToGondolinExportRegionalHosts(t *testing.T) {
+	full := combine.Combine(
+		[]trufflehog.Detector{{DirName: "datadogtoken", Keyword: "datadog", Hosts: []string{"api.datadoghq.com"}}},
+		[]gitleaks.Rule{{ID: "datadog-access-token", Keyword: "datadog", Regex: `[a-f0-9]{40}`}},
+	)
+	svc := full.Services[0]
+
+	gondolin := ToGondolin(full, Options{})
+	hosts := gondolin.KeywordHostMap["datadog"]
+	if len(hosts) != 1+len(svc.RegionalHosts) {
+		t.Errorf("KeywordHostMap[datadog] = %v, want TH host plus regional hosts", hosts)
+	}
+	if got := gondolin.HostRegions["api.datadoghq.eu"]; got != "eu1" {
+		t.Errorf("HostRegions[api.datadoghq.eu] = %q, want eu1", got)
+	}
+	if _, ok := gondolin.HostRegions["api.datadoghq.com"]; ok {
+		t.Error("the default endpoint should not carry a region")
+	}
+}
+
+func TestToGondolinRegionalHostsNeedPrimaryHosts(t *testing.T) {
+	full := combine.Combine(
+		[]trufflehog.Detector{{DirName: "datadogtoken", Keyword: "datadog"}},
+		[]gitleaks.Rule{{ID: "datadog-access-token", Keyword: "datadog", Regex: `[a-f0-9]{40}`}},
+	)
+	if len(full.Services[0].RegionalHosts) == 0 {
+		t.Fatal("datadog should have curated regional hosts")
+	}
+
+	gondolin := ToGondolin(full, Options{})
+	if hosts, ok := gondolin.KeywordHostMap["datadog"]; ok {
+		t.Errorf("KeywordHostMap[datadog] = %v, want no entry for a service without hosts", hosts)
+	}
+	if len(gondolin.HostRegions) != 0 {
+		t.Errorf("HostRegions = %v, want none", gondolin.HostRegions)
+	}
+}
+
+func TestToGondolinExportHostRoles(t *testing.T) {
+	full := combine.Combine(
+		[]trufflehog.Detector{{DirName: "example", Keyword: "example", Hosts: []string{"api.example.com", "auth.example.com"}}},
+		[]gitleaks.Rule{{ID: "example-api-key", Keyword: "example", Regex: `ex_[a-z]{16}`}},
+	)
+
+	gondolin := ToGondolin(full, Options{})
+	if gondolin.HostRoles["auth.example.com"] != combine.HostRoleAuth {
+		t.Errorf("gondolin HostRoles = %v", gondolin.HostRoles)
+	}
+	if gondolin.HostRoles["sts.amazonaws.com"] != combine.HostRoleAPI {
+		t.Errorf("policy override hosts should be classified too, got %v", gondolin.HostRoles)
+	}
+}
+
+func TestToGondolinCarriesLicenses(t *testing.T) {
+	full := combine.Combine(nil, nil)
+	g := ToGondolin(full, Options{})
+	if len(g.Licenses) == 0 || !reflect.DeepEqual(g.Licenses, full.Licenses) {
+		t.Errorf("gondolin licenses = %+v, want the full export's", g.Licenses)
+	}
+	v1, dropped, err := Migrate(g, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v1.Licenses != nil || !strings.Contains(strings.Join(dropped, "; "), "licenses") {
+		t.Errorf("migrate to v1 kept licenses or didn't report them: %v", dropped)
+	}
+}
+
+func TestToGondolinExcludesDeprecatedServices(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{
+		{Keyword: "rockset", Hosts: []string{"api.rs2.usw2.rockset.com"}, Deprecated: "shut down", Rules: []combine.Rule{{ID: "rockset-api-key", Regex: `[a-zA-Z0-9]{64}`}}},
+		{Keyword: "stripe", Hosts: []string{"api.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-access-token", Regex: `sk_live_[a-z]+`}}},
+	}}
+	g := ToGondolin(full, Options{})
+	if _, ok := g.KeywordHostMap["rockset"]; ok {
+		t.Error("deprecated service kept in keyword_host_map")
+	}
+	if len(g.ValuePatterns) != 1 || g.ValuePatterns[0].ID != "stripe-access-token" {
+		t.Errorf("value_patterns = %+v, want only stripe-access-token", g.ValuePatterns)
+	}
+}
+
+func TestToGondolinHostTemplates(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{{
+		Keyword:       "slack",
+		HostTemplates: []trufflehog.HostTemplate{{Template: "{workspace}.slack.com", Wildcard: "*.slack.com"}},
+	}}}
+	g := ToGondolin(full, Options{})
+	if !reflect.DeepEqual(g.KeywordHostMap["slack"], []string{"*.slack.com"}) {
+		t.Errorf("keyword_host_map[slack] = %v, want the template wildcard", g.KeywordHostMap["slack"])
+	}
+	if got := g.HostTemplates["*.slack.com"]; got != "{workspace}.slack.com" {
+		t.Errorf("host_templates[*.slack.com] = %q", got)
+	}
+	if errs := ValidateGondolin(g, ValidateOptions{}); len(errs) != 0 {
+		t.Errorf("ValidateGondolin: %v", errs)
+	}
+
+	g.HostTemplates["*.slack.com"] = "{workspace}.example.com"
+	errs := ValidateGondolin(g.WithContentHash(), ValidateOptions{})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "host_templates") {
+		t.Errorf("ValidateGondolin with a mismatched template = %v, want one host_templates error", errs)
+	}
+}
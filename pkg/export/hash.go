@@ -0,0 +1,18 @@
+package export
+
+import (
+	"time"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// WithContentHash returns a copy of g with ContentHash set to the digest of
+// its content, excluding GeneratedAt, Generator, Removed and the hash itself.
+func (g Gondolin) WithContentHash() Gondolin {
+	generatedAt, generator, removed := g.GeneratedAt, g.Generator, g.Removed
+	g.GeneratedAt, g.Generator, g.Removed = time.Time{}, nil, nil
+	g.ContentHash = ""
+	g.ContentHash = combine.HashJSON(g)
+	g.GeneratedAt, g.Generator, g.Removed = generatedAt, generator, removed
+	return g
+}
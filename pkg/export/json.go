@@ -0,0 +1,149 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"secret-detector-export/pkg/jsonstream"
+)
+
+// EncodeJSON writes v as JSON followed by a newline. Output is indented unless
+// compact is set. Services, patterns and map entries are written one at a
+// time, so memory stays flat however large the export grows; the bytes
+// match json.Encoder's.
+func EncodeJSON(w io.Writer, v any, compact bool) error {
+	indent := "  "
+	if compact {
+		indent = ""
+	}
+	if err := jsonstream.Encode(w, v, indent); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// PruneEmptyJSON round-trips v through JSON and drops object fields whose
+// value is null, an empty array, or an empty object. Required scalar fields
+// (schema_version, ids, regexes) are never empty, so only optional
+// containers disappear. Members keep the order v encodes them in (struct
+// declaration order, sorted map keys), so compact output lists fields like
+// the indented one.
+func PruneEmptyJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	generic, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+	return pruneEmpty(generic), nil
+}
+
+// orderedObject is a decoded JSON object that marshals its members in the
+// order they were read.
+type orderedObject []orderedMember
+
+type orderedMember struct {
+	key   string
+	value any
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// decodeOrdered reads one JSON value from dec: objects as orderedObject,
+// arrays as []any, numbers as json.Number.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := orderedObject{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, orderedMember{key: key.(string), value: value})
+		}
+		_, err := dec.Token() // }
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err := dec.Token() // ]
+		return arr, err
+	}
+	return tok, nil
+}
+
+func pruneEmpty(v any) any {
+	switch t := v.(type) {
+	case orderedObject:
+		kept := t[:0]
+		for _, m := range t {
+			m.value = pruneEmpty(m.value)
+			if !isEmptyJSONValue(m.value) {
+				kept = append(kept, m)
+			}
+		}
+		return kept
+	case []any:
+		for i, child := range t {
+			t[i] = pruneEmpty(child)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func isEmptyJSONValue(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(t) == 0
+	case orderedObject:
+		return len(t) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPruneEmptyJSONDropsEmptyContainers(t *testing.T) {
+	g := Gondolin{
+		SchemaVersion:    1,
+		GeneratedAt:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		KeywordHostMap:   map[string][]string{"stripe": {"api.stripe.com"}, "empty": {}},
+		ExactNameHostMap: map[string][]string{},
+		ValuePatterns:    []ValuePattern{{ID: "stripe-access-token", Regex: `sk_live_[a-z]+`}},
+	}
+
+	pruned, err := PruneEmptyJSON(g)
+	if err != nil {
+		t.Fatalf("PruneEmptyJSON: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, pruned, true); err != nil {
+		t.Fatalf("EncodeJSON: %v", err)
+	}
+	out := buf.String()
+
+	if strings.Contains(out, "\n  ") {
+		t.Errorf("compact output should not be indented: %s", out)
+	}
+	if strings.Contains(out, "exact_name_host_map") {
+		t.Errorf("empty exact_name_host_map should be dropped: %s", out)
+	}
+	if strings.Contains(out, `"empty"`) {
+		t.Errorf("keyword with no hosts should be dropped: %s", out)
+	}
+
+	var decoded Gondolin
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode compact output: %v", err)
+	}
+	if decoded.SchemaVersion != 1 || len(decoded.ValuePatterns) != 1 {
+		t.Errorf("decoded = %+v, want schema 1 with one pattern", decoded)
+	}
+	if hosts := decoded.KeywordHostMap["stripe"]; len(hosts) != 1 || hosts[0] != "api.stripe.com" {
+		t.Errorf("KeywordHostMap[stripe] = %v, want [api.stripe.com]", hosts)
+	}
+}
+
+func TestPruneEmptyJSONKeepsFieldOrder(t *testing.T) {
+	// Nothing here is empty, so pruning must leave the encoding unchanged,
+	// schema_version first and fields in declaration order.
+	g := Gondolin{
+		SchemaVersion:    2,
+		GeneratedAt:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		KeywordHostMap:   map[string][]string{"stripe": {"api.stripe.com"}, "aws": {"sts.amazonaws.com"}},
+		ExactNameHostMap: map[string][]string{"STRIPE_KEY": {"api.stripe.com"}},
+		ValuePatterns:    []ValuePattern{{ID: "stripe-access-token", Keyword: "stripe", Regex: `sk_live_[a-z]+`}},
+	}
+	pruned, err := PruneEmptyJSON(g)
+	if err != nil {
+		t.Fatalf("PruneEmptyJSON: %v", err)
+	}
+	var got, want bytes.Buffer
+	if err := EncodeJSON(&got, pruned, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncodeJSON(&want, g, true); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("pruned output\n%s\nwant\n%s", got.String(), want.String())
+	}
+	if !strings.HasPrefix(got.String(), `{"schema_version":2,`) {
+		t.Errorf("schema_version should come first: %s", got.String())
+	}
+}
+
+func TestEncodeJSONIndentsByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, map[string]int{"a": 1}, false); err != nil {
+		t.Fatalf("EncodeJSON: %v", err)
+	}
+	if got, want := buf.String(), "{\n  \"a\": 1\n}\n"; got != want {
+		t.Errorf("EncodeJSON = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,192 @@
+package export
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"sort"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// Lint check names, stable so CI can grep or suppress them.
+const (
+	LintShortKeyword      = "short-keyword"
+	LintUnanchoredRegex   = "unanchored-regex"
+	LintUnboundedWildcard = "unbounded-wildcard"
+	LintSharedHost        = "shared-host"
+	LintNoKeywords        = "no-keywords"
+	LintEmptyService      = "empty-service"
+	LintHomographHost     = "homograph-host"
+	LintDeadEntropy       = "dead-entropy" // reported by the lint subcommand via pkg/samples
+)
+
+// LintOptions sets the thresholds for Lint checks.
+type LintOptions struct {
+	MinKeywordLen      int // keywords shorter than this are flagged
+	MaxServicesPerHost int // hosts mapped from more keywords than this are flagged
+}
+
+// DefaultLintOptions returns the thresholds used by the lint subcommand.
+func DefaultLintOptions() LintOptions {
+	return LintOptions{MinKeywordLen: 4, MaxServicesPerHost: 3}
+}
+
+// LintIssue is one suspicious item. Unlike validation errors, lint issues
+// are legal exports that deserve a human look.
+type LintIssue struct {
+	Check   string `json:"check"`
+	Subject string `json:"subject"` // keyword, host, or rule ID
+	Message string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Check, i.Subject, i.Message)
+}
+
+// LintFull checks a full export. Issues are sorted by check, then subject.
+func LintFull(e combine.Export, opts LintOptions) []LintIssue {
+	var issues []LintIssue
+	hostKeywords := make(map[string][]string)
+	for _, svc := range e.Services {
+		issues = append(issues, lintKeyword(svc.Keyword, opts)...)
+		if len(svc.Hosts) == 0 && len(svc.RegionalHosts) == 0 && len(svc.Rules) == 0 {
+			issues = append(issues, LintIssue{Check: LintEmptyService, Subject: svc.Keyword, Message: "service has no hosts and no rules"})
+		}
+		for _, h := range svc.AllowedHosts() {
+			hostKeywords[h] = append(hostKeywords[h], svc.Keyword)
+		}
+		for _, r := range svc.Rules {
+			issues = append(issues, lintRule(r.ID, r.Regex, r.Keywords)...)
+		}
+	}
+	for _, th := range e.THOnlyHosts {
+		for _, h := range th.Hosts {
+			hostKeywords[h] = append(hostKeywords[h], th.Keyword)
+		}
+	}
+	issues = append(issues, lintSharedHosts(hostKeywords, opts)...)
+	issues = append(issues, lintHomographs(hostKeywords)...)
+	sortLintIssues(issues)
+	return issues
+}
+
+// LintGondolin checks a gondolin export. Issues are sorted by check, then
+// subject.
+func LintGondolin(g Gondolin, opts LintOptions) []LintIssue {
+	var issues []LintIssue
+	hostKeywords := make(map[string][]string)
+	for keyword, hosts := range g.KeywordHostMap {
+		issues = append(issues, lintKeyword(keyword, opts)...)
+		if len(hosts) == 0 {
+			issues = append(issues, LintIssue{Check: LintEmptyService, Subject: keyword, Message: "keyword maps to no hosts"})
+		}
+		for _, h := range hosts {
+			hostKeywords[h] = append(hostKeywords[h], keyword)
+		}
+	}
+	for _, p := range g.ValuePatterns {
+		issues = append(issues, lintRule(p.ID, p.Regex, p.Keywords)...)
+	}
+	issues = append(issues, lintSharedHosts(hostKeywords, opts)...)
+	issues = append(issues, lintHomographs(hostKeywords)...)
+	sortLintIssues(issues)
+	return issues
+}
+
+func lintKeyword(keyword string, opts LintOptions) []LintIssue {
+	if n := len(combine.NormalizeKeyword(keyword)); n < opts.MinKeywordLen {
+		return []LintIssue{{
+			Check:   LintShortKeyword,
+			Subject: keyword,
+			Message: fmt.Sprintf("keyword is %d chars (< %d); substring matching on env names will over-match", n, opts.MinKeywordLen),
+		}}
+	}
+	return nil
+}
+
+func lintRule(id, expr string, keywords []string) []LintIssue {
+	var issues []LintIssue
+	if len(keywords) == 0 {
+		issues = append(issues, LintIssue{Check: LintNoKeywords, Subject: id, Message: "rule has no keyword pre-filter; its regex runs on every value"})
+	}
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		// Compile failures are validate's job.
+		return issues
+	}
+	if !hasOp(re, isAssertion) {
+		issues = append(issues, LintIssue{Check: LintUnanchoredRegex, Subject: id, Message: "regex has no anchor or word boundary"})
+	}
+	if hasOp(re, isUnboundedWildcard) {
+		issues = append(issues, LintIssue{Check: LintUnboundedWildcard, Subject: id, Message: "regex contains an unbounded wildcard (.* or .+)"})
+	}
+	return issues
+}
+
+func lintSharedHosts(hostKeywords map[string][]string, opts LintOptions) []LintIssue {
+	var issues []LintIssue
+	for host, keywords := range hostKeywords {
+		if len(keywords) > opts.MaxServicesPerHost {
+			sort.Strings(keywords)
+			issues = append(issues, LintIssue{
+				Check:   LintSharedHost,
+				Subject: host,
+				Message: fmt.Sprintf("host is mapped from %d services (> %d): %v", len(keywords), opts.MaxServicesPerHost, keywords),
+			})
+		}
+	}
+	return issues
+}
+
+func lintHomographs(hostKeywords map[string][]string) []LintIssue {
+	var issues []LintIssue
+	for host := range hostKeywords {
+		if risk := trufflehog.HomographRisk(host); risk != "" {
+			issues = append(issues, LintIssue{Check: LintHomographHost, Subject: host, Message: "possible lookalike host: " + risk})
+		}
+	}
+	return issues
+}
+
+func sortLintIssues(issues []LintIssue) {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Check != issues[j].Check {
+			return issues[i].Check < issues[j].Check
+		}
+		if issues[i].Subject != issues[j].Subject {
+			return issues[i].Subject < issues[j].Subject
+		}
+		return issues[i].Message < issues[j].Message
+	})
+}
+
+// hasOp reports whether any node in re satisfies pred.
+func hasOp(re *syntax.Regexp, pred func(*syntax.Regexp) bool) bool {
+	if pred(re) {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasOp(sub, pred) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAssertion(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText, syntax.OpWordBoundary:
+		return true
+	}
+	return false
+}
+
+func isUnboundedWildcard(re *syntax.Regexp) bool {
+	unbounded := re.Op == syntax.OpStar || re.Op == syntax.OpPlus || (re.Op == syntax.OpRepeat && re.Max < 0)
+	if !unbounded || len(re.Sub) != 1 {
+		return false
+	}
+	op := re.Sub[0].Op
+	return op == syntax.OpAnyChar || op == syntax.OpAnyCharNotNL
+}
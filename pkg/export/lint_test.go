@@ -0,0 +1,57 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestLintFull(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-access-token", Regex: `\b(sk_live_[a-z0-9]{24})\b`, Keywords: []string{"sk_live_"}}}},
+			{Keyword: "aws", Hosts: []string{"shared.example.com"}, Rules: []combine.Rule{{ID: "aws-loose", Regex: `key=(.*)`}}},
+			{Keyword: "alpha", Hosts: []string{"shared.example.com"}},
+			{Keyword: "bravo", Hosts: []string{"shared.example.com"}},
+			{Keyword: "ghost"},
+		},
+		THOnlyHosts: []combine.THOnlyEntry{{Keyword: "charlie", Hosts: []string{"shared.example.com"}}},
+	}
+
+	got := map[string][]string{}
+	for _, issue := range LintFull(full, DefaultLintOptions()) {
+		got[issue.Check] = append(got[issue.Check], issue.Subject)
+	}
+	want := map[string][]string{
+		LintEmptyService:      {"ghost"},
+		LintNoKeywords:        {"aws-loose"},
+		LintSharedHost:        {"shared.example.com"},
+		LintShortKeyword:      {"aws"},
+		LintUnanchoredRegex:   {"aws-loose"},
+		LintUnboundedWildcard: {"aws-loose"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LintFull issues = %v, want %v", got, want)
+	}
+}
+
+func TestLintGondolin(t *testing.T) {
+	g := Gondolin{
+		KeywordHostMap: map[string][]string{"stripe": {"api.stripe.com"}, "empty": nil, "paypal": {"xn--pypal-4ve.com"}},
+		ValuePatterns: []ValuePattern{
+			{ID: "anchored", Regex: `^ghp_[A-Za-z0-9]{36}$`, Keywords: []string{"ghp_"}},
+			{ID: "greedy", Regex: `\btoken:.+`, Keywords: []string{"token"}},
+			{ID: "broken", Regex: `(`, Keywords: []string{"x"}},
+		},
+	}
+	issues := LintGondolin(g, DefaultLintOptions())
+	want := []LintIssue{
+		{Check: LintEmptyService, Subject: "empty", Message: "keyword maps to no hosts"},
+		{Check: LintHomographHost, Subject: "xn--pypal-4ve.com", Message: `possible lookalike host: label "pаypal" mixes scripts Cyrillic+Latin`},
+		{Check: LintUnboundedWildcard, Subject: "greedy", Message: "regex contains an unbounded wildcard (.* or .+)"},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("LintGondolin = %v, want %v", issues, want)
+	}
+}
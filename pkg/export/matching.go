@@ -0,0 +1,78 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// Keyword match modes a gondolin export declares in matching.keywords: how
+// a keyword_host_map key matches an env var name once both are lower-cased
+// and the keyword is stripped of - and _.
+const (
+	KeywordMatchSubstring = "substring" // the keyword occurs anywhere in the name, also stripped of - and _ (the default)
+	KeywordMatchWord      = "word"      // the keyword spells one or more consecutive whole words of the name
+	KeywordMatchPrefix    = "prefix"    // the keyword spells the name's leading word or words
+)
+
+// Matching declares how consumers apply the name maps to env var names, so
+// they don't each have to guess. Words are runs of letters and digits.
+type Matching struct {
+	Keywords      string `json:"keywords"`       // a KeywordMatch constant
+	CaseSensitive bool   `json:"case_sensitive"` // always false
+	IgnoredChars  string `json:"ignored_chars"`  // stripped from keywords (and, in substring mode, names) before comparing
+	ExactNames    string `json:"exact_names"`    // "override": an exact_name_host_map hit replaces keyword matches
+	// KeywordRegexes maps each keyword_host_map key to a regex that
+	// implements Keywords over the raw name (opt-in, word and prefix only).
+	KeywordRegexes map[string]string `json:"keyword_regexes,omitempty"`
+}
+
+// ParseKeywordMatch checks a -keyword-match value. "" is
+// KeywordMatchSubstring.
+func ParseKeywordMatch(s string) (string, error) {
+	switch s {
+	case "", KeywordMatchSubstring:
+		return KeywordMatchSubstring, nil
+	case KeywordMatchWord, KeywordMatchPrefix:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown keyword match %q (want substring, word or prefix)", s)
+}
+
+// NewMatching declares mode, with keyword regexes for every key of
+// keywordHosts when regexes is set.
+func NewMatching(mode string, keywordHosts map[string][]string, regexes bool) *Matching {
+	m := &Matching{Keywords: mode, IgnoredChars: "-_", ExactNames: "override"}
+	if regexes {
+		m.KeywordRegexes = make(map[string]string, len(keywordHosts))
+		for k := range keywordHosts {
+			m.KeywordRegexes[k] = KeywordRegex(k, mode)
+		}
+	}
+	return m
+}
+
+// KeywordRegex spells keyword match mode mode as a case-insensitive regex
+// over the raw env var name. In word and prefix mode, non-word characters
+// may separate any two characters of the keyword, since words join: "cisco"
+// and "meraki" spell "ciscomeraki".
+func KeywordRegex(keyword, mode string) string {
+	sep := "[^a-z0-9]*"
+	if mode == KeywordMatchSubstring {
+		sep = "[-_]*"
+	}
+	var chars []string
+	for _, r := range combine.NormalizeKeyword(keyword) {
+		chars = append(chars, regexp.QuoteMeta(string(r)))
+	}
+	body := strings.Join(chars, sep)
+	switch mode {
+	case KeywordMatchWord:
+		return `(?i)(?:^|[^a-z0-9])` + body + `(?:[^a-z0-9]|$)`
+	case KeywordMatchPrefix:
+		return `(?i)^[^a-z0-9]*` + body + `(?:[^a-z0-9]|$)`
+	}
+	return `(?i)` + body
+}
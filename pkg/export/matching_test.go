@@ -0,0 +1,43 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestMatchingDeclaration(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{{Keyword: "cisco-meraki", Hosts: []string{"api.meraki.com"}}}}
+
+	g := ToGondolin(full, Options{})
+	if m := g.Matching; m == nil || m.Keywords != KeywordMatchSubstring || m.CaseSensitive || m.IgnoredChars != "-_" || m.ExactNames != "override" || m.KeywordRegexes != nil {
+		t.Errorf("default matching = %+v", g.Matching)
+	}
+
+	g = ToGondolin(full, Options{KeywordMatch: KeywordMatchWord, KeywordRegexes: true})
+	if got, want := g.Matching.KeywordRegexes["cisco-meraki"], `(?i)(?:^|[^a-z0-9])c[^a-z0-9]*i[^a-z0-9]*s[^a-z0-9]*c[^a-z0-9]*o[^a-z0-9]*m[^a-z0-9]*e[^a-z0-9]*r[^a-z0-9]*a[^a-z0-9]*k[^a-z0-9]*i(?:[^a-z0-9]|$)`; got != want {
+		t.Errorf("word regex = %s, want %s", got, want)
+	}
+	if len(g.Matching.KeywordRegexes) != len(g.KeywordHostMap) {
+		t.Errorf("%d regexes for %d keywords", len(g.Matching.KeywordRegexes), len(g.KeywordHostMap))
+	}
+	if errs := ValidateGondolin(g, ValidateOptions{}); len(errs) != 0 {
+		t.Errorf("valid export: %v", errs)
+	}
+	if _, err := ParseKeywordMatch("fuzzy"); err == nil {
+		t.Error("ParseKeywordMatch(fuzzy): no error")
+	}
+
+	g.Matching = &Matching{Keywords: "fuzzy", CaseSensitive: true, KeywordRegexes: map[string]string{"cisco-meraki": "(", "nope": "x"}}
+	errs := ValidateGondolin(g, ValidateOptions{})
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	for _, want := range []string{"matching.keywords", "matching.case_sensitive", "matching.keyword_regexes[cisco-meraki]", "matching.keyword_regexes[nope]: keyword not in keyword_host_map"} {
+		if !strings.Contains(strings.Join(msgs, "\n"), want) {
+			t.Errorf("validation lacks %q: %v", want, msgs)
+		}
+	}
+}
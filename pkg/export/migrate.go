@@ -0,0 +1,184 @@
+package export
+
+import (
+	"fmt"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// gondolinUpgrades[v] converts a schema v export to v+1. Upgrades re-derive
+// new fields from data already present in the older document plus the
+// curated data files compiled into this binary.
+var gondolinUpgrades = map[int]func(Gondolin) Gondolin{
+	1: upgradeGondolinV1ToV2,
+}
+
+// gondolinDowngrades[v] converts a schema v export to v-1 and reports which
+// populated fields had to be dropped.
+var gondolinDowngrades = map[int]func(Gondolin) (Gondolin, []string){
+	2: downgradeGondolinV2ToV1,
+}
+
+// Migrate converts g to schema version to, one step at a time. The
+// returned notes describe data lost by downgrades.
+func Migrate(g Gondolin, to int) (Gondolin, []string, error) {
+	if g.SchemaVersion < 1 || g.SchemaVersion > SchemaVersion {
+		return g, nil, fmt.Errorf("unsupported input schema_version %d (supported: 1..%d)", g.SchemaVersion, SchemaVersion)
+	}
+	if to < 1 || to > SchemaVersion {
+		return g, nil, fmt.Errorf("unsupported target schema_version %d (supported: 1..%d)", to, SchemaVersion)
+	}
+
+	var notes []string
+	for g.SchemaVersion < to {
+		g = gondolinUpgrades[g.SchemaVersion](g)
+	}
+	for g.SchemaVersion > to {
+		var dropped []string
+		g, dropped = gondolinDowngrades[g.SchemaVersion](g)
+		notes = append(notes, dropped...)
+	}
+	return g, notes, nil
+}
+
+func upgradeGondolinV1ToV2(g Gondolin) Gondolin {
+	patterns := make([]ValuePattern, len(g.ValuePatterns))
+	for i, p := range g.ValuePatterns {
+		p.Flags = DerivePatternFlags(p.Regex)
+		p.AnchoredRegex = AnchoredRegex(p.Regex)
+		p.setDialects()
+		category := combine.ServiceCategory(p.Keyword)
+		p.Policy = combine.PolicyHint(p.ID, category)
+		p.Severity = combine.RuleSeverity(p.ID, p.Keyword, category)
+		// v1 patterns don't carry the entropy threshold, so the score
+		// assumes there is none.
+		p.FalsePositiveScore = FalsePositiveScore(p.Regex, 0, p.Keywords)
+		patterns[i] = p
+	}
+	g.ValuePatterns = patterns
+
+	// v1 never carried verification paths, so path_prefixes stays empty and
+	// roles are classified from host names alone.
+	g.PrimaryHostMap = make(map[string]string)
+	g.HostRoles = make(map[string]string)
+	g.HostRegions = make(map[string]string)
+	g.HostTemplates = make(map[string]string)
+	for keyword, hosts := range g.KeywordHostMap {
+		if primary := combine.ChoosePrimaryHost(keyword, hosts); primary != "" {
+			g.PrimaryHostMap[keyword] = primary
+		}
+		for h, role := range combine.ClassifyHostRoles(hosts, nil) {
+			g.HostRoles[h] = role
+		}
+		present := make(map[string]bool, len(hosts))
+		for _, h := range hosts {
+			present[h] = true
+		}
+		for _, rh := range combine.RegionalHostsFor(keyword) {
+			if present[rh.Host] {
+				g.HostRegions[rh.Host] = rh.Region
+			}
+		}
+		for _, t := range combine.TenantHostsFor(keyword) {
+			if present[t.Wildcard] {
+				g.HostTemplates[t.Wildcard] = t.Template
+			}
+		}
+	}
+
+	g.SchemaVersion = 2
+	return g.WithContentHash()
+}
+
+func downgradeGondolinV2ToV1(g Gondolin) (Gondolin, []string) {
+	var dropped []string
+	note := func(field string, n int) {
+		if n > 0 {
+			dropped = append(dropped, fmt.Sprintf("dropped %s (%d entries)", field, n))
+		}
+	}
+	note("primary_host_map", len(g.PrimaryHostMap))
+	note("path_prefixes", len(g.PathPrefixes))
+	note("host_regions", len(g.HostRegions))
+	note("host_templates", len(g.HostTemplates))
+	note("host_roles", len(g.HostRoles))
+	if g.Generator != nil {
+		dropped = append(dropped, "dropped generator")
+	}
+	if g.NameTrie != nil {
+		dropped = append(dropped, "dropped name_trie")
+	}
+	note("merged_patterns", len(g.MergedPatterns))
+	note("licenses", len(g.Licenses))
+	if g.KeywordBloom != nil {
+		dropped = append(dropped, "dropped keyword_bloom")
+	}
+	if g.Removed != nil {
+		dropped = append(dropped, "dropped removed")
+	}
+	if g.Wildcards != "" {
+		dropped = append(dropped, "dropped wildcards")
+	}
+	note("service_weights", len(g.ServiceWeights))
+	note("exact_name_sources", len(g.ExactNameSources))
+	if g.Matching != nil {
+		dropped = append(dropped, "dropped matching ("+g.Matching.Keywords+")")
+	}
+
+	flags, anchored, dialects, policies, severities, scores := 0, 0, 0, 0, 0, 0
+	patterns := make([]ValuePattern, len(g.ValuePatterns))
+	for i, p := range g.ValuePatterns {
+		if p.Flags != nil {
+			flags++
+		}
+		if p.AnchoredRegex != "" {
+			anchored++
+		}
+		if p.RegexRE2 != "" || p.RegexJS != "" || p.AnchoredRegexRE2 != "" || p.AnchoredRegexJS != "" || p.JSIncompatible != "" {
+			dialects++
+		}
+		if p.Policy != "" {
+			policies++
+		}
+		if p.Severity != "" {
+			severities++
+		}
+		if p.FalsePositiveScore != 0 {
+			scores++
+		}
+		p.Flags = nil
+		p.AnchoredRegex = ""
+		p.RegexRE2, p.RegexJS, p.JSIncompatible = "", "", ""
+		p.AnchoredRegexRE2, p.AnchoredRegexJS = "", ""
+		p.Policy = ""
+		p.Severity = ""
+		p.FalsePositiveScore = 0
+		patterns[i] = p
+	}
+	note("value_patterns[].flags", flags)
+	note("value_patterns[].anchored_regex", anchored)
+	note("value_patterns[] dialect variants", dialects)
+	note("value_patterns[].policy", policies)
+	note("value_patterns[].severity", severities)
+	note("value_patterns[].false_positive_score", scores)
+
+	g.ValuePatterns = patterns
+	g.PrimaryHostMap = nil
+	g.PathPrefixes = nil
+	g.HostRegions = nil
+	g.HostTemplates = nil
+	g.HostRoles = nil
+	g.Generator = nil
+	g.NameTrie = nil
+	g.MergedPatterns = nil
+	g.Licenses = nil
+	g.KeywordBloom = nil
+	g.Removed = nil
+	g.Wildcards = ""
+	g.ServiceWeights = nil
+	g.Matching = nil
+	g.ExactNameSources = nil
+	g.ContentHash = ""
+	g.SchemaVersion = 1
+	return g, dropped
+}
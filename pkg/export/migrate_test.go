@@ -0,0 +1,71 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestMigrateGondolinRoundTrip(t *testing.T) {
+	full := combine.Export{
+		GeneratedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Services: []combine.Service{
+			{
+				Keyword: "stripe",
+				Hosts:   []string{"api.stripe.com", "dashboard.stripe.com"},
+				Rules:   []combine.Rule{{ID: "stripe-access-token", Regex: `(?i)sk_live_[a-z0-9]{24}`, Policy: combine.PolicyRedact}},
+			},
+		},
+	}
+	current := ToGondolin(full, Options{})
+
+	v1, notes, err := Migrate(current, 1)
+	if err != nil {
+		t.Fatalf("downgrade: %v", err)
+	}
+	if v1.SchemaVersion != 1 {
+		t.Fatalf("downgraded SchemaVersion = %d, want 1", v1.SchemaVersion)
+	}
+	if v1.ContentHash != "" || v1.PrimaryHostMap != nil || v1.HostRoles != nil || v1.ValuePatterns[0].Flags != nil {
+		t.Errorf("downgrade left v2 fields behind: %+v", v1)
+	}
+	if len(notes) == 0 {
+		t.Error("downgrade should report dropped fields")
+	}
+	if current.ValuePatterns[0].Flags == nil {
+		t.Error("downgrade must not mutate its input")
+	}
+
+	v2, notes, err := Migrate(v1, 2)
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("upgrade notes = %v, want none", notes)
+	}
+	if v2.SchemaVersion != 2 || v2.ContentHash == "" {
+		t.Errorf("upgraded SchemaVersion/ContentHash = %d/%q", v2.SchemaVersion, v2.ContentHash)
+	}
+	if got := v2.PrimaryHostMap["stripe"]; got != "api.stripe.com" {
+		t.Errorf("upgraded PrimaryHostMap[stripe] = %q, want api.stripe.com", got)
+	}
+	if p := v2.ValuePatterns[0]; p.Flags == nil || !p.Flags.CaseInsensitive || p.Policy != combine.PolicyRedact || p.Severity != combine.SeverityCritical {
+		t.Errorf("upgraded pattern = %+v, want case-insensitive flags, redact policy and critical severity", p)
+	}
+	if v2.HostRoles["api.stripe.com"] != combine.HostRoleAPI {
+		t.Errorf("upgraded HostRoles = %v", v2.HostRoles)
+	}
+}
+
+func TestMigrateGondolinRejectsUnknownVersions(t *testing.T) {
+	if _, _, err := Migrate(Gondolin{SchemaVersion: 99}, 1); err == nil {
+		t.Error("expected error for unknown input schema_version")
+	}
+	if _, _, err := Migrate(Gondolin{SchemaVersion: 1}, 99); err == nil {
+		t.Error("expected error for unknown target schema_version")
+	}
+	if _, _, err := Migrate(Gondolin{}, 1); err == nil {
+		t.Error("expected error for missing schema_version")
+	}
+}
@@ -0,0 +1,68 @@
+package export
+
+import (
+	"regexp"
+	"regexp/syntax"
+)
+
+// PatternFlags describes how a value pattern's regex should be compiled by
+// engines that don't understand Go's leading inline flag group (notably JS
+// RegExp). All fields are derived from the original Gitleaks expression.
+type PatternFlags struct {
+	CaseInsensitive bool `json:"case_insensitive,omitempty"` // leading (?i)
+	Multiline       bool `json:"multiline,omitempty"`        // leading (?m)
+	DotAll          bool `json:"dot_all,omitempty"`          // leading (?s)
+	AnchoredStart   bool `json:"anchored_start,omitempty"`   // starts with ^ or \A
+	AnchoredEnd     bool `json:"anchored_end,omitempty"`     // ends with $ or \z
+}
+
+// leadingFlagsRe matches a global inline flag group at the very start of a
+// regex, e.g. "(?i)" or "(?is)". Scoped groups like "(?i:...)" don't match.
+var leadingFlagsRe = regexp.MustCompile(`^\(\?([imsU]+)\)`)
+
+// DerivePatternFlags inspects a regex and reports its global flags and
+// anchoring. Returns nil when nothing notable is set so the field can be
+// omitted from the export.
+func DerivePatternFlags(expr string) *PatternFlags {
+	var f PatternFlags
+
+	if m := leadingFlagsRe.FindStringSubmatch(expr); m != nil {
+		for _, c := range m[1] {
+			switch c {
+			case 'i':
+				f.CaseInsensitive = true
+			case 'm':
+				f.Multiline = true
+			case 's':
+				f.DotAll = true
+			}
+		}
+	}
+
+	if re, err := syntax.Parse(expr, syntax.Perl); err == nil {
+		f.AnchoredStart, f.AnchoredEnd = regexAnchors(re.Simplify())
+	}
+
+	if f == (PatternFlags{}) {
+		return nil
+	}
+	return &f
+}
+
+// regexAnchors reports whether a parsed regex begins with a start anchor and
+// ends with an end anchor at the top level.
+func regexAnchors(re *syntax.Regexp) (start, end bool) {
+	first, last := re, re
+	if re.Op == syntax.OpConcat && len(re.Sub) > 0 {
+		first, last = re.Sub[0], re.Sub[len(re.Sub)-1]
+	}
+	switch first.Op {
+	case syntax.OpBeginText, syntax.OpBeginLine:
+		start = true
+	}
+	switch last.Op {
+	case syntax.OpEndText, syntax.OpEndLine:
+		end = true
+	}
+	return start, end
+}
@@ -0,0 +1,33 @@
+package export
+
+import "testing"
+
+func TestDerivePatternFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want *PatternFlags
+	}{
+		{"plain", `sk_live_[a-zA-Z0-9]{24}`, nil},
+		{"case insensitive", `(?i)\bcloudflare_[a-z0-9]{16}\b`, &PatternFlags{CaseInsensitive: true}},
+		{"combined flags", `(?is)key=.+`, &PatternFlags{CaseInsensitive: true, DotAll: true}},
+		{"scoped flags are not global", `(?i:abc)[0-9]+`, nil},
+		{"anchored both ends", `^AGE-SECRET-KEY-1[0-9A-Z]{58}$`, &PatternFlags{AnchoredStart: true, AnchoredEnd: true}},
+		{"anchored start only", `\Axoxb-[0-9]+`, &PatternFlags{AnchoredStart: true}},
+		{"flags and anchors", `(?i)^ghp_[a-z0-9]{36}$`, &PatternFlags{CaseInsensitive: true, AnchoredStart: true, AnchoredEnd: true}},
+		{"alternation is not anchored", `^a|b$`, nil},
+		{"invalid regex keeps prefix flags", `(?i)[`, &PatternFlags{CaseInsensitive: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DerivePatternFlags(tt.expr)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("DerivePatternFlags(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+			if got != nil && *got != *tt.want {
+				t.Errorf("DerivePatternFlags(%q) = %+v, want %+v", tt.expr, *got, *tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"slices"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// reasonExcluded explains a removal from a gondolin export of something the
+// full export still has (-top, -categories, -require-hosts, ...).
+const reasonExcluded = "left out by the gondolin export options"
+
+// Tombstones turns the removals in d into a Removed section. current is the
+// full export the new output derives from and supplies the reasons; opts
+// are the gondolin options when d compares gondolin exports, nil otherwise.
+// Removed services and rules are reported as such, not also as lost hosts.
+func Tombstones(d Diff, previousHash string, current combine.Export, opts *Options) *combine.Removed {
+	r := &combine.Removed{Previous: previousHash}
+	services := make(map[string]combine.Service, len(current.Services))
+	mergedInto := make(map[string]string)
+	rules := make(map[string]combine.Service)
+	for _, svc := range current.Services {
+		services[svc.Keyword] = svc
+		for _, k := range svc.MergedFrom {
+			mergedInto[k] = svc.Keyword
+		}
+		for _, rule := range svc.Rules {
+			rules[rule.ID] = svc
+		}
+	}
+
+	lostHosts := make(map[string][]string)
+	for _, hc := range d.HostChanges {
+		lostHosts[hc.Keyword] = hc.Removed
+	}
+	for _, k := range d.RemovedServices {
+		reason := ""
+		svc, ok := services[k]
+		switch {
+		case mergedInto[k] != "":
+			reason = "merged into " + mergedInto[k]
+		case ok && svc.Deprecated != "":
+			reason = "deprecated: " + svc.Deprecated
+		case combine.DeprecationReason(k) != "":
+			reason = "deprecated: " + combine.DeprecationReason(k)
+		case ok && opts != nil:
+			reason = reasonExcluded
+		}
+		r.Services = append(r.Services, combine.RemovedService{Keyword: k, Hosts: lostHosts[k], Reason: reason})
+	}
+	for _, hc := range d.HostChanges {
+		if slices.Contains(d.RemovedServices, hc.Keyword) {
+			continue
+		}
+		for _, h := range hc.Removed {
+			r.Hosts = append(r.Hosts, combine.RemovedHost{Keyword: hc.Keyword, Host: h})
+		}
+	}
+	for _, ref := range d.RemovedRules {
+		reason := ""
+		svc, ok := rules[ref.ID]
+		switch {
+		case opts != nil && opts.PatternDenylist[ref.ID]:
+			reason = "pattern denylist"
+		case ok && opts != nil && svc.Deprecated != "":
+			reason = "deprecated: " + svc.Deprecated
+		case ok && opts != nil:
+			reason = reasonExcluded
+		}
+		r.Rules = append(r.Rules, combine.RemovedRule{ID: ref.ID, Keyword: ref.Keyword, Reason: reason})
+	}
+	return r
+}
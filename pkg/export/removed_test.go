@@ -0,0 +1,61 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestTombstones(t *testing.T) {
+	previous := combine.Export{Services: []combine.Service{
+		{Keyword: "mailgun", Hosts: []string{"api.mailgun.net", "api.eu.mailgun.net"}, Rules: []combine.Rule{{ID: "mailgun-token", Regex: `key-[a-z0-9]{32}`}}},
+		{Keyword: "mail-gun", Hosts: []string{"api.mailgun.net"}, Rules: []combine.Rule{{ID: "mailgun-pub", Regex: `pubkey-[a-z0-9]{32}`}}},
+		{Keyword: "shutdown", Hosts: []string{"api.shutdown.example"}, Rules: []combine.Rule{{ID: "shutdown-key", Regex: `sd_[a-z0-9]{32}`}}},
+	}}.WithContentHash()
+	current := combine.Export{Services: []combine.Service{
+		{Keyword: "mailgun", Hosts: []string{"api.mailgun.net"}, MergedFrom: []string{"mail-gun"}, Rules: []combine.Rule{{ID: "mailgun-token", Regex: `key-[a-z0-9]{32}`}}},
+		{Keyword: "shutdown", Hosts: []string{"api.shutdown.example"}, Deprecated: "vendor shut down", Rules: []combine.Rule{{ID: "shutdown-key", Regex: `sd_[a-z0-9]{32}`}}},
+	}}
+
+	r := Tombstones(DiffFull(previous, current), previous.ContentHash, current, nil)
+	want := &combine.Removed{
+		Previous: previous.ContentHash,
+		Services: []combine.RemovedService{{Keyword: "mail-gun", Hosts: []string{"api.mailgun.net"}, Reason: "merged into mailgun"}},
+		Hosts:    []combine.RemovedHost{{Keyword: "mailgun", Host: "api.eu.mailgun.net"}},
+		Rules:    []combine.RemovedRule{{ID: "mailgun-pub", Keyword: "mail-gun"}},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("full tombstones =\n%+v\nwant\n%+v", r, want)
+	}
+
+	opts := Options{PatternDenylist: map[string]bool{"mailgun-token": true}}
+	prevG, curG := ToGondolin(previous, Options{}), ToGondolin(current, opts)
+	r = Tombstones(DiffGondolin(prevG, curG), prevG.ContentHash, current, &opts)
+	reasons := make(map[string]string)
+	for _, s := range r.Services {
+		reasons[s.Keyword] = s.Reason
+	}
+	for _, rule := range r.Rules {
+		reasons[rule.ID] = rule.Reason
+	}
+	for k, want := range map[string]string{
+		"shutdown":      "deprecated: vendor shut down",
+		"shutdown-key":  "deprecated: vendor shut down",
+		"mailgun-token": "pattern denylist",
+		"mail-gun":      "merged into mailgun",
+	} {
+		if reasons[k] != want {
+			t.Errorf("reason for %s = %q, want %q", k, reasons[k], want)
+		}
+	}
+}
+
+func TestRemovedNotHashed(t *testing.T) {
+	g := ToGondolin(combine.Export{}, Options{})
+	with := g
+	with.Removed = &combine.Removed{Previous: "sha256:00"}
+	if with.WithContentHash().ContentHash != g.ContentHash {
+		t.Error("removed changed the content hash")
+	}
+}
@@ -0,0 +1,127 @@
+package export
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// jsonSchemaDialect is the JSON Schema draft the generated schemas declare.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema for exports of kind ("full" or
+// "gondolin", as DetectKind reports), derived from the Go types so it can't
+// drift from what the exporter writes. Fields without omitempty are
+// required; named structs are shared through $defs.
+func JSONSchema(kind string) (map[string]any, error) {
+	var t reflect.Type
+	var title string
+	switch kind {
+	case "full":
+		t, title = reflect.TypeFor[combine.Export](), "secret-mapping full export"
+	case "gondolin":
+		t, title = reflect.TypeFor[Gondolin](), fmt.Sprintf("secret-mapping gondolin export (schema_version %d)", SchemaVersion)
+	default:
+		return nil, fmt.Errorf("unknown export kind %q", kind)
+	}
+	g := &schemaGen{defs: make(map[string]map[string]any), names: make(map[reflect.Type]string)}
+	root := g.structSchema(t)
+	root["$schema"] = jsonSchemaDialect
+	root["title"] = title
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+	return root, nil
+}
+
+type schemaGen struct {
+	defs  map[string]map[string]any
+	names map[reflect.Type]string
+}
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	textType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// schema returns the schema of a value of type t.
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Implements(textType) || reflect.PointerTo(t).Implements(textType):
+		return map[string]any{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.ref(t)
+	}
+	return map[string]any{} // interfaces: anything
+}
+
+// ref returns a $ref to the definition of struct t, adding it on first use.
+// Definitions are named after the type, qualified by package only when two
+// packages define the same name.
+func (g *schemaGen) ref(t reflect.Type) map[string]any {
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		if _, taken := g.defs[name]; taken || name == "" {
+			name = strings.ReplaceAll(t.String(), ".", "_")
+		}
+		g.names[t] = name
+		g.defs[name] = nil // reserve before recursing, for self-references
+		g.defs[name] = g.structSchema(t)
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// structSchema describes struct t as an object with its JSON fields.
+func (g *schemaGen) structSchema(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		props[name] = g.schema(sf.Type)
+		if !strings.Contains(","+opts+",", ",omitempty,") {
+			required = append(required, name)
+		}
+	}
+	// Unknown properties stay allowed: consumers of one schema version must
+	// accept fields added later.
+	return map[string]any{"type": "object", "properties": props, "required": required}
+}
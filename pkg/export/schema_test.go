@@ -0,0 +1,57 @@
+package export
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestJSONSchemaCoversExport(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{{
+		Keyword: "github",
+		Hosts:   []string{"api.github.com"},
+		Rules:   []combine.Rule{{ID: "github-pat", Regex: `ghp_[0-9a-zA-Z]{36}`, Keywords: []string{"ghp_"}}},
+	}}}
+	for kind, v := range map[string]any{"full": full, "gondolin": ToGondolin(full, Options{NameTrie: true})} {
+		s, err := JSONSchema(kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := json.Marshal(s); err != nil {
+			t.Fatalf("%s: marshal schema: %v", kind, err)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var top map[string]json.RawMessage
+		if err := json.Unmarshal(data, &top); err != nil {
+			t.Fatal(err)
+		}
+		props := s["properties"].(map[string]any)
+		for key := range top {
+			if _, ok := props[key]; !ok {
+				t.Errorf("%s: schema has no property %q", kind, key)
+			}
+		}
+		for _, key := range s["required"].([]string) {
+			if _, ok := top[key]; !ok {
+				t.Errorf("%s: required property %q missing from an export", kind, key)
+			}
+		}
+	}
+
+	g, _ := JSONSchema("gondolin")
+	if req := g["required"].([]string); !slices.Contains(req, "keyword_host_map") || slices.Contains(req, "name_trie") {
+		t.Errorf("gondolin required = %v", req)
+	}
+	defs := g["$defs"].(map[string]map[string]any)
+	if got := defs["KeywordBloom"]["properties"].(map[string]any)["bits"]; got.(map[string]any)["contentEncoding"] != "base64" {
+		t.Errorf("keyword_bloom.bits = %v, want base64 string", got)
+	}
+	if _, err := JSONSchema("yaml"); err == nil {
+		t.Error("JSONSchema(yaml) succeeded")
+	}
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NormalizeSecretGroup rewrites expr so the secret is capture group 1 and
+// the only capture group: every other group, named or not, becomes
+// non-capturing. group is the rule's secret_group; 0 follows Gitleaks and
+// means the first capture group, or the whole match when there is none.
+// It returns the new expression and secret group (1, or 0 for a regex
+// without groups).
+//
+// Gitleaks rules disagree on which group holds the secret, so without this
+// consumers have to honor secret_group per rule. The rewrite is textual, so
+// the rest of the expression is left exactly as written upstream.
+func NormalizeSecretGroup(expr string, group int) (string, int, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return "", 0, err
+	}
+	n := re.NumSubexp()
+	switch {
+	case group < 0 || group > n:
+		return "", 0, fmt.Errorf("secret_group %d out of range (regex has %d groups)", group, n)
+	case n == 0:
+		return expr, 0, nil
+	case group == 0 && n > 1:
+		// Gitleaks takes the first group that participated in the match,
+		// which a single static group can't express.
+		return "", 0, fmt.Errorf("secret_group unset with %d capture groups", n)
+	case group == 0:
+		group = 1
+	}
+
+	var b strings.Builder
+	capture := 0
+	inClass := false
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case c == '\\' && strings.HasPrefix(expr[i:], `\Q`):
+			// Quoted literal text runs to \E or the end.
+			end := strings.Index(expr[i+2:], `\E`)
+			if end < 0 {
+				b.WriteString(expr[i:])
+				i = len(expr)
+				continue
+			}
+			b.WriteString(expr[i : i+2+end+2])
+			i += 2 + end + 1
+			continue
+		case c == '\\' && i+1 < len(expr):
+			b.WriteString(expr[i : i+2])
+			i++
+			continue
+		case inClass:
+			if c == '[' && strings.HasPrefix(expr[i:], "[:") {
+				if end := strings.Index(expr[i:], ":]"); end >= 0 {
+					b.WriteString(expr[i : i+end+2])
+					i += end + 1
+					continue
+				}
+			}
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+			b.WriteByte(c)
+			// A ] right after [ or [^ is a literal member, not the end.
+			if strings.HasPrefix(expr[i+1:], "^") {
+				b.WriteByte('^')
+				i++
+			}
+			if strings.HasPrefix(expr[i+1:], "]") {
+				b.WriteByte(']')
+				i++
+			}
+			continue
+		case c == '(':
+			rest := expr[i+1:]
+			name := ""
+			switch {
+			case strings.HasPrefix(rest, "?P<"), strings.HasPrefix(rest, "?<"):
+				end := strings.IndexByte(rest, '>')
+				if end < 0 {
+					break
+				}
+				name = rest[:end+1]
+			case strings.HasPrefix(rest, "?"):
+				b.WriteByte(c)
+				continue // non-capturing or flags
+			}
+			capture++
+			if capture == group {
+				b.WriteByte(c)
+				b.WriteString(name)
+			} else {
+				b.WriteString("(?:")
+			}
+			i += len(name)
+			continue
+		}
+		b.WriteByte(c)
+	}
+
+	out := b.String()
+	if got, err := regexp.Compile(out); err != nil || got.NumSubexp() != 1 {
+		return "", 0, fmt.Errorf("rewriting capture groups of %q failed", expr)
+	}
+	return out, 1, nil
+}
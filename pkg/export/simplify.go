@@ -0,0 +1,462 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// RegexRewrite records how the gondolin export rewrote one rule's regex,
+// for -regex-report.
+type RegexRewrite struct {
+	ID       string   `json:"id"`
+	Before   string   `json:"before"`
+	After    string   `json:"after"`
+	Rewrites []string `json:"rewrites"`
+}
+
+// SimplifyRegex shrinks expr without changing what it matches: leading
+// flags that affect nothing are dropped, character classes are rewritten to
+// their shortest equivalent ([0-9] → \d, [a-zA-Z0-9_] → \w), and
+// non-capturing groups that group nothing are removed. Every step is checked
+// by comparing the parsed and simplified syntax trees before and after, and
+// skipped if they differ. It returns the new expression and a description of
+// each rewrite applied (none when expr is returned unchanged).
+func SimplifyRegex(expr string) (string, []string) {
+	if _, err := regexp.Compile(expr); err != nil {
+		return expr, nil
+	}
+	var notes []string
+	for _, step := range []func(string) (string, []string){stripNoopFlags, canonicalizeClasses, collapseGroups} {
+		out, stepNotes := step(expr)
+		if out != expr && sameSyntax(expr, out) {
+			expr = out
+			notes = append(notes, stepNotes...)
+		}
+	}
+	return expr, notes
+}
+
+// sameSyntax reports whether a and b parse to the same simplified syntax
+// tree, i.e. match the same strings with the same capture groups.
+func sameSyntax(a, b string) bool {
+	ta, err := syntax.Parse(a, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	tb, err := syntax.Parse(b, syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return ta.Simplify().String() == tb.Simplify().String()
+}
+
+// stripNoopFlags drops each flag of the leading flag group that doesn't
+// change the parsed regex, e.g. (?i) on [0-9]{32}.
+func stripNoopFlags(expr string) (string, []string) {
+	m := leadingFlagsRe.FindStringSubmatch(expr)
+	if m == nil {
+		return expr, nil
+	}
+	flags, body := m[1], expr[len(m[0]):]
+	var notes []string
+	for _, c := range m[1] {
+		kept := strings.Replace(flags, string(c), "", 1)
+		candidate := body
+		if kept != "" {
+			candidate = "(?" + kept + ")" + body
+		}
+		current := "(?" + flags + ")" + body
+		if sameSyntax(current, candidate) {
+			flags = kept
+			notes = append(notes, fmt.Sprintf("dropped no-op flag %c", c))
+		}
+	}
+	if flags == "" {
+		return body, notes
+	}
+	return "(?" + flags + ")" + body, notes
+}
+
+// canonicalizeClasses replaces every bracketed character class with its
+// shortest equivalent spelling, where that is shorter.
+func canonicalizeClasses(expr string) (string, []string) {
+	var b strings.Builder
+	var notes []string
+	for i := 0; i < len(expr); {
+		end := atomEnd(expr, i)
+		tok := expr[i:end]
+		if expr[i] == '[' {
+			if c, ok := canonicalClass(tok); ok && len(c) < len(tok) {
+				notes = append(notes, fmt.Sprintf("rewrote %s as %s", tok, c))
+				tok = c
+			}
+		} else if open, ok := groupBody(tok); ok {
+			// Recurse into the group body, keeping its opening.
+			inner, innerNotes := canonicalizeClasses(tok[len(open) : len(tok)-1])
+			tok = open + inner + ")"
+			notes = append(notes, innerNotes...)
+		}
+		b.WriteString(tok)
+		i = end
+	}
+	return b.String(), notes
+}
+
+var (
+	digitClass = []rune{'0', '9'}
+	wordClass  = []rune{'0', '9', 'A', 'Z', '_', '_', 'a', 'z'}
+)
+
+// canonicalClass spells class (a whole "[...]" token) as compactly as it
+// can: a bare shorthand (\d, \w) or a single literal when the set allows,
+// otherwise sorted, merged ranges with shorthands folded in. Whitespace is
+// never folded into \s: JavaScript's \s also matches \v and Unicode
+// spaces, so the rewrite would widen the pattern for JS consumers.
+func canonicalClass(class string) (string, bool) {
+	negated := strings.HasPrefix(class, "[^")
+	positive := class
+	if negated {
+		positive = "[" + class[2:]
+	}
+	re, err := syntax.Parse(positive, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var ranges []rune
+	switch re.Op {
+	case syntax.OpCharClass:
+		ranges = re.Rune
+	case syntax.OpLiteral:
+		if len(re.Rune) != 1 || re.Flags&syntax.FoldCase != 0 {
+			return "", false
+		}
+		ranges = []rune{re.Rune[0], re.Rune[0]}
+	default:
+		return "", false
+	}
+
+	for _, sh := range []struct {
+		set  []rune
+		name string
+	}{{digitClass, `\d`}, {wordClass, `\w`}} {
+		if slices.Equal(ranges, sh.set) {
+			if negated {
+				return strings.ToUpper(sh.name[:2]), true // \D, \W
+			}
+			return sh.name, true
+		}
+	}
+	if !negated && len(ranges) == 2 && ranges[0] == ranges[1] {
+		return quoteLiteral(ranges[0]), true
+	}
+
+	var b strings.Builder
+	b.WriteString("[")
+	if negated {
+		b.WriteString("^")
+	}
+	rest := ranges
+	for _, sh := range []struct {
+		set  []rune
+		name string
+	}{{wordClass, `\w`}, {digitClass, `\d`}} {
+		if without, ok := subtractRanges(rest, sh.set); ok {
+			b.WriteString(sh.name)
+			rest = without
+		}
+	}
+	for i := 0; i+1 < len(rest); i += 2 {
+		lo, hi := rest[i], rest[i+1]
+		b.WriteString(quoteClassRune(lo))
+		switch {
+		case hi == lo+1:
+			b.WriteString(quoteClassRune(hi))
+		case hi > lo:
+			b.WriteString("-" + quoteClassRune(hi))
+		}
+	}
+	b.WriteString("]")
+	return b.String(), true
+}
+
+// subtractRanges removes set from ranges (both sorted [lo, hi] pairs) when
+// ranges contains all of set.
+func subtractRanges(ranges, set []rune) ([]rune, bool) {
+	contains := func(lo, hi rune) bool {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= lo && hi <= ranges[i+1] {
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i+1 < len(set); i += 2 {
+		if !contains(set[i], set[i+1]) {
+			return nil, false
+		}
+	}
+	var out []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		pieces := []rune{ranges[i], ranges[i+1]}
+		for j := 0; j+1 < len(set); j += 2 {
+			var next []rune
+			for k := 0; k+1 < len(pieces); k += 2 {
+				lo, hi := pieces[k], pieces[k+1]
+				if set[j+1] < lo || set[j] > hi {
+					next = append(next, lo, hi)
+					continue
+				}
+				if lo < set[j] {
+					next = append(next, lo, set[j]-1)
+				}
+				if hi > set[j+1] {
+					next = append(next, set[j+1]+1, hi)
+				}
+			}
+			pieces = next
+		}
+		out = append(out, pieces...)
+	}
+	return out, true
+}
+
+// quoteClassRune spells r inside a bracketed class.
+func quoteClassRune(r rune) string {
+	switch r {
+	case '\\', ']', '[', '^', '-':
+		return `\` + string(r)
+	case '\t':
+		return `\t`
+	case '\n':
+		return `\n`
+	case '\r':
+		return `\r`
+	case '\f':
+		return `\f`
+	case '\v':
+		return `\v`
+	}
+	if unicode.IsPrint(r) {
+		return string(r)
+	}
+	return fmt.Sprintf(`\x{%x}`, r)
+}
+
+// quoteLiteral spells r outside a class.
+func quoteLiteral(r rune) string {
+	if r < utf8.RuneSelf && strings.ContainsRune(`\.+*?()|[]{}^$`, r) {
+		return `\` + string(r)
+	}
+	return quoteClassRune(r)
+}
+
+// collapseGroups removes non-capturing groups that group nothing: one
+// wrapping a single atom ((?:[a-z])+ → [a-z]+), an unquantified one without
+// alternation or flags at its top level ((?:ab)c → abc), and one spanning
+// the whole regex.
+func collapseGroups(expr string) (string, []string) {
+	prefix := leadingFlagsRe.FindString(expr)
+	out, n := collapseIn(expr[len(prefix):], true)
+	if n == 0 {
+		return expr, nil
+	}
+	return prefix + out, []string{fmt.Sprintf("removed %d redundant non-capturing groups", n)}
+}
+
+func collapseIn(s string, whole bool) (string, int) {
+	var b strings.Builder
+	removed := 0
+	for i := 0; i < len(s); {
+		end := atomEnd(s, i)
+		tok := s[i:end]
+		if open, ok := groupBody(tok); ok {
+			inner, n := collapseIn(tok[len(open):len(tok)-1], false)
+			removed += n
+			tok = open + inner + ")"
+			if open == "(?:" && inner != "" {
+				single := atomEnd(inner, 0) == len(inner)
+				plain := !quantified(s, end) && !hasTopLevel(inner, '|') && !hasTopLevelFlags(inner)
+				if single || plain || (whole && i == 0 && end == len(s)) {
+					tok = inner
+					removed++
+				}
+			}
+		}
+		b.WriteString(tok)
+		i = end
+	}
+	return b.String(), removed
+}
+
+// groupBody returns the opening of tok when tok is a group with a body,
+// as opposed to a flag group like "(?i)" or an unterminated one.
+func groupBody(tok string) (open string, ok bool) {
+	if !strings.HasPrefix(tok, "(") || !strings.HasSuffix(tok, ")") {
+		return "", false
+	}
+	open = groupOpening(tok)
+	return open, len(open) < len(tok)
+}
+
+// groupOpening returns the opening of the group token tok: "(", "(?:",
+// "(?i:", "(?P<name>", or "(?i)" for a flag group.
+func groupOpening(tok string) string {
+	if !strings.HasPrefix(tok, "(?") {
+		return "("
+	}
+	if strings.HasPrefix(tok, "(?P<") || strings.HasPrefix(tok, "(?<") {
+		if end := strings.IndexByte(tok, '>'); end >= 0 {
+			return tok[:end+1]
+		}
+	}
+	for j := 2; j < len(tok); j++ {
+		if tok[j] == ':' || tok[j] == ')' {
+			return tok[:j+1]
+		}
+	}
+	return tok
+}
+
+var repeatRe = regexp.MustCompile(`^\{\d+(,\d*)?\}`)
+
+// quantified reports whether the atom ending at s[end] is repeated.
+func quantified(s string, end int) bool {
+	if end >= len(s) {
+		return false
+	}
+	return strings.ContainsRune("*+?", rune(s[end])) || repeatRe.MatchString(s[end:])
+}
+
+// hasTopLevel reports whether s contains c outside groups, classes and
+// escapes.
+func hasTopLevel(s string, c byte) bool {
+	for i := 0; i < len(s); i = atomEnd(s, i) {
+		if s[i] == c {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTopLevelFlags reports whether s sets flags for the rest of its group,
+// as in "(?i)abc", which removing the enclosing group would widen.
+func hasTopLevelFlags(s string) bool {
+	for i := 0; i < len(s); {
+		end := atomEnd(s, i)
+		if tok := s[i:end]; strings.HasPrefix(tok, "(?") && strings.HasSuffix(groupOpening(tok), ")") {
+			return true
+		}
+		i = end
+	}
+	return false
+}
+
+// atomEnd returns the index just past the atom starting at s[i]: an escape
+// (including \Q…\E, \x{…} and \p{…}), a bracketed class, a parenthesized
+// group, or a single character.
+func atomEnd(s string, i int) int {
+	switch s[i] {
+	case '\\':
+		return escapeEnd(s, i)
+	case '[':
+		j := i + 1
+		if j < len(s) && s[j] == '^' {
+			j++
+		}
+		if j < len(s) && s[j] == ']' {
+			j++
+		}
+		for j < len(s) {
+			switch {
+			case s[j] == '\\':
+				j = escapeEnd(s, j)
+			case strings.HasPrefix(s[j:], "[:") && strings.Contains(s[j:], ":]"):
+				j += strings.Index(s[j:], ":]") + 2
+			case s[j] == ']':
+				return j + 1
+			default:
+				j++
+			}
+		}
+		return len(s)
+	case '(':
+		j := i + 1
+		for j < len(s) && s[j] != ')' {
+			j = atomEnd(s, j)
+		}
+		return min(j+1, len(s))
+	}
+	_, size := utf8.DecodeRuneInString(s[i:])
+	return i + size
+}
+
+func escapeEnd(s string, i int) int {
+	if i+1 >= len(s) {
+		return len(s)
+	}
+	rest := s[i+2:]
+	switch s[i+1] {
+	case 'Q':
+		if end := strings.Index(rest, `\E`); end >= 0 {
+			return i + 2 + end + 2
+		}
+		return len(s)
+	case 'x', 'p', 'P':
+		if strings.HasPrefix(rest, "{") {
+			if end := strings.IndexByte(rest, '}'); end >= 0 {
+				return i + 2 + end + 1
+			}
+			return len(s)
+		}
+		if s[i+1] == 'x' {
+			return min(i+4, len(s))
+		}
+		return min(i+3, len(s))
+	}
+	_, size := utf8.DecodeRuneInString(s[i+1:])
+	return i + 1 + size
+}
+
+// ExportedRegex returns the regex and secret group rule r is exported with
+// in value_patterns, and the simplifications applied when simplify is set.
+func ExportedRegex(r combine.Rule, simplify bool) (expr string, group int, rewrites []string) {
+	// The secret is always group 1 (or the whole match when there is no
+	// group); rules the rewrite can't handle keep their upstream groups.
+	expr, group, err := NormalizeSecretGroup(r.Regex, r.SecretGroup)
+	if err != nil {
+		expr, group = r.Regex, r.SecretGroup
+	}
+	if simplify {
+		expr, rewrites = SimplifyRegex(expr)
+	}
+	return expr, group, rewrites
+}
+
+// RegexRewrites lists the value patterns of full whose exported regex
+// differs from the upstream one under opts.
+func RegexRewrites(full combine.Export, opts Options) []RegexRewrite {
+	out := []RegexRewrite{}
+	for _, svc := range full.Services {
+		for _, r := range svc.Rules {
+			if opts.PatternDenylist[r.ID] {
+				continue
+			}
+			expr, _, rewrites := ExportedRegex(r, opts.SimplifyRegexes)
+			if expr == r.Regex {
+				continue
+			}
+			if !sameSyntax(r.Regex, expr) {
+				rewrites = append([]string{"made capture groups other than the secret non-capturing"}, rewrites...)
+			}
+			out = append(out, RegexRewrite{ID: r.ID, Before: r.Regex, After: expr, Rewrites: rewrites})
+		}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestSimplifyRegex(t *testing.T) {
+	tests := []struct {
+		expr, want string
+	}{
+		{`(?i)[0-9]{32}`, `\d{32}`},
+		{`(?is)key_[a-f0-9]{8}`, `(?i)key_[\da-f]{8}`},
+		{`[a-zA-Z0-9_]{20}`, `\w{20}`},
+		{`[^0-9]`, `\D`},
+		{`[a-zA-Z0-9_\-]{20}`, `[\w\-]{20}`},
+		{`[.]com`, `\.com`},
+		{`(?:[a-z])+(?:ab)c`, `[a-z]+abc`},
+		{`(?:xox[bp])-(?:a|b)`, `xox[bp]-(?:a|b)`},
+		{`(?:a|b)`, `a|b`},
+		{`(?:ab)+`, `(?:ab)+`},
+		{`(?:(?i)ab)c`, `(?:(?i)ab)c`},
+		{`x(?:(?:a|b))y`, `x(?:a|b)y`},
+		{`([0-9]{4})`, `(\d{4})`},
+		{`\Q[0-9]\E[0-9]`, `\Q[0-9]\E\d`},
+		{`ghp_[0-9a-zA-Z]{36}`, `ghp_[\dA-Za-z]{36}`},
+		{`key[\t\n\f\r ]+=`, `key[\t\n\f\r ]+=`},
+		{`[^\t\n\f\r ]{8}`, `[^\t\n\f\r ]{8}`},
+	}
+	for _, tt := range tests {
+		got, notes := SimplifyRegex(tt.expr)
+		if got != tt.want {
+			t.Errorf("SimplifyRegex(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+		if (got != tt.expr) != (len(notes) > 0) {
+			t.Errorf("SimplifyRegex(%q): notes %q for %q", tt.expr, notes, got)
+		}
+		if !sameSyntax(tt.expr, got) {
+			t.Errorf("SimplifyRegex(%q) = %q changed the regex", tt.expr, got)
+		}
+	}
+}
+
+func TestRegexRewrites(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{{
+		Keyword: "stripe",
+		Hosts:   []string{"api.stripe.com"},
+		Rules: []combine.Rule{
+			{ID: "stripe-key", Regex: `(sk|rk)_live_([0-9a-z]{24})`, SecretGroup: 2},
+			{ID: "stripe-plain", Regex: `whsec_[a-zA-Z0-9]{32}`},
+			{ID: "stripe-denied", Regex: `(?i)[0-9]{8}`},
+		},
+	}}}
+	opts := Options{SimplifyRegexes: true, PatternDenylist: map[string]bool{"stripe-denied": true}}
+	rewrites := RegexRewrites(full, opts)
+	if len(rewrites) != 2 {
+		t.Fatalf("rewrites = %+v, want stripe-key and stripe-plain", rewrites)
+	}
+	if r := rewrites[0]; r.After != `(?:sk|rk)_live_([\da-z]{24})` || !strings.Contains(r.Rewrites[0], "non-capturing") {
+		t.Errorf("stripe-key rewrite = %+v", r)
+	}
+
+	exported := func(opts Options, id string) string {
+		for _, p := range ToGondolin(full, opts).ValuePatterns {
+			if p.ID == id {
+				return p.Regex
+			}
+		}
+		return ""
+	}
+	if got := exported(opts, "stripe-plain"); got != `whsec_[\dA-Za-z]{32}` {
+		t.Errorf("exported stripe-plain regex = %q", got)
+	}
+	if got := exported(Options{}, "stripe-plain"); got != `whsec_[a-zA-Z0-9]{32}` {
+		t.Errorf("regex simplified without Options.SimplifyRegexes: %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// ParsePopularityWeights decodes a popularity data file: a JSON object of
+// service keyword → weight, e.g. derived from package download counts or
+// internal usage telemetry. Only the relative order of weights matters.
+// Keys are normalized with combine.NormalizeKeyword; weights must be >= 0.
+func ParsePopularityWeights(data []byte) (map[string]float64, error) {
+	var raw map[string]float64
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	weights := make(map[string]float64, len(raw))
+	for _, k := range sortedMapKeys(raw) {
+		w := raw[k]
+		if w < 0 {
+			return nil, fmt.Errorf("%s: weight %v must be >= 0", k, w)
+		}
+		norm := combine.NormalizeKeyword(k)
+		if _, dup := weights[norm]; dup {
+			return nil, fmt.Errorf("%s: listed twice (as another spelling of %q)", k, norm)
+		}
+		weights[norm] = w
+	}
+	return weights, nil
+}
+
+// TopServices returns at most n services, ranked by:
+//  1. position in the curated popularity list (listed services first)
+//  2. services with hosts before services without (only those can forward)
+//  3. combined host + rule count, descending
+//  4. keyword, ascending (deterministic tie-break)
+//
+// The input slice is not modified. n <= 0 returns the services unchanged.
+func TopServices(services []combine.Service, n int, popularity []string) []combine.Service {
+	return TopServicesWeighted(services, n, popularity, nil)
+}
+
+// TopServicesWeighted is TopServices ranking first by weights (normalized
+// keyword → weight, see ParsePopularityWeights), highest first. Services
+// without a weight rank as weight 0, and the curated list breaks ties.
+func TopServicesWeighted(services []combine.Service, n int, popularity []string, weights map[string]float64) []combine.Service {
+	if n <= 0 || len(services) <= n {
+		return services
+	}
+
+	rank := make(map[string]int, len(popularity))
+	for i, k := range popularity {
+		rank[combine.NormalizeKeyword(k)] = i
+	}
+	rankOf := func(s combine.Service) int {
+		if r, ok := rank[combine.NormalizeKeyword(s.Keyword)]; ok {
+			return r
+		}
+		return len(popularity)
+	}
+
+	ranked := make([]combine.Service, len(services))
+	copy(ranked, services)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if wa, wb := weights[combine.NormalizeKeyword(a.Keyword)], weights[combine.NormalizeKeyword(b.Keyword)]; wa != wb {
+			return wa > wb
+		}
+		if ra, rb := rankOf(a), rankOf(b); ra != rb {
+			return ra < rb
+		}
+		if ha, hb := len(a.Hosts) > 0, len(b.Hosts) > 0; ha != hb {
+			return ha
+		}
+		if wa, wb := len(a.Hosts)+len(a.Rules), len(b.Hosts)+len(b.Rules); wa != wb {
+			return wa > wb
+		}
+		return a.Keyword < b.Keyword
+	})
+
+	kept := ranked[:n]
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Keyword < kept[j].Keyword })
+	return kept
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestTopServices(t *testing.T) {
+	services := []combine.Service{
+		{Keyword: "age", Rules: []combine.Rule{{ID: "age-secret-key"}}},
+		{Keyword: "beamer", Hosts: []string{"api.getbeamer.com"}, Rules: []combine.Rule{{ID: "beamer-api-token"}}},
+		{Keyword: "github", Hosts: []string{"api.github.com"}, Rules: []combine.Rule{{ID: "github-pat"}}},
+		{Keyword: "slack", Hosts: []string{"slack.com", "api.slack.com"}, Rules: []combine.Rule{{ID: "slack-bot-token"}, {ID: "slack-user-token"}}},
+		{Keyword: "zendesk", Hosts: []string{"a.zendesk.com"}, Rules: []combine.Rule{{ID: "zendesk-secret-key"}}},
+	}
+
+	got := TopServices(services, 3, []string{"github"})
+	var keywords []string
+	for _, s := range got {
+		keywords = append(keywords, s.Keyword)
+	}
+	// github (curated), then slack (most hosts+rules), then beamer/zendesk tie
+	// broken alphabetically; output is re-sorted by keyword.
+	want := []string{"beamer", "github", "slack"}
+	if len(keywords) != len(want) {
+		t.Fatalf("TopServices keywords = %v, want %v", keywords, want)
+	}
+	for i := range want {
+		if keywords[i] != want[i] {
+			t.Fatalf("TopServices keywords = %v, want %v", keywords, want)
+		}
+	}
+
+	if services[0].Keyword != "age" {
+		t.Error("TopServices must not reorder its input")
+	}
+	if got := TopServices(services, 0, nil); len(got) != len(services) {
+		t.Errorf("TopServices(n=0) len = %d, want %d", len(got), len(services))
+	}
+}
+
+func TestToGondolinExportTop(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "alpha", Hosts: []string{"api.alpha.com"}, Rules: []combine.Rule{{ID: "alpha-key", Regex: `alpha_[a-z]+`}}},
+			{Keyword: "beta", Hosts: []string{"api.beta.com"}, Rules: []combine.Rule{{ID: "beta-key", Regex: `beta_[a-z]+`}}},
+		},
+	}
+
+	gondolin := ToGondolin(full, Options{Top: 1, Popularity: []string{"beta"}})
+	if _, ok := gondolin.KeywordHostMap["alpha"]; ok {
+		t.Error("alpha should be trimmed by -top 1")
+	}
+	if _, ok := gondolin.KeywordHostMap["beta"]; !ok {
+		t.Error("beta should be kept (curated popularity)")
+	}
+	if len(gondolin.KeywordHostMap) != 1 {
+		t.Errorf("KeywordHostMap = %v, want only beta (overrides must not exceed -top)", gondolin.KeywordHostMap)
+	}
+	if _, ok := gondolin.ExactNameHostMap["AWS_ACCESS_KEY_ID"]; ok {
+		t.Error("AWS_ACCESS_KEY_ID should follow the trimmed aws keyword out")
+	}
+
+	// A -top that cuts nothing leaves the export as it was.
+	untrimmed := ToGondolin(full, Options{})
+	if wide := ToGondolin(full, Options{Top: 100}); !reflect.DeepEqual(wide, untrimmed) {
+		t.Errorf("-top 100 of 2 services changed the export:\n%+v\nwant\n%+v", wide, untrimmed)
+	}
+	if len(gondolin.ValuePatterns) != 1 || gondolin.ValuePatterns[0].ID != "beta-key" {
+		t.Errorf("ValuePatterns = %+v, want only beta-key", gondolin.ValuePatterns)
+	}
+}
+
+func TestToGondolinExportTopKeepsOverridesOfKeptServices(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "alpha", Hosts: []string{"api.alpha.com"}, Rules: []combine.Rule{{ID: "alpha-key", Regex: `alpha_[a-z]+`}}},
+			{Keyword: "aws", Rules: []combine.Rule{{ID: "aws-access-token", Regex: `AKIA[A-Z0-9]{16}`}}},
+		},
+	}
+
+	gondolin := ToGondolin(full, Options{Top: 1, Popularity: []string{"aws"}})
+	if len(gondolin.KeywordHostMap) != 1 || len(gondolin.KeywordHostMap["aws"]) == 0 {
+		t.Errorf("KeywordHostMap = %v, want only the aws override", gondolin.KeywordHostMap)
+	}
+}
+
+func TestPopularityWeights(t *testing.T) {
+	weights, err := ParsePopularityWeights([]byte(`{"Alpha": 10, "gamma": 250.5, "beta": 0}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weights["alpha"] != 10 || weights["gamma"] != 250.5 {
+		t.Errorf("weights = %v", weights)
+	}
+	for _, bad := range []string{`{"alpha": -1}`, `{"alpha": 1, "ALPHA": 2}`, `["alpha"]`} {
+		if _, err := ParsePopularityWeights([]byte(bad)); err == nil {
+			t.Errorf("ParsePopularityWeights(%s): no error", bad)
+		}
+	}
+
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "alpha", Hosts: []string{"api.alpha.com"}, Rules: []combine.Rule{{ID: "alpha-key", Regex: `alpha_[a-z]+`}}},
+			{Keyword: "beta", Hosts: []string{"api.beta.com"}, Rules: []combine.Rule{{ID: "beta-key", Regex: `beta_[a-z]+`}}},
+			{Keyword: "gamma", Rules: []combine.Rule{{ID: "gamma-key", Regex: `gamma_[a-z]+`}}},
+		},
+	}
+	// Weights outrank the curated list.
+	top := TopServicesWeighted(full.Services, 1, []string{"beta"}, weights)
+	if len(top) != 1 || top[0].Keyword != "gamma" {
+		t.Errorf("TopServicesWeighted = %+v, want gamma", top)
+	}
+
+	g := ToGondolin(full, Options{Weights: weights})
+	var ids []string
+	for _, p := range g.ValuePatterns {
+		ids = append(ids, p.ID)
+	}
+	// gamma is unlinked (no hosts) but heaviest; beta has weight 0.
+	if want := []string{"gamma-key", "alpha-key", "beta-key"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("value_patterns = %v, want %v", ids, want)
+	}
+	if want := map[string]float64{"alpha": 10, "gamma": 250.5}; !reflect.DeepEqual(g.ServiceWeights, want) {
+		t.Errorf("service_weights = %v, want %v", g.ServiceWeights, want)
+	}
+	if errs := ValidateGondolin(g.WithContentHash(), ValidateOptions{}); len(errs) > 0 {
+		t.Errorf("validate: %v", errs)
+	}
+	if ToGondolin(full, Options{}).ServiceWeights != nil {
+		t.Error("service_weights without weights")
+	}
+}
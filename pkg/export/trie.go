@@ -0,0 +1,175 @@
+package export
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// NameTrie is the opt-in name_trie section of a gondolin export: the keys of
+// keyword_host_map and exact_name_host_map packed into tries, for consumers
+// that look up thousands of env var names per process launch. Hosts are
+// still read from the maps; the tries only find the keys.
+type NameTrie struct {
+	// Keywords holds keyword_host_map keys normalized the way names are
+	// compared (lower case, without "-" and "_"). Walk it with Substrings
+	// over the normalized env var name.
+	Keywords PackedTrie `json:"keywords"`
+	// ExactNames holds exact_name_host_map keys in upper case. Walk it with
+	// Lookup over the upper-cased env var name.
+	ExactNames PackedTrie `json:"exact_names"`
+}
+
+// PackedTrie is a trie flattened into arrays. Nodes are numbered
+// breadth-first from the root (0), siblings in label order, so node i > 0 is
+// the target of edge i-1:
+//   - Labels has one code point per edge; node i's label is code point i-1.
+//   - Node n's outgoing edges are First[n] … First[n+1]-1, sorted by label.
+//   - Ends lists, ascending, the nodes where entries end; Keys[k] are the
+//     map keys ending at Ends[k] (several keys may normalize alike).
+type PackedTrie struct {
+	Labels string     `json:"labels"`
+	First  []int      `json:"first"`
+	Ends   []int      `json:"ends"`
+	Keys   [][]string `json:"keys"`
+}
+
+// BuildNameTrie packs the keys of keywordHosts and exactNames.
+func BuildNameTrie(keywordHosts, exactNames map[string][]string) NameTrie {
+	keywords := make(map[string][]string, len(keywordHosts))
+	for k := range keywordHosts {
+		if norm := combine.NormalizeKeyword(k); norm != "" {
+			keywords[norm] = append(keywords[norm], k)
+		}
+	}
+	names := make(map[string][]string, len(exactNames))
+	for k := range exactNames {
+		if upper := strings.ToUpper(k); upper != "" {
+			names[upper] = append(names[upper], k)
+		}
+	}
+	return NameTrie{Keywords: packTrie(keywords), ExactNames: packTrie(names)}
+}
+
+// Equal reports whether t and u encode the same tries. Empty and missing
+// arrays (as left by -compact) compare equal.
+func (t NameTrie) Equal(u NameTrie) bool {
+	return t.Keywords.equal(u.Keywords) && t.ExactNames.equal(u.ExactNames)
+}
+
+func (t PackedTrie) equal(u PackedTrie) bool {
+	if t.Labels != u.Labels || !slices.Equal(t.First, u.First) || !slices.Equal(t.Ends, u.Ends) || len(t.Keys) != len(u.Keys) {
+		return false
+	}
+	for i := range t.Keys {
+		if !slices.Equal(t.Keys[i], u.Keys[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// packTrie builds a PackedTrie from word → keys.
+func packTrie(entries map[string][]string) PackedTrie {
+	type node struct {
+		children map[rune]*node
+		keys     []string
+	}
+	root := &node{}
+	for word, keys := range entries {
+		n := root
+		for _, r := range word {
+			if n.children == nil {
+				n.children = make(map[rune]*node)
+			}
+			c := n.children[r]
+			if c == nil {
+				c = &node{}
+				n.children[r] = c
+			}
+			n = c
+		}
+		n.keys = append(n.keys, keys...)
+	}
+
+	t := PackedTrie{First: []int{0}, Ends: []int{}, Keys: [][]string{}}
+	var labels []rune
+	queue := []*node{root}
+	for i := 0; i < len(queue); i++ {
+		n := queue[i]
+		if len(n.keys) > 0 {
+			keys := append([]string(nil), n.keys...)
+			sort.Strings(keys)
+			t.Ends = append(t.Ends, i)
+			t.Keys = append(t.Keys, keys)
+		}
+		rs := make([]rune, 0, len(n.children))
+		for r := range n.children {
+			rs = append(rs, r)
+		}
+		sort.Slice(rs, func(a, b int) bool { return rs[a] < rs[b] })
+		for _, r := range rs {
+			labels = append(labels, r)
+			queue = append(queue, n.children[r])
+		}
+		t.First = append(t.First, len(labels))
+	}
+	t.Labels = string(labels)
+	return t
+}
+
+// Lookup returns the keys stored under exactly s, or nil.
+func (t PackedTrie) Lookup(s string) []string {
+	labels := []rune(t.Labels)
+	n := 0
+	for _, r := range s {
+		if n = t.child(labels, n, r); n < 0 {
+			return nil
+		}
+	}
+	return t.keysAt(n)
+}
+
+// Substrings returns the keys of every entry that occurs in s, sorted and
+// deduplicated. It walks the trie once from each offset of s.
+func (t PackedTrie) Substrings(s string) []string {
+	labels := []rune(t.Labels)
+	runes := []rune(s)
+	seen := make(map[string]bool)
+	var out []string
+	for start := range runes {
+		n := 0
+		for _, r := range runes[start:] {
+			if n = t.child(labels, n, r); n < 0 {
+				break
+			}
+			for _, k := range t.keysAt(n) {
+				if !seen[k] {
+					seen[k] = true
+					out = append(out, k)
+				}
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// child returns the node reached from n over label r, or -1.
+func (t PackedTrie) child(labels []rune, n int, r rune) int {
+	lo, hi := t.First[n], t.First[n+1]
+	e := lo + sort.Search(hi-lo, func(i int) bool { return labels[lo+i] >= r })
+	if e < hi && labels[e] == r {
+		return e + 1
+	}
+	return -1
+}
+
+func (t PackedTrie) keysAt(n int) []string {
+	if k := sort.SearchInts(t.Ends, n); k < len(t.Ends) && t.Ends[k] == n {
+		return t.Keys[k]
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestNameTrieMatchesMaps(t *testing.T) {
+	keywordHosts := map[string][]string{
+		"github":   {"api.github.com"},
+		"git":      {"git.example.com"},
+		"open-ai":  {"api.openai.com"},
+		"openai":   {"api.openai.com"},
+		"stripe":   {"api.stripe.com"},
+		"münchen":  {"api.münchen.example"},
+		"--":       {"ignored.example"}, // normalizes to ""
+		"sendgrid": {"api.sendgrid.com"},
+	}
+	exactNames := map[string][]string{"DD_API_KEY": {"api.datadoghq.com"}, "hf_token": {"huggingface.co"}}
+	trie := BuildNameTrie(keywordHosts, exactNames)
+
+	// Round-trip through JSON, as consumers see it.
+	data, err := json.Marshal(trie)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded NameTrie
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(trie) {
+		t.Fatal("name trie changed across a JSON round trip")
+	}
+
+	for _, name := range []string{"GITHUB_TOKEN", "OPEN_AI_KEY", "MY_STRIPE_SENDGRID", "MÜNCHEN_KEY", "UNRELATED", "", "GI"} {
+		norm := combine.NormalizeKeyword(name)
+		var want []string
+		for k := range keywordHosts {
+			if kn := combine.NormalizeKeyword(k); kn != "" && strings.Contains(norm, kn) {
+				want = append(want, k)
+			}
+		}
+		sort.Strings(want)
+		if got := decoded.Keywords.Substrings(norm); !reflect.DeepEqual(got, want) {
+			t.Errorf("Substrings(%q) = %v, want %v", norm, got, want)
+		}
+	}
+
+	if got := decoded.ExactNames.Lookup("HF_TOKEN"); !reflect.DeepEqual(got, []string{"hf_token"}) {
+		t.Errorf("Lookup(HF_TOKEN) = %v", got)
+	}
+	if got := decoded.ExactNames.Lookup("DD_API"); got != nil {
+		t.Errorf("Lookup of a prefix = %v, want nil", got)
+	}
+}
+
+func TestToGondolinNameTrie(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{{Keyword: "github", Hosts: []string{"api.github.com"}}}}
+	if g := ToGondolin(full, Options{}); g.NameTrie != nil {
+		t.Error("name_trie emitted without Options.NameTrie")
+	}
+	g := ToGondolin(full, Options{NameTrie: true})
+	if g.NameTrie == nil || !reflect.DeepEqual(g.NameTrie.Keywords.Substrings("mygithubtoken"), []string{"github"}) {
+		t.Fatalf("name_trie = %+v", g.NameTrie)
+	}
+	if errs := ValidateGondolin(g, ValidateOptions{}); len(errs) != 0 {
+		t.Errorf("ValidateGondolin: %v", errs)
+	}
+
+	g.KeywordHostMap["gitlab"] = []string{"gitlab.com"}
+	errs := ValidateGondolin(g.WithContentHash(), ValidateOptions{})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "name_trie") {
+		t.Errorf("ValidateGondolin with a stale trie = %v, want one name_trie error", errs)
+	}
+}
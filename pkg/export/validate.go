@@ -0,0 +1,349 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// ValidateOptions controls checks that depend on how the export was built.
+type ValidateOptions struct {
+	AllowIPHosts bool
+}
+
+// DetectKind reports whether raw JSON is a gondolin or a combined
+// (full) export, based on top-level keys.
+func DetectKind(data []byte) (string, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return "", err
+	}
+	if _, ok := top["keyword_host_map"]; ok {
+		return "gondolin", nil
+	}
+	if _, ok := top["schema_version"]; ok {
+		return "gondolin", nil
+	}
+	if _, ok := top["services"]; ok {
+		return "full", nil
+	}
+	return "", errors.New("unrecognized export: expected gondolin (keyword_host_map) or full (services) JSON")
+}
+
+// validateHost checks a single exported host. Wildcards ("*.example.com")
+// are validated on their base domain.
+func validateHost(host string, opts ValidateOptions) error {
+	base := strings.TrimPrefix(host, "*.")
+	if base != strings.ToLower(base) {
+		return fmt.Errorf("host %q is not lowercase", host)
+	}
+	if trufflehog.IsNoiseHost(base, opts.AllowIPHosts) {
+		return fmt.Errorf("host %q fails trufflehog.IsNoiseHost", host)
+	}
+	if base == host && trufflehog.IsPublicSuffix(host) {
+		return fmt.Errorf("host %q is a public suffix (use a wildcard entry)", host)
+	}
+	return nil
+}
+
+// validateRegex checks that a pattern is non-empty, compiles, and that
+// secretGroup refers to an existing capture group.
+func validateRegex(expr string, secretGroup int) error {
+	if strings.TrimSpace(expr) == "" {
+		return errors.New("empty regex")
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("regex does not compile: %w", err)
+	}
+	if secretGroup < 0 || secretGroup > re.NumSubexp() {
+		return fmt.Errorf("secret_group %d out of range (regex has %d groups)", secretGroup, re.NumSubexp())
+	}
+	return nil
+}
+
+// ValidateGondolin checks a gondolin export against the current schema and
+// its internal invariants. It returns every problem found, not just the first.
+func ValidateGondolin(g Gondolin, opts ValidateOptions) []error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if g.SchemaVersion < 1 || g.SchemaVersion > SchemaVersion {
+		add("schema_version %d is not supported (1..%d)", g.SchemaVersion, SchemaVersion)
+	}
+	if g.SchemaVersion >= 2 && g.ContentHash != "" && g.WithContentHash().ContentHash != g.ContentHash {
+		add("content_hash %s does not match content", g.ContentHash)
+	}
+
+	checkHosts := func(where string, hosts []string) {
+		if len(hosts) == 0 {
+			add("%s: no hosts", where)
+		}
+		for _, h := range hosts {
+			if err := validateHost(h, opts); err != nil {
+				add("%s: %v", where, err)
+			}
+		}
+	}
+	for _, k := range sortedMapKeys(g.KeywordHostMap) {
+		if k == "" {
+			add("keyword_host_map: empty keyword")
+		}
+		checkHosts("keyword_host_map["+k+"]", g.KeywordHostMap[k])
+	}
+	for _, k := range sortedMapKeys(g.ExactNameHostMap) {
+		checkHosts("exact_name_host_map["+k+"]", g.ExactNameHostMap[k])
+	}
+	switch g.Wildcards {
+	case "", WildcardsBoth:
+	case WildcardsExpand:
+		for _, section := range []struct {
+			name string
+			m    map[string][]string
+		}{{"keyword_host_map", g.KeywordHostMap}, {"exact_name_host_map", g.ExactNameHostMap}} {
+			for _, k := range sortedMapKeys(section.m) {
+				for _, h := range section.m[k] {
+					if strings.HasPrefix(h, "*.") {
+						add("%s[%s]: wildcard %q in an export with wildcards expanded", section.name, k, h)
+					}
+				}
+			}
+		}
+	default:
+		add("wildcards: unknown policy %q", g.Wildcards)
+	}
+
+	for _, name := range sortedMapKeys(g.ExactNameSources) {
+		if _, ok := g.ExactNameHostMap[name]; !ok {
+			add("exact_name_sources[%s]: name not in exact_name_host_map", name)
+		}
+		if k := g.ExactNameSources[name]; g.KeywordHostMap[k] == nil {
+			add("exact_name_sources[%s]: keyword %q not in keyword_host_map", name, k)
+		}
+	}
+
+	if m := g.Matching; m != nil {
+		if mode, err := ParseKeywordMatch(m.Keywords); err != nil || mode != m.Keywords {
+			add("matching.keywords: unknown keyword match %q", m.Keywords)
+		}
+		if m.CaseSensitive {
+			add("matching.case_sensitive: must be false")
+		}
+		for _, k := range sortedMapKeys(m.KeywordRegexes) {
+			if _, ok := g.KeywordHostMap[k]; !ok {
+				add("matching.keyword_regexes[%s]: keyword not in keyword_host_map", k)
+			} else if _, err := regexp.Compile(m.KeywordRegexes[k]); err != nil {
+				add("matching.keyword_regexes[%s]: %v", k, err)
+			}
+		}
+	}
+
+	for _, k := range sortedMapKeys(g.ServiceWeights) {
+		if w := g.ServiceWeights[k]; !(w > 0) {
+			add("service_weights[%s]: weight %v must be > 0", k, w)
+		}
+	}
+
+	for _, k := range sortedMapKeys(g.PrimaryHostMap) {
+		primary := g.PrimaryHostMap[k]
+		hosts, ok := g.KeywordHostMap[k]
+		if !ok {
+			add("primary_host_map[%s]: keyword not in keyword_host_map", k)
+			continue
+		}
+		found := false
+		for _, h := range hosts {
+			if h == primary {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("primary_host_map[%s]: %q is not one of the keyword's hosts", k, primary)
+		}
+	}
+	for _, h := range sortedMapKeys(g.PathPrefixes) {
+		for _, p := range g.PathPrefixes[h] {
+			if !strings.HasPrefix(p, "/") || !strings.HasSuffix(p, "/") {
+				add("path_prefixes[%s]: %q must start and end with /", h, p)
+			}
+		}
+	}
+	for _, h := range sortedMapKeys(g.HostRoles) {
+		if !combine.IsValidHostRole(g.HostRoles[h]) {
+			add("host_roles[%s]: unknown role %q", h, g.HostRoles[h])
+		}
+	}
+
+	if len(g.HostTemplates) > 0 {
+		mapped := make(map[string]bool)
+		for _, hosts := range g.KeywordHostMap {
+			for _, h := range hosts {
+				mapped[h] = true
+			}
+		}
+		for _, w := range sortedMapKeys(g.HostTemplates) {
+			if t, ok := trufflehog.ParseHostTemplate(g.HostTemplates[w]); !ok || t.Wildcard != w {
+				add("host_templates[%s]: %q is not a template for this wildcard", w, g.HostTemplates[w])
+			}
+			if !mapped[w] {
+				add("host_templates[%s]: wildcard not in keyword_host_map", w)
+			}
+		}
+	}
+
+	if g.NameTrie != nil && !g.NameTrie.Equal(BuildNameTrie(g.KeywordHostMap, g.ExactNameHostMap)) {
+		add("name_trie: does not match keyword_host_map and exact_name_host_map")
+	}
+
+	if g.MergedPatterns != nil && !mergedPatternsEqual(g.MergedPatterns, MergePatterns(g.ValuePatterns)) {
+		add("merged_patterns: does not match value_patterns")
+	}
+
+	if b := g.KeywordBloom; b != nil {
+		if b.M <= 0 || b.K <= 0 || len(b.Bits)*8 < b.M {
+			add("keyword_bloom: %d bytes of bits cannot hold m=%d (k=%d)", len(b.Bits), b.M, b.K)
+		} else {
+			for _, p := range g.ValuePatterns {
+				for _, kw := range p.Keywords {
+					if kw := strings.ToLower(kw); !b.MayContain(kw) || !slices.Contains(b.Lengths, len(kw)) {
+						add("keyword_bloom: misses keyword %q of %s", kw, p.ID)
+					}
+				}
+			}
+		}
+	}
+
+	seenIDs := make(map[string]bool, len(g.ValuePatterns))
+	for i, p := range g.ValuePatterns {
+		where := fmt.Sprintf("value_patterns[%d] (%s)", i, p.ID)
+		if p.ID == "" {
+			add("%s: empty id", where)
+		} else if seenIDs[p.ID] {
+			add("%s: duplicate id", where)
+		}
+		seenIDs[p.ID] = true
+		if err := validateRegex(p.Regex, p.SecretGroup); err != nil {
+			add("%s: %v", where, err)
+		}
+		if p.AnchoredRegex != "" {
+			if err := validateRegex(p.AnchoredRegex, p.SecretGroup); err != nil {
+				add("%s: anchored_regex: %v", where, err)
+			}
+		}
+		if p.RegexRE2 != "" || p.RegexJS != "" || p.AnchoredRegexRE2 != "" || p.AnchoredRegexJS != "" || p.JSIncompatible != "" {
+			want := ValuePattern{Regex: p.Regex, AnchoredRegex: p.AnchoredRegex}
+			want.setDialects()
+			switch {
+			case p.RegexRE2 != want.RegexRE2:
+				add("%s: regex_re2 is not the RE2 spelling of regex", where)
+			case p.RegexJS != want.RegexJS || p.JSIncompatible != want.JSIncompatible:
+				add("%s: regex_js is not the JavaScript spelling of regex", where)
+			case p.AnchoredRegexRE2 != want.AnchoredRegexRE2:
+				add("%s: anchored_regex_re2 is not the RE2 spelling of anchored_regex", where)
+			case p.AnchoredRegexJS != want.AnchoredRegexJS:
+				add("%s: anchored_regex_js is not the JavaScript spelling of anchored_regex", where)
+			}
+		}
+		if p.Keyword != "" {
+			if _, ok := g.KeywordHostMap[p.Keyword]; !ok {
+				add("%s: keyword %q does not resolve in keyword_host_map", where, p.Keyword)
+			}
+		}
+		if p.Policy != "" && !combine.IsValidPolicy(p.Policy) {
+			add("%s: unknown policy %q", where, p.Policy)
+		}
+		if p.Severity != "" && !combine.IsValidSeverity(p.Severity) {
+			add("%s: unknown severity %q", where, p.Severity)
+		}
+		if p.FalsePositiveScore < 0 || p.FalsePositiveScore > 1 {
+			add("%s: false_positive_score %v outside [0, 1]", where, p.FalsePositiveScore)
+		}
+	}
+
+	return errs
+}
+
+// ValidateCombined checks a full export's hosts, rules, and keyword
+// uniqueness.
+func ValidateCombined(e combine.Export, opts ValidateOptions) []error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if e.ContentHash != "" && e.WithContentHash().ContentHash != e.ContentHash {
+		add("content_hash %s does not match content", e.ContentHash)
+	}
+
+	seen := make(map[string]bool, len(e.Services))
+	for i, svc := range e.Services {
+		where := fmt.Sprintf("services[%d] (%s)", i, svc.Keyword)
+		if svc.Keyword == "" {
+			add("%s: empty keyword", where)
+		} else if seen[svc.Keyword] {
+			add("%s: duplicate keyword", where)
+		}
+		seen[svc.Keyword] = true
+
+		for _, h := range svc.Hosts {
+			if err := validateHost(h, opts); err != nil {
+				add("%s: %v", where, err)
+			}
+		}
+		for _, rh := range svc.RegionalHosts {
+			if err := validateHost(rh.Host, opts); err != nil {
+				add("%s: regional: %v", where, err)
+			}
+		}
+		if len(svc.Rules) == 0 {
+			add("%s: no rules", where)
+		}
+		for _, r := range svc.Rules {
+			if err := validateRegex(r.Regex, r.SecretGroup); err != nil {
+				add("%s: rule %s: %v", where, r.ID, err)
+				continue
+			}
+			if len(r.Examples) > 0 {
+				re := regexp.MustCompile(r.Regex)
+				for _, ex := range r.Examples {
+					if !re.MatchString(ex) {
+						add("%s: rule %s: example %q does not match the regex", where, r.ID, ex)
+					}
+				}
+			}
+		}
+	}
+
+	for i, th := range e.THOnlyHosts {
+		where := fmt.Sprintf("th_only_hosts[%d] (%s)", i, th.Keyword)
+		if len(th.Hosts) == 0 {
+			add("%s: no hosts", where)
+		}
+		for _, h := range th.Hosts {
+			if err := validateHost(h, opts); err != nil {
+				add("%s: %v", where, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
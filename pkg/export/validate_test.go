@@ -0,0 +1,119 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestValidateGondolinAcceptsGeneratedExport(t *testing.T) {
+	full := combine.Export{
+		GeneratedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Services: []combine.Service{
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-access-token", Regex: `(sk_live_[a-z0-9]{24})`, SecretGroup: 1}}},
+			{Keyword: "age", Rules: []combine.Rule{{ID: "age-secret-key", Regex: `AGE-SECRET-KEY-1[0-9A-Z]{58}`}}},
+		},
+	}
+
+	if errs := ValidateCombined(full.WithContentHash(), ValidateOptions{}); len(errs) != 0 {
+		t.Errorf("ValidateCombined: %v", errs)
+	}
+	if errs := ValidateGondolin(ToGondolin(full, Options{}), ValidateOptions{}); len(errs) != 0 {
+		t.Errorf("ValidateGondolin: %v", errs)
+	}
+}
+
+func TestValidateGondolinReportsProblems(t *testing.T) {
+	g := Gondolin{
+		SchemaVersion: SchemaVersion,
+		KeywordHostMap: map[string][]string{
+			"stripe": {"api.stripe.com"},
+			"bad":    {"localhost", "*.example.com"},
+			"heroku": {"herokuapp.com", "*.herokuapp.com"},
+		},
+		ExactNameHostMap: map[string][]string{"FOO_KEY": {"10.0.0.1"}},
+		PrimaryHostMap:   map[string]string{"stripe": "dashboard.stripe.com"},
+		HostRoles:        map[string]string{"api.stripe.com": "mystery"},
+		ValuePatterns: []ValuePattern{
+			{ID: "empty", Regex: ""},
+			{ID: "broken", Regex: `(`},
+			{ID: "dangling", Keyword: "nope", Regex: `x`},
+			{ID: "group", Regex: `x`, SecretGroup: 2},
+			{ID: "group", Regex: `y`},
+		},
+	}
+	g = g.WithContentHash()
+	g.ContentHash = "sha256:stale"
+
+	errs := ValidateGondolin(g, ValidateOptions{})
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	joined := strings.Join(msgs, "\n")
+
+	for _, want := range []string{
+		"content_hash",
+		`keyword_host_map[bad]: host "localhost"`,
+		`exact_name_host_map[FOO_KEY]: host "10.0.0.1"`,
+		"primary_host_map[stripe]",
+		`unknown role "mystery"`,
+		"(empty): empty regex",
+		"(broken): regex does not compile",
+		`(dangling): keyword "nope" does not resolve`,
+		"(group): secret_group 2 out of range",
+		"(group): duplicate id",
+		`host "herokuapp.com" is a public suffix`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing problem containing %q in:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "*.example.com") || strings.Contains(joined, `"*.herokuapp.com"`) {
+		t.Errorf("wildcard host should validate on its base domain:\n%s", joined)
+	}
+
+	if errs := ValidateGondolin(Gondolin{SchemaVersion: 99}, ValidateOptions{}); len(errs) == 0 {
+		t.Error("expected schema_version problem")
+	}
+}
+
+func TestValidateCombinedReportsProblems(t *testing.T) {
+	e := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "dup", Hosts: []string{"api.dup.com"}, Rules: []combine.Rule{{ID: "dup-key", Regex: `d`, Examples: []string{"d1", "x"}}}},
+			{Keyword: "dup", Hosts: []string{"intranet.local"}, Rules: []combine.Rule{{ID: "dup-key-2", Regex: ""}}},
+		},
+		THOnlyHosts: []combine.THOnlyEntry{{Keyword: "th", DirName: "th", Hosts: []string{"("}}},
+	}
+
+	errs := ValidateCombined(e, ValidateOptions{})
+	if len(errs) != 5 {
+		t.Errorf("ValidateCombined problems = %d, want 5: %v", len(errs), errs)
+	}
+}
+
+func TestDetectExportKind(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"schema_version": 1, "keyword_host_map": {}}`, "gondolin"},
+		{`{"generated_at": "2026-01-01T00:00:00Z", "services": []}`, "full"},
+		{`{"foo": 1}`, ""},
+	}
+	for _, tt := range tests {
+		got, err := DetectKind([]byte(tt.data))
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("DetectKind(%s) = %q, want error", tt.data, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("DetectKind(%s) = %q, %v; want %q", tt.data, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// Wildcard policies for gondolin hosts (Options.Wildcards). A wildcard such
+// as "*.datadoghq.com" stands for any subdomain, not the bare domain.
+const (
+	WildcardsKeep   = "keep"   // list wildcards as is (the default)
+	WildcardsExpand = "expand" // replace each wildcard by the known concrete hosts under it
+	WildcardsBoth   = "both"   // list the wildcard and the known concrete hosts under it
+)
+
+// ParseWildcardPolicy checks a -wildcards value. "" is WildcardsKeep.
+func ParseWildcardPolicy(s string) (string, error) {
+	switch s {
+	case "", WildcardsKeep:
+		return WildcardsKeep, nil
+	case WildcardsExpand, WildcardsBoth:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown wildcard policy %q (want keep, expand or both)", s)
+}
+
+// concreteHosts returns every non-wildcard host the export or the curated
+// maps know of, sorted. These are what wildcards expand to.
+func concreteHosts(full combine.Export) []string {
+	seen := make(map[string]bool)
+	add := func(hosts []string) {
+		for _, h := range hosts {
+			if !strings.HasPrefix(h, "*.") {
+				seen[h] = true
+			}
+		}
+	}
+	for _, svc := range full.Services {
+		add(svc.AllowedHosts())
+	}
+	for _, th := range full.THOnlyHosts {
+		add(th.Hosts)
+	}
+	for _, hosts := range keywordHostMapOverrides {
+		add(hosts)
+	}
+	for _, hosts := range exactNameHostMap {
+		add(hosts)
+	}
+	out := make([]string, 0, len(seen))
+	for h := range seen {
+		out = append(out, h)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// applyWildcardPolicy rewrites the wildcards in hosts by policy, expanding
+// them to the concrete hosts under them. The result is sorted and may be
+// empty when expand finds nothing a wildcard covers.
+func applyWildcardPolicy(hosts []string, policy string, concrete []string) []string {
+	if policy == "" || policy == WildcardsKeep {
+		return hosts
+	}
+	seen := make(map[string]bool, len(hosts))
+	var out []string
+	add := func(h string) {
+		if !seen[h] {
+			seen[h] = true
+			out = append(out, h)
+		}
+	}
+	for _, h := range hosts {
+		base, ok := strings.CutPrefix(h, "*.")
+		if !ok {
+			add(h)
+			continue
+		}
+		if policy == WildcardsBoth {
+			add(h)
+		}
+		for _, c := range concrete {
+			if strings.HasSuffix(c, "."+base) {
+				add(c)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestWildcardPolicy(t *testing.T) {
+	full := combine.Export{Services: []combine.Service{
+		{Keyword: "datadog", Hosts: []string{"*.datadoghq.com", "api.datadoghq.com"}, RegionalHosts: []combine.RegionalHost{{Host: "us5.datadoghq.com", Region: "us5"}}},
+		{Keyword: "acme", Hosts: []string{"*.acme.example"}, Rules: []combine.Rule{{ID: "acme-key", Regex: `acme_[a-z0-9]{32}`}},
+			HostTemplates: []trufflehog.HostTemplate{{Template: "{tenant}.acme.example", Wildcard: "*.acme.example"}}},
+	}}
+
+	keep := ToGondolin(full, Options{})
+	if keep.Wildcards != "" || !slices.Contains(keep.KeywordHostMap["datadog"], "*.datadoghq.com") || keep.HostTemplates["*.acme.example"] == "" {
+		t.Errorf("keep: wildcards %q, datadog %v, templates %v", keep.Wildcards, keep.KeywordHostMap["datadog"], keep.HostTemplates)
+	}
+
+	expand := ToGondolin(full, Options{Wildcards: WildcardsExpand})
+	if want := []string{"api.datadoghq.com", "us5.datadoghq.com"}; !reflect.DeepEqual(expand.KeywordHostMap["datadog"], want) {
+		t.Errorf("expand: datadog = %v, want %v", expand.KeywordHostMap["datadog"], want)
+	}
+	if _, ok := expand.KeywordHostMap["acme"]; ok || len(expand.HostTemplates) != 0 {
+		t.Errorf("expand: acme = %v, templates %v; want both dropped", expand.KeywordHostMap["acme"], expand.HostTemplates)
+	}
+	for _, p := range expand.ValuePatterns {
+		if p.ID == "acme-key" && p.Keyword != "" {
+			t.Errorf("expand: acme-key still linked to %q", p.Keyword)
+		}
+	}
+	for name, hosts := range expand.ExactNameHostMap {
+		for _, h := range hosts {
+			if strings.HasPrefix(h, "*.") {
+				t.Errorf("expand: exact_name_host_map[%s] keeps %s", name, h)
+			}
+		}
+	}
+	if expand.Wildcards != WildcardsExpand || len(ValidateGondolin(expand, ValidateOptions{})) != 0 {
+		t.Errorf("expand: wildcards %q, validate %v", expand.Wildcards, ValidateGondolin(expand, ValidateOptions{}))
+	}
+
+	both := ToGondolin(full, Options{Wildcards: WildcardsBoth})
+	if want := []string{"*.datadoghq.com", "api.datadoghq.com", "us5.datadoghq.com"}; !reflect.DeepEqual(both.KeywordHostMap["datadog"], want) {
+		t.Errorf("both: datadog = %v, want %v", both.KeywordHostMap["datadog"], want)
+	}
+
+	tampered := expand
+	tampered.KeywordHostMap = make(map[string][]string, len(expand.KeywordHostMap))
+	for k, hosts := range expand.KeywordHostMap {
+		tampered.KeywordHostMap[k] = hosts
+	}
+	tampered.KeywordHostMap["datadog"] = append([]string{"*.datadoghq.com"}, expand.KeywordHostMap["datadog"]...)
+	if errs := ValidateGondolin(tampered.WithContentHash(), ValidateOptions{}); len(errs) != 1 || !strings.Contains(errs[0].Error(), "wildcards expanded") {
+		t.Errorf("wildcard in an expanded export: validate = %v", errs)
+	}
+	if _, err := ParseWildcardPolicy("explode"); err == nil {
+		t.Error("ParseWildcardPolicy(explode): no error")
+	}
+}
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// WorklistEntry is a Gitleaks service exported without hosts, a candidate
+// for data/well_known_hosts.json.
+type WorklistEntry struct {
+	Keyword    string   `json:"keyword"`
+	Rules      int      `json:"rules"`
+	Weight     float64  `json:"weight,omitempty"`     // -popularity weight
+	Popularity int      `json:"popularity,omitempty"` // 1-based position in the curated popularity list
+	Candidates []string `json:"candidates,omitempty"` // probed hosts that resolve
+}
+
+// NoHostWorklist lists the services without hosts, ranked by rule count,
+// then popularity weight (normalized keyword → weight, see
+// ParsePopularityWeights), then position in the curated popularity list,
+// then keyword.
+func NoHostWorklist(services []combine.Service, popularity []string, weights map[string]float64) []WorklistEntry {
+	rank := make(map[string]int, len(popularity))
+	for i, k := range popularity {
+		rank[combine.NormalizeKeyword(k)] = i + 1
+	}
+	var entries []WorklistEntry
+	for _, svc := range services {
+		if len(svc.Hosts) > 0 {
+			continue
+		}
+		norm := combine.NormalizeKeyword(svc.Keyword)
+		entries = append(entries, WorklistEntry{Keyword: svc.Keyword, Rules: len(svc.Rules), Weight: weights[norm], Popularity: rank[norm]})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Rules != b.Rules {
+			return a.Rules > b.Rules
+		}
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		if (a.Popularity == 0) != (b.Popularity == 0) {
+			return a.Popularity != 0
+		}
+		if a.Popularity != b.Popularity {
+			return a.Popularity < b.Popularity
+		}
+		return a.Keyword < b.Keyword
+	})
+	return entries
+}
+
+// CandidateHosts guesses API hosts for a keyword: api.<k>.com, <k>.com and
+// api.<k>.io, for the keyword with its separators as hyphens and, when
+// different, with them removed.
+func CandidateHosts(keyword string) []string {
+	hyphenated := strings.ReplaceAll(strings.ToLower(keyword), "_", "-")
+	bases := []string{hyphenated}
+	if joined := strings.ReplaceAll(hyphenated, "-", ""); joined != hyphenated {
+		bases = append(bases, joined)
+	}
+	var hosts []string
+	for _, b := range bases {
+		for _, h := range []string{"api." + b + ".com", b + ".com", "api." + b + ".io"} {
+			if trufflehog.IsValidHostname(h) {
+				hosts = append(hosts, h)
+			}
+		}
+	}
+	return hosts
+}
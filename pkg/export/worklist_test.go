@@ -0,0 +1,43 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestNoHostWorklist(t *testing.T) {
+	rules := func(n int) []combine.Rule { return make([]combine.Rule, n) }
+	services := []combine.Service{
+		{Keyword: "meraki", Hosts: []string{"api.meraki.com"}, Rules: rules(5)},
+		{Keyword: "zeta", Rules: rules(1)},
+		{Keyword: "alpha", Rules: rules(1)},
+		{Keyword: "stripe", Rules: rules(1)},
+		{Keyword: "heavy", Rules: rules(1)},
+		{Keyword: "many", Rules: rules(3)},
+	}
+	got := NoHostWorklist(services, []string{"stripe"}, map[string]float64{"heavy": 2})
+	var keywords []string
+	for _, e := range got {
+		keywords = append(keywords, e.Keyword)
+	}
+	if want := []string{"many", "heavy", "stripe", "alpha", "zeta"}; !reflect.DeepEqual(keywords, want) {
+		t.Errorf("order = %v, want %v", keywords, want)
+	}
+	if got[1].Weight != 2 || got[2].Popularity != 1 || got[0].Rules != 3 {
+		t.Errorf("entries = %+v", got)
+	}
+}
+
+func TestCandidateHosts(t *testing.T) {
+	if got, want := CandidateHosts("Cisco_Meraki"), []string{
+		"api.cisco-meraki.com", "cisco-meraki.com", "api.cisco-meraki.io",
+		"api.ciscomeraki.com", "ciscomeraki.com", "api.ciscomeraki.io",
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CandidateHosts = %v, want %v", got, want)
+	}
+	if got := CandidateHosts("bad.key word"); got != nil {
+		t.Errorf("invalid keyword: %v", got)
+	}
+}
@@ -0,0 +1,185 @@
+// Package extractor runs external extractors: executables that add secret
+// sources next to TruffleHog and Gitleaks, so organizations can feed
+// proprietary services into the export without forking.
+//
+// The contract is JSON over stdio. The extractor is started with its
+// configured arguments and gets one Request on stdin; it writes one Response
+// to stdout and exits 0. The tail of its stderr is reported if it fails.
+// Detectors contribute hosts the way TruffleHog detectors do, rules
+// contribute value patterns the way Gitleaks rules do, and both are combined
+// by keyword with the upstream sources.
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// ProtocolVersion is the version of Request and Response this package
+// speaks. An extractor must echo it in its response.
+const ProtocolVersion = 1
+
+// maxStderr bounds how much of an extractor's stderr is kept for errors.
+const maxStderr = 4 << 10
+
+// Request is written to an extractor's stdin.
+type Request struct {
+	Protocol     int  `json:"protocol"`
+	AllowIPHosts bool `json:"allow_ip_hosts,omitempty"` // IP-literal hosts will be kept (-allow-ip-hosts)
+}
+
+// Response is read from an extractor's stdout. Detectors and rules use the
+// JSON shapes of trufflehog.Detector and gitleaks.Rule; keyword may be left
+// empty and is then derived from dir_name or id like upstream. A detector's
+// dir_name is exported prefixed with Name.
+type Response struct {
+	Protocol  int                   `json:"protocol"`
+	Name      string                `json:"name,omitempty"` // source name for logs (default: the executable's base name)
+	Detectors []trufflehog.Detector `json:"detectors,omitempty"`
+	Rules     []gitleaks.Rule       `json:"rules,omitempty"`
+	Warnings  []string              `json:"warnings,omitempty"`
+}
+
+// Options controls how responses are checked.
+type Options struct {
+	AllowIPHosts      bool
+	HostNormalization trufflehog.HostNormalization // applied to every host
+}
+
+// Result is a checked Response. Entries that failed the checks are dropped
+// and explained in Warnings, as TruffleHog extraction does.
+type Result struct {
+	Name      string
+	Detectors []trufflehog.Detector
+	Rules     []gitleaks.Rule
+	Warnings  []error
+}
+
+// Run starts command (an executable and its arguments, split on
+// whitespace; no shell is involved), exchanges one request and response,
+// and checks the response. It stops the extractor when ctx is done.
+func Run(ctx context.Context, command string, opts Options) (Result, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return Result{}, errors.New("empty extractor command")
+	}
+	req, err := json.Marshal(Request{Protocol: ProtocolVersion, AllowIPHosts: opts.AllowIPHosts})
+	if err != nil {
+		return Result{}, err
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(req)
+	var stdout bytes.Buffer
+	stderr := &tailBuffer{max: maxStderr}
+	cmd.Stdout, cmd.Stderr = &stdout, stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Result{}, fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return Result{}, fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return Result{}, fmt.Errorf("%s: decode response: %w", args[0], err)
+	}
+	if resp.Protocol != ProtocolVersion {
+		return Result{}, fmt.Errorf("%s: response speaks protocol %d, want %d", args[0], resp.Protocol, ProtocolVersion)
+	}
+	if resp.Name == "" {
+		resp.Name = filepath.Base(args[0])
+	}
+	return check(resp, opts), nil
+}
+
+// check normalizes resp and drops entries that upstream extraction would
+// not have produced: detectors without usable hosts, rules without an ID or
+// with a regex Go can't compile.
+func check(resp Response, opts Options) Result {
+	res := Result{Name: resp.Name}
+	warn := func(format string, args ...any) {
+		res.Warnings = append(res.Warnings, fmt.Errorf("%s: %s", resp.Name, fmt.Sprintf(format, args...)))
+	}
+	for _, w := range resp.Warnings {
+		warn("%s", w)
+	}
+
+	for _, d := range resp.Detectors {
+		if d.DirName == "" {
+			d.DirName = d.Keyword
+		}
+		if d.Keyword == "" {
+			d.Keyword = trufflehog.DeriveKeyword(d.DirName)
+		}
+		if d.Keyword == "" {
+			warn("detector without keyword or dir_name")
+			continue
+		}
+		var hosts []string
+		for _, h := range d.Hosts {
+			norm, err := trufflehog.NormalizeHostWith(h, opts.HostNormalization)
+			if err != nil {
+				warn("detector %s: %v", d.Keyword, err)
+				continue
+			}
+			if trufflehog.IsNoiseHost(strings.TrimPrefix(norm, "*."), opts.AllowIPHosts) {
+				warn("detector %s: dropped host %q", d.Keyword, h)
+				continue
+			}
+			hosts = append(hosts, norm)
+		}
+		if len(hosts) == 0 {
+			warn("detector %s: no usable hosts", d.Keyword)
+			continue
+		}
+		sort.Strings(hosts)
+		d.Hosts = hosts
+		// Upstream detectors are identified by directory; the prefix keeps
+		// extractor entries apart from them and from each other.
+		d.DirName = resp.Name + "/" + d.DirName
+		res.Detectors = append(res.Detectors, d)
+	}
+
+	for _, r := range resp.Rules {
+		if r.ID == "" {
+			warn("rule without id")
+			continue
+		}
+		if _, err := regexp.Compile(r.Regex); err != nil || strings.TrimSpace(r.Regex) == "" {
+			warn("rule %s: invalid regex %q", r.ID, r.Regex)
+			continue
+		}
+		if r.Keyword == "" {
+			r.Keyword = gitleaks.DeriveKeyword(r.ID)
+		}
+		res.Rules = append(res.Rules, r)
+	}
+	return res
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	buf []byte
+	max int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string { return string(b.buf) }
@@ -0,0 +1,95 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// TestHelperExtractor is the extractor the tests run: the test binary
+// re-executed with EXTRACTOR_HELPER set to the response to give.
+func TestHelperExtractor(t *testing.T) {
+	mode := os.Getenv("EXTRACTOR_HELPER")
+	if mode == "" {
+		t.Skip("helper process")
+	}
+	var req Request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "bad request:", err)
+		os.Exit(3)
+	}
+	switch mode {
+	case "ok":
+		json.NewEncoder(os.Stdout).Encode(Response{
+			Protocol: req.Protocol,
+			Name:     "acme",
+			Detectors: []trufflehog.Detector{
+				{DirName: "acmepay", Hosts: []string{"API.AcmePay.example", "localhost", "10.0.0.1"}},
+				{Keyword: "nohosts", Hosts: []string{"localhost"}},
+			},
+			Rules: []gitleaks.Rule{
+				{ID: "acmepay-api-key", Regex: `acme_[a-z0-9]{32}`},
+				{ID: "broken", Regex: `(`},
+			},
+			Warnings: []string{"skipped legacy entry"},
+		})
+	case "old":
+		json.NewEncoder(os.Stdout).Encode(Response{Protocol: 99})
+	case "fail":
+		fmt.Fprintln(os.Stderr, "credentials missing")
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func helperCommand(t *testing.T, mode string) string {
+	t.Setenv("EXTRACTOR_HELPER", mode)
+	return os.Args[0] + " -test.run=^TestHelperExtractor$"
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	res, err := Run(ctx, helperCommand(t, "ok"), Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Name != "acme" {
+		t.Errorf("Name = %q", res.Name)
+	}
+	if len(res.Detectors) != 1 {
+		t.Fatalf("Detectors = %+v", res.Detectors)
+	}
+	d := res.Detectors[0]
+	if d.Keyword != "acmepay" || d.DirName != "acme/acmepay" || strings.Join(d.Hosts, ",") != "api.acmepay.example" {
+		t.Errorf("detector = %+v", d)
+	}
+	if len(res.Rules) != 1 || res.Rules[0].ID != "acmepay-api-key" || res.Rules[0].Keyword != "acmepay" {
+		t.Errorf("Rules = %+v", res.Rules)
+	}
+	var warnings []string
+	for _, w := range res.Warnings {
+		warnings = append(warnings, w.Error())
+	}
+	got := strings.Join(warnings, "\n")
+	for _, want := range []string{"acme: skipped legacy entry", `dropped host "localhost"`, `dropped host "10.0.0.1"`, "nohosts: no usable hosts", "rule broken: invalid regex"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("warnings missing %q:\n%s", want, got)
+		}
+	}
+
+	if _, err := Run(ctx, helperCommand(t, "old"), Options{}); err == nil || !strings.Contains(err.Error(), "protocol 99") {
+		t.Errorf("protocol mismatch: err = %v", err)
+	}
+	if _, err := Run(ctx, helperCommand(t, "fail"), Options{}); err == nil || !strings.Contains(err.Error(), "credentials missing") {
+		t.Errorf("failing extractor: err = %v", err)
+	}
+	if _, err := Run(ctx, " ", Options{}); err == nil {
+		t.Error("empty command: no error")
+	}
+}
@@ -1,4 +1,9 @@
-package main
+// Package gitleaks extracts regex rules from a Gitleaks config and derives a
+// service keyword for each.
+//
+// Gitleaks is MIT-licensed; patterns, keywords, and metadata may be embedded
+// with attribution.
+package gitleaks
 
 import (
 	"os"
@@ -8,8 +13,8 @@ import (
 	"github.com/BurntSushi/toml"
 )
 
-// GLRule represents a single Gitleaks rule with its derived service keyword.
-type GLRule struct {
+// Rule represents a single Gitleaks rule with its derived service keyword.
+type Rule struct {
 	ID          string   `json:"id"`
 	Keyword     string   `json:"keyword"` // derived service keyword
 	Description string   `json:"description,omitempty"`
@@ -19,14 +24,14 @@ type GLRule struct {
 	Keywords    []string `json:"keywords,omitempty"`
 }
 
-// gitleaksConfig mirrors the TOML shape (only fields we care about).
-type gitleaksConfig struct {
-	Title      string         `toml:"title"`
-	MinVersion string         `toml:"minVersion"`
-	Rules      []gitleaksRule `toml:"rules"`
+// config mirrors the TOML shape (only fields we care about).
+type config struct {
+	Title      string `toml:"title"`
+	MinVersion string `toml:"minVersion"`
+	Rules      []rule `toml:"rules"`
 }
 
-type gitleaksRule struct {
+type rule struct {
 	ID          string   `toml:"id"`
 	Description string   `toml:"description"`
 	Regex       string   `toml:"regex"`
@@ -38,20 +43,20 @@ type gitleaksRule struct {
 	Path        string   `toml:"path"`
 }
 
-// extractGitleaksRules reads gitleaks.toml and returns all rules with regex
+// Extract reads gitleaks.toml and returns all rules with regex
 // patterns, each annotated with a derived service keyword.
-func extractGitleaksRules(tomlPath string) ([]GLRule, error) {
+func Extract(tomlPath string) ([]Rule, error) {
 	data, err := os.ReadFile(tomlPath)
 	if err != nil {
 		return nil, err
 	}
 
-	var cfg gitleaksConfig
+	var cfg config
 	if err := toml.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
 
-	var rules []GLRule
+	var rules []Rule
 	for _, r := range cfg.Rules {
 		if r.SkipReport {
 			continue // respect Gitleaks "skipReport" (typically noisy/informational rules)
@@ -60,9 +65,9 @@ func extractGitleaksRules(tomlPath string) ([]GLRule, error) {
 			continue // skip path-only rules
 		}
 
-		rules = append(rules, GLRule{
+		rules = append(rules, Rule{
 			ID:          r.ID,
-			Keyword:     deriveKeywordFromGitleaksID(r.ID),
+			Keyword:     DeriveKeyword(r.ID),
 			Description: r.Description,
 			Regex:       r.Regex,
 			Entropy:     r.Entropy,
@@ -0,0 +1,86 @@
+package gitleaks
+
+import "strings"
+
+// credentialWords are individual words that describe a credential type rather
+// than a service. Used for splitting hyphenated Gitleaks rule IDs.
+var credentialWords = map[string]bool{
+	// Core credential nouns
+	"api": true, "key": true, "token": true, "secret": true,
+	"password": true, "credential": true, "credentials": true,
+
+	// Auth-related
+	"access": true, "auth": true, "authentication": true,
+	"oauth": true, "pat": true, "sso": true, "scim": true,
+
+	// Roles / scopes
+	"admin": true, "user": true, "client": true, "service": true,
+	"bot": true, "app": true, "org": true, "organization": true,
+	"account": true, "personal": true, "personnal": true, // gitleaks typo
+
+	// Modifiers
+	"public": true, "pub": true, "private": true, "global": true,
+	"shared": true, "custom": true, "sensitive": true,
+	"long": true, "short": true, "lived": true,
+	"fine": true, "grained": true,
+	"legacy": true, "workspace": true, "routable": true,
+	"test": true, "batch": true, "bearer": true,
+
+	// Infra / CI credential types
+	"deploy": true, "runner": true, "cicd": true, "job": true,
+	"trigger": true, "registration": true, "pipeline": true,
+	"feed": true, "incoming": true, "session": true, "cookie": true,
+	"kubernetes": true, "agent": true, "feature": true, "flag": true,
+	"cloud": true, "upload": true, "reference": true, "identity": true,
+
+	// Crypto / format
+	"signing": true, "encryption": true, "ca": true, "origin": true,
+	"insert": true, "browser": true, "base64": true,
+	"config": true, "refresh": true,
+
+	// Web
+	"webhook": true, "url": true, "header": true, "page": true,
+
+	// Abbreviations used by gitlab
+	"ptt": true, "rrt": true,
+}
+
+// glServiceOverrides maps Gitleaks derived service names to canonical keywords
+// for cases where the heuristic gives the wrong result.
+var glServiceOverrides = map[string]string{
+	"aws-amazon-bedrock":     "aws",
+	"contentful-delivery":    "contentful",
+	"curl":                   "curl",
+	"hashicorp-tf":           "hashicorp",
+	"microsoft-teams":        "microsoft-teams",
+	"new-relic":              "newrelic",
+	"settlemint-application": "settlemint",
+	"yandex-aws":             "yandex",
+}
+
+// DeriveKeyword extracts a service keyword from a hyphenated
+// Gitleaks rule ID like "openai-api-key" → "openai".
+//
+// Scans left-to-right and stops at the first credential-type word.
+func DeriveKeyword(ruleID string) string {
+	ruleID = strings.ToLower(strings.TrimSpace(ruleID))
+	if ruleID == "" {
+		return ""
+	}
+	parts := strings.Split(ruleID, "-")
+	var serviceParts []string
+	for _, p := range parts {
+		if credentialWords[p] {
+			break
+		}
+		serviceParts = append(serviceParts, p)
+	}
+	if len(serviceParts) == 0 {
+		return ruleID
+	}
+	name := strings.Join(serviceParts, "-")
+	if override, ok := glServiceOverrides[name]; ok {
+		return override
+	}
+	return name
+}
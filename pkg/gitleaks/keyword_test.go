@@ -0,0 +1,82 @@
+package gitleaks
+
+import "testing"
+
+func TestDeriveKeyword(t *testing.T) {
+	tests := []struct {
+		ruleID string
+		want   string
+	}{
+		// Simple cases
+		{"openai-api-key", "openai"},
+		{"anthropic-api-key", "anthropic"},
+		{"stripe-access-token", "stripe"},
+		{"sendgrid-api-token", "sendgrid"},
+
+		// GitHub variants — all map to "github"
+		{"github-pat", "github"},
+		{"github-fine-grained-pat", "github"},
+		{"github-oauth", "github"},
+		{"github-app-token", "github"},
+		{"github-refresh-token", "github"},
+
+		// GitLab variants — all map to "gitlab"
+		{"gitlab-pat", "gitlab"},
+		{"gitlab-deploy-token", "gitlab"},
+		{"gitlab-cicd-job-token", "gitlab"},
+		{"gitlab-runner-authentication-token", "gitlab"},
+		{"gitlab-ptt", "gitlab"},
+		{"gitlab-rrt", "gitlab"},
+		{"gitlab-scim-token", "gitlab"},
+		{"gitlab-session-cookie", "gitlab"},
+
+		// Slack variants — all map to "slack"
+		{"slack-bot-token", "slack"},
+		{"slack-user-token", "slack"},
+		{"slack-app-token", "slack"},
+		{"slack-legacy-bot-token", "slack"},
+		{"slack-webhook-url", "slack"},
+
+		// Multi-word service names
+		{"cloudflare-api-key", "cloudflare"},
+		{"cloudflare-global-api-key", "cloudflare"},
+		{"cloudflare-origin-ca-key", "cloudflare"},
+		{"digitalocean-access-token", "digitalocean"},
+		{"digitalocean-pat", "digitalocean"},
+
+		// Compound service names kept intact
+		{"cisco-meraki-api-key", "cisco-meraki"},
+		{"microsoft-teams-webhook", "microsoft-teams"},
+
+		// Overrides
+		{"aws-amazon-bedrock-api-key-long-lived", "aws"},
+		{"hashicorp-tf-api-token", "hashicorp"},
+		{"new-relic-user-api-key", "newrelic"},
+		{"settlemint-application-access-token", "settlemint"},
+		{"yandex-aws-access-token", "yandex"},
+
+		// Modifiers correctly treated as credential words
+		{"shopify-shared-secret", "shopify"},
+		{"shopify-custom-access-token", "shopify"},
+		{"facebook-page-access-token", "facebook"},
+		{"twitter-bearer-token", "twitter"},
+		{"flutterwave-encryption-key", "flutterwave"},
+
+		// Edge cases
+		{"jwt", "jwt"},
+		{"jwt-base64", "jwt"},
+		{"private-key", "private-key"},     // all words are credential-type, falls back to full ID
+		{"generic-api-key", "generic"},
+		{"", ""},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ruleID, func(t *testing.T) {
+			got := DeriveKeyword(tt.ruleID)
+			if got != tt.want {
+				t.Errorf("DeriveKeyword(%q) = %q, want %q", tt.ruleID, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package hostcheck verifies that exported hosts still exist: that they
+// resolve in DNS and, optionally, that they serve HTTPS. Upstream detectors
+// sometimes reference decommissioned endpoints.
+package hostcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Statuses of a DNS check.
+const (
+	StatusOK    = "ok"    // the host resolved
+	StatusDead  = "dead"  // the resolver says the name doesn't exist
+	StatusError = "error" // the lookup failed for another reason (timeout, SERVFAIL)
+)
+
+// Resolver looks up a host's addresses. *net.Resolver implements it.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DNSOptions controls ResolveAll.
+type DNSOptions struct {
+	Concurrency int           // parallel lookups (default 16)
+	Timeout     time.Duration // per lookup (default 5s)
+	Resolver    Resolver      // default net.DefaultResolver
+}
+
+// DNSResult is the outcome of resolving one host.
+type DNSResult struct {
+	Host   string `json:"host"`
+	Status string `json:"status"`
+	Addrs  int    `json:"addrs,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ResolveAll resolves every host and returns results sorted by host.
+// Wildcard hosts ("*.example.com") are checked on their base domain. Only
+// NXDOMAIN-style answers count as dead: transient failures are reported as
+// errors so a flaky resolver can't make hosts disappear.
+func ResolveAll(ctx context.Context, hosts []string, opts DNSOptions) []DNSResult {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 16
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Resolver == nil {
+		opts.Resolver = net.DefaultResolver
+	}
+
+	results := make([]DNSResult, len(hosts))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = resolve(ctx, opts, host)
+		}()
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].Host < results[j].Host })
+	return results
+}
+
+func resolve(ctx context.Context, opts DNSOptions, host string) DNSResult {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	addrs, err := opts.Resolver.LookupHost(ctx, strings.TrimPrefix(host, "*."))
+	switch {
+	case err == nil:
+		return DNSResult{Host: host, Status: StatusOK, Addrs: len(addrs)}
+	case isNotFound(err):
+		return DNSResult{Host: host, Status: StatusDead, Error: err.Error()}
+	default:
+		return DNSResult{Host: host, Status: StatusError, Error: err.Error()}
+	}
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
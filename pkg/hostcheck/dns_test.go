@@ -0,0 +1,36 @@
+package hostcheck
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeResolver map[string]error
+
+func (f fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if err, ok := f[host]; ok {
+		return nil, err
+	}
+	return []string{"192.0.2.1"}, nil
+}
+
+func TestResolveAll(t *testing.T) {
+	r := fakeResolver{
+		"gone.example.com":  &net.DNSError{Err: "no such host", Name: "gone.example.com", IsNotFound: true},
+		"flaky.example.com": &net.DNSError{Err: "i/o timeout", Name: "flaky.example.com", IsTimeout: true},
+	}
+	hosts := []string{"gone.example.com", "api.example.com", "*.example.com", "flaky.example.com"}
+
+	got := ResolveAll(context.Background(), hosts, DNSOptions{Concurrency: 2, Timeout: time.Second, Resolver: r})
+	var statuses []string
+	for _, res := range got {
+		statuses = append(statuses, res.Host+"="+res.Status)
+	}
+	want := []string{"*.example.com=ok", "api.example.com=ok", "flaky.example.com=error", "gone.example.com=dead"}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("ResolveAll = %v, want %v", statuses, want)
+	}
+}
@@ -0,0 +1,147 @@
+package hostcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// StatusNoTLS marks a host that answered, but not with valid TLS: the port
+// is closed, the handshake fails, or the certificate doesn't verify.
+const StatusNoTLS = "no-tls"
+
+// DefaultUserAgent identifies probes to the services being checked.
+const DefaultUserAgent = "hogwash-hostcheck (+https://github.com/hochej/secret-mapping)"
+
+// HTTPSOptions controls ProbeAll.
+type HTTPSOptions struct {
+	Rate        float64       // requests per second across all workers (default 5)
+	Concurrency int           // requests in flight (default 8)
+	Timeout     time.Duration // per request (default 10s)
+	UserAgent   string        // default DefaultUserAgent
+	Client      *http.Client  // default: a client that doesn't follow redirects
+}
+
+// HTTPSResult is the outcome of probing one host. Only metadata is kept;
+// response bodies are never read.
+type HTTPSResult struct {
+	Host       string `json:"host"`
+	Status     string `json:"status"` // ok, no-tls, or error
+	Method     string `json:"method,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMS  int64  `json:"latency_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ProbeAll requests https://<host>/ for every host and returns results
+// sorted by host. Any HTTP response, whatever its status code, means the
+// host serves TLS. HEAD is tried first; hosts that reject it (405, 501) get
+// a GET whose body is closed unread. Wildcard hosts are probed on their base
+// domain.
+func ProbeAll(ctx context.Context, hosts []string, opts HTTPSOptions) []HTTPSResult {
+	if opts.Rate <= 0 {
+		opts.Rate = 5
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	}
+
+	tick := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+	defer tick.Stop()
+	results := make([]HTTPSResult, len(hosts))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		if i > 0 {
+			select {
+			case <-tick.C:
+			case <-ctx.Done():
+			}
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = probe(ctx, opts, host)
+		}()
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].Host < results[j].Host })
+	return results
+}
+
+func probe(ctx context.Context, opts HTTPSOptions, host string) HTTPSResult {
+	r := HTTPSResult{Host: host}
+	url := "https://" + strings.TrimPrefix(host, "*.") + "/"
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		code, latency, err := request(ctx, opts, method, url)
+		r.Method, r.LatencyMS = method, latency.Milliseconds()
+		if err != nil {
+			r.Status, r.Error = classify(err), err.Error()
+			return r
+		}
+		r.Status, r.StatusCode = StatusOK, code
+		if code != http.StatusMethodNotAllowed && code != http.StatusNotImplemented {
+			break
+		}
+	}
+	return r
+}
+
+func request(ctx context.Context, opts HTTPSOptions, method, url string) (int, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("User-Agent", opts.UserAgent)
+	start := time.Now()
+	resp, err := opts.Client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return 0, latency, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, latency, nil
+}
+
+// classify separates "this host doesn't serve TLS" from failures that say
+// nothing about the host (timeouts, DNS, cancellation).
+func classify(err error) string {
+	var recordErr tls.RecordHeaderError
+	var verifyErr *tls.CertificateVerificationError
+	var hostnameErr x509.HostnameError
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &recordErr), errors.As(err, &verifyErr), errors.As(err, &hostnameErr),
+		errors.As(err, &authorityErr), errors.As(err, &invalidErr), errors.Is(err, syscall.ECONNREFUSED):
+		return StatusNoTLS
+	}
+	// net/http replaces tls.RecordHeaderError with a plain error when the
+	// server speaks plaintext HTTP.
+	if strings.Contains(err.Error(), "server gave HTTP response to HTTPS client") {
+		return StatusNoTLS
+	}
+	return StatusError
+}
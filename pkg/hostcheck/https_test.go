@@ -0,0 +1,51 @@
+package hostcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbeAll(t *testing.T) {
+	var userAgents []string
+	tlsSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("body that must not matter"))
+	}))
+	defer tlsSrv.Close()
+	plainSrv := httptest.NewServer(http.NotFoundHandler())
+	defer plainSrv.Close()
+
+	tlsHost := strings.TrimPrefix(tlsSrv.URL, "https://")
+	plainHost := strings.TrimPrefix(plainSrv.URL, "http://")
+	got := ProbeAll(context.Background(), []string{tlsHost, plainHost}, HTTPSOptions{
+		Rate:      100,
+		Timeout:   5 * time.Second,
+		UserAgent: "test-agent",
+		Client:    tlsSrv.Client(),
+	})
+
+	byHost := map[string]HTTPSResult{}
+	for _, r := range got {
+		byHost[r.Host] = r
+	}
+	if r := byHost[tlsHost]; r.Status != StatusOK || r.Method != http.MethodGet || r.StatusCode != http.StatusNotFound {
+		t.Errorf("TLS host = %+v, want ok via GET fallback with 404", r)
+	}
+	if r := byHost[plainHost]; r.Status != StatusNoTLS {
+		t.Errorf("plain HTTP host = %+v, want no-tls", r)
+	}
+	for _, ua := range userAgents {
+		if ua != "test-agent" {
+			t.Errorf("User-Agent = %q", ua)
+		}
+	}
+}
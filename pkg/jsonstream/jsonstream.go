@@ -0,0 +1,254 @@
+// Package jsonstream writes JSON incrementally: objects, maps and slices are
+// emitted entry by entry, so only one element (one service, one value
+// pattern) is marshaled into memory at a time. The bytes are identical to
+// encoding/json's, indented or not, so content hashes don't depend on the
+// encoder used.
+package jsonstream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Encode writes v to w like json.Marshal, or like json.MarshalIndent with
+// indent when indent is non-empty. No trailing newline is written.
+func Encode(w io.Writer, v any, indent string) error {
+	bw := bufio.NewWriter(w)
+	e := &encoder{w: bw, indent: indent}
+	e.value(reflect.ValueOf(v), 0)
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+type encoder struct {
+	w      *bufio.Writer
+	indent string
+	buf    bytes.Buffer
+	err    error
+}
+
+var (
+	marshalerType     = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func (e *encoder) write(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+// value writes v at nesting depth. Containers are streamed; anything
+// encoding/json treats specially is handed to it whole.
+func (e *encoder) value(v reflect.Value, depth int) {
+	if e.err != nil {
+		return
+	}
+	if !v.IsValid() {
+		e.write("null")
+		return
+	}
+	if customMarshaler(v) {
+		e.marshal(v, depth)
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.write("null")
+			return
+		}
+		e.value(v.Elem(), depth)
+	case reflect.Struct:
+		fields, ok := structFields(v.Type())
+		if !ok {
+			e.marshal(v, depth)
+			return
+		}
+		var entries []entry
+		for _, f := range fields {
+			fv := v.Field(f.index)
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			entries = append(entries, entry{key: f.name, value: fv})
+		}
+		e.container("{", "}", entries, depth)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Key().Implements(textMarshalerType) {
+			e.marshal(v, depth)
+			return
+		}
+		if v.IsNil() {
+			e.write("null")
+			return
+		}
+		entries := make([]entry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries = append(entries, entry{key: iter.Key().String(), value: iter.Value()})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+		e.container("{", "}", entries, depth)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.marshal(v, depth) // base64
+			return
+		}
+		if v.IsNil() {
+			e.write("null")
+			return
+		}
+		entries := make([]entry, v.Len())
+		for i := range entries {
+			entries[i] = entry{value: v.Index(i), noKey: true}
+		}
+		e.container("[", "]", entries, depth)
+	default:
+		e.marshal(v, depth)
+	}
+}
+
+// entry is one object member or array element.
+type entry struct {
+	key   string
+	noKey bool
+	value reflect.Value
+}
+
+func (e *encoder) container(open, end string, entries []entry, depth int) {
+	e.write(open)
+	for i, en := range entries {
+		if i > 0 {
+			e.write(",")
+		}
+		if e.indent != "" {
+			e.write("\n" + strings.Repeat(e.indent, depth+1))
+		}
+		if !en.noKey {
+			key, err := json.Marshal(en.key)
+			if err != nil {
+				e.err = err
+				return
+			}
+			e.write(string(key))
+			if e.indent != "" {
+				e.write(": ")
+			} else {
+				e.write(":")
+			}
+		}
+		e.value(en.value, depth+1)
+	}
+	if len(entries) > 0 && e.indent != "" {
+		e.write("\n" + strings.Repeat(e.indent, depth))
+	}
+	e.write(end)
+}
+
+// marshal writes v with encoding/json, indented to depth.
+func (e *encoder) marshal(v reflect.Value, depth int) {
+	if e.err != nil {
+		return
+	}
+	if v.CanAddr() {
+		v = v.Addr() // pointer-receiver MarshalJSON, as encoding/json does
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		e.err = err
+		return
+	}
+	if e.indent != "" {
+		e.buf.Reset()
+		if err := json.Indent(&e.buf, data, strings.Repeat(e.indent, depth), e.indent); err != nil {
+			e.err = err
+			return
+		}
+		data = e.buf.Bytes()
+	}
+	if e.err == nil {
+		_, e.err = e.w.Write(data)
+	}
+}
+
+// customMarshaler reports whether encoding/json would call a MarshalJSON or
+// MarshalText method on v.
+func customMarshaler(v reflect.Value) bool {
+	t := v.Type()
+	if t.Implements(marshalerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	if v.CanAddr() {
+		pt := reflect.PointerTo(t)
+		return pt.Implements(marshalerType) || pt.Implements(textMarshalerType)
+	}
+	return false
+}
+
+type field struct {
+	index     int
+	name      string
+	omitEmpty bool
+}
+
+// structFields lists t's JSON fields in order. ok is false for structs
+// using features left to encoding/json (embedded fields, ",string").
+func structFields(t reflect.Type) (fields []field, ok bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous {
+			return nil, false
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		f := field{index: i, name: name}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "":
+			case "omitempty":
+				f.omitEmpty = true
+			default: // ",string", "omitzero"
+				return nil, false
+			}
+		}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		fields = append(fields, f)
+	}
+	return fields, true
+}
+
+// isEmptyValue mirrors encoding/json's omitempty test.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package jsonstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	Name  string            `json:"name"`
+	Tags  []string          `json:"tags,omitempty"`
+	Attrs map[string]string `json:"attrs,omitempty"`
+}
+
+type embedded struct {
+	inner
+	Extra int `json:"extra"`
+}
+
+type quoted struct {
+	N int `json:"n,string"`
+}
+
+type doc struct {
+	When    time.Time        `json:"when"`
+	Ptr     *inner           `json:"ptr,omitempty"`
+	Nil     *inner           `json:"nil"`
+	Items   []inner          `json:"items"`
+	Empty   []inner          `json:"empty"`
+	NilList []string         `json:"nil_list"`
+	Bytes   []byte           `json:"bytes"`
+	Map     map[string][]int `json:"map"`
+	Any     any              `json:"any"`
+	Embed   embedded         `json:"embed"`
+	Quoted  quoted           `json:"quoted"`
+	Escaped string           `json:"escaped"`
+	Float   float64          `json:"float"`
+	Skip    string           `json:"-"`
+	NoTag   bool
+	private int
+}
+
+func TestEncodeMatchesEncodingJSON(t *testing.T) {
+	d := doc{
+		When:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Ptr:     &inner{Name: "p", Tags: []string{"a", "b"}},
+		Items:   []inner{{Name: "x", Attrs: map[string]string{"z": "1", "a": "2"}}, {Name: "y"}},
+		Empty:   []inner{},
+		Bytes:   []byte("hi"),
+		Map:     map[string][]int{"b": {1, 2}, "a": {}, "c": nil},
+		Any:     map[string]any{"k": []any{1.5, "v", nil, map[string]any{}}},
+		Embed:   embedded{inner: inner{Name: "e"}, Extra: 1},
+		Quoted:  quoted{N: 7},
+		Escaped: "<a&b>   \"q\"",
+		Float:   1e21,
+	}
+	for _, v := range []any{d, &d, []doc{d, {}}, map[string]any{"x": d}, "scalar", nil, 3} {
+		want, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got bytes.Buffer
+		if err := Encode(&got, v, ""); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != string(want) {
+			t.Errorf("compact:\n got %s\nwant %s", got.String(), want)
+		}
+
+		want, err = json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got.Reset()
+		if err := Encode(&got, v, "  "); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != string(want) {
+			t.Errorf("indented:\n got %s\nwant %s", got.String(), want)
+		}
+	}
+}
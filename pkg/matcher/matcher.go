@@ -0,0 +1,288 @@
+// Package matcher applies a gondolin export at runtime: it maps env var
+// names to the hosts their secrets may be forwarded to and finds secrets in
+// values. It is the reference implementation of the matching semantics, so
+// Go consumers don't each reimplement them.
+package matcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// Finding is a secret detected in a value.
+type Finding struct {
+	PatternID string   `json:"pattern_id"`
+	Keyword   string   `json:"keyword,omitempty"` // service keyword, if the pattern links to hosts
+	Secret    string   `json:"secret"`            // the secret_group submatch, or the whole match
+	Start     int      `json:"start"`             // byte offset of Secret in the value
+	End       int      `json:"end"`
+	Policy    string   `json:"policy,omitempty"`
+	Hosts     []string `json:"hosts,omitempty"` // keyword_host_map entry for Keyword
+}
+
+// Matcher answers name and value queries against one export. It is safe for
+// concurrent use. Regexes are compiled on first use and cached.
+type Matcher struct {
+	exact    map[string][]string // upper-cased env var name → hosts
+	keywords []keywordHosts      // sorted by keyword
+	mode     string              // keyword match mode the export declares
+	hosts    map[string][]string // keyword → hosts, for linking findings
+	patterns []*pattern
+}
+
+type keywordHosts struct {
+	keyword string
+	norm    string
+	hosts   []string
+}
+
+type pattern struct {
+	export.ValuePattern
+	keywords []string // lower-cased pre-filter hints
+
+	once sync.Once
+	re   *regexp.Regexp
+	err  error
+}
+
+// New builds a Matcher from a gondolin export.
+func New(g export.Gondolin) *Matcher {
+	m := &Matcher{
+		exact: make(map[string][]string, len(g.ExactNameHostMap)),
+		hosts: g.KeywordHostMap,
+		mode:  export.KeywordMatchSubstring,
+	}
+	if g.Matching != nil {
+		m.mode = g.Matching.Keywords
+	}
+	for name, hosts := range g.ExactNameHostMap {
+		m.exact[strings.ToUpper(name)] = hosts
+	}
+	for keyword, hosts := range g.KeywordHostMap {
+		if norm := combine.NormalizeKeyword(keyword); norm != "" {
+			m.keywords = append(m.keywords, keywordHosts{keyword: keyword, norm: norm, hosts: hosts})
+		}
+	}
+	sort.Slice(m.keywords, func(i, j int) bool { return m.keywords[i].keyword < m.keywords[j].keyword })
+
+	for _, vp := range g.ValuePatterns {
+		p := &pattern{ValuePattern: vp}
+		for _, kw := range vp.Keywords {
+			p.keywords = append(p.keywords, strings.ToLower(kw))
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	return m
+}
+
+// Load decodes a gondolin or full export and builds a Matcher from it. Full
+// exports are reduced with export.DefaultOptions first, exactly as
+// `-mode gondolin` would.
+func Load(data []byte) (*Matcher, error) {
+	kind, err := export.DetectKind(data)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "full" {
+		var full combine.Export
+		if err := json.Unmarshal(data, &full); err != nil {
+			return nil, fmt.Errorf("decode full export: %w", err)
+		}
+		return New(export.ToGondolin(full, export.DefaultOptions())), nil
+	}
+	var g export.Gondolin
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("decode gondolin export: %w", err)
+	}
+	return New(g), nil
+}
+
+// LoadFile reads an export from path and builds a Matcher from it.
+func LoadFile(path string) (*Matcher, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// HostsForEnvName returns the hosts a secret held in env var name may be sent
+// to. An exact_name_host_map entry wins outright; otherwise every keyword
+// that matches the name contributes its hosts. Keywords match as substrings
+// (both compared with case, hyphens, and underscores ignored) unless the
+// export declares word or prefix matching; see OnWordBoundary. The result is sorted and
+// deduplicated, and nil when nothing matches.
+func (m *Matcher) HostsForEnvName(name string) []string {
+	if hosts, ok := m.exact[strings.ToUpper(name)]; ok {
+		return append([]string(nil), hosts...)
+	}
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, kh := range m.matchingKeywords(name) {
+		for _, h := range kh.hosts {
+			if !seen[h] {
+				seen[h] = true
+				out = append(out, h)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// KeywordsForEnvName explains HostsForEnvName: exact reports whether name
+// hit exact_name_host_map, and keywords lists the sorted keyword_host_map
+// keys matched otherwise.
+func (m *Matcher) KeywordsForEnvName(name string) (keywords []string, exact bool) {
+	if _, ok := m.exact[strings.ToUpper(name)]; ok {
+		return nil, true
+	}
+	for _, kh := range m.matchingKeywords(name) {
+		keywords = append(keywords, kh.keyword)
+	}
+	return keywords, false
+}
+
+// OnWordBoundary reports whether keyword, normalized, spells one or more
+// consecutive whole words of env var name, as "openai" does in
+// OPENAI_API_KEY. Words are runs of letters and digits. A substring match
+// that isn't on a word boundary, like "linear" in LINEARIZE_CACHE_URL, is
+// likely unintended.
+func OnWordBoundary(name, keyword string) bool {
+	return spellsWords(name, keyword, false)
+}
+
+// spellsWords reports whether keyword, normalized, spells consecutive whole
+// words of name: any run of them, or with leading only the first ones.
+func spellsWords(name, keyword string, leading bool) bool {
+	norm := combine.NormalizeKeyword(keyword)
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	})
+	for i := range words {
+		if leading && i > 0 {
+			break
+		}
+		joined := ""
+		for _, w := range words[i:] {
+			joined += w
+			if joined == norm {
+				return true
+			}
+			if !strings.HasPrefix(norm, joined) {
+				break
+			}
+		}
+	}
+	return false
+}
+
+func (m *Matcher) matchingKeywords(name string) []keywordHosts {
+	norm := combine.NormalizeKeyword(name)
+	var out []keywordHosts
+	for _, kh := range m.keywords {
+		var ok bool
+		switch m.mode {
+		case export.KeywordMatchWord:
+			ok = spellsWords(name, kh.keyword, false)
+		case export.KeywordMatchPrefix:
+			ok = spellsWords(name, kh.keyword, true)
+		default:
+			ok = strings.Contains(norm, kh.norm)
+		}
+		if ok {
+			out = append(out, kh)
+		}
+	}
+	return out
+}
+
+// DetectSecrets returns every pattern match in value, ordered by position
+// then pattern ID. Patterns whose keyword hints don't occur in the value
+// (case-insensitively) are skipped without running the regex. Patterns that
+// fail to compile never match; see CompileErrors.
+func (m *Matcher) DetectSecrets(value string) []Finding {
+	var lower string
+	var findings []Finding
+	for _, p := range m.patterns {
+		if len(p.keywords) > 0 {
+			if lower == "" {
+				lower = strings.ToLower(value)
+			}
+			if !containsAny(lower, p.keywords) {
+				continue
+			}
+		}
+		re := p.compile()
+		if re == nil {
+			continue
+		}
+		group := p.SecretGroup
+		if group > re.NumSubexp() {
+			group = 0
+		}
+		for _, loc := range re.FindAllStringSubmatchIndex(value, -1) {
+			start, end := loc[2*group], loc[2*group+1]
+			if start < 0 {
+				start, end = loc[0], loc[1]
+			}
+			findings = append(findings, Finding{
+				PatternID: p.ID,
+				Keyword:   p.Keyword,
+				Secret:    value[start:end],
+				Start:     start,
+				End:       end,
+				Policy:    p.Policy,
+				Hosts:     m.hosts[p.Keyword],
+			})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Start != findings[j].Start {
+			return findings[i].Start < findings[j].Start
+		}
+		return findings[i].PatternID < findings[j].PatternID
+	})
+	return findings
+}
+
+// CompileErrors compiles every pattern and reports those that fail, keyed by
+// pattern ID. Consumers that want to fail fast can call it once at startup.
+func (m *Matcher) CompileErrors() map[string]error {
+	errs := make(map[string]error)
+	for _, p := range m.patterns {
+		if p.compile() == nil {
+			errs[p.ID] = p.err
+		}
+	}
+	return errs
+}
+
+func (p *pattern) compile() *regexp.Regexp {
+	p.once.Do(func() {
+		p.re, p.err = regexp.Compile(p.Regex)
+	})
+	return p.re
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package matcher
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+func testGondolin() export.Gondolin {
+	return export.Gondolin{
+		SchemaVersion: export.SchemaVersion,
+		KeywordHostMap: map[string][]string{
+			"stripe":      {"api.stripe.com"},
+			"github":      {"api.github.com", "github.com"},
+			"private-key": {"example.com"},
+		},
+		ExactNameHostMap: map[string][]string{
+			"DD_API_KEY": {"api.datadoghq.com"},
+		},
+		ValuePatterns: []export.ValuePattern{
+			{ID: "stripe-access-token", Keyword: "stripe", Regex: `\b(sk_live_[a-z0-9]{8})\b`, SecretGroup: 1, Keywords: []string{"sk_live_"}, Policy: combine.PolicyRedact},
+			{ID: "github-pat", Keyword: "github", Regex: `ghp_[A-Za-z0-9]{8}`, Keywords: []string{"ghp_"}},
+			{ID: "broken", Regex: `(`},
+		},
+	}
+}
+
+func TestHostsForEnvName(t *testing.T) {
+	m := New(testGondolin())
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"STRIPE_SECRET_KEY", []string{"api.stripe.com"}},
+		{"dd_api_key", []string{"api.datadoghq.com"}}, // exact names are case-insensitive
+		{"GITHUB_STRIPE_TOKEN", []string{"api.github.com", "api.stripe.com", "github.com"}},
+		{"SSH_PRIVATE_KEY", []string{"example.com"}}, // hyphens and underscores are ignored
+		{"HOME", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.HostsForEnvName(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HostsForEnvName(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywordsForEnvName(t *testing.T) {
+	m := New(testGondolin())
+
+	if kws, exact := m.KeywordsForEnvName("GITHUB_STRIPE_TOKEN"); exact || !reflect.DeepEqual(kws, []string{"github", "stripe"}) {
+		t.Errorf("KeywordsForEnvName(GITHUB_STRIPE_TOKEN) = %v, %v", kws, exact)
+	}
+	if kws, exact := m.KeywordsForEnvName("DD_API_KEY"); !exact || kws != nil {
+		t.Errorf("KeywordsForEnvName(DD_API_KEY) = %v, %v, want exact", kws, exact)
+	}
+}
+
+func TestOnWordBoundary(t *testing.T) {
+	for _, c := range []struct {
+		name, keyword string
+		want          bool
+	}{
+		{"OPENAI_API_KEY", "openai", true},
+		{"CISCO_MERAKI_KEY", "cisco-meraki", true},
+		{"my-stripe.key", "stripe", true},
+		{"LINEARIZE_CACHE_URL", "linear", false},
+		{"GITHUBTOKEN", "github", false},
+		{"AWS_ACCESS_KEY", "awsaccesskeyid", false},
+	} {
+		if got := OnWordBoundary(c.name, c.keyword); got != c.want {
+			t.Errorf("OnWordBoundary(%q, %q) = %v, want %v", c.name, c.keyword, got, c.want)
+		}
+	}
+}
+
+func TestKeywordMatchModes(t *testing.T) {
+	names := []string{"OPENAI_API_KEY", "MY_OPENAI_KEY", "OPENAIKEY", "CISCO_MERAKI_TOKEN", "CISCOMERAKI", "LINEARIZE_URL", "_LINEAR_KEY", "linear"}
+	keywords := []string{"openai", "cisco-meraki", "linear"}
+	want := map[string]map[string][]string{
+		export.KeywordMatchSubstring: {
+			"OPENAI_API_KEY": {"openai"}, "MY_OPENAI_KEY": {"openai"}, "OPENAIKEY": {"openai"},
+			"CISCO_MERAKI_TOKEN": {"cisco-meraki"}, "CISCOMERAKI": {"cisco-meraki"},
+			"LINEARIZE_URL": {"linear"}, "_LINEAR_KEY": {"linear"}, "linear": {"linear"},
+		},
+		export.KeywordMatchWord: {
+			"OPENAI_API_KEY": {"openai"}, "MY_OPENAI_KEY": {"openai"},
+			"CISCO_MERAKI_TOKEN": {"cisco-meraki"}, "CISCOMERAKI": {"cisco-meraki"},
+			"_LINEAR_KEY": {"linear"}, "linear": {"linear"},
+		},
+		export.KeywordMatchPrefix: {
+			"OPENAI_API_KEY":     {"openai"},
+			"CISCO_MERAKI_TOKEN": {"cisco-meraki"}, "CISCOMERAKI": {"cisco-meraki"},
+			"_LINEAR_KEY": {"linear"}, "linear": {"linear"},
+		},
+	}
+	for mode, byName := range want {
+		g := export.Gondolin{KeywordHostMap: map[string][]string{}}
+		for _, k := range keywords {
+			g.KeywordHostMap[k] = []string{k + ".example.com"}
+		}
+		g.Matching = export.NewMatching(mode, g.KeywordHostMap, true)
+		m := New(g)
+		for _, name := range names {
+			got, _ := m.KeywordsForEnvName(name)
+			if !reflect.DeepEqual(got, byName[name]) {
+				t.Errorf("%s: KeywordsForEnvName(%s) = %v, want %v", mode, name, got, byName[name])
+			}
+			// The precomputed regexes must agree with the matcher.
+			var viaRegex []string
+			for _, k := range keywords {
+				if regexp.MustCompile(g.Matching.KeywordRegexes[k]).MatchString(name) {
+					viaRegex = append(viaRegex, k)
+				}
+			}
+			if !reflect.DeepEqual(viaRegex, byName[name]) {
+				t.Errorf("%s: keyword regexes match %s as %v, want %v", mode, name, viaRegex, byName[name])
+			}
+		}
+	}
+}
+
+func TestDetectSecrets(t *testing.T) {
+	m := New(testGondolin())
+
+	got := m.DetectSecrets("token ghp_ABCDabcd and sk_live_abcd1234")
+	if len(got) != 2 {
+		t.Fatalf("DetectSecrets = %+v, want 2 findings", got)
+	}
+	if got[0].PatternID != "github-pat" || got[0].Secret != "ghp_ABCDabcd" || got[0].Start != 6 {
+		t.Errorf("first finding = %+v", got[0])
+	}
+	stripe := got[1]
+	if stripe.Secret != "sk_live_abcd1234" || stripe.Policy != combine.PolicyRedact {
+		t.Errorf("stripe finding = %+v", stripe)
+	}
+	if !reflect.DeepEqual(stripe.Hosts, []string{"api.stripe.com"}) {
+		t.Errorf("stripe hosts = %v", stripe.Hosts)
+	}
+
+	if got := m.DetectSecrets("nothing to see"); got != nil {
+		t.Errorf("DetectSecrets on clean value = %+v, want nil", got)
+	}
+
+	// Keyword hints gate the regex: without "ghp_" the pattern never compiles.
+	fresh := New(testGondolin())
+	fresh.DetectSecrets("sk_live_abcd1234")
+	if fresh.patterns[1].re != nil {
+		t.Error("github-pat should not be compiled before its keyword appears")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	errs := New(testGondolin()).CompileErrors()
+	if len(errs) != 1 || errs["broken"] == nil {
+		t.Errorf("CompileErrors = %v, want only broken", errs)
+	}
+}
+
+func TestLoadFullExport(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{{
+			Keyword: "stripe",
+			Hosts:   []string{"api.stripe.com"},
+			Rules:   []combine.Rule{{ID: "stripe-access-token", Regex: `sk_live_[a-z0-9]{8}`}},
+		}},
+	}
+	data, err := json.Marshal(full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(data)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := m.HostsForEnvName("STRIPE_KEY"); !reflect.DeepEqual(got, []string{"api.stripe.com"}) {
+		t.Errorf("HostsForEnvName = %v", got)
+	}
+	if got := m.DetectSecrets("sk_live_abcd1234"); len(got) != 1 || got[0].Keyword != "stripe" {
+		t.Errorf("DetectSecrets = %+v", got)
+	}
+}
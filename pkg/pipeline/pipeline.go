@@ -0,0 +1,159 @@
+// Package pipeline runs extraction, combination, and validation in memory,
+// with the semantics of the hogwash CLI, so a Go service can regenerate a
+// dataset without shelling out:
+//
+//	full, err := pipeline.New(pipeline.Options{
+//		TruffleHogDir:  "trufflehog/pkg/detectors",
+//		GitleaksConfig: "gitleaks/config/gitleaks.toml",
+//	}).Run(ctx)
+//
+// Everything after combination that needs the network or writes files
+// (host checks, audits, outputs) stays with the caller; pkg/export reduces
+// the result to the gondolin format.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/trace"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/dataset"
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/extractor"
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// ErrStrict is wrapped by the error Run returns when Options.Strict is set
+// and TruffleHog extraction produced warnings.
+var ErrStrict = errors.New("strict")
+
+// Options configures a Pipeline. At least one source must be set.
+type Options struct {
+	TruffleHogDir  string   // TruffleHog pkg/detectors directory
+	GitleaksConfig string   // Gitleaks TOML config
+	Extractors     []string // external extractor commands (see pkg/extractor)
+
+	AllowIPHosts           bool // keep IP-literal hosts
+	WildcardPublicSuffixes bool // export public-suffix hosts as wildcards instead of rejecting them
+	Normalization          combine.Normalization
+	HostNormalization      trufflehog.HostNormalization // applied to every extracted host
+	Strict                 bool                         // fail when TruffleHog extraction produces warnings
+
+	// OnSource, if set, is called after each source is extracted, before
+	// Strict is enforced, so callers can log what was skipped and why.
+	OnSource func(SourceReport)
+}
+
+// SourceReport summarizes what one source contributed.
+type SourceReport struct {
+	Source    string               // "trufflehog", "gitleaks", or an extractor's name
+	Detectors int                  // detectors with hosts
+	Rules     int                  // value rules
+	Skipped   []trufflehog.Skipped // TruffleHog detectors that yielded no hosts, with reasons
+	Warnings  []error              // non-fatal problems in entries that were kept or dropped
+}
+
+// SourceError tags an extraction failure with the source it came from
+// (trufflehog, gitleaks, or extractor).
+type SourceError struct {
+	Source string
+	Err    error
+}
+
+func (e *SourceError) Error() string { return e.Err.Error() }
+func (e *SourceError) Unwrap() error { return e.Err }
+
+// Pipeline regenerates a full export from its sources.
+type Pipeline struct {
+	opts Options
+}
+
+// New returns a Pipeline for opts.
+func New(opts Options) *Pipeline {
+	return &Pipeline{opts: opts}
+}
+
+// Run extracts every source, combines them, and validates the result the
+// way `hogwash validate` does. Extraction failures are *SourceError;
+// validation failures are *dataset.ValidationError. It stops early once
+// ctx is done and then returns ctx.Err(). Run may be called concurrently.
+func (p *Pipeline) Run(ctx context.Context) (combine.Export, error) {
+	opts := p.opts
+	if opts.TruffleHogDir == "" && opts.GitleaksConfig == "" && len(opts.Extractors) == 0 {
+		return combine.Export{}, errors.New("pipeline: no sources configured")
+	}
+	report := func(r SourceReport) {
+		if opts.OnSource != nil {
+			opts.OnSource(r)
+		}
+	}
+
+	var thDetectors []trufflehog.Detector
+	var glRules []gitleaks.Rule
+
+	if opts.TruffleHogDir != "" {
+		var skipped []trufflehog.Skipped
+		var warnings []error
+		var err error
+		region := trace.StartRegion(ctx, "extract trufflehog")
+		thDetectors, skipped, warnings, err = trufflehog.ExtractContext(ctx, opts.TruffleHogDir, trufflehog.ExtractOptions{
+			AllowIPHosts:           opts.AllowIPHosts,
+			WildcardPublicSuffixes: opts.WildcardPublicSuffixes,
+			HostNormalization:      opts.HostNormalization,
+		})
+		region.End()
+		if ctx.Err() != nil {
+			return combine.Export{}, ctx.Err()
+		}
+		if err != nil {
+			return combine.Export{}, &SourceError{"trufflehog", fmt.Errorf("trufflehog extraction: %w", err)}
+		}
+		report(SourceReport{Source: "trufflehog", Detectors: len(thDetectors), Skipped: skipped, Warnings: warnings})
+		if opts.Strict && len(warnings) > 0 {
+			return combine.Export{}, &SourceError{"trufflehog", fmt.Errorf("%w: trufflehog extraction produced %d warnings (first: %v)", ErrStrict, len(warnings), warnings[0])}
+		}
+	}
+
+	if opts.GitleaksConfig != "" {
+		var err error
+		trace.WithRegion(ctx, "extract gitleaks", func() {
+			glRules, err = gitleaks.Extract(opts.GitleaksConfig)
+		})
+		if err != nil {
+			return combine.Export{}, &SourceError{"gitleaks", fmt.Errorf("gitleaks extraction: %w", err)}
+		}
+		report(SourceReport{Source: "gitleaks", Rules: len(glRules)})
+	}
+
+	for _, command := range opts.Extractors {
+		var res extractor.Result
+		var err error
+		trace.WithRegion(ctx, "extract plugin", func() {
+			res, err = extractor.Run(ctx, command, extractor.Options{AllowIPHosts: opts.AllowIPHosts, HostNormalization: opts.HostNormalization})
+		})
+		if ctx.Err() != nil {
+			return combine.Export{}, ctx.Err()
+		}
+		if err != nil {
+			return combine.Export{}, &SourceError{"extractor", fmt.Errorf("extractor: %w", err)}
+		}
+		report(SourceReport{Source: res.Name, Detectors: len(res.Detectors), Rules: len(res.Rules), Warnings: res.Warnings})
+		thDetectors = append(thDetectors, res.Detectors...)
+		glRules = append(glRules, res.Rules...)
+	}
+
+	if ctx.Err() != nil {
+		return combine.Export{}, ctx.Err()
+	}
+	var full combine.Export
+	trace.WithRegion(ctx, "combine", func() {
+		full = combine.CombineWith(thDetectors, glRules, combine.Options{Normalization: opts.Normalization})
+	})
+	if errs := export.ValidateCombined(full, export.ValidateOptions{AllowIPHosts: opts.AllowIPHosts}); len(errs) > 0 {
+		return combine.Export{}, &dataset.ValidationError{Kind: "full", Problems: errs}
+	}
+	return full, nil
+}
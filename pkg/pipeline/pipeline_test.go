@@ -0,0 +1,75 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSources lays out a one-detector TruffleHog tree and a Gitleaks
+// config whose rule matches it, plus an unparsable detector.
+func writeSources(t *testing.T) (thDir, glPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	thDir = filepath.Join(dir, "detectors")
+	files := map[string]string{
+		filepath.Join(thDir, "meraki", "v1", "meraki.go"): "package meraki\n\nfunc endpoint() string {\n\treturn \"https://api.meraki.com/api/v1/organizations\"\n}\n",
+		filepath.Join(thDir, "broken", "broken.go"):       "package broken\n\nfunc {\n",
+		filepath.Join(dir, "gitleaks.toml"): `[[rules]]
+id = "cisco-meraki-api-key"
+regex = '''(?i)\bmeraki_[a-z0-9]{16}\b'''
+keywords = ["meraki"]
+`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return thDir, filepath.Join(dir, "gitleaks.toml")
+}
+
+func TestRun(t *testing.T) {
+	thDir, glPath := writeSources(t)
+	var reports []SourceReport
+	full, err := New(Options{
+		TruffleHogDir:  thDir,
+		GitleaksConfig: glPath,
+		OnSource:       func(r SourceReport) { reports = append(reports, r) },
+	}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(full.Services) != 1 || full.Services[0].Keyword != "cisco-meraki" || full.Services[0].Hosts[0] != "api.meraki.com" {
+		t.Errorf("Services = %+v", full.Services)
+	}
+	if len(reports) != 2 || reports[0].Source != "trufflehog" || reports[0].Detectors != 1 || len(reports[0].Skipped) != 1 ||
+		reports[1].Source != "gitleaks" || reports[1].Rules != 1 {
+		t.Errorf("reports = %+v", reports)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	thDir, glPath := writeSources(t)
+	ctx := context.Background()
+
+	if _, err := New(Options{}).Run(ctx); err == nil {
+		t.Error("no sources: no error")
+	}
+
+	var se *SourceError
+	if _, err := New(Options{TruffleHogDir: thDir, GitleaksConfig: glPath + ".missing"}).Run(ctx); !errors.As(err, &se) || se.Source != "gitleaks" {
+		t.Errorf("missing gitleaks config: err = %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := New(Options{TruffleHogDir: thDir}).Run(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled: err = %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+// Package provenance builds in-toto statements carrying SLSA v1 provenance
+// for generated datasets: what was produced, from which upstream checkouts,
+// by which tool build, with which flags.
+package provenance
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const (
+	// StatementType is the in-toto statement type.
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PredicateType is the SLSA provenance predicate type.
+	PredicateType = "https://slsa.dev/provenance/v1"
+	// BuildType identifies how to interpret ExternalParameters.
+	BuildType = "https://github.com/hochej/secret-mapping/export@v1"
+)
+
+// Statement is an in-toto v1 statement with a SLSA provenance predicate.
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Subject  `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Subject is an artifact the statement is about.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance is the SLSA v1 provenance predicate.
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition records the inputs of the run.
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   map[string]string    `json:"externalParameters"` // flag name → value
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// ResourceDescriptor identifies one input: an upstream checkout (by git
+// commit) or a local file (by sha256).
+type ResourceDescriptor struct {
+	Name   string            `json:"name"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// RunDetails records the tool build and timing.
+type RunDetails struct {
+	Builder  Builder       `json:"builder"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+// Builder identifies the tool that produced the subject.
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// BuildMetadata records when the run happened.
+type BuildMetadata struct {
+	StartedOn  time.Time `json:"startedOn"`
+	FinishedOn time.Time `json:"finishedOn"`
+}
+
+// Input is a path the run read, named by the flag that supplied it.
+type Input struct {
+	Name string // e.g. "trufflehog"
+	Path string
+}
+
+// New builds a statement for the file at outPath. Directory inputs are
+// resolved to the git commit of their enclosing checkout; file inputs are
+// resolved to that commit when inside a checkout, and to their sha256
+// otherwise.
+func New(outPath string, inputs []Input, params map[string]string, started, finished time.Time) (Statement, error) {
+	digest, err := fileSHA256(outPath)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	var deps []ResourceDescriptor
+	for _, in := range inputs {
+		dep, err := resolveInput(in)
+		if err != nil {
+			return Statement{}, fmt.Errorf("%s input %s: %w", in.Name, in.Path, err)
+		}
+		deps = append(deps, dep)
+	}
+
+	return Statement{
+		Type:          StatementType,
+		Subject:       []Subject{{Name: filepath.Base(outPath), Digest: map[string]string{"sha256": digest}}},
+		PredicateType: PredicateType,
+		Predicate: Provenance{
+			BuildDefinition: BuildDefinition{
+				BuildType:            BuildType,
+				ExternalParameters:   params,
+				ResolvedDependencies: deps,
+			},
+			RunDetails: RunDetails{
+				Builder:  builder(),
+				Metadata: BuildMetadata{StartedOn: started.UTC(), FinishedOn: finished.UTC()},
+			},
+		},
+	}, nil
+}
+
+func resolveInput(in Input) (ResourceDescriptor, error) {
+	dep := ResourceDescriptor{Name: in.Name}
+	commit, remote, err := GitCommit(in.Path)
+	if err == nil {
+		dep.Digest = map[string]string{"gitCommit": commit}
+		if remote != "" {
+			dep.URI = "git+" + remote
+		}
+		return dep, nil
+	}
+	info, statErr := os.Stat(in.Path)
+	if statErr != nil {
+		return dep, statErr
+	}
+	if info.IsDir() {
+		// A bare directory has no stable identity; record it by path only.
+		dep.URI = "file://" + absPath(in.Path)
+		return dep, nil
+	}
+	digest, err := fileSHA256(in.Path)
+	if err != nil {
+		return dep, err
+	}
+	dep.URI = "file://" + absPath(in.Path)
+	dep.Digest = map[string]string{"sha256": digest}
+	return dep, nil
+}
+
+// builder describes this binary from its embedded build info.
+func builder() Builder {
+	b := Builder{ID: "https://github.com/hochej/secret-mapping"}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+	b.Version = map[string]string{"go": info.GoVersion}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		b.Version["hogwash"] = v
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Version["vcs.revision"] = s.Value
+		case "vcs.modified":
+			if s.Value == "true" {
+				b.Version["vcs.modified"] = s.Value
+			}
+		}
+	}
+	return b
+}
+
+// GitCommit returns the HEAD commit (and origin URL, if configured) of the
+// git checkout containing path. It reads .git directly so no git binary is
+// needed.
+func GitCommit(path string) (commit, remote string, err error) {
+	gitDir, err := findGitDir(path)
+	if err != nil {
+		return "", "", err
+	}
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", "", err
+	}
+	ref, isRef := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: ")
+	if !isRef {
+		commit = ref // detached HEAD, as left by shallow CI clones
+	} else if commit, err = resolveRef(gitDir, ref); err != nil {
+		return "", "", err
+	}
+	return commit, originURL(gitDir), nil
+}
+
+// findGitDir walks up from path to the nearest .git directory, following
+// `gitdir:` files used by worktrees and submodules.
+func findGitDir(path string) (string, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	for {
+		candidate := filepath.Join(dir, ".git")
+		if info, err := os.Stat(candidate); err == nil {
+			if info.IsDir() {
+				return candidate, nil
+			}
+			data, err := os.ReadFile(candidate)
+			if err != nil {
+				return "", err
+			}
+			if target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: "); ok {
+				if !filepath.IsAbs(target) {
+					target = filepath.Join(dir, target)
+				}
+				return target, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("not inside a git checkout")
+		}
+		dir = parent
+	}
+}
+
+func resolveRef(gitDir, ref string) (string, error) {
+	if data, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+	f, err := os.Open(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", ref, err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if sha, name, ok := strings.Cut(sc.Text(), " "); ok && name == ref {
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("resolve %s: not found", ref)
+}
+
+// originURL reads remote "origin"'s url from the repository config. Errors
+// just mean no URL is recorded.
+func originURL(gitDir string) string {
+	f, err := os.Open(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	inOrigin := false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); inOrigin && ok && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func fileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return filepath.ToSlash(abs)
+	}
+	return filepath.ToSlash(p)
+}
@@ -0,0 +1,73 @@
+package provenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGitCommit(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeFile(t, filepath.Join(repo, ".git", "packed-refs"), "# pack-refs with: peeled\nabc123 refs/heads/main\n")
+	writeFile(t, filepath.Join(repo, ".git", "config"), "[core]\n\tbare = false\n[remote \"origin\"]\n\turl = https://github.com/gitleaks/gitleaks.git\n")
+	toml := filepath.Join(repo, "config", "gitleaks.toml")
+	writeFile(t, toml, "title = \"x\"\n")
+
+	commit, remote, err := GitCommit(toml)
+	if err != nil {
+		t.Fatalf("GitCommit: %v", err)
+	}
+	if commit != "abc123" || remote != "https://github.com/gitleaks/gitleaks.git" {
+		t.Errorf("GitCommit = %q, %q", commit, remote)
+	}
+
+	// Loose refs win over packed-refs; detached HEADs are used as-is.
+	writeFile(t, filepath.Join(repo, ".git", "refs", "heads", "main"), "def456\n")
+	if commit, _, _ := GitCommit(repo); commit != "def456" {
+		t.Errorf("loose ref commit = %q, want def456", commit)
+	}
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "0123abcd\n")
+	if commit, _, _ := GitCommit(repo); commit != "0123abcd" {
+		t.Errorf("detached commit = %q, want 0123abcd", commit)
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "gondolin.json")
+	writeFile(t, out, "{}\n")
+	denylist := filepath.Join(dir, "denylist.json")
+	writeFile(t, denylist, "[]\n")
+
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	st, err := New(out, []Input{{Name: "pattern-denylist", Path: denylist}}, map[string]string{"mode": "gondolin"}, started, started.Add(time.Second))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if st.Type != StatementType || st.PredicateType != PredicateType {
+		t.Errorf("types = %q, %q", st.Type, st.PredicateType)
+	}
+	// sha256 of "{}\n"
+	if got := st.Subject[0].Digest["sha256"]; got != "ca3d163bab055381827226140568f3bef7eaac187cebd76878e0b63e9e442356" || st.Subject[0].Name != "gondolin.json" {
+		t.Errorf("subject = %+v", st.Subject)
+	}
+	deps := st.Predicate.BuildDefinition.ResolvedDependencies
+	if len(deps) != 1 || deps[0].Name != "pattern-denylist" || deps[0].Digest["sha256"] == "" {
+		t.Errorf("resolved dependencies = %+v", deps)
+	}
+	if st.Predicate.BuildDefinition.ExternalParameters["mode"] != "gondolin" {
+		t.Errorf("external parameters = %v", st.Predicate.BuildDefinition.ExternalParameters)
+	}
+}
@@ -0,0 +1,216 @@
+package samples
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"regexp/syntax"
+	"unicode"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// maxAlphabet caps the alphabets counted rune by rune; larger ones (negated
+// classes span all of Unicode) count as unbounded.
+const maxAlphabet = 1 << 12
+
+// EntropyResult is how a rule's entropy threshold fares against generated
+// matches. Gitleaks drops a match unless the Shannon entropy of its secret
+// is strictly greater than the threshold, so a threshold no realistic
+// secret exceeds silently turns the rule off.
+type EntropyResult struct {
+	ID        string
+	Threshold float64
+	// Bound is the highest entropy any secret can reach: log2 of the
+	// smaller of its maximum length and alphabet size. +Inf when both are
+	// unbounded.
+	Bound   float64
+	Samples int
+	Passed  int
+	MaxSeen float64 // highest entropy among the samples' secrets
+}
+
+// Dead reports whether no secret can pass the threshold, whatever the input.
+func (r EntropyResult) Dead() bool { return r.Threshold > 0 && r.Bound <= r.Threshold }
+
+// Suspect reports whether none of the generated samples passed. Samples
+// stay near the minimum length of unbounded repetitions, so this is a hint
+// for a human, not proof.
+func (r EntropyResult) Suspect() bool { return r.Samples > 0 && r.Passed == 0 }
+
+// CheckEntropy generates n matches for r, seeded like ForRule, and measures
+// the entropy of each one's secret against r.Entropy. Rules without an
+// entropy threshold return a zero result.
+func CheckEntropy(r combine.Rule, n int) (EntropyResult, error) {
+	res := EntropyResult{ID: r.ID, Threshold: r.Entropy}
+	if r.Entropy <= 0 {
+		return res, nil
+	}
+	re, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return res, err
+	}
+	tree, err := syntax.Parse(r.Regex, syntax.Perl)
+	if err != nil {
+		return res, err
+	}
+	res.Bound = secretEntropyBound(tree.Simplify(), r.SecretGroup)
+
+	rng := ruleRand(r)
+	for i := 0; i < n; i++ {
+		s, err := Generate(r.Regex, rng)
+		if err != nil {
+			return res, fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+		e := ShannonEntropy(secretOf(re.FindStringSubmatch(s), r.SecretGroup))
+		res.Samples++
+		res.MaxSeen = max(res.MaxSeen, e)
+		if e > r.Entropy {
+			res.Passed++
+		}
+	}
+	return res, nil
+}
+
+// ShannonEntropy is Gitleaks' entropy measure: bits per character over the
+// string's rune frequencies, normalized by its length in bytes.
+func ShannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+	}
+	var e float64
+	for _, n := range counts {
+		freq := float64(n) / float64(len(s))
+		e -= freq * math.Log2(freq)
+	}
+	return e
+}
+
+// secretOf picks the secret out of a submatch the way Gitleaks does: the
+// secret group, else the first non-empty capture group, else the match.
+func secretOf(m []string, group int) string {
+	if group > 0 && group < len(m) {
+		return m[group]
+	}
+	for _, s := range m[1:] {
+		if s != "" {
+			return s
+		}
+	}
+	return m[0]
+}
+
+// secretEntropyBound bounds the entropy of the secret group (every
+// candidate group when Gitleaks would pick the first that matched).
+func secretEntropyBound(re *syntax.Regexp, group int) float64 {
+	var groups []*syntax.Regexp
+	var walk func(*syntax.Regexp)
+	walk = func(re *syntax.Regexp) {
+		if re.Op == syntax.OpCapture && (group == 0 || re.Cap == group) {
+			groups = append(groups, re)
+		}
+		for _, sub := range re.Sub {
+			walk(sub)
+		}
+	}
+	walk(re)
+	if len(groups) == 0 {
+		groups = []*syntax.Regexp{re}
+	}
+	bound := 0.0
+	for _, g := range groups {
+		n := min(maxLength(g), alphabetSize(g))
+		if n > 0 {
+			bound = max(bound, math.Log2(n))
+		}
+	}
+	return bound
+}
+
+// maxLength is the longest match of re in runes; +Inf if unbounded.
+func maxLength(re *syntax.Regexp) float64 {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return float64(len(re.Rune))
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return 1
+	case syntax.OpCapture, syntax.OpQuest:
+		return maxLength(re.Sub[0])
+	case syntax.OpConcat:
+		n := 0.0
+		for _, sub := range re.Sub {
+			n += maxLength(sub)
+		}
+		return n
+	case syntax.OpAlternate:
+		n := 0.0
+		for _, sub := range re.Sub {
+			n = max(n, maxLength(sub))
+		}
+		return n
+	case syntax.OpStar, syntax.OpPlus:
+		if maxLength(re.Sub[0]) == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	case syntax.OpRepeat:
+		if re.Max < 0 {
+			if maxLength(re.Sub[0]) == 0 {
+				return 0
+			}
+			return math.Inf(1)
+		}
+		return float64(re.Max) * maxLength(re.Sub[0])
+	}
+	return 0 // empty-width assertions and empty matches
+}
+
+// alphabetSize counts the distinct runes re can match; +Inf for any
+// character.
+func alphabetSize(re *syntax.Regexp) float64 {
+	runes := make(map[rune]bool)
+	if !collectRunes(re, runes) {
+		return math.Inf(1)
+	}
+	return float64(len(runes))
+}
+
+// collectRunes adds the runes re can match to set, returning false for
+// "any character".
+func collectRunes(re *syntax.Regexp, set map[rune]bool) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			set[r] = true
+			if re.Flags&syntax.FoldCase != 0 {
+				for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+					set[f] = true
+				}
+			}
+		}
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if re.Rune[i+1]-re.Rune[i] > maxAlphabet {
+				return false
+			}
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				set[r] = true
+			}
+		}
+		if len(set) > maxAlphabet {
+			return false
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return false
+	}
+	for _, sub := range re.Sub {
+		if !collectRunes(sub, set) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+package samples
+
+import (
+	"math"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestShannonEntropy(t *testing.T) {
+	for s, want := range map[string]float64{
+		"":         0,
+		"aaaa":     0,
+		"abab":     1,
+		"abcdefgh": 3,
+	} {
+		if got := ShannonEntropy(s); math.Abs(got-want) > 1e-9 {
+			t.Errorf("ShannonEntropy(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestCheckEntropy(t *testing.T) {
+	for _, tc := range []struct {
+		rule           combine.Rule
+		dead, suspect  bool
+		wantBoundAbove float64
+	}{
+		// 16 hex digits carry at most log2(16) = 4 bits.
+		{rule: combine.Rule{ID: "hex", Regex: `tok_([0-9a-f]{32})`, Entropy: 4.5}, dead: true, suspect: true},
+		// The secret group is only 8 characters long: at most 3 bits.
+		{rule: combine.Rule{ID: "short", Regex: `key=([a-zA-Z0-9]{8})\b`, Entropy: 3, SecretGroup: 1}, dead: true, suspect: true},
+		{rule: combine.Rule{ID: "fine", Regex: `ghp_[0-9a-zA-Z]{36}`, Entropy: 3}, wantBoundAbove: 5},
+		// Unbounded, but generated matches stay short and repetitive.
+		{rule: combine.Rule{ID: "unbounded", Regex: `x_[a-z]+`, Entropy: 3}, suspect: true, wantBoundAbove: 4},
+	} {
+		res, err := CheckEntropy(tc.rule, 50)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.rule.ID, err)
+		}
+		if res.Dead() != tc.dead || res.Suspect() != tc.suspect || res.Bound < tc.wantBoundAbove {
+			t.Errorf("%s: dead %v, suspect %v, result %+v", tc.rule.ID, res.Dead(), res.Suspect(), res)
+		}
+	}
+
+	if res, err := CheckEntropy(combine.Rule{ID: "none", Regex: `[a-z]{4}`}, 50); err != nil || res.Samples != 0 || res.Dead() || res.Suspect() {
+		t.Errorf("rule without threshold: %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,67 @@
+package samples
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"slices"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// MaxExamples caps the examples embedded per rule: they document the rule
+// and seed consumer fixtures, they aren't a test corpus.
+const MaxExamples = 2
+
+// ForRule returns up to n distinct samples for r. The generator is seeded
+// from the rule's regex and keywords, so a given rule version always gets
+// the same examples and they only change when the rule does.
+func ForRule(r combine.Rule, n int) ([]string, error) {
+	rng := ruleRand(r)
+	p := export.ValuePattern{ID: r.ID, Regex: r.Regex, Keywords: r.Keywords}
+
+	var out []string
+	for i := 0; i < n; i++ {
+		s, err := ForPattern(p, rng)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// WithExamples returns a copy of e with n examples on every rule, and by
+// rule ID the errors of rules the generator can't satisfy; those are left
+// without examples. The content hash is not updated.
+func WithExamples(e combine.Export, n int) (combine.Export, map[string]error) {
+	failed := make(map[string]error)
+	services := make([]combine.Service, len(e.Services))
+	for i, svc := range e.Services {
+		svc.Rules = slices.Clone(svc.Rules)
+		for j := range svc.Rules {
+			examples, err := ForRule(svc.Rules[j], n)
+			if err != nil {
+				failed[svc.Rules[j].ID] = err
+				examples = nil
+			}
+			svc.Rules[j].Examples = examples
+		}
+		services[i] = svc
+	}
+	e.Services = services
+	return e, failed
+}
+
+// ruleRand returns a generator seeded from r's regex and keywords.
+func ruleRand(r combine.Rule) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(r.Regex))
+	for _, kw := range r.Keywords {
+		h.Write([]byte{0})
+		h.Write([]byte(kw))
+	}
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
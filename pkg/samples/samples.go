@@ -0,0 +1,161 @@
+// Package samples synthesizes fake secrets that match exported value
+// patterns by walking each regex's syntax tree in reverse. Generated strings
+// are always re-checked against the regex (and the pattern's keyword
+// pre-filter), so a returned sample is guaranteed to be detected.
+package samples
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+
+	"secret-detector-export/pkg/export"
+)
+
+// maxAttempts bounds how many random candidates are tried per sample.
+// Failures are almost always boundary assertions (\b) next to characters
+// the generator picked badly; a few retries resolve those.
+const maxAttempts = 64
+
+// maxExtraRepeat caps how far unbounded repetitions (*, +, {n,}) go past
+// their minimum, keeping samples short and readable.
+const maxExtraRepeat = 3
+
+// Generate returns a string matching expr (Go regexp syntax).
+func Generate(expr string, rng *rand.Rand) (string, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	tree, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	tree = tree.Simplify()
+	for i := 0; i < maxAttempts; i++ {
+		var b strings.Builder
+		generate(&b, tree, rng)
+		if s := b.String(); re.MatchString(s) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("no matching sample after %d attempts", maxAttempts)
+}
+
+// ForPattern returns a sample for p that a matcher would report: it matches
+// the regex and, when p has keyword hints, contains one of them.
+func ForPattern(p export.ValuePattern, rng *rand.Rand) (string, error) {
+	re, err := regexp.Compile(p.Regex)
+	if err != nil {
+		return "", err
+	}
+	var last string
+	for i := 0; i < maxAttempts; i++ {
+		s, err := Generate(p.Regex, rng)
+		if err != nil {
+			return "", err
+		}
+		if hasKeyword(s, p.Keywords) {
+			return s, nil
+		}
+		last = s
+	}
+	// The regex can match without a keyword (e.g. the keyword sits in an
+	// optional prefix). Place one in front, where most patterns allow context.
+	for _, kw := range p.Keywords {
+		if s := kw + "=" + last; re.MatchString(s) {
+			return s, nil
+		}
+	}
+	return "", errors.New("no sample contains a keyword hint")
+}
+
+func hasKeyword(s string, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	lower := strings.ToLower(s)
+	for _, kw := range keywords {
+		if strings.Contains(lower, strings.ToLower(kw)) {
+			return true
+		}
+	}
+	return false
+}
+
+func generate(b *strings.Builder, re *syntax.Regexp, rng *rand.Rand) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && rng.Intn(2) == 0 {
+				r = unicode.SimpleFold(r)
+			}
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(pickRune(re.Rune, rng))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		b.WriteRune(rune('a' + rng.Intn(26)))
+	case syntax.OpCapture:
+		generate(b, re.Sub[0], rng)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			generate(b, sub, rng)
+		}
+	case syntax.OpAlternate:
+		generate(b, re.Sub[rng.Intn(len(re.Sub))], rng)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := repeatBounds(re)
+		n := lo
+		if hi > lo {
+			n += rng.Intn(hi - lo + 1)
+		}
+		for i := 0; i < n; i++ {
+			generate(b, re.Sub[0], rng)
+		}
+	default:
+		// Empty-width assertions (^, $, \b, \A, \z) and empty matches emit
+		// nothing; Generate's final match check rejects bad placements.
+	}
+}
+
+func repeatBounds(re *syntax.Regexp) (lo, hi int) {
+	switch re.Op {
+	case syntax.OpStar:
+		return 0, maxExtraRepeat
+	case syntax.OpPlus:
+		return 1, 1 + maxExtraRepeat
+	case syntax.OpQuest:
+		return 0, 1
+	}
+	lo, hi = re.Min, re.Max
+	if hi < 0 {
+		hi = lo + maxExtraRepeat
+	}
+	return lo, hi
+}
+
+// pickRune chooses a rune from a class given as [lo, hi] pairs, preferring
+// printable ASCII so samples stay copy-pasteable. Negated classes like
+// [^"'] span all of Unicode; restricting them to ASCII keeps them sane.
+func pickRune(ranges []rune, rng *rand.Rand) rune {
+	var ascii []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], 0x21), min(ranges[i+1], 0x7e)
+		for r := lo; r <= hi; r++ {
+			ascii = append(ascii, r)
+		}
+	}
+	if len(ascii) > 0 {
+		return ascii[rng.Intn(len(ascii))]
+	}
+	if len(ranges) == 0 {
+		return 'x'
+	}
+	i := 2 * rng.Intn(len(ranges)/2)
+	return ranges[i] + rune(rng.Intn(int(ranges[i+1]-ranges[i])+1))
+}
@@ -0,0 +1,95 @@
+package samples
+
+import (
+	"math/rand"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+func TestGenerate(t *testing.T) {
+	exprs := []string{
+		`(?i)\bcloudflare_[a-z0-9]{16}\b`,
+		`ghp_[0-9a-zA-Z]{36}`,
+		`(?:AKIA|ASIA)[A-Z2-7]{16}`,
+		`xox[baprs]-([0-9a-zA-Z]{10,48})`,
+		`(?i)[\w.-]{0,50}?(?:api|token)(?:[ \t\w.-]{0,20})[\s'"]{0,3}(?:=|:{1,3}=?)[\x60'"\s=]{0,5}([a-z0-9]{32})(?:[\x60'"\s;]|\\[nr]|$)`,
+		`-----BEGIN[ A-Z0-9_-]{0,100}PRIVATE KEY(?: BLOCK)?-----[\s\S-]{64,}?KEY(?: BLOCK)?-----`,
+		`^sk_live_[^"'\s]{24}$`,
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, expr := range exprs {
+		s, err := Generate(expr, rng)
+		if err != nil {
+			t.Errorf("Generate(%q): %v", expr, err)
+			continue
+		}
+		if !regexp.MustCompile(expr).MatchString(s) {
+			t.Errorf("Generate(%q) = %q does not match", expr, s)
+		}
+	}
+
+	if _, err := Generate(`(`, rng); err == nil {
+		t.Error("Generate accepted an invalid regex")
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	a, _ := Generate(`[a-z]{20}`, rand.New(rand.NewSource(7)))
+	b, _ := Generate(`[a-z]{20}`, rand.New(rand.NewSource(7)))
+	if a != b {
+		t.Errorf("same seed gave %q and %q", a, b)
+	}
+}
+
+func TestForPatternIncludesKeyword(t *testing.T) {
+	p := export.ValuePattern{
+		ID:       "example-token",
+		Regex:    `(?:example|sample)?[_-]?([a-f0-9]{12})`,
+		Keywords: []string{"example"},
+	}
+	s, err := ForPattern(p, rand.New(rand.NewSource(3)))
+	if err != nil {
+		t.Fatalf("ForPattern: %v", err)
+	}
+	if !strings.Contains(strings.ToLower(s), "example") || !regexp.MustCompile(p.Regex).MatchString(s) {
+		t.Errorf("ForPattern = %q, want a match containing the keyword", s)
+	}
+}
+
+func TestWithExamples(t *testing.T) {
+	rule := combine.Rule{ID: "github-pat", Regex: `ghp_[0-9a-zA-Z]{36}`, Keywords: []string{"ghp_"}}
+	e := combine.Export{Services: []combine.Service{{
+		Keyword: "github",
+		Rules:   []combine.Rule{rule, {ID: "broken", Regex: `\bx\Bx\b\B`}},
+	}}}
+	got, failed := WithExamples(e, MaxExamples)
+	if e.Services[0].Rules[0].Examples != nil {
+		t.Error("WithExamples modified its input")
+	}
+	examples := got.Services[0].Rules[0].Examples
+	if len(examples) != 2 || examples[0] == examples[1] {
+		t.Fatalf("examples = %q, want 2 distinct", examples)
+	}
+	for _, ex := range examples {
+		if !regexp.MustCompile(rule.Regex).MatchString(ex) {
+			t.Errorf("example %q does not match", ex)
+		}
+	}
+	if _, ok := failed["broken"]; !ok || got.Services[0].Rules[1].Examples != nil {
+		t.Errorf("failed = %v, want the unsatisfiable rule reported without examples", failed)
+	}
+
+	again, _ := ForRule(rule, MaxExamples)
+	if !slices.Equal(again, examples) {
+		t.Errorf("ForRule = %q on a second call, want %q", again, examples)
+	}
+	rule.Regex = `ghp_[0-9a-zA-Z]{40}`
+	if changed, _ := ForRule(rule, MaxExamples); slices.Equal(changed, examples) {
+		t.Error("a changed regex kept the same examples")
+	}
+}
@@ -0,0 +1,194 @@
+// Package sign creates and verifies detached Ed25519 signatures in the
+// minisign format, so published datasets can be checked with either
+// `hogwash verify` or a stock `minisign -V`.
+//
+// Signatures use minisign's legacy "Ed" algorithm (the message is signed
+// directly, not pre-hashed), which only needs the standard library. Public
+// keys and signature files are byte-compatible with minisign; secret keys
+// use a simpler unencrypted encoding of the same shape, so keep them in a
+// secret store rather than on disk.
+package sign
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// algorithm is minisign's identifier for non-prehashed Ed25519 signatures.
+var algorithm = [2]byte{'E', 'd'}
+
+const (
+	untrustedPrefix = "untrusted comment: "
+	trustedPrefix   = "trusted comment: "
+)
+
+// KeyID identifies a key pair. minisign stores it little-endian and prints it
+// as upper-case hex.
+type KeyID [8]byte
+
+func (id KeyID) String() string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(id[:]))
+}
+
+// PublicKey is a minisign public key.
+type PublicKey struct {
+	ID  KeyID
+	Key ed25519.PublicKey
+}
+
+// PrivateKey is a signing key and the ID of its public half.
+type PrivateKey struct {
+	ID  KeyID
+	Key ed25519.PrivateKey
+}
+
+// Public returns the public half of k.
+func (k PrivateKey) Public() PublicKey {
+	return PublicKey{ID: k.ID, Key: k.Key.Public().(ed25519.PublicKey)}
+}
+
+// GenerateKey creates a new key pair with a random key ID.
+func GenerateKey() (PrivateKey, error) {
+	var k PrivateKey
+	if _, err := io.ReadFull(rand.Reader, k.ID[:]); err != nil {
+		return k, err
+	}
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return k, err
+	}
+	k.Key = priv
+	return k, nil
+}
+
+// MarshalText encodes the public key in minisign's two-line format.
+func (k PublicKey) MarshalText() ([]byte, error) {
+	return encodeKeyFile("minisign public key "+k.ID.String(), k.ID, k.Key), nil
+}
+
+// MarshalText encodes the private key as a two-line file shaped like the
+// public key. It is not encrypted.
+func (k PrivateKey) MarshalText() ([]byte, error) {
+	return encodeKeyFile("hogwash secret key "+k.ID.String(), k.ID, k.Key), nil
+}
+
+func encodeKeyFile(comment string, id KeyID, key []byte) []byte {
+	blob := make([]byte, 0, 2+len(id)+len(key))
+	blob = append(blob, algorithm[:]...)
+	blob = append(blob, id[:]...)
+	blob = append(blob, key...)
+	return []byte(untrustedPrefix + comment + "\n" + base64.StdEncoding.EncodeToString(blob) + "\n")
+}
+
+// ParsePublicKey decodes a minisign public key file or its bare base64 line.
+func ParsePublicKey(data []byte) (PublicKey, error) {
+	id, key, err := decodeKeyFile(data, ed25519.PublicKeySize)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("public key: %w", err)
+	}
+	return PublicKey{ID: id, Key: ed25519.PublicKey(key)}, nil
+}
+
+// ParsePrivateKey decodes a key written by PrivateKey.MarshalText.
+func ParsePrivateKey(data []byte) (PrivateKey, error) {
+	id, key, err := decodeKeyFile(data, ed25519.PrivateKeySize)
+	if err != nil {
+		return PrivateKey{}, fmt.Errorf("secret key: %w", err)
+	}
+	return PrivateKey{ID: id, Key: ed25519.PrivateKey(key)}, nil
+}
+
+func decodeKeyFile(data []byte, keySize int) (KeyID, []byte, error) {
+	var id KeyID
+	line := lastNonCommentLine(data)
+	blob, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return id, nil, fmt.Errorf("decode base64: %w", err)
+	}
+	if len(blob) != 2+len(id)+keySize {
+		return id, nil, fmt.Errorf("unexpected length %d", len(blob))
+	}
+	if !bytes.Equal(blob[:2], algorithm[:]) {
+		return id, nil, fmt.Errorf("unsupported algorithm %q", blob[:2])
+	}
+	copy(id[:], blob[2:10])
+	return id, blob[10:], nil
+}
+
+func lastNonCommentLine(data []byte) string {
+	var last string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line != "" && !strings.HasPrefix(line, untrustedPrefix) {
+			last = line
+		}
+	}
+	return last
+}
+
+// Sign returns a minisign signature file for message. trustedComment is
+// covered by the signature; it must be a single line.
+func Sign(k PrivateKey, message []byte, trustedComment string) ([]byte, error) {
+	if strings.ContainsAny(trustedComment, "\r\n") {
+		return nil, errors.New("trusted comment must be a single line")
+	}
+	sig := ed25519.Sign(k.Key, message)
+	global := ed25519.Sign(k.Key, append(append([]byte(nil), sig...), trustedComment...))
+
+	blob := make([]byte, 0, 2+len(k.ID)+len(sig))
+	blob = append(blob, algorithm[:]...)
+	blob = append(blob, k.ID[:]...)
+	blob = append(blob, sig...)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%ssignature from hogwash secret key %s\n", untrustedPrefix, k.ID)
+	fmt.Fprintf(&b, "%s\n", base64.StdEncoding.EncodeToString(blob))
+	fmt.Fprintf(&b, "%s%s\n", trustedPrefix, trustedComment)
+	fmt.Fprintf(&b, "%s\n", base64.StdEncoding.EncodeToString(global))
+	return b.Bytes(), nil
+}
+
+// Verify checks a minisign signature file against message and returns its
+// trusted comment.
+func Verify(pub PublicKey, message, sigFile []byte) (string, error) {
+	lines := strings.Split(strings.TrimRight(string(sigFile), "\r\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], untrustedPrefix) || !strings.HasPrefix(lines[2], trustedPrefix) {
+		return "", errors.New("malformed signature file")
+	}
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(blob) != 2+len(pub.ID)+ed25519.SignatureSize {
+		return "", errors.New("malformed signature")
+	}
+	if !bytes.Equal(blob[:2], algorithm[:]) {
+		return "", fmt.Errorf("unsupported signature algorithm %q", blob[:2])
+	}
+	var id KeyID
+	copy(id[:], blob[2:10])
+	if id != pub.ID {
+		return "", fmt.Errorf("signed by key %s, expected %s", id, pub.ID)
+	}
+	sig := blob[10:]
+	if !ed25519.Verify(pub.Key, message, sig) {
+		return "", errors.New("signature does not match content")
+	}
+
+	trusted := strings.TrimPrefix(lines[2], trustedPrefix)
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || !ed25519.Verify(pub.Key, append(append([]byte(nil), sig...), trusted...), global) {
+		return "", errors.New("trusted comment signature is invalid")
+	}
+	return trusted, nil
+}
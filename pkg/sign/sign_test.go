@@ -0,0 +1,57 @@
+package sign
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	priv, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	// Keys survive their text encoding.
+	privText, _ := priv.MarshalText()
+	parsedPriv, err := ParsePrivateKey(privText)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey: %v", err)
+	}
+	pubText, _ := priv.Public().MarshalText()
+	if !strings.HasPrefix(string(pubText), "untrusted comment: minisign public key "+priv.ID.String()) {
+		t.Errorf("public key file = %q", pubText)
+	}
+	pub, err := ParsePublicKey(pubText)
+	if err != nil {
+		t.Fatalf("ParsePublicKey: %v", err)
+	}
+
+	msg := []byte(`{"schema_version":2}`)
+	sig, err := Sign(parsedPriv, msg, "file:gondolin.json")
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	trusted, err := Verify(pub, msg, sig)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if trusted != "file:gondolin.json" {
+		t.Errorf("trusted comment = %q", trusted)
+	}
+
+	if _, err := Verify(pub, []byte(`{"schema_version":1}`), sig); err == nil {
+		t.Error("Verify accepted tampered content")
+	}
+	tampered := strings.Replace(string(sig), "file:gondolin.json", "file:other.json", 1)
+	if _, err := Verify(pub, msg, []byte(tampered)); err == nil {
+		t.Error("Verify accepted a tampered trusted comment")
+	}
+
+	other, _ := GenerateKey()
+	if _, err := Verify(other.Public(), msg, sig); err == nil {
+		t.Error("Verify accepted a signature from another key")
+	}
+	if _, err := Sign(priv, msg, "two\nlines"); err == nil {
+		t.Error("Sign accepted a multi-line trusted comment")
+	}
+}
@@ -0,0 +1,98 @@
+package trufflehog
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// Gap causes refine a no_urls or all_noise skip with what the extractor
+// would have to learn to recover the detector's hosts.
+const (
+	GapSprintfHost = "sprintf_host" // fmt.Sprintf supplies the host: "https://%s/v1", "%s/v1"
+	GapConstURL    = "const_url"    // URL concatenated from a named value: "https://" + host, baseURL + "/v1"
+)
+
+// gapCause looks for URLs that file builds in ways extraction doesn't
+// resolve, and returns the Gap constant for the first kind found, in order of
+// precedence, or "".
+func gapCause(file *ast.File) string {
+	cause := ""
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Sprintf" && len(n.Args) > 1 {
+				if format, ok := stringLit(n.Args[0]); ok && sprintfHost(format) {
+					cause = strongerGap(cause, GapSprintfHost)
+				}
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.ADD && concatURL(n.X, n.Y) {
+				cause = strongerGap(cause, GapConstURL)
+			}
+		}
+		return cause != GapSprintfHost
+	})
+	return cause
+}
+
+// strongerGap returns whichever of two Gap constants takes precedence.
+func strongerGap(a, b string) string {
+	if a == GapSprintfHost || b == "" {
+		return a
+	}
+	return b
+}
+
+// sprintfHost reports whether a Sprintf format's host is a verb: either the
+// format starts with one ("%s/v1/me") or the URL's whole host is one
+// ("https://%s/v1/me"). A verb in only the first label is a host template,
+// which extraction already handles.
+func sprintfHost(format string) bool {
+	for _, scheme := range []string{"https://", "http://"} {
+		if rest, ok := strings.CutPrefix(format, scheme); ok {
+			host, _, _ := strings.Cut(rest, "/")
+			return host != "" && hostPlaceholderRe.ReplaceAllString(host, "") == ""
+		}
+	}
+	loc := hostPlaceholderRe.FindStringIndex(format)
+	return loc != nil && loc[0] == 0 && strings.HasPrefix(format[loc[1]:], "/")
+}
+
+// concatURL reports whether x + y joins a bare URL scheme to a named value
+// ("https://" + host), or a named base URL to a path (baseURL + "/v1").
+func concatURL(x, y ast.Expr) bool {
+	if lit, ok := stringLit(x); ok && (lit == "https://" || lit == "http://") {
+		return exprName(y) != ""
+	}
+	if lit, ok := stringLit(y); ok && strings.HasPrefix(lit, "/") {
+		name := strings.ToLower(exprName(x))
+		for _, hint := range []string{"url", "host", "endpoint", "domain", "base"} {
+			if strings.Contains(name, hint) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func stringLit(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// exprName is the name of an identifier or selector expression, or "".
+func exprName(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package trufflehog
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+)
+
+// HostNormalization configures NormalizeHostWith. The zero value is the
+// normalization every host gets.
+type HostNormalization struct {
+	StripWWW bool // "www.example.com" → "example.com", unless that leaves a public suffix
+}
+
+// NormalizeHostWith is the one normalization pass hosts go through wherever
+// they enter: TruffleHog URLs, extractor output, and curated data files. It
+// trims space, lower-cases, strips a default port (:80, :443) and trailing
+// dots, and converts internationalized labels to punycode ("bücher.example"
+// → "xn--bcher-kva.example"), so every exported host is plain ASCII and
+// compares byte-for-byte. Other ports are an error. Wildcards ("*.…") keep
+// their prefix and are never www-stripped.
+func NormalizeHostWith(host string, opts HostNormalization) (string, error) {
+	orig := host
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		if port != "80" && port != "443" {
+			return "", fmt.Errorf("host %q: non-default port %s", orig, port)
+		}
+		host = h
+	}
+	wildcard := strings.HasPrefix(host, "*.")
+	host = strings.TrimRight(strings.TrimPrefix(host, "*."), ".")
+	// Plain ASCII hosts skip IDNA, whose STD3 rules reject names DNS
+	// accepts (underscores, "--" in the third and fourth position).
+	if !isASCII(host) || strings.Contains(host, "xn--") {
+		ascii, err := idna.Lookup.ToASCII(host)
+		if err != nil {
+			return "", fmt.Errorf("host %q: %w", orig, err)
+		}
+		host = ascii
+	}
+	if wildcard {
+		return "*." + host, nil
+	}
+	if rest, ok := strings.CutPrefix(host, "www."); ok && opts.StripWWW && strings.Contains(rest, ".") && !IsPublicSuffix(rest) {
+		host = rest
+	}
+	return host, nil
+}
+
+// NormalizeHost is NormalizeHostWith with the default normalization.
+func NormalizeHost(host string) (string, error) {
+	return NormalizeHostWith(host, HostNormalization{})
+}
+
+// NormalizeCuratedHost normalizes a host from a curated data file, which
+// must already name the bare domain: a "www." host there would escape
+// -strip-www, as curated files are loaded once.
+func NormalizeCuratedHost(host string) (string, error) {
+	norm, err := NormalizeHost(host)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(norm, "www.") {
+		return "", fmt.Errorf("host %q: curated hosts must not start with www.", host)
+	}
+	return norm, nil
+}
+
+// scripts are the scripts homograph checks distinguish. Digits, hyphens and
+// other Common characters belong to none.
+var scripts = map[string]*unicode.RangeTable{
+	"Latin":    unicode.Latin,
+	"Cyrillic": unicode.Cyrillic,
+	"Greek":    unicode.Greek,
+	"Armenian": unicode.Armenian,
+	"Cherokee": unicode.Cherokee,
+	"Han":      unicode.Han,
+	"Hiragana": unicode.Hiragana,
+	"Katakana": unicode.Katakana,
+	"Hangul":   unicode.Hangul,
+	"Arabic":   unicode.Arabic,
+	"Hebrew":   unicode.Hebrew,
+	"Thai":     unicode.Thai,
+}
+
+// latinConfusables are non-Latin letters commonly rendered identically to a
+// Latin one.
+var latinConfusables = map[rune]bool{
+	// Cyrillic
+	'а': true, 'в': true, 'е': true, 'к': true, 'м': true, 'н': true, 'о': true, 'р': true,
+	'с': true, 'т': true, 'у': true, 'х': true, 'і': true, 'ј': true, 'ѕ': true, 'ԁ': true,
+	'һ': true, 'ԛ': true, 'ԝ': true, 'ү': true,
+	// Greek
+	'α': true, 'ι': true, 'κ': true, 'ν': true, 'ο': true, 'ρ': true, 'τ': true, 'υ': true,
+	'χ': true,
+	// Armenian
+	'օ': true, 'ս': true, 'հ': true, 'ո': true,
+}
+
+// HomographRisk explains why host could be a lookalike of another name, or
+// returns "" when it looks safe. A label is risky when it mixes scripts
+// ("pаypal" with a Cyrillic а) or consists entirely of non-Latin letters
+// that render as Latin ones. Punycode labels are decoded before checking.
+func HomographRisk(host string) string {
+	for _, label := range strings.Split(strings.TrimPrefix(host, "*."), ".") {
+		if strings.HasPrefix(label, "xn--") {
+			decoded, err := idna.Punycode.ToUnicode(label)
+			if err != nil {
+				return fmt.Sprintf("label %q is invalid punycode", label)
+			}
+			label = decoded
+		}
+		if reason := labelRisk(label); reason != "" {
+			return fmt.Sprintf("label %q %s", label, reason)
+		}
+	}
+	return ""
+}
+
+func labelRisk(label string) string {
+	seen := make(map[string]bool)
+	allConfusable, letters := true, 0
+	for _, r := range label {
+		if r < unicode.MaxASCII && !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if !latinConfusables[r] {
+			allConfusable = false
+		}
+		for name, table := range scripts {
+			if unicode.Is(table, r) {
+				seen[name] = true
+			}
+		}
+	}
+	if len(seen) > 1 && !cjkOnly(seen) {
+		names := make([]string, 0, len(seen))
+		for name := range seen {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "mixes scripts " + strings.Join(names, "+")
+	}
+	if letters > 0 && allConfusable && !seen["Latin"] {
+		return "consists of characters confusable with Latin letters"
+	}
+	return ""
+}
+
+// cjkOnly reports whether the scripts are a legitimate Japanese/Korean/Chinese
+// mix, which routinely combines Han with kana or Hangul.
+func cjkOnly(seen map[string]bool) bool {
+	for name := range seen {
+		switch name {
+		case "Han", "Hiragana", "Katakana", "Hangul":
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package trufflehog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	tests := map[string]string{
+		"API.Stripe.com":        "api.stripe.com",
+		"bücher.example":        "xn--bcher-kva.example",
+		"*.Bücher.example":      "*.xn--bcher-kva.example",
+		"xn--bcher-kva.example": "xn--bcher-kva.example",
+	}
+	for in, want := range tests {
+		got, err := NormalizeHost(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeHost(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := NormalizeHost("xn--zz.example"); err == nil {
+		t.Error("expected error for invalid punycode")
+	}
+}
+
+func TestNormalizeHostWith(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		stripWWW bool
+		want     string
+	}{
+		{" API.Stripe.com. ", false, "api.stripe.com"},
+		{"api.stripe.com:443", false, "api.stripe.com"},
+		{"Bücher.example:80", false, "xn--bcher-kva.example"},
+		{"my_tenant.example.com", false, "my_tenant.example.com"},
+		{"www.example.com", false, "www.example.com"},
+		{"WWW.Example.com.", true, "example.com"},
+		{"www.co.uk", true, "www.co.uk"},
+		{"*.www.example.com", true, "*.www.example.com"},
+	} {
+		got, err := NormalizeHostWith(tc.in, HostNormalization{StripWWW: tc.stripWWW})
+		if err != nil || got != tc.want {
+			t.Errorf("NormalizeHostWith(%q, www=%v) = %q, %v; want %q", tc.in, tc.stripWWW, got, err, tc.want)
+		}
+	}
+	if _, err := NormalizeHostWith("api.stripe.com:8443", HostNormalization{}); err == nil {
+		t.Error("non-default port: no error")
+	}
+	if _, err := NormalizeCuratedHost("www.stripe.com"); err == nil {
+		t.Error("curated www host: no error")
+	}
+}
+
+func TestHomographRisk(t *testing.T) {
+	risky := map[string]string{
+		"pаypal.com":        "mixes scripts Cyrillic+Latin", // Cyrillic а
+		"xn--pypal-4ve.com": "mixes scripts Cyrillic+Latin", // same, as punycode
+		"api.ѕtripe.com":    "mixes scripts",
+		"раура.com":         "confusable with Latin", // all-Cyrillic lookalike
+	}
+	for host, want := range risky {
+		if got := HomographRisk(host); !strings.Contains(got, want) {
+			t.Errorf("HomographRisk(%q) = %q, want it to mention %q", host, got, want)
+		}
+	}
+	for _, host := range []string{"api.stripe.com", "xn--bcher-kva.example", "пример.рф", "日本語ひらがな.jp", "*.slack.com"} {
+		if got := HomographRisk(host); got != "" {
+			t.Errorf("HomographRisk(%q) = %q, want safe", host, got)
+		}
+	}
+}
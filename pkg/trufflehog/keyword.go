@@ -0,0 +1,100 @@
+package trufflehog
+
+import (
+	"encoding/json"
+	"strings"
+
+	"secret-detector-export/data"
+)
+
+// credentialSuffixes are concatenated credential-type words that TruffleHog
+// appends to service names in its directory structure. Ordered longest-first
+// so we greedily strip the longest match.
+//
+// Example: "cloudflareapitoken" → strip "apitoken" → "cloudflare"
+//          "airtablepersonalaccesstoken" → strip "personalaccesstoken" → "airtable"
+var credentialSuffixes = []string{
+	// Long compound suffixes (must come before their shorter components)
+	"personalaccesstoken",
+	"personaltoken",
+	"personalapikey",
+	"organizationapi",
+	"globalapikey",
+	"apppassword",
+	"consumerkey",
+	"orgtoken",
+	"bottoken",
+	"accesstoken",
+	"apitokenv2",
+	"apitoken",
+	"apikey",
+	"api",
+	"oauth2",
+	"oauth",
+	"webhook",
+	"tokenv2",
+	"tokenv3",
+	"token",
+	"cakey",
+	"key",
+	// Version suffixes
+	"v2",
+	"v3",
+	// Platform suffixes — note: "io" intentionally excluded because many
+	// services are .io domains (frame.io, fly.io, keen.io etc.)
+	"cloud",
+	"license",
+}
+
+// thKeywordOverrides maps TruffleHog directory names to canonical keywords
+// for cases where suffix-stripping doesn't work (data/th_keyword_overrides.json):
+// ambiguous or wrong stripping ("gcpapplicationdefaultcredentials"), .io
+// names the "io" rule would cut, keys that would strip to a generic word
+// ("privatekey" → "private"), and compound names that belong to a broader
+// service ("sonarcloud" → "sonar"). Names that merely differ from the
+// Gitleaks keyword, like "meraki", are aliased in data/service_aliases.json
+// instead.
+var thKeywordOverrides = mustLoadKeywordOverrides()
+
+func mustLoadKeywordOverrides() map[string]string {
+	var m map[string]string
+	if err := json.Unmarshal(data.THKeywordOverrides, &m); err != nil {
+		panic("invalid embedded th_keyword_overrides.json: " + err.Error())
+	}
+	return m
+}
+
+// DeriveKeyword extracts a service keyword from a TruffleHog
+// detector directory name like "cloudflareapitoken" → "cloudflare".
+//
+// Tries manual overrides first, then strips known credential suffixes.
+func DeriveKeyword(dirName string) string {
+	return DeriveKeywordWith(dirName, thKeywordOverrides)
+}
+
+// DeriveKeywordWith is DeriveKeyword with overrides (lower-case directory
+// name → keyword) in place of the embedded ones, for previewing edits to
+// the override file.
+func DeriveKeywordWith(dirName string, overrides map[string]string) string {
+	dirName = strings.ToLower(strings.TrimSpace(dirName))
+	if dirName == "" {
+		return ""
+	}
+
+	// Check manual overrides first
+	if override, ok := overrides[dirName]; ok {
+		return override
+	}
+
+	// Try stripping known credential suffixes (longest first)
+	for _, suffix := range credentialSuffixes {
+		if strings.HasSuffix(dirName, suffix) {
+			base := dirName[:len(dirName)-len(suffix)]
+			if len(base) >= 3 { // avoid stripping to nothing or too-short names
+				return base
+			}
+		}
+	}
+
+	return dirName
+}
@@ -0,0 +1,101 @@
+package trufflehog
+
+import "testing"
+
+func TestDeriveKeyword(t *testing.T) {
+	tests := []struct {
+		dirName string
+		want    string
+	}{
+		// Simple names (no suffix to strip)
+		{"anthropic", "anthropic"},
+		{"openai", "openai"},
+		{"stripe", "stripe"},
+		{"github", "github"},
+
+		// Credential-type suffix stripping
+		{"cloudflareapitoken", "cloudflare"},
+		{"cloudflareglobalapikey", "cloudflare"},
+		{"cloudflarecakey", "cloudflare"},
+		{"datadogtoken", "datadog"},
+		{"digitaloceantoken", "digitalocean"},
+		{"digitaloceanv2", "digitalocean"},
+		{"discordbottoken", "discord"},
+		{"discordwebhook", "discord"},
+		{"facebookoauth", "facebook"},
+		{"fastlypersonaltoken", "fastly"},
+		{"npmtoken", "npm"},
+		{"npmtokenv2", "npm"},       // strips "v2" first? Let's check
+		{"nugetapikey", "nuget"},
+		{"snykkey", "snyk"},
+		{"sentryorgtoken", "sentry"},
+		{"telegrambottoken", "telegram"},
+		{"twitterconsumerkey", "twitter"},
+		{"airtableoauth", "airtable"},
+		{"airtablepersonalaccesstoken", "airtable"},
+		{"asanaoauth", "asana"},
+		{"asanapersonalaccesstoken", "asana"},
+		{"bitbucketapppassword", "bitbucket"},
+		{"contentfulpersonalaccesstoken", "contentful"},
+		{"linearapi", "linear"},
+		{"newrelicpersonalapikey", "newrelic"},
+		{"sendbirdorganizationapi", "sendbird"},
+		{"sendinbluev2", "sendinblue"},
+		{"sonarcloud", "sonar"},
+
+		// Manual overrides
+		{"adafruitio", "adafruit"},
+		{"adobeio", "adobe"},
+		{"flyio", "flyio"},
+		{"frameio", "frameio"},
+		{"privatekey", "privatekey"},
+		{"gcpapplicationdefaultcredentials", "gcp"},
+		{"hubspot_apikey", "hubspot"},
+
+		// .io services that should NOT have io stripped
+		{"customerio", "customerio"},
+		{"twilio", "twilio"},
+		{"keenio", "keenio"},
+		{"logzio", "logzio"},
+		{"podio", "podio"},
+
+		// Short names where suffix stripping would be too aggressive
+		{"npm", "npm"},
+		{"gcp", "gcp"},
+
+		// Edge cases
+		{"", ""},
+		{"a", "a"}, // too short to strip
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dirName, func(t *testing.T) {
+			got := DeriveKeyword(tt.dirName)
+			if got != tt.want {
+				t.Errorf("DeriveKeyword(%q) = %q, want %q", tt.dirName, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNoFalsePositives verifies that short keywords don't accidentally match
+// unrelated services.
+func TestNoFalsePositives(t *testing.T) {
+	// "vault" should NOT derive from "alienvault"
+	got := DeriveKeyword("alienvault")
+	if got == "vault" {
+		t.Errorf("alienvault should NOT produce keyword 'vault', got %q", got)
+	}
+
+	// "age" should NOT match "finage" or "imagekit"
+	got = DeriveKeyword("finage")
+	if got == "age" {
+		t.Errorf("finage should NOT produce keyword 'age', got %q", got)
+	}
+
+	// "coin" from "coinbase" shouldn't match random "coin" TH entries
+	got = DeriveKeyword("coincap")
+	if got == "coinbase" {
+		t.Errorf("coincap should NOT produce keyword 'coinbase', got %q", got)
+	}
+}
@@ -0,0 +1,145 @@
+package trufflehog
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var apiVersionSegmentRe = regexp.MustCompile(`^v\d+(\.\d+)?$`)
+
+// webhookSegments name the path segment under which a webhook URL carries
+// its secret, e.g. discord.com/api/webhooks/<id>/<token>.
+var webhookSegments = map[string]bool{"hook": true, "hooks": true, "webhook": true, "webhooks": true}
+
+// PathPrefixFromURLPath derives the API path prefix a verification URL lives
+// under. Leading segments are kept up to and including the first version
+// or webhook segment (within the first three), otherwise only the first
+// segment:
+//
+//	/client/v4/user/tokens/verify → /client/v4/
+//	/v1/models                    → /v1/
+//	/api/webhooks/%s/%s           → /api/webhooks/
+//	/oauth/token                  → /oauth/
+//	/ or ""                       → "" (whole host)
+//
+// Sprintf verbs and template placeholders end the usable prefix.
+func PathPrefixFromURLPath(p string) string {
+	if i := strings.IndexAny(p, "%{"); i >= 0 {
+		p = p[:i]
+		// A partial segment ("/users/%s" → "/users/") is fine; a cut in the
+		// middle of a segment ("/u%s") would not be a real prefix.
+		if j := strings.LastIndex(p, "/"); j >= 0 {
+			p = p[:j+1]
+		}
+	}
+
+	var segments []string
+	for _, s := range strings.Split(p, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	// The last segment of a complete path is the endpoint itself unless the
+	// path ended with a slash; only directories are useful as prefixes.
+	if !strings.HasSuffix(p, "/") {
+		segments = segments[:len(segments)-1]
+		if len(segments) == 0 {
+			return ""
+		}
+	}
+
+	keep := 1
+	for i := 0; i < len(segments) && i < 3; i++ {
+		if apiVersionSegmentRe.MatchString(segments[i]) || webhookSegments[strings.ToLower(segments[i])] {
+			keep = i + 1
+			break
+		}
+	}
+	return "/" + strings.Join(segments[:keep], "/") + "/"
+}
+
+// PathPrefixSet accumulates path prefixes per host. A host seen with an
+// empty prefix (a URL at the host root) is unscoped: forwarding can't be
+// narrowed to specific paths, so it ends up with no prefixes at all.
+type PathPrefixSet struct {
+	prefixes map[string]map[string]bool
+	unscoped map[string]bool
+}
+
+// NewPathPrefixSet returns an empty PathPrefixSet.
+func NewPathPrefixSet() *PathPrefixSet {
+	return &PathPrefixSet{
+		prefixes: make(map[string]map[string]bool),
+		unscoped: make(map[string]bool),
+	}
+}
+
+// Add records that host was referenced under prefix ("" = host root).
+func (s *PathPrefixSet) Add(host, prefix string) {
+	if prefix == "" {
+		s.unscoped[host] = true
+		return
+	}
+	if s.prefixes[host] == nil {
+		s.prefixes[host] = make(map[string]bool)
+	}
+	s.prefixes[host][prefix] = true
+}
+
+// AddAll merges an already-derived host → prefixes map. Hosts in hosts that
+// have no entry in prefixes are treated as unscoped.
+func (s *PathPrefixSet) AddAll(hosts []string, prefixes map[string][]string) {
+	for _, h := range hosts {
+		ps := prefixes[h]
+		if len(ps) == 0 {
+			s.Add(h, "")
+			continue
+		}
+		for _, p := range ps {
+			s.Add(h, p)
+		}
+	}
+}
+
+// Result returns host → sorted prefixes for scoped hosts, or nil if none.
+func (s *PathPrefixSet) Result() map[string][]string {
+	var out map[string][]string
+	for host, set := range s.prefixes {
+		if s.unscoped[host] {
+			continue
+		}
+		if out == nil {
+			out = make(map[string][]string)
+		}
+		out[host] = collapsePrefixes(sortedSet(set))
+	}
+	return out
+}
+
+// collapsePrefixes removes prefixes already covered by a shorter one in the
+// same list (e.g. /api/ covers /api/v1/).
+func collapsePrefixes(prefixes []string) []string {
+	sorted := append([]string(nil), prefixes...)
+	sort.Strings(sorted)
+	var out []string
+	for _, p := range sorted {
+		if len(out) > 0 && strings.HasPrefix(p, out[len(out)-1]) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func sortedSet(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
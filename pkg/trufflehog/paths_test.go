@@ -0,0 +1,58 @@
+package trufflehog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathPrefixFromURLPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/client/v4/user/tokens/verify", "/client/v4/"},
+		{"/api/v1/organizations", "/api/v1/"},
+		{"/v1/models", "/v1/"},
+		{"/oauth/token", "/oauth/"},
+		{"/api/webhooks/%s/%s", "/api/webhooks/"},
+		{"/services/hooks/incoming", "/services/hooks/"},
+		{"/users/me/", "/users/"},
+		{"/v1/users/%s", "/v1/"},
+		{"/u%s/profile", ""},
+		{"/verify", ""},
+		{"/", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := PathPrefixFromURLPath(tt.path); got != tt.want {
+				t.Errorf("PathPrefixFromURLPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathPrefixSet(t *testing.T) {
+	s := NewPathPrefixSet()
+	s.Add("api.example.com", "/v1/")
+	s.Add("api.example.com", "/v2/")
+	s.Add("api.example.com", "/v1/")
+	s.Add("mixed.example.com", "/api/")
+	s.Add("mixed.example.com", "")
+	s.AddAll([]string{"nested.example.com", "root.example.com"}, map[string][]string{
+		"nested.example.com": {"/api/v1/", "/api/"},
+	})
+
+	want := map[string][]string{
+		"api.example.com":    {"/v1/", "/v2/"},
+		"nested.example.com": {"/api/"},
+	}
+	if got := s.Result(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Result() = %v, want %v", got, want)
+	}
+
+	if got := NewPathPrefixSet().Result(); got != nil {
+		t.Errorf("empty Result() = %v, want nil", got)
+	}
+}
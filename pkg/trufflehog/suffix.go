@@ -0,0 +1,28 @@
+package trufflehog
+
+import (
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// IsPublicSuffix reports whether host is itself a public suffix, such as
+// "s3.amazonaws.com" or "herokuapp.com": a domain under which unrelated
+// parties register names. Forwarding a secret to every such host is never
+// what a detector means.
+func IsPublicSuffix(host string) bool {
+	host = strings.ToLower(strings.TrimPrefix(host, "*."))
+	suffix, _ := publicsuffix.PublicSuffix(host)
+	return suffix == host
+}
+
+// RegistrableDomain returns the public suffix plus one label ("eTLD+1") of
+// host, e.g. "stripe.com" for "api.stripe.com". Hosts without one (public
+// suffixes themselves) are returned unchanged.
+func RegistrableDomain(host string) string {
+	host = strings.ToLower(strings.TrimPrefix(host, "*."))
+	if d, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return d
+	}
+	return host
+}
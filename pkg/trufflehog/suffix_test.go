@@ -0,0 +1,35 @@
+package trufflehog
+
+import "testing"
+
+func TestIsPublicSuffix(t *testing.T) {
+	tests := map[string]bool{
+		"com":                     true,
+		"co.uk":                   true,
+		"s3.amazonaws.com":        true,
+		"herokuapp.com":           true,
+		"*.herokuapp.com":         true, // checked on the base domain
+		"api.stripe.com":          false,
+		"myapp.herokuapp.com":     false,
+		"bucket.s3.amazonaws.com": false,
+	}
+	for host, want := range tests {
+		if got := IsPublicSuffix(host); got != want {
+			t.Errorf("IsPublicSuffix(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := map[string]string{
+		"api.stripe.com":       "stripe.com",
+		"*.slack.com":          "slack.com",
+		"eu.api.example.co.uk": "example.co.uk",
+		"herokuapp.com":        "herokuapp.com",
+	}
+	for host, want := range tests {
+		if got := RegistrableDomain(host); got != want {
+			t.Errorf("RegistrableDomain(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package trufflehog
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// HostTemplate is a tenant-scoped host such as "{workspace}.slack.com",
+// where every customer gets their own subdomain. Wildcard is the form
+// allowlists use ("*.slack.com").
+type HostTemplate struct {
+	Template string `json:"template"`
+	Wildcard string `json:"wildcard"`
+}
+
+// hostPlaceholderRe matches a Sprintf verb or a {name} placeholder.
+var hostPlaceholderRe = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]|\{[^{}]*\}`)
+
+// defaultPlaceholder names Sprintf verbs, which carry no name of their own.
+const defaultPlaceholder = "{tenant}"
+
+// ParseHostTemplate turns a host with placeholders in its first label, such
+// as "%s.zendesk.com" or "{org}-api.example.com", into a HostTemplate. Sprintf
+// verbs become "{tenant}". ok is false when the host has no placeholder, has
+// one past the first label, or leaves only a TLD-level suffix like "com" or
+// "co.uk" behind.
+func ParseHostTemplate(host string) (t HostTemplate, ok bool) {
+	host = strings.ToLower(host)
+	first, rest, found := strings.Cut(host, ".")
+	if !found || !hostPlaceholderRe.MatchString(first) || hostPlaceholderRe.MatchString(rest) {
+		return HostTemplate{}, false
+	}
+	if !IsValidHostname(rest) || !strings.Contains(rest, ".") {
+		return HostTemplate{}, false
+	}
+	if suffix, icann := publicsuffix.PublicSuffix(rest); suffix == rest && icann {
+		return HostTemplate{}, false
+	}
+	label := hostPlaceholderRe.ReplaceAllStringFunc(first, func(p string) string {
+		if strings.HasPrefix(p, "{") && len(p) > 2 {
+			return p
+		}
+		return defaultPlaceholder
+	})
+	if literal := hostPlaceholderRe.ReplaceAllString(label, "x"); !IsValidHostname(literal) {
+		return HostTemplate{}, false
+	}
+	return HostTemplate{Template: label + "." + rest, Wildcard: "*." + rest}, true
+}
+
+// hostTemplateFromURL returns the host template of a URL whose host has
+// placeholders, along with the URL's path.
+func hostTemplateFromURL(u string) (t HostTemplate, path string, ok bool) {
+	hostport, path, found := splitRawURL(u)
+	if !found {
+		return HostTemplate{}, "", false
+	}
+	host, _, _ := strings.Cut(hostport, ":")
+	t, ok = ParseHostTemplate(host)
+	return t, path, ok
+}
+
+// splitRawURL splits u into its host[:port] and path without decoding
+// anything, so paths with Sprintf verbs ("/api/webhooks/%s/%s") that
+// url.Parse rejects as bad escapes survive.
+func splitRawURL(u string) (hostport, path string, ok bool) {
+	_, rest, found := strings.Cut(u, "://")
+	if !found {
+		return "", "", false
+	}
+	hostport = rest
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		hostport, path = rest[:i], rest[i:]
+		if j := strings.IndexAny(path, "?#"); j >= 0 {
+			path = path[:j]
+		}
+	}
+	return hostport, path, true
+}
+
+// MergeHostTemplates returns the union of a and b keyed by wildcard, sorted
+// by wildcard. For a wildcard in both, a's template wins.
+func MergeHostTemplates(a, b []HostTemplate) []HostTemplate {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(a)+len(b))
+	var out []HostTemplate
+	for _, t := range append(append([]HostTemplate(nil), a...), b...) {
+		if !seen[t.Wildcard] {
+			seen[t.Wildcard] = true
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Wildcard < out[j].Wildcard })
+	return out
+}
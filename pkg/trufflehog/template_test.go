@@ -0,0 +1,73 @@
+package trufflehog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseHostTemplate(t *testing.T) {
+	tests := []struct {
+		host   string
+		want   HostTemplate
+		wantOK bool
+	}{
+		{"%s.zendesk.com", HostTemplate{"{tenant}.zendesk.com", "*.zendesk.com"}, true},
+		{"{Workspace}.slack.com", HostTemplate{"{workspace}.slack.com", "*.slack.com"}, true},
+		{"%s-api.example.com", HostTemplate{"{tenant}-api.example.com", "*.example.com"}, true},
+		{"{shop}.myshopify.com", HostTemplate{"{shop}.myshopify.com", "*.myshopify.com"}, true}, // private suffix
+		{"api.%s.example.com", HostTemplate{}, false},                                           // placeholder past the first label
+		{"%s.com", HostTemplate{}, false},
+		{"%s.co.uk", HostTemplate{}, false},
+		{"%s", HostTemplate{}, false},
+		{"api.example.com", HostTemplate{}, false},
+		{"%s_x.example.com", HostTemplate{}, false}, // "_" is not a hostname character
+	}
+	for _, tt := range tests {
+		got, ok := ParseHostTemplate(tt.host)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseHostTemplate(%q) = %+v, %v; want %+v, %v", tt.host, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestExtractHostTemplates(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "zendeskapi")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := `package zendeskapi
+
+import "fmt"
+
+func url(domain string) string {
+	return fmt.Sprintf("https://%s.zendesk.com/api/v2/users/me.json", domain)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "zendeskapi.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	detectors, _, warnings, err := Extract(root, ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none", warnings)
+	}
+	if len(detectors) != 1 {
+		t.Fatalf("detectors = %+v", detectors)
+	}
+	d := detectors[0]
+	if !reflect.DeepEqual(d.Hosts, []string{"*.zendesk.com"}) {
+		t.Errorf("Hosts = %v, want [*.zendesk.com]", d.Hosts)
+	}
+	if want := []HostTemplate{{"{tenant}.zendesk.com", "*.zendesk.com"}}; !reflect.DeepEqual(d.HostTemplates, want) {
+		t.Errorf("HostTemplates = %+v, want %+v", d.HostTemplates, want)
+	}
+	if want := map[string][]string{"*.zendesk.com": {"/api/v2/"}}; !reflect.DeepEqual(d.PathPrefixes, want) {
+		t.Errorf("PathPrefixes = %v, want %v", d.PathPrefixes, want)
+	}
+}
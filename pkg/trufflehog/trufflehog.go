@@ -0,0 +1,388 @@
+// Package trufflehog extracts verification hosts from TruffleHog detector
+// sources.
+//
+// TruffleHog is AGPL-3.0. Only verification URLs/hosts are extracted
+// (factual data, not copyrightable). No regex patterns are copied.
+package trufflehog
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Detector represents a single TruffleHog detector with extracted hosts.
+type Detector struct {
+	DirName      string              `json:"dir_name"` // original directory name
+	Keyword      string              `json:"keyword"`  // derived service keyword
+	Hosts        []string            `json:"hosts"`
+	PathPrefixes map[string][]string `json:"path_prefixes,omitempty"` // host → API path prefixes (absent = whole host)
+	RotationURL  string              `json:"rotation_url,omitempty"`  // howtorotate.com guide linked from the detector
+	// HostTemplates are tenant-scoped hosts from Sprintf-style URLs; their
+	// wildcards are also listed in Hosts.
+	HostTemplates []HostTemplate `json:"host_templates,omitempty"`
+}
+
+// Skip reasons, stable so reports can be grouped and compared across runs.
+const (
+	SkipParseError = "parse_error" // the detector package couldn't be read or parsed
+	SkipNoURLs     = "no_urls"     // no http(s) URL string literals
+	SkipAllNoise   = "all_noise"   // every URL was filtered out (noise, rotation guides, bad hosts)
+)
+
+// Skipped is a detector directory that yielded no hosts.
+type Skipped struct {
+	DirName string `json:"dir_name"`
+	Reason  string `json:"reason"`          // one of the Skip constants
+	Cause   string `json:"cause,omitempty"` // one of the Gap constants, for no_urls and all_noise
+	Error   string `json:"error,omitempty"` // parse error text
+}
+
+func (s Skipped) String() string {
+	if s.Error != "" {
+		return s.DirName + ": " + s.Error
+	}
+	return s.DirName + ": " + strings.ReplaceAll(s.Reason, "_", " ")
+}
+
+// ExtractOptions controls host filtering during extraction.
+type ExtractOptions struct {
+	AllowIPHosts bool
+	// WildcardPublicSuffixes rewrites hosts that are public suffixes
+	// ("s3.amazonaws.com") to wildcard entries ("*.s3.amazonaws.com")
+	// instead of dropping them.
+	WildcardPublicSuffixes bool
+	// HostNormalization is applied to every extracted host.
+	HostNormalization HostNormalization
+}
+
+// Extract walks the TruffleHog detectors directory and
+// extracts verification hosts from each detector's Go source files.
+//
+// IMPORTANT: Only URLs/hosts are extracted (factual data). No regex patterns
+// are extracted to avoid AGPL license contamination.
+//
+// Returns the detectors that yielded hosts, the detectors that didn't and
+// why, and non-fatal warnings.
+func Extract(detectorsRoot string, opts ExtractOptions) ([]Detector, []Skipped, []error, error) {
+	return ExtractContext(context.Background(), detectorsRoot, opts)
+}
+
+// ExtractContext is Extract, stopping with ctx's error between detectors
+// once ctx is done.
+func ExtractContext(ctx context.Context, detectorsRoot string, opts ExtractOptions) ([]Detector, []Skipped, []error, error) {
+	entries, err := os.ReadDir(detectorsRoot)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var detectors []Detector
+	var skipped []Skipped
+	var warnings []error
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+
+		dirName := e.Name()
+		svcDir := filepath.Join(detectorsRoot, dirName)
+
+		parseDir, err := chooseHighestVersionDir(svcDir)
+		if err != nil {
+			skipped = append(skipped, Skipped{DirName: dirName, Reason: SkipParseError, Error: err.Error()})
+			continue
+		}
+
+		pkgURLs, ws, err := extractHostsFromGoPackage(parseDir, opts)
+		warnings = append(warnings, ws...)
+		if err != nil {
+			skipped = append(skipped, Skipped{DirName: dirName, Reason: SkipParseError, Error: err.Error()})
+			continue
+		}
+		if len(pkgURLs.hosts) == 0 {
+			reason := SkipAllNoise
+			if pkgURLs.urls == 0 {
+				reason = SkipNoURLs
+			}
+			skipped = append(skipped, Skipped{DirName: dirName, Reason: reason, Cause: pkgURLs.gap})
+			continue
+		}
+
+		sort.Strings(pkgURLs.hosts)
+
+		detectors = append(detectors, Detector{
+			DirName:      dirName,
+			Keyword:      DeriveKeyword(dirName),
+			Hosts:        pkgURLs.hosts,
+			PathPrefixes: pkgURLs.prefixes,
+			RotationURL:  pkgURLs.rotationURL,
+
+			HostTemplates: MergeHostTemplates(pkgURLs.templates, nil),
+		})
+	}
+
+	sort.Slice(detectors, func(i, j int) bool {
+		return detectors[i].DirName < detectors[j].DirName
+	})
+	sort.Slice(skipped, func(i, j int) bool {
+		return skipped[i].DirName < skipped[j].DirName
+	})
+
+	return detectors, skipped, warnings, nil
+}
+
+var versionDirRe = regexp.MustCompile(`^v(\d+)$`)
+
+// chooseHighestVersionDir selects the highest versioned subdirectory if present.
+// Many TruffleHog detectors are versioned as <service>/v1, <service>/v2, ...
+func chooseHighestVersionDir(serviceDir string) (string, error) {
+	entries, err := os.ReadDir(serviceDir)
+	if err != nil {
+		return "", err
+	}
+
+	bestVersion := -1
+	bestDir := ""
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		m := versionDirRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		v, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		if v > bestVersion {
+			bestVersion = v
+			bestDir = filepath.Join(serviceDir, e.Name())
+		}
+	}
+
+	if bestDir != "" {
+		return bestDir, nil
+	}
+	return serviceDir, nil
+}
+
+// packageURLs is what extractHostsFromGoPackage finds in one detector package.
+type packageURLs struct {
+	hosts       []string
+	prefixes    map[string][]string // host → path prefixes, for hosts whose URLs all sit below an API path
+	rotationURL string              // lexically first howtorotate.com guide, if any
+	templates   []HostTemplate      // tenant-scoped hosts; wildcards are in hosts too
+	urls        int                 // http(s) URL literals seen, kept or not
+	gap         string              // a Gap constant when URLs are built in ways not resolved yet
+}
+
+// extractHostsFromGoPackage parses all non-test Go files and extracts hosts
+// from http(s) URL string literals. Noise is filtered, except that
+// howtorotate.com guide links are kept aside as the rotation URL.
+func extractHostsFromGoPackage(dir string, opts ExtractOptions) (packageURLs, []error, error) {
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+	}, 0)
+	if err != nil {
+		return packageURLs{}, nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var out packageURLs
+	var warnings []error
+	prefixes := NewPathPrefixSet()
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			out.gap = strongerGap(out.gap, gapCause(file))
+			ast.Inspect(file, func(n ast.Node) bool {
+				lit, ok := n.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+
+				s, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					warnings = append(warnings, fmt.Errorf("%s: unquote string literal %q: %w", fset.Position(lit.Pos()), lit.Value, err))
+					return true
+				}
+
+				if !strings.HasPrefix(s, "https://") && !strings.HasPrefix(s, "http://") {
+					return true
+				}
+				out.urls++
+				if isRotationGuideURL(s) {
+					if out.rotationURL == "" || s < out.rotationURL {
+						out.rotationURL = s
+					}
+					return true
+				}
+				if isNoiseURL(s) {
+					return true
+				}
+				if tmpl, path, ok := hostTemplateFromURL(s); ok {
+					if IsNoiseHost(strings.TrimPrefix(tmpl.Wildcard, "*."), opts.AllowIPHosts) {
+						return true
+					}
+					if _, ok := seen[tmpl.Wildcard]; !ok {
+						seen[tmpl.Wildcard] = struct{}{}
+						out.hosts = append(out.hosts, tmpl.Wildcard)
+					}
+					out.templates = append(out.templates, tmpl)
+					prefixes.Add(tmpl.Wildcard, PathPrefixFromURLPath(path))
+					return true
+				}
+
+				pu, err := url.Parse(s)
+				if _, rawPath, _ := splitRawURL(s); err != nil && hostPlaceholderRe.MatchString(rawPath) {
+					// Sprintf verbs in the path read as bad escapes; parse
+					// with them filled in and keep the raw path for prefixes.
+					if pu, err = url.Parse(hostPlaceholderRe.ReplaceAllString(s, "x")); err == nil {
+						pu.Path = rawPath
+					}
+				}
+				if err != nil {
+					warnings = append(warnings, fmt.Errorf("%s: parse url %q: %w", fset.Position(lit.Pos()), s, err))
+					return true
+				}
+				host, err := NormalizeHostWith(pu.Hostname(), opts.HostNormalization)
+				if err != nil {
+					warnings = append(warnings, fmt.Errorf("%s: %w", fset.Position(lit.Pos()), err))
+					return true
+				}
+				if risk := HomographRisk(host); risk != "" {
+					warnings = append(warnings, fmt.Errorf("%s: host %q dropped as a possible homograph: %s", fset.Position(lit.Pos()), host, risk))
+					return true
+				}
+				if host == "" || IsNoiseHost(host, opts.AllowIPHosts) {
+					return true
+				}
+				if IsPublicSuffix(host) {
+					if !opts.WildcardPublicSuffixes {
+						return true
+					}
+					host = "*." + host
+				}
+
+				if _, ok := seen[host]; !ok {
+					seen[host] = struct{}{}
+					out.hosts = append(out.hosts, host)
+				}
+				prefixes.Add(host, PathPrefixFromURLPath(pu.Path))
+
+				return true
+			})
+		}
+	}
+
+	out.prefixes = prefixes.Result()
+	// Files are visited in map order; sort so the template kept per
+	// wildcard doesn't depend on it.
+	sort.Slice(out.templates, func(i, j int) bool {
+		if out.templates[i].Wildcard != out.templates[j].Wildcard {
+			return out.templates[i].Wildcard < out.templates[j].Wildcard
+		}
+		return out.templates[i].Template < out.templates[j].Template
+	})
+	return out, warnings, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// isRotationGuideURL reports whether u links to a howtorotate.com guide.
+func isRotationGuideURL(u string) bool {
+	pu, err := url.Parse(u)
+	if err != nil || (pu.Scheme != "https" && pu.Scheme != "http") {
+		return false
+	}
+	host := strings.ToLower(pu.Hostname())
+	return host == "howtorotate.com" || host == "www.howtorotate.com"
+}
+
+func isNoiseURL(u string) bool {
+	lower := strings.ToLower(u)
+	return strings.Contains(lower, "howtorotate.com") ||
+		strings.Contains(lower, "github.com/truffle")
+}
+
+var validHostRe = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9\-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9\-]*[a-zA-Z0-9])?)*$`)
+
+// IsValidHostname reports whether host is a syntactically valid DNS name.
+func IsValidHostname(host string) bool {
+	return validHostRe.MatchString(host)
+}
+
+// IsNoiseHost reports whether host should be excluded from exports: empty,
+// localhost, known non-service domains, IP literals (unless allowed and
+// routable), internal-only suffixes, invalid DNS names, and bare words.
+func IsNoiseHost(host string, allowIPHosts bool) bool {
+	host = strings.ToLower(host)
+	if host == "" {
+		return true
+	}
+	if host == "localhost" {
+		return true
+	}
+	if host == "howtorotate.com" || host == "github.com" || strings.HasSuffix(host, "fsf.org") {
+		return true
+	}
+
+	// Safe default: no IP literals at all.
+	if ip := net.ParseIP(host); ip != nil {
+		if !allowIPHosts {
+			return true
+		}
+		// Even with allowIPHosts, still block obvious non-routable ranges.
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+			return true
+		}
+	}
+
+	// Filter out internal-only namespaces.
+	internalSuffixes := []string{
+		".local", ".localdomain", ".internal", ".lan", ".home",
+		".svc", ".cluster.local", ".svc.cluster.local",
+	}
+	for _, s := range internalSuffixes {
+		if strings.HasSuffix(host, s) {
+			return true
+		}
+	}
+
+	// Filter out hostnames that aren't valid DNS names (e.g., regex fragments
+	// like "(" from URLs embedded in regexp patterns)
+	if !validHostRe.MatchString(host) {
+		return true
+	}
+	// Must contain at least one dot (bare words aren't useful hosts)
+	if !strings.Contains(host, ".") {
+		return true
+	}
+	return false
+}
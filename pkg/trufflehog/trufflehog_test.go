@@ -0,0 +1,217 @@
+package trufflehog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractNormalizesIDNAndDropsHomographs(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "bucher")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := `package bucher
+
+const (
+	api   = "https://API.Bücher.example/v1/keys"
+	spoof = "https://pаypal.com/v1/verify" // Cyrillic а
+)
+`
+	if err := os.WriteFile(filepath.Join(dir, "bucher.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	detectors, _, warnings, err := Extract(root, ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(detectors) != 1 || !reflect.DeepEqual(detectors[0].Hosts, []string{"api.xn--bcher-kva.example"}) {
+		t.Fatalf("detectors = %+v", detectors)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "homograph") {
+		t.Errorf("warnings = %v, want one homograph warning", warnings)
+	}
+}
+
+func TestExtractContextCanceled(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "stripe"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, _, err := ExtractContext(ctx, root, ExtractOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ExtractContext with canceled ctx = %v, want context.Canceled", err)
+	}
+}
+
+func TestExtractRotationURL(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "stripe")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := `package stripe
+
+const (
+	api    = "https://api.stripe.com/v1/charges"
+	guide  = "https://howtorotate.com/docs/tutorials/stripe/"
+	source = "https://github.com/trufflesecurity/trufflehog"
+)
+`
+	if err := os.WriteFile(filepath.Join(dir, "stripe.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	detectors, _, _, err := Extract(root, ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(detectors) != 1 || !reflect.DeepEqual(detectors[0].Hosts, []string{"api.stripe.com"}) {
+		t.Fatalf("detectors = %+v", detectors)
+	}
+	if got, want := detectors[0].RotationURL, "https://howtorotate.com/docs/tutorials/stripe/"; got != want {
+		t.Errorf("RotationURL = %q, want %q", got, want)
+	}
+}
+
+func TestExtractWebhookPathPrefix(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "discordwebhook")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := `package discordwebhook
+
+import "fmt"
+
+func url(id, token string) string {
+	return fmt.Sprintf("https://discord.com/api/webhooks/%s/%s", id, token)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "discordwebhook.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	detectors, _, _, err := Extract(root, ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	want := map[string][]string{"discord.com": {"/api/webhooks/"}}
+	if len(detectors) != 1 || !reflect.DeepEqual(detectors[0].PathPrefixes, want) {
+		t.Fatalf("detectors = %+v, want path prefixes %v", detectors, want)
+	}
+}
+
+func TestExtractSkipReasons(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"broken/broken.go":       "package broken\n\nfunc {\n",
+		"offline/offline.go":     "package offline\n\nconst prefix = \"off_\"\n",
+		"noisy/noisy.go":         "package noisy\n\nconst (\n\tdocs = \"https://howtorotate.com/docs/noisy\"\n\tlocal = \"http://localhost:8080/verify\"\n)\n",
+		"acme/v1/acme.go":        "package acme\n\nconst api = \"https://api.acme.example/v1/keys\"\n",
+		"acme/v1/acme_test.go":   "package acme\n",
+		"testsonly/only_test.go": "package testsonly\n\nconst api = \"https://api.testsonly.example\"\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	detectors, skipped, _, err := Extract(root, ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(detectors) != 1 || detectors[0].DirName != "acme" {
+		t.Errorf("detectors = %+v", detectors)
+	}
+	var got []string
+	for _, s := range skipped {
+		got = append(got, s.DirName+"="+s.Reason)
+		if (s.Reason == SkipParseError) != (s.Error != "") {
+			t.Errorf("%s: error %q for reason %s", s.DirName, s.Error, s.Reason)
+		}
+	}
+	want := []string{"broken=parse_error", "noisy=all_noise", "offline=no_urls", "testsonly=no_urls"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("skipped = %v, want %v", got, want)
+	}
+	if s := (Skipped{DirName: "offline", Reason: SkipNoURLs}).String(); s != "offline: no urls" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestExtractHostNormalization(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "acme")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := "package acme\n\nconst (\n\tapi = \"https://API.Acme.example.:443/v1/keys\"\n\tsite = \"https://www.acme.example/account\"\n)\n"
+	if err := os.WriteFile(filepath.Join(dir, "acme.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for stripWWW, want := range map[bool][]string{
+		false: {"api.acme.example", "www.acme.example"},
+		true:  {"acme.example", "api.acme.example"},
+	} {
+		detectors, _, _, err := Extract(root, ExtractOptions{HostNormalization: HostNormalization{StripWWW: stripWWW}})
+		if err != nil || len(detectors) != 1 || !reflect.DeepEqual(detectors[0].Hosts, want) {
+			t.Errorf("StripWWW %v: detectors = %+v, %v; want hosts %v", stripWWW, detectors, err, want)
+		}
+	}
+}
+
+func TestExtractGapCauses(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"sprintf/sprintf.go":   "package sprintf\n\nimport \"fmt\"\n\nfunc url(host string) string { return fmt.Sprintf(\"https://%s/api/v1/me\", host) }\n",
+		"relative/relative.go": "package relative\n\nimport \"fmt\"\n\nfunc url(base string) string { return fmt.Sprintf(\"%s/v2/keys\", base) }\n",
+		"scheme/scheme.go":     "package scheme\n\nconst domain = \"api.scheme.example\"\n\nvar verify = \"https://\" + domain\n",
+		"base/base.go":         "package base\n\nimport \"example.com/shared\"\n\nvar verify = shared.BaseURL + \"/keys\"\n",
+		"offline/offline.go":   "package offline\n\nvar prefix = \"off_\" + suffix\n\nconst suffix = \"/x\"\n",
+		"tenant/tenant.go":     "package tenant\n\nimport \"fmt\"\n\nfunc url(t string) string { return fmt.Sprintf(\"https://%s.tenant.example/v1\", t) + helper }\n\nvar helper = \"\"\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	detectors, skipped, _, err := Extract(root, ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(detectors) != 1 || detectors[0].DirName != "tenant" {
+		t.Errorf("detectors = %+v, want only the host template", detectors)
+	}
+	got := make(map[string]string)
+	for _, s := range skipped {
+		got[s.DirName] = s.Reason + "/" + s.Cause
+	}
+	want := map[string]string{
+		"sprintf":  "all_noise/sprintf_host",
+		"relative": "no_urls/sprintf_host",
+		"scheme":   "all_noise/const_url",
+		"base":     "no_urls/const_url",
+		"offline":  "no_urls/",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("skipped = %v, want %v", got, want)
+	}
+}
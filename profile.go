@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// profileFlags are -cpuprofile, -memprofile and -trace for tuning the
+// pipeline on large upstream trees. The extraction, combine and reduction
+// phases are marked as trace regions.
+type profileFlags struct {
+	cpu   string
+	mem   string
+	trace string
+}
+
+func (pf *profileFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&pf.cpu, "cpuprofile", "", "Write a CPU profile of the run to this file (go tool pprof)")
+	fs.StringVar(&pf.mem, "memprofile", "", "Write an allocation profile of the run to this file when it ends (go tool pprof)")
+	fs.StringVar(&pf.trace, "trace", "", "Write an execution trace of the run to this file (go tool trace); pipeline phases are marked as regions")
+}
+
+// start begins CPU profiling and tracing as requested. The returned func
+// stops them and writes the allocation profile; it must run before exit.
+func (pf profileFlags) start() (stop func() error, err error) {
+	var stops []func() error
+	stop = func() error {
+		var errs []error
+		for i := len(stops) - 1; i >= 0; i-- {
+			errs = append(errs, stops[i]())
+		}
+		return errors.Join(errs...)
+	}
+
+	if pf.cpu != "" {
+		f, err := os.Create(pf.cpu)
+		if err != nil {
+			return nil, fmt.Errorf("-cpuprofile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("-cpuprofile: %w", err)
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return closeProfile("-cpuprofile", f)
+		})
+	}
+
+	if pf.trace != "" {
+		f, err := os.Create(pf.trace)
+		if err != nil {
+			_ = stop()
+			return nil, fmt.Errorf("-trace: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			_ = f.Close()
+			_ = stop()
+			return nil, fmt.Errorf("-trace: %w", err)
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return closeProfile("-trace", f)
+		})
+	}
+
+	if pf.mem != "" {
+		// Fail before the run rather than after it if the path is unusable.
+		f, err := os.Create(pf.mem)
+		if err != nil {
+			_ = stop()
+			return nil, fmt.Errorf("-memprofile: %w", err)
+		}
+		stops = append(stops, func() error {
+			runtime.GC() // up-to-date statistics
+			if err := pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
+				_ = f.Close()
+				return fmt.Errorf("-memprofile: %w", err)
+			}
+			return closeProfile("-memprofile", f)
+		})
+	}
+	return stop, nil
+}
+
+func closeProfile(flagName string, f *os.File) error {
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("%s: %w", flagName, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileFlags(t *testing.T) {
+	dir := t.TempDir()
+	pf := profileFlags{
+		cpu:   filepath.Join(dir, "cpu.out"),
+		mem:   filepath.Join(dir, "mem.out"),
+		trace: filepath.Join(dir, "trace.out"),
+	}
+	stop, err := pf.start()
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	for _, path := range []string{pf.cpu, pf.mem, pf.trace} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s: not written (%v)", filepath.Base(path), err)
+		}
+	}
+
+	bad := profileFlags{mem: filepath.Join(dir, "missing", "mem.out")}
+	if _, err := bad.start(); err == nil {
+		t.Error("start with an unwritable -memprofile succeeded")
+	}
+}
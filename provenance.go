@@ -0,0 +1,37 @@
+package main
+
+import (
+	"time"
+
+	"secret-detector-export/pkg/provenance"
+)
+
+// writeProvenance records how output o was produced. The -sign-key path is
+// deliberately left out of the recorded parameters.
+func writeProvenance(cfg exportConfig, o outputSpec, started time.Time) error {
+	var inputs []provenance.Input
+	for _, in := range []provenance.Input{
+		{Name: "trufflehog", Path: cfg.THDir},
+		{Name: "gitleaks", Path: cfg.GLPath},
+		{Name: "from-full", Path: cfg.FromFull},
+		{Name: "pattern-denylist", Path: cfg.PatternDenylist},
+		{Name: "popularity", Path: cfg.Popularity},
+	} {
+		if in.Path != "" {
+			inputs = append(inputs, in)
+		}
+	}
+
+	params := make(map[string]string, len(cfg.Flags))
+	for name, value := range cfg.Flags {
+		if name != "sign-key" {
+			params[name] = value
+		}
+	}
+
+	st, err := provenance.New(o.Path, inputs, params, started, time.Now())
+	if err != nil {
+		return err
+	}
+	return writeJSONAtomic(o.Provenance, true, cfg.SyncDir, false, st)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/matcher"
+)
+
+// queryResult answers one lookup. Which fields are set depends on the query:
+// env-name and keyword queries resolve to hosts, host queries resolve back to
+// the keywords and exact names that unlock the host.
+type queryResult struct {
+	Query        string   `json:"query"`
+	ExactName    bool     `json:"exact_name,omitempty"` // env-name hit exact_name_host_map
+	Keywords     []string `json:"keywords,omitempty"`
+	ExactNames   []string `json:"exact_names,omitempty"`
+	Hosts        []string `json:"hosts,omitempty"`
+	PrimaryHosts []string `json:"primary_hosts,omitempty"`
+	Patterns     []string `json:"patterns,omitempty"`
+	Role         string   `json:"role,omitempty"`
+	Region       string   `json:"region,omitempty"`
+	PathPrefixes []string `json:"path_prefixes,omitempty"`
+}
+
+func (r queryResult) empty() bool {
+	return len(r.Keywords) == 0 && len(r.ExactNames) == 0 && len(r.Hosts) == 0 && len(r.Patterns) == 0
+}
+
+// queryEnvName resolves an env var name the way the runtime matcher does.
+func queryEnvName(g export.Gondolin, name string) queryResult {
+	m := matcher.New(g)
+	r := queryResult{Query: "env-name=" + name}
+	r.Keywords, r.ExactName = m.KeywordsForEnvName(name)
+	r.Hosts = m.HostsForEnvName(name)
+	r.PrimaryHosts = primaryHosts(g, r.Keywords)
+	r.Patterns = patternsForKeywords(g, r.Keywords)
+	return r
+}
+
+// queryKeyword looks up a service keyword, ignoring case, hyphens, and
+// underscores.
+func queryKeyword(g export.Gondolin, keyword string) queryResult {
+	r := queryResult{Query: "keyword=" + keyword}
+	norm := combine.NormalizeKeyword(keyword)
+	for _, k := range sortedKeys(g.KeywordHostMap) {
+		if combine.NormalizeKeyword(k) == norm {
+			r.Keywords = append(r.Keywords, k)
+			r.Hosts = append(r.Hosts, g.KeywordHostMap[k]...)
+		}
+	}
+	sort.Strings(r.Hosts)
+	r.Hosts = dedupSorted(r.Hosts)
+	r.PrimaryHosts = primaryHosts(g, r.Keywords)
+	r.Patterns = patternsForKeywords(g, r.Keywords)
+	return r
+}
+
+// queryHost finds every keyword and exact name that unlocks host, directly
+// or through a wildcard entry, plus the host's metadata.
+func queryHost(g export.Gondolin, host string) queryResult {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	r := queryResult{Query: "host=" + host}
+	for _, k := range sortedKeys(g.KeywordHostMap) {
+		if hostListCovers(g.KeywordHostMap[k], host) {
+			r.Keywords = append(r.Keywords, k)
+		}
+	}
+	for _, name := range sortedKeys(g.ExactNameHostMap) {
+		if hostListCovers(g.ExactNameHostMap[name], host) {
+			r.ExactNames = append(r.ExactNames, name)
+		}
+	}
+	r.Patterns = patternsForKeywords(g, r.Keywords)
+	r.Role = g.HostRoles[host]
+	r.Region = g.HostRegions[host]
+	r.PathPrefixes = g.PathPrefixes[host]
+	return r
+}
+
+// hostListCovers reports whether host is in hosts, either literally or under
+// a "*.domain" wildcard entry.
+func hostListCovers(hosts []string, host string) bool {
+	for _, h := range hosts {
+		if h == host {
+			return true
+		}
+		if base, ok := strings.CutPrefix(h, "*."); ok && strings.HasSuffix(host, "."+base) {
+			return true
+		}
+	}
+	return false
+}
+
+func primaryHosts(g export.Gondolin, keywords []string) []string {
+	var out []string
+	for _, k := range keywords {
+		if h, ok := g.PrimaryHostMap[k]; ok {
+			out = append(out, h)
+		}
+	}
+	sort.Strings(out)
+	return dedupSorted(out)
+}
+
+func patternsForKeywords(g export.Gondolin, keywords []string) []string {
+	want := make(map[string]bool, len(keywords))
+	for _, k := range keywords {
+		want[k] = true
+	}
+	var out []string
+	for _, p := range g.ValuePatterns {
+		if p.Keyword != "" && want[p.Keyword] {
+			out = append(out, p.ID)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func dedupSorted(s []string) []string {
+	out := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// runQuery implements `hogwash query -dataset <export.json> (-env-name NAME | -host HOST | -keyword KW)`.
+func runQuery(args []string) error {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	dataset := fs.String("dataset", "", "Gondolin or full export to query (required)")
+	envName := fs.String("env-name", "", "Which hosts may a secret in this env var be sent to?")
+	host := fs.String("host", "", "Which keywords and exact env var names unlock this host?")
+	keyword := fs.String("keyword", "", "Which hosts and patterns belong to this service keyword?")
+	asJSON := fs.Bool("json", false, "Write the result as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s query -dataset <export.json> (-env-name NAME | -host HOST | -keyword KEYWORD)\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dataset == "" {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("query: -dataset is required"))
+	}
+	set := 0
+	for _, v := range []string{*envName, *host, *keyword} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("query: exactly one of -env-name, -host, -keyword is required"))
+	}
+
+	// Full exports are reduced as -mode gondolin would, so answers match
+	// what the runtime sees.
+	g, err := loadGondolin(*dataset, export.DefaultOptions())
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	var r queryResult
+	switch {
+	case *envName != "":
+		r = queryEnvName(g, *envName)
+	case *host != "":
+		r = queryHost(g, *host)
+	default:
+		r = queryKeyword(g, *keyword)
+	}
+
+	if *asJSON {
+		if err := export.EncodeJSON(os.Stdout, r, false); err != nil {
+			return err
+		}
+	} else {
+		printQueryResult(r)
+	}
+	if r.empty() {
+		return fmt.Errorf("query: %s: no match", r.Query)
+	}
+	return nil
+}
+
+func printQueryResult(r queryResult) {
+	fmt.Fprintln(os.Stdout, r.Query)
+	line := func(label string, values []string) {
+		if len(values) > 0 {
+			fmt.Fprintf(os.Stdout, "  %-14s %s\n", label+":", strings.Join(values, ", "))
+		}
+	}
+	if r.ExactName {
+		fmt.Fprintf(os.Stdout, "  %-14s %s\n", "match:", "exact_name_host_map")
+	}
+	line("keywords", r.Keywords)
+	line("exact names", r.ExactNames)
+	line("hosts", r.Hosts)
+	line("primary hosts", r.PrimaryHosts)
+	line("patterns", r.Patterns)
+	if r.Role != "" {
+		line("role", []string{r.Role})
+	}
+	if r.Region != "" {
+		line("region", []string{r.Region})
+	}
+	line("path prefixes", r.PathPrefixes)
+}
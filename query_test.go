@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"secret-detector-export/pkg/export"
+)
+
+func queryTestGondolin() export.Gondolin {
+	return export.Gondolin{
+		KeywordHostMap: map[string][]string{
+			"cloudflare": {"api.cloudflare.com"},
+			"slack":      {"slack.com", "*.slack.com"},
+			"slack-bot":  {"slack.com"},
+		},
+		PrimaryHostMap:   map[string]string{"slack": "slack.com"},
+		HostRoles:        map[string]string{"slack.com": "api"},
+		ExactNameHostMap: map[string][]string{"CF_API_TOKEN": {"api.cloudflare.com"}},
+		ValuePatterns: []export.ValuePattern{
+			{ID: "slack-bot-token", Keyword: "slack", Regex: `xoxb-[0-9a-z-]+`},
+			{ID: "cloudflare-api-key", Keyword: "cloudflare", Regex: `[a-z0-9]{40}`},
+		},
+	}
+}
+
+func TestQueryEnvName(t *testing.T) {
+	g := queryTestGondolin()
+
+	r := queryEnvName(g, "SLACK_BOT_TOKEN")
+	want := queryResult{
+		Query:        "env-name=SLACK_BOT_TOKEN",
+		Keywords:     []string{"slack", "slack-bot"},
+		Hosts:        []string{"*.slack.com", "slack.com"},
+		PrimaryHosts: []string{"slack.com"},
+		Patterns:     []string{"slack-bot-token"},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("queryEnvName = %+v, want %+v", r, want)
+	}
+
+	if r := queryEnvName(g, "CF_API_TOKEN"); !r.ExactName || !reflect.DeepEqual(r.Hosts, []string{"api.cloudflare.com"}) {
+		t.Errorf("queryEnvName(CF_API_TOKEN) = %+v", r)
+	}
+	if r := queryEnvName(g, "HOME"); !r.empty() {
+		t.Errorf("queryEnvName(HOME) = %+v, want empty", r)
+	}
+}
+
+func TestQueryHost(t *testing.T) {
+	g := queryTestGondolin()
+
+	r := queryHost(g, "api.cloudflare.com")
+	if !reflect.DeepEqual(r.Keywords, []string{"cloudflare"}) || !reflect.DeepEqual(r.ExactNames, []string{"CF_API_TOKEN"}) {
+		t.Errorf("queryHost(api.cloudflare.com) = %+v", r)
+	}
+	// Wildcard entries cover subdomains.
+	if r := queryHost(g, "Hooks.Slack.com"); !reflect.DeepEqual(r.Keywords, []string{"slack"}) {
+		t.Errorf("queryHost(hooks.slack.com) keywords = %v", r.Keywords)
+	}
+	if r := queryHost(g, "slack.com"); r.Role != "api" || len(r.Keywords) != 2 {
+		t.Errorf("queryHost(slack.com) = %+v", r)
+	}
+}
+
+func TestQueryKeyword(t *testing.T) {
+	r := queryKeyword(queryTestGondolin(), "Slack_Bot")
+	if !reflect.DeepEqual(r.Keywords, []string{"slack-bot"}) || !reflect.DeepEqual(r.Hosts, []string{"slack.com"}) || r.Patterns != nil {
+		t.Errorf("queryKeyword(Slack_Bot) = %+v", r)
+	}
+}
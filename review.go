@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/gitleaks"
+	"secret-detector-export/pkg/trufflehog"
+)
+
+// defaultPinFile is the prefix match pin file, relative to a checkout of
+// this repository. It is embedded at build time.
+const defaultPinFile = "data/match_pins.json"
+
+// reviewItem is one Gitleaks service awaiting a matching decision: bound to
+// TruffleHog detectors by prefix only, or bound to none and left without
+// hosts.
+type reviewItem struct {
+	Keyword     string
+	Rules       int
+	Prefix      []string // TH keywords bound by prefix; empty for a service without hosts
+	Suggestions []string // TH keywords sharing a substring with Keyword, as alias candidates
+}
+
+// reviewSession holds the curated files a review edits and what the
+// extraction offers as alias targets.
+type reviewSession struct {
+	aliases    map[string]string
+	pins       map[string][]string
+	thKeywords map[string]string // normalized → spelling
+
+	accepted, rejected, aliased int
+}
+
+// runReview implements `hogwash review -trufflehog <dir> -gitleaks <toml>`:
+// an interactive walk through prefix matches and services without hosts
+// that records each decision in the alias and pin files.
+func runReview(args []string) error {
+	fs := flag.NewFlagSet("review", flag.ExitOnError)
+	thDir := fs.String("trufflehog", "", "Path to trufflehog/pkg/detectors/ (required)")
+	glPath := fs.String("gitleaks", "", "Path to gitleaks/config/gitleaks.toml (required)")
+	normalize := fs.String("normalize", string(combine.NormalizeBasic), "Keyword comparison, as the export flag of the same name: 'basic' or 'loose'")
+	aliasPath := fs.String("aliases", defaultAliasFile, "Alias data file to edit")
+	pinPath := fs.String("pins", defaultPinFile, "Prefix match pin file to edit")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s review -trufflehog <dir> -gitleaks <toml> [flags]\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Walks through prefix matches and services without hosts. For each, answer\n")
+		fmt.Fprintf(fs.Output(), "accept, reject, alias <th-keyword>, skip or quit. Decisions are written to the\n")
+		fmt.Fprintf(fs.Output(), "alias and pin files; reviewed services are not asked about again.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *thDir == "" || *glPath == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("review: -trufflehog and -gitleaks are required"))
+	}
+	level, err := combine.ParseNormalization(*normalize)
+	if err != nil {
+		return withExitCode(exitUsage, fmt.Errorf("review: -normalize: %w", err))
+	}
+
+	aliases, err := readAliases(*aliasPath)
+	if err != nil {
+		return fmt.Errorf("review: %w", err)
+	}
+	var pins map[string][]string
+	if err := readJSONInput(*pinPath, &pins); err != nil {
+		return fmt.Errorf("review: %w", err)
+	}
+	if pins == nil {
+		pins = make(map[string][]string)
+	}
+	s := &reviewSession{aliases: aliases, pins: pins}
+	detectors, _, _, err := trufflehog.Extract(*thDir, trufflehog.ExtractOptions{})
+	if err != nil {
+		return fmt.Errorf("review: trufflehog extraction: %w", err)
+	}
+	rules, err := gitleaks.Extract(*glPath)
+	if err != nil {
+		return fmt.Errorf("review: gitleaks extraction: %w", err)
+	}
+	full := combine.CombineWith(detectors, rules, combine.Options{Normalization: level})
+	s.thKeywords = extractedTHKeywords(full)
+
+	items := s.items(full)
+	if len(items) == 0 {
+		fmt.Fprintln(os.Stdout, "Nothing to review.")
+		return nil
+	}
+	if err := s.run(os.Stdin, os.Stdout, items); err != nil {
+		return fmt.Errorf("review: %w", err)
+	}
+	if s.aliased > 0 {
+		if err := writeDataFile(*aliasPath, s.aliases); err != nil {
+			return fmt.Errorf("review: %w", err)
+		}
+	}
+	if s.accepted+s.rejected > 0 {
+		if err := writeDataFile(*pinPath, s.pins); err != nil {
+			return fmt.Errorf("review: %w", err)
+		}
+	}
+	logger.Info(fmt.Sprintf("Review: %d accepted, %d rejected, %d aliased; rebuild to embed the changes", s.accepted, s.rejected, s.aliased))
+	return nil
+}
+
+// items lists the services of full that still need a decision: those bound
+// by prefix or left without hosts, unless the alias or pin files already
+// cover them.
+func (s *reviewSession) items(full combine.Export) []reviewItem {
+	var items []reviewItem
+	for _, svc := range full.Services {
+		if _, ok := findAlias(s.aliases, svc.Keyword); ok || s.pinned(svc.Keyword) {
+			continue
+		}
+		item := reviewItem{Keyword: svc.Keyword, Rules: len(svc.Rules)}
+		switch {
+		case svc.MatchType == "prefix":
+			seen := make(map[string]bool)
+			for _, dir := range svc.MatchedTH {
+				if k := trufflehog.DeriveKeyword(dir); !seen[k] {
+					seen[k] = true
+					item.Prefix = append(item.Prefix, k)
+				}
+			}
+			sort.Strings(item.Prefix)
+		case len(svc.Hosts) == 0 && svc.MatchType == "":
+			item.Suggestions = s.suggest(svc.Keyword)
+		default:
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func (s *reviewSession) pinned(keyword string) bool {
+	norm := combine.NormalizeKeyword(keyword)
+	for k := range s.pins {
+		if combine.NormalizeKeyword(k) == norm {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest lists extracted TH keywords that contain keyword or are contained
+// in it, the usual shape of a missed alias.
+func (s *reviewSession) suggest(keyword string) []string {
+	norm := combine.NormalizeKeyword(keyword)
+	var out []string
+	for thNorm, th := range s.thKeywords {
+		if len(thNorm) >= 4 && (strings.Contains(thNorm, norm) || strings.Contains(norm, thNorm)) {
+			out = append(out, th)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// run prompts for each item on w and reads answers from r until the items
+// run out, the operator quits, or r ends.
+func (s *reviewSession) run(r io.Reader, w io.Writer, items []reviewItem) error {
+	in := bufio.NewScanner(r)
+	for i, item := range items {
+		fmt.Fprintf(w, "\n[%d/%d] %s (%d rules)\n", i+1, len(items), item.Keyword, item.Rules)
+		if len(item.Prefix) > 0 {
+			fmt.Fprintf(w, "  bound by prefix to: %s\n", strings.Join(item.Prefix, ", "))
+		} else {
+			fmt.Fprintf(w, "  no TruffleHog detector bound; exported without hosts\n")
+			if len(item.Suggestions) > 0 {
+				fmt.Fprintf(w, "  alias candidates: %s\n", strings.Join(item.Suggestions, ", "))
+			}
+		}
+	prompt:
+		for {
+			if len(item.Prefix) > 0 {
+				fmt.Fprint(w, "accept, reject, alias <th-keyword>, skip, quit? ")
+			} else {
+				fmt.Fprint(w, "reject, alias <th-keyword>, skip, quit? ")
+			}
+			if !in.Scan() {
+				fmt.Fprintln(w)
+				return in.Err()
+			}
+			verb, arg, _ := strings.Cut(strings.TrimSpace(in.Text()), " ")
+			switch verb {
+			case "a", "accept":
+				if len(item.Prefix) == 0 {
+					fmt.Fprintln(w, "  nothing to accept: use alias or reject")
+					continue
+				}
+				s.pins[item.Keyword] = item.Prefix
+				s.accepted++
+			case "r", "reject":
+				s.pins[item.Keyword] = []string{}
+				s.rejected++
+			case "l", "alias":
+				th, ok := s.thKeywords[combine.NormalizeKeyword(strings.TrimSpace(arg))]
+				if !ok {
+					fmt.Fprintf(w, "  no TruffleHog keyword %q in this extraction\n", arg)
+					continue
+				}
+				s.aliases[strings.ToLower(item.Keyword)] = th
+				s.aliased++
+			case "s", "skip", "":
+			case "q", "quit":
+				return nil
+			default:
+				fmt.Fprintf(w, "  unknown answer %q\n", verb)
+				continue
+			}
+			break prompt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestReviewSession(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "cisco-meraki", MatchType: "alias", MatchedTH: []string{"meraki"}, Hosts: []string{"api.meraki.com"}},
+			{Keyword: "cloud", MatchType: "prefix", MatchedTH: []string{"cloudflareapitoken", "cloudsmith"}, Rules: []combine.Rule{{ID: "cloud-key"}}},
+			{Keyword: "sendgrid-v2", Rules: []combine.Rule{{ID: "sendgrid-v2-key"}}},
+			{Keyword: "twilio", Rules: []combine.Rule{{ID: "twilio-key"}}},
+			{Keyword: "pinned", MatchType: "prefix", MatchedTH: []string{"pinnedapp"}},
+			{Keyword: "acme", Rules: []combine.Rule{{ID: "acme-key"}}},
+		},
+		THOnlyHosts: []combine.THOnlyEntry{{Keyword: "sendgrid", DirName: "sendgrid", Hosts: []string{"api.sendgrid.com"}}},
+	}
+	s := &reviewSession{
+		aliases: map[string]string{"cisco-meraki": "meraki"},
+		pins:    map[string][]string{"pinned": {}},
+	}
+	s.thKeywords = extractedTHKeywords(full)
+
+	items := s.items(full)
+	var keywords []string
+	for _, it := range items {
+		keywords = append(keywords, it.Keyword)
+	}
+	if want := []string{"cloud", "sendgrid-v2", "twilio", "acme"}; !reflect.DeepEqual(keywords, want) {
+		t.Fatalf("items = %v, want %v", keywords, want)
+	}
+	if want := []string{"cloudflare", "cloudsmith"}; !reflect.DeepEqual(items[0].Prefix, want) {
+		t.Errorf("cloud prefix = %v, want %v", items[0].Prefix, want)
+	}
+	if want := []string{"sendgrid"}; !reflect.DeepEqual(items[1].Suggestions, want) {
+		t.Errorf("sendgrid-v2 suggestions = %v, want %v", items[1].Suggestions, want)
+	}
+
+	// accept; an unknown alias target and a bogus answer re-prompt; reject;
+	// quit before acme.
+	in := "accept\nalias send-grd\nmaybe\nalias send_grid\nr\nq\n"
+	var out strings.Builder
+	if err := s.run(strings.NewReader(in), &out, items); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string][]string{"pinned": {}, "cloud": {"cloudflare", "cloudsmith"}, "twilio": {}}; !reflect.DeepEqual(s.pins, want) {
+		t.Errorf("pins = %v, want %v", s.pins, want)
+	}
+	if want := map[string]string{"cisco-meraki": "meraki", "sendgrid-v2": "sendgrid"}; !reflect.DeepEqual(s.aliases, want) {
+		t.Errorf("aliases = %v, want %v", s.aliases, want)
+	}
+	if s.accepted != 1 || s.rejected != 1 || s.aliased != 1 {
+		t.Errorf("counts = %d/%d/%d, want 1/1/1", s.accepted, s.rejected, s.aliased)
+	}
+	for _, want := range []string{"[1/4] cloud (1 rules)", "alias candidates: sendgrid", `no TruffleHog keyword "send-grd"`, `unknown answer "maybe"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	// Input ending mid-review stops without error.
+	if err := (&reviewSession{pins: map[string][]string{}, aliases: map[string]string{}}).run(strings.NewReader(""), &out, items); err != nil {
+		t.Errorf("EOF: %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"secret-detector-export/pkg/matcher"
+)
+
+// scanFinding is one match reported by `scan`, located by file and line.
+type scanFinding struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`   // 1-based
+	Column    int    `json:"column"` // 1-based byte column
+	PatternID string `json:"pattern_id"`
+	Keyword   string `json:"keyword,omitempty"`
+	Secret    string `json:"secret"` // masked unless -show-secrets
+	Policy    string `json:"policy,omitempty"`
+}
+
+// scanOptions controls which files `scan` reads and how it reports them.
+type scanOptions struct {
+	MaxSize     int64 // skip files larger than this many bytes (0 = no limit)
+	ShowSecrets bool
+}
+
+// runScan implements `hogwash scan [flags] [path ...]`.
+func runScan(args []string) error {
+	flags := flag.NewFlagSet("scan", flag.ExitOnError)
+	dataset := flags.String("dataset", "", "Gondolin or full export whose value_patterns to apply (required)")
+	outPath := flags.String("out", "-", "Output file path (or - for stdout)")
+	force := flags.Bool("force", false, "Overwrite -out if it already exists")
+	maxSize := flags.Int64("max-size", 1<<20, "Skip files larger than this many bytes (0 = no limit)")
+	showSecrets := flags.Bool("show-secrets", false, "Report matched secrets verbatim instead of masked")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s scan -dataset <export.json> [flags] [file | dir | -] ...\n\n", os.Args[0])
+		fmt.Fprintln(flags.Output(), "With no paths, scans stdin. Directories are walked recursively; .git and binary files are skipped.")
+		fmt.Fprintln(flags.Output())
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dataset == "" {
+		flags.Usage()
+		return withExitCode(exitUsage, errors.New("scan: -dataset is required"))
+	}
+
+	m, err := matcher.LoadFile(*dataset)
+	if err != nil {
+		return fmt.Errorf("scan: %w", err)
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	opts := scanOptions{MaxSize: *maxSize, ShowSecrets: *showSecrets}
+
+	findings := []scanFinding{}
+	files := 0
+	for _, p := range paths {
+		found, n, err := scanPath(m, p, opts)
+		if err != nil {
+			return fmt.Errorf("scan: %w", err)
+		}
+		findings = append(findings, found...)
+		files += n
+	}
+
+	if err := writeJSONOutput(*outPath, *force, false, false, findings); err != nil {
+		return fmt.Errorf("scan: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "scan: %d files scanned, %d findings\n", files, len(findings))
+	return nil
+}
+
+// scanPath scans stdin ("-"), a single file, or a directory tree, and
+// returns the findings and the number of files actually scanned.
+func scanPath(m *matcher.Matcher, path string, opts scanOptions) ([]scanFinding, int, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, 0, fmt.Errorf("read stdin: %w", err)
+		}
+		return scanContent(m, "-", data, opts), 1, nil
+	}
+
+	var findings []scanFinding
+	files := 0
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" && p != path {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if opts.MaxSize > 0 {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if info.Size() > opts.MaxSize {
+				return nil
+			}
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if isBinary(data) {
+			return nil
+		}
+		files++
+		findings = append(findings, scanContent(m, p, data, opts)...)
+		return nil
+	})
+	return findings, files, err
+}
+
+// scanContent runs every value pattern over data and converts byte offsets
+// to line/column positions.
+func scanContent(m *matcher.Matcher, name string, data []byte, opts scanOptions) []scanFinding {
+	content := string(data)
+	var findings []scanFinding
+	for _, f := range m.DetectSecrets(content) {
+		line := 1 + strings.Count(content[:f.Start], "\n")
+		col := f.Start - strings.LastIndexByte(content[:f.Start], '\n')
+		secret := f.Secret
+		if !opts.ShowSecrets {
+			secret = maskSecret(secret)
+		}
+		findings = append(findings, scanFinding{
+			File:      name,
+			Line:      line,
+			Column:    col,
+			PatternID: f.PatternID,
+			Keyword:   f.Keyword,
+			Secret:    secret,
+			Policy:    f.Policy,
+		})
+	}
+	return findings
+}
+
+// isBinary uses the same heuristic as git: a NUL byte in the first 8 KB.
+func isBinary(data []byte) bool {
+	if len(data) > 8000 {
+		data = data[:8000]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
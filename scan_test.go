@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/matcher"
+)
+
+func TestScanPath(t *testing.T) {
+	m := matcher.New(export.Gondolin{
+		ValuePatterns: []export.ValuePattern{
+			{ID: "stripe-access-token", Keyword: "stripe", Regex: `key=(sk_live_[a-z0-9]{8})`, SecretGroup: 1, Keywords: []string{"sk_live_"}},
+		},
+	})
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("config.env", "# settings\nkey=sk_live_abcd1234\n")
+	write("image.bin", "key=sk_live_abcd1234\x00")
+	write(".git/config", "key=sk_live_abcd1234\n")
+
+	got, files, err := scanPath(m, dir, scanOptions{})
+	if err != nil {
+		t.Fatalf("scanPath: %v", err)
+	}
+	if files != 1 {
+		t.Errorf("files scanned = %d, want 1 (binary and .git skipped)", files)
+	}
+	if len(got) != 1 {
+		t.Fatalf("findings = %+v, want 1", got)
+	}
+	f := got[0]
+	if f.Line != 2 || f.Column != 5 || f.PatternID != "stripe-access-token" || f.Secret != "sk_l************" {
+		t.Errorf("finding = %+v", f)
+	}
+
+	got, _, _ = scanPath(m, filepath.Join(dir, "config.env"), scanOptions{ShowSecrets: true})
+	if len(got) != 1 || got[0].Secret != "sk_live_abcd1234" {
+		t.Errorf("ShowSecrets findings = %+v", got)
+	}
+
+	if _, files, _ := scanPath(m, dir, scanOptions{MaxSize: 10}); files != 0 {
+		t.Errorf("MaxSize should skip every file, scanned %d", files)
+	}
+}
+
+func TestRunScanRequiresDataset(t *testing.T) {
+	if err := runScan(nil); err == nil || exitCode(err) != exitUsage {
+		t.Errorf("runScan without -dataset = %v, want a usage error", err)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/matcher"
+)
+
+// envReport is what scan-env reports for one variable. Secret values are
+// masked; only the pattern and position of a match are useful here.
+type envReport struct {
+	Name     string            `json:"name"`
+	Hosts    []string          `json:"hosts,omitempty"`
+	Findings []matcher.Finding `json:"findings,omitempty"`
+}
+
+// runScanEnv implements `hogwash scan-env [flags]`.
+func runScanEnv(args []string) error {
+	fs := flag.NewFlagSet("scan-env", flag.ExitOnError)
+	dataset := fs.String("dataset", "", "Gondolin or full export to match against (required)")
+	envFile := fs.String("env-file", "", "Read variables from this .env file (or - for stdin) instead of the current environment")
+	asJSON := fs.Bool("json", false, "Write the report as JSON")
+	all := fs.Bool("all", false, "Also list variables with no host mapping and no pattern match")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s scan-env -dataset <export.json> [flags]\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dataset == "" {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("scan-env: -dataset is required"))
+	}
+
+	m, err := matcher.LoadFile(*dataset)
+	if err != nil {
+		return fmt.Errorf("scan-env: %w", err)
+	}
+
+	var env map[string]string
+	if *envFile != "" {
+		env, err = readDotEnvFile(*envFile)
+		if err != nil {
+			return fmt.Errorf("scan-env: %w", err)
+		}
+	} else {
+		env = environMap(os.Environ())
+	}
+
+	reports := scanEnv(m, env, *all)
+	if *asJSON {
+		if reports == nil {
+			reports = []envReport{}
+		}
+		return export.EncodeJSON(os.Stdout, reports, false)
+	}
+	for _, r := range reports {
+		fmt.Fprintln(os.Stdout, r.Name)
+		if len(r.Hosts) > 0 {
+			fmt.Fprintf(os.Stdout, "  hosts:    %s\n", strings.Join(r.Hosts, ", "))
+		}
+		for _, f := range r.Findings {
+			line := fmt.Sprintf("  pattern:  %s (%s)", f.PatternID, f.Secret)
+			if f.Policy != "" {
+				line += " policy=" + f.Policy
+			}
+			fmt.Fprintln(os.Stdout, line)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "scan-env: %d variables scanned, %d reported\n", len(env), len(reports))
+	return nil
+}
+
+// scanEnv matches every variable by name and value. Variables with neither
+// hosts nor findings are omitted unless all is set. Reports are sorted by
+// name and finding secrets are masked.
+func scanEnv(m *matcher.Matcher, env map[string]string, all bool) []envReport {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var reports []envReport
+	for _, name := range names {
+		r := envReport{
+			Name:     name,
+			Hosts:    m.HostsForEnvName(name),
+			Findings: m.DetectSecrets(env[name]),
+		}
+		for i := range r.Findings {
+			r.Findings[i].Secret = maskSecret(r.Findings[i].Secret)
+		}
+		if all || len(r.Hosts) > 0 || len(r.Findings) > 0 {
+			reports = append(reports, r)
+		}
+	}
+	return reports
+}
+
+// maskSecret keeps a short prefix (usually the token's fixed marker, like
+// "ghp_") so matches can be told apart without leaking the secret.
+func maskSecret(s string) string {
+	const keep = 4
+	if len(s) <= keep*2 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:keep] + strings.Repeat("*", len(s)-keep)
+}
+
+func environMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if name, value, ok := strings.Cut(kv, "="); ok && name != "" {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+func readDotEnvFile(path string) (map[string]string, error) {
+	if path == "-" {
+		return parseDotEnv(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	env, err := parseDotEnv(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return env, nil
+}
+
+// parseDotEnv reads KEY=VALUE lines. Blank lines and # comments are skipped,
+// an "export " prefix is allowed, and values wrapped in matching single or
+// double quotes are unquoted. Multi-line values are not supported.
+func parseDotEnv(r io.Reader) (map[string]string, error) {
+	env := make(map[string]string)
+	sc := bufio.NewScanner(r)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		value = strings.TrimSpace(value)
+		if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') && value[n-1] == value[0] {
+			value = value[1 : n-1]
+		}
+		env[name] = value
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/matcher"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	in := `# comment
+
+STRIPE_SECRET_KEY=sk_live_abcd1234
+export GITHUB_TOKEN="ghp_ABCDabcd"
+QUOTED='a b'
+EMPTY=
+`
+	got, err := parseDotEnv(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("parseDotEnv: %v", err)
+	}
+	want := map[string]string{
+		"STRIPE_SECRET_KEY": "sk_live_abcd1234",
+		"GITHUB_TOKEN":      "ghp_ABCDabcd",
+		"QUOTED":            "a b",
+		"EMPTY":             "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDotEnv = %v, want %v", got, want)
+	}
+
+	if _, err := parseDotEnv(strings.NewReader("not a pair\n")); err == nil {
+		t.Error("expected error for line without =")
+	}
+}
+
+func TestScanEnv(t *testing.T) {
+	m := matcher.New(export.Gondolin{
+		KeywordHostMap: map[string][]string{"stripe": {"api.stripe.com"}},
+		ValuePatterns: []export.ValuePattern{
+			{ID: "github-pat", Regex: `ghp_[A-Za-z0-9]{8}`, Keywords: []string{"ghp_"}},
+		},
+	})
+	env := map[string]string{
+		"STRIPE_SECRET_KEY": "whatever",
+		"MY_TOKEN":          "ghp_ABCDabcd",
+		"HOME":              "/root",
+	}
+
+	got := scanEnv(m, env, false)
+	if len(got) != 2 || got[0].Name != "MY_TOKEN" || got[1].Name != "STRIPE_SECRET_KEY" {
+		t.Fatalf("scanEnv = %+v, want MY_TOKEN and STRIPE_SECRET_KEY", got)
+	}
+	if f := got[0].Findings; len(f) != 1 || f[0].Secret != "ghp_********" {
+		t.Errorf("MY_TOKEN findings = %+v, want one masked github-pat", f)
+	}
+	if len(scanEnv(m, env, true)) != 3 {
+		t.Error("all should report every variable")
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/matcher"
+)
+
+// hostEntry is one result of the /hosts endpoint.
+type hostEntry struct {
+	Host     string   `json:"host"`
+	Keywords []string `json:"keywords"`
+}
+
+// datasetServer serves one full export and the gondolin export derived from
+// it. Everything is pre-rendered at construction; handlers only look up.
+type datasetServer struct {
+	full     []byte
+	gondolin []byte
+	services map[string]combine.Service
+	byNorm   map[string][]string // normalized keyword → keywords, sorted
+	hosts    []hostEntry         // sorted by host
+	sizes    datasetSizes
+
+	// For the gRPC API.
+	fullHash string
+	g        export.Gondolin
+	matcher  *matcher.Matcher
+}
+
+func newDatasetServer(full combine.Export, opts export.Options) (*datasetServer, error) {
+	return newDatasetServerFor(full, export.ToGondolin(full, opts))
+}
+
+// newDatasetServerFor serves full alongside g, a gondolin export already
+// derived from it (e.g. the one update validated and wrote).
+func newDatasetServerFor(full combine.Export, g export.Gondolin) (*datasetServer, error) {
+	s := &datasetServer{
+		services: make(map[string]combine.Service, len(full.Services)),
+		byNorm:   make(map[string][]string, len(full.Services)),
+		fullHash: full.ContentHash,
+		g:        g,
+	}
+	s.matcher = matcher.New(s.g)
+	s.sizes = sizesOf(full, &s.g)
+
+	var err error
+	if s.full, err = encodeJSONBytes(full); err != nil {
+		return nil, err
+	}
+	if s.gondolin, err = encodeJSONBytes(s.g); err != nil {
+		return nil, err
+	}
+
+	byHost := make(map[string][]string)
+	addHost := func(host, keyword string) {
+		for _, k := range byHost[host] {
+			if k == keyword {
+				return
+			}
+		}
+		byHost[host] = append(byHost[host], keyword)
+	}
+	for _, svc := range full.Services {
+		s.services[svc.Keyword] = svc
+		norm := combine.NormalizeKeyword(svc.Keyword)
+		s.byNorm[norm] = append(s.byNorm[norm], svc.Keyword)
+		for _, h := range svc.AllowedHosts() {
+			addHost(h, svc.Keyword)
+		}
+	}
+	for _, th := range full.THOnlyHosts {
+		for _, h := range th.Hosts {
+			addHost(h, th.Keyword)
+		}
+	}
+	for h, keywords := range byHost {
+		sort.Strings(keywords)
+		s.hosts = append(s.hosts, hostEntry{Host: h, Keywords: keywords})
+	}
+	sort.Slice(s.hosts, func(i, j int) bool { return s.hosts[i].Host < s.hosts[j].Host })
+	for _, keywords := range s.byNorm {
+		sort.Strings(keywords)
+	}
+	return s, nil
+}
+
+func (s *datasetServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /gondolin.json", func(w http.ResponseWriter, r *http.Request) {
+		serveJSONBytes(w, r, s.gondolin)
+	})
+	mux.HandleFunc("GET /full.json", func(w http.ResponseWriter, r *http.Request) {
+		serveJSONBytes(w, r, s.full)
+	})
+	mux.HandleFunc("GET /services/{keyword}", s.handleService)
+	mux.HandleFunc("GET /hosts", s.handleHosts)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	return mux
+}
+
+// handleService returns one service by keyword. Lookups are normalized the
+// same way combine matches keywords, so /services/CLOUD-FLARE finds
+// cloudflare; a normalized keyword shared by several services is a 409
+// listing them, since picking one would be arbitrary.
+func (s *datasetServer) handleService(w http.ResponseWriter, r *http.Request) {
+	keyword := r.PathValue("keyword")
+	svc, ok := s.services[keyword]
+	if !ok {
+		switch candidates := s.byNorm[combine.NormalizeKeyword(keyword)]; len(candidates) {
+		case 0:
+		case 1:
+			svc, ok = s.services[candidates[0]]
+		default:
+			http.Error(w, fmt.Sprintf("ambiguous service %q: one of %s", keyword, strings.Join(candidates, ", ")), http.StatusConflict)
+			return
+		}
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown service %q", keyword), http.StatusNotFound)
+		return
+	}
+	serveJSON(w, r, svc)
+}
+
+// handleHosts lists hosts whose name contains q (case-insensitive), with the
+// service keywords that map to each. An empty q lists every host.
+func (s *datasetServer) handleHosts(w http.ResponseWriter, r *http.Request) {
+	q := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	matches := []hostEntry{}
+	for _, e := range s.hosts {
+		if strings.Contains(e.Host, q) {
+			matches = append(matches, e)
+		}
+	}
+	serveJSON(w, r, matches)
+}
+
+// handleMetrics exposes the dataset gauges in the Prometheus text format.
+// The generation timestamp is the served export's generated_at.
+func (s *datasetServer) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	writeDatasetMetrics(w, s.sizes, s.g.GeneratedAt)
+}
+
+func serveJSON(w http.ResponseWriter, r *http.Request, v any) {
+	body, err := encodeJSONBytes(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	serveJSONBytes(w, r, body)
+}
+
+// serveJSONBytes writes body with a strong ETag derived from its bytes.
+// http.ServeContent answers a matching If-None-Match with 304 Not Modified
+// (and handles HEAD and Range requests).
+func serveJSONBytes(w http.ResponseWriter, r *http.Request, body []byte) {
+	sum := sha256.Sum256(body)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "application/json")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+func encodeJSONBytes(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := export.EncodeJSON(&buf, v, false); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// runServe implements `hogwash serve -from-full <full.json> [flags]`.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8080", "Address to listen on")
+	grpcAddr := fs.String("grpc-addr", "", "Also serve the gRPC API (proto/hogwash/v1/dataset.proto) on this address, over cleartext HTTP/2")
+	fromFull := fs.String("from-full", "", "Full export to serve; the gondolin export is derived from it (required)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s serve -from-full <full.json> [flags]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Endpoints: GET /gondolin.json, /full.json, /services/{keyword}, /hosts?q=<substring>, /metrics")
+		fmt.Fprintln(fs.Output(), "gRPC (-grpc-addr): hogwash.v1.Dataset/LookupEnvName, LookupHost, DetectValue, GetDataset")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *fromFull == "" {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("serve: -from-full is required"))
+	}
+
+	var full combine.Export
+	if err := readJSONInput(*fromFull, &full); err != nil {
+		return fmt.Errorf("serve: %w", err)
+	}
+	srv, err := newDatasetServer(full.WithContentHash(), export.DefaultOptions())
+	if err != nil {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "serve: %d services, %d hosts on http://%s\n", len(srv.services), len(srv.hosts), *addr)
+	errs := make(chan error, 2)
+	listen := func(addr string, h http.Handler) {
+		server := &http.Server{
+			Addr:              addr,
+			Handler:           h,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		errs <- server.ListenAndServe()
+	}
+	if *grpcAddr != "" {
+		fmt.Fprintf(os.Stderr, "serve: gRPC on %s\n", *grpcAddr)
+		go listen(*grpcAddr, srv.grpcHandler())
+	}
+	go listen(*addr, srv.handler())
+	return <-errs
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+func TestDatasetServer(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "cloudflare", Hosts: []string{"api.cloudflare.com"}, Rules: []combine.Rule{{ID: "cloudflare-api-key", Regex: `[a-z0-9]{37}`}}},
+			{Keyword: "stripe", Hosts: []string{"api.stripe.com"}, Rules: []combine.Rule{{ID: "stripe-access-token", Regex: `sk_live_[a-z0-9]+`}}},
+		},
+		THOnlyHosts: []combine.THOnlyEntry{{Keyword: "nogl", Hosts: []string{"api.nogl.com"}}},
+	}
+	srv, err := newDatasetServer(full.WithContentHash(), export.Options{})
+	if err != nil {
+		t.Fatalf("newDatasetServer: %v", err)
+	}
+	h := srv.handler()
+
+	get := func(path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/gondolin.json", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/gondolin.json status = %d", rec.Code)
+	}
+	var g export.Gondolin
+	if err := json.Unmarshal(rec.Body.Bytes(), &g); err != nil || len(g.KeywordHostMap["stripe"]) != 1 {
+		t.Errorf("/gondolin.json body = %s (err %v)", rec.Body, err)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+	if rec := get("/gondolin.json", etag); rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match status = %d, want 304", rec.Code)
+	}
+
+	rec = get("/services/Cloud-Flare", "")
+	var svc combine.Service
+	if err := json.Unmarshal(rec.Body.Bytes(), &svc); err != nil || svc.Keyword != "cloudflare" {
+		t.Errorf("/services/Cloud-Flare = %d %s", rec.Code, rec.Body)
+	}
+	if rec := get("/services/nope", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("/services/nope status = %d, want 404", rec.Code)
+	}
+
+	rec = get("/hosts?q=API.N", "")
+	var hosts []hostEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &hosts); err != nil || len(hosts) != 1 || hosts[0].Host != "api.nogl.com" {
+		t.Errorf("/hosts?q=API.N = %s", rec.Body)
+	}
+
+	rec = get("/metrics", "")
+	if body := rec.Body.String(); !strings.Contains(body, "hogwash_dataset_services 3\n") || !strings.Contains(body, "hogwash_dataset_hosts 3\n") {
+		t.Errorf("/metrics = %s", body)
+	}
+}
+
+func TestDatasetServerAmbiguousService(t *testing.T) {
+	full := combine.Export{
+		Services: []combine.Service{
+			{Keyword: "cloud-flare", Rules: []combine.Rule{{ID: "cloud-flare-key", Regex: `cf_[a-z]{8}`}}},
+			{Keyword: "cloudflare", Rules: []combine.Rule{{ID: "cloudflare-api-key", Regex: `[a-z0-9]{37}`}}},
+		},
+	}
+	srv, err := newDatasetServer(full.WithContentHash(), export.Options{})
+	if err != nil {
+		t.Fatalf("newDatasetServer: %v", err)
+	}
+	h := srv.handler()
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	for i := 0; i < 5; i++ {
+		if rec := get("/services/CLOUDFLARE"); rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "cloud-flare, cloudflare") {
+			t.Fatalf("/services/CLOUDFLARE = %d %s, want 409 listing both", rec.Code, rec.Body)
+		}
+	}
+	rec := get("/services/cloud-flare")
+	var svc combine.Service
+	if err := json.Unmarshal(rec.Body.Bytes(), &svc); err != nil || svc.Keyword != "cloud-flare" {
+		t.Errorf("/services/cloud-flare = %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"secret-detector-export/pkg/sign"
+)
+
+// signatureSuffix is appended to the signed file's path, as minisign does.
+const signatureSuffix = ".minisig"
+
+// signOutput writes a detached signature for the file at outPath and returns
+// the signature's path. The trusted comment records the signing time, file
+// name, and content hash, so they can't be altered without the key.
+func signOutput(keyPath, outPath, contentHash string, force, syncDir bool) (string, error) {
+	keyData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return "", err
+	}
+	key, err := sign.ParsePrivateKey(keyData)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", keyPath, err)
+	}
+	content, err := os.ReadFile(outPath)
+	if err != nil {
+		return "", err
+	}
+
+	comment := fmt.Sprintf("timestamp:%d\tfile:%s", time.Now().Unix(), filepath.Base(outPath))
+	if contentHash != "" {
+		comment += "\tcontent_hash:" + contentHash
+	}
+	sig, err := sign.Sign(key, content, comment)
+	if err != nil {
+		return "", err
+	}
+
+	sigPath := outPath + signatureSuffix
+	err = writeFileAtomic(sigPath, force, syncDir, 0o644, func(w io.Writer) error {
+		_, err := w.Write(sig)
+		return err
+	})
+	return sigPath, err
+}
+
+// runKeygen implements `hogwash keygen [flags]`.
+func runKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	outPath := fs.String("out", "hogwash.key", "Secret key path; the public key is written next to it with a .pub extension (so -out must not end in .pub)")
+	force := fs.Bool("force", false, "Overwrite existing key files")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s keygen [flags]\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pubPath := strings.TrimSuffix(*outPath, filepath.Ext(*outPath)) + ".pub"
+	if pubPath == *outPath {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("keygen: -out %s would also be the public key path; use another extension", *outPath))
+	}
+
+	key, err := sign.GenerateKey()
+	if err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+	privText, _ := key.MarshalText()
+	pubText, _ := key.Public().MarshalText()
+
+	if err := writeFileAtomic(*outPath, *force, false, 0o600, func(w io.Writer) error {
+		_, err := w.Write(privText)
+		return err
+	}); err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+	if err := writeFileAtomic(pubPath, *force, false, 0o644, func(w io.Writer) error {
+		_, err := w.Write(pubText)
+		return err
+	}); err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "keygen: key %s\n  secret: %s (keep private)\n  public: %s\n", key.ID, *outPath, pubPath)
+	return nil
+}
+
+// runVerify implements `hogwash verify -pubkey <key.pub> <file>`.
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	pubPath := fs.String("pubkey", "", "Public key file (minisign format) (required)")
+	sigPath := fs.String("sig", "", "Signature file (default: <file>"+signatureSuffix+")")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s verify -pubkey <key.pub> [flags] <file>\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *pubPath == "" {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("verify: expected -pubkey and exactly one file"))
+	}
+	path := fs.Arg(0)
+	if *sigPath == "" {
+		*sigPath = path + signatureSuffix
+	}
+
+	pubData, err := os.ReadFile(*pubPath)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	pub, err := sign.ParsePublicKey(pubData)
+	if err != nil {
+		return fmt.Errorf("verify: %s: %w", *pubPath, err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	sig, err := os.ReadFile(*sigPath)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+	trusted, err := sign.Verify(pub, content, sig)
+	if err != nil {
+		return fmt.Errorf("verify: %s: %w", path, err)
+	}
+	fmt.Fprintf(os.Stderr, "verify: %s: signature OK (key %s)\ntrusted comment: %s\n", path, pub.ID, trusted)
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunKeygen(t *testing.T) {
+	dir := t.TempDir()
+	if err := runKeygen([]string{"-out", filepath.Join(dir, "hogwash.key")}); err != nil {
+		t.Fatalf("runKeygen: %v", err)
+	}
+	for _, name := range []string{"hogwash.key", "hogwash.pub"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("keygen did not write %s: %v", name, err)
+		}
+	}
+
+	// A .pub -out would put both keys at one path.
+	pub := filepath.Join(dir, "k.pub")
+	for _, args := range [][]string{{"-out", pub}, {"-force", "-out", pub}} {
+		err := runKeygen(args)
+		if err == nil || exitCode(err) != exitUsage {
+			t.Errorf("runKeygen(%v) = %v, want a usage error", args, err)
+		}
+	}
+	if _, err := os.Stat(pub); !os.IsNotExist(err) {
+		t.Errorf("keygen wrote %s despite the error: %v", pub, err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"secret-detector-export/pkg/trufflehog"
+)
+
+func TestSkippedReport(t *testing.T) {
+	dir := t.TempDir()
+	thDir := filepath.Join(dir, "detectors")
+	files := map[string]string{
+		filepath.Join(thDir, "meraki", "meraki.go"): "package meraki\n\nconst api = \"https://api.meraki.com/api/v1/organizations\"\n",
+		filepath.Join(thDir, "broken", "broken.go"): "package broken\n\nfunc {\n",
+	}
+	for path, src := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := exportConfig{THDir: thDir, Mode: "full", PublicSuffixes: "reject"}
+	plain, err := buildExport(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.full.Skipped != nil || plain.thSkipped != 1 {
+		t.Errorf("without -include-skipped: skipped = %v, thSkipped = %d", plain.full.Skipped, plain.thSkipped)
+	}
+
+	cfg.IncludeSkipped = true
+	cfg.SkippedOut = filepath.Join(dir, "skipped.json")
+	out, err := buildExport(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.full.Skipped) != 1 || out.full.Skipped[0].DirName != "broken" || out.full.Skipped[0].Reason != trufflehog.SkipParseError {
+		t.Errorf("skipped = %+v", out.full.Skipped)
+	}
+	if out.full.ContentHash != plain.full.ContentHash {
+		t.Error("skipped changed the content hash")
+	}
+
+	data, err := os.ReadFile(cfg.SkippedOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report []trufflehog.Skipped
+	if err := json.Unmarshal(data, &report); err != nil || len(report) != 1 || report[0].Error == "" {
+		t.Errorf("-skipped-out = %s (%v)", data, err)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// runStats implements `hogwash stats [flags] <export.json>`.
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	top := fs.Int("top", 10, "List the N services with the most hosts (0 = none)")
+	asJSON := fs.Bool("json", false, "Write the metrics as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s stats [flags] <export.json | ->\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("stats: expected exactly one input file, got %d", fs.NArg()))
+	}
+	if *top < 0 {
+		return fmt.Errorf("stats: invalid -top %d: must be >= 0", *top)
+	}
+	path := fs.Arg(0)
+
+	var raw json.RawMessage
+	if err := readJSONInput(path, &raw); err != nil {
+		return fmt.Errorf("stats: %w", err)
+	}
+	kind, err := export.DetectKind(raw)
+	if err != nil {
+		return fmt.Errorf("stats: %s: %w", path, err)
+	}
+	var c export.Coverage
+	switch kind {
+	case "gondolin":
+		var g export.Gondolin
+		if err := json.Unmarshal(raw, &g); err != nil {
+			return fmt.Errorf("stats: decode %s: %w", path, err)
+		}
+		c = export.CoverageGondolin(g, *top)
+	default:
+		var e combine.Export
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return fmt.Errorf("stats: decode %s: %w", path, err)
+		}
+		c = export.CoverageFull(e, *top)
+	}
+
+	if *asJSON {
+		return export.EncodeJSON(os.Stdout, c, false)
+	}
+	return printCoverage(c, kind)
+}
+
+func printCoverage(c export.Coverage, kind string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Export:\t%s\n", kind)
+	fmt.Fprintf(w, "Services:\t%d\n", c.Services)
+	fmt.Fprintf(w, "Distinct hosts:\t%d (%d registrable domains)\n", c.DistinctHosts, c.RegistrableDomains)
+	d := c.HostsPerService
+	fmt.Fprintf(w, "Hosts per service:\tmin %d, median %d, mean %.1f, max %d\n", d.Min, d.Median, d.Mean, d.Max)
+	for _, b := range d.Buckets {
+		fmt.Fprintf(w, "  %s hosts:\t%d\t%s\n", b.Label, b.Count, bar(b.Count, c.Services))
+	}
+	fmt.Fprintf(w, "Patterns:\t%d\n", c.Patterns)
+	fmt.Fprintf(w, "  With keyword pre-filter:\t%d (%.1f%%)\n", c.PatternsWithKeywords, 100*c.KeywordRatio)
+	fmt.Fprintf(w, "  RE2-compatible:\t%d (%.1f%%)\n", c.RE2Compatible, 100*c.RE2Ratio)
+	fmt.Fprintln(w, "Rules per category:")
+	for _, category := range sortedKeys(c.RulesPerCategory) {
+		fmt.Fprintf(w, "  %s:\t%d\n", category, c.RulesPerCategory[category])
+	}
+	if len(c.TopByHosts) > 0 {
+		fmt.Fprintln(w, "Top services by host count:")
+		for _, s := range c.TopByHosts {
+			fmt.Fprintf(w, "  %s:\t%d\n", s.Keyword, s.Hosts)
+		}
+	}
+	if len(c.TopDomains) > 0 {
+		fmt.Fprintln(w, "Top registrable domains by host count:")
+		for _, d := range c.TopDomains {
+			fmt.Fprintf(w, "  %s:\t%d hosts, %d services\n", d.Domain, d.Hosts, d.Services)
+		}
+	}
+	return w.Flush()
+}
+
+// bar renders n/total as a fixed-width text bar.
+func bar(n, total int) string {
+	const width = 30
+	if total == 0 {
+		return ""
+	}
+	return strings.Repeat("#", n*width/total)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+)
+
+// StatsHistoryRecord is one line of the -stats-history file: the run
+// summary of -stats-out, dated and keyed by the content hash of every
+// output, so coverage can be charted across upstream releases.
+type StatsHistoryRecord struct {
+	Time          time.Time          `json:"time"`
+	Version       string             `json:"version"`        // hogwash release that produced the run
+	ContentHashes map[string]string  `json:"content_hashes"` // mode → content_hash
+	Combined      combine.Stats      `json:"combined"`
+	Gondolin      *GondolinModeStats `json:"gondolin,omitempty"`
+	THSkipped     int                `json:"th_skipped"`
+	THWarnings    int                `json:"th_warnings"`
+}
+
+// newStatsHistoryRecord summarizes out, written with outputs, at now.
+func newStatsHistoryRecord(out exportOutput, outputs []outputSpec, now time.Time) StatsHistoryRecord {
+	r := StatsHistoryRecord{
+		Time:          now.UTC().Truncate(time.Second),
+		Version:       version,
+		ContentHashes: make(map[string]string),
+		Combined:      out.full.Stats,
+		Gondolin:      out.gondolinStats,
+		THSkipped:     out.thSkipped,
+		THWarnings:    out.thWarnings,
+	}
+	for _, o := range outputs {
+		_, r.ContentHashes[o.Mode] = out.render(o.Mode)
+	}
+	return r
+}
+
+// appendStatsHistory appends r to path as one line of NDJSON, creating the
+// file if needed. Earlier lines are never rewritten.
+func appendStatsHistory(path string, r StatsHistoryRecord) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	// One write per record, so concurrent runs don't interleave lines.
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunExportStatsHistoryAppends(t *testing.T) {
+	dir := t.TempDir()
+	history := filepath.Join(dir, "history.ndjson")
+	cfg := exportConfig{
+		THDir:          filepath.Join("testdata", "trufflehog", "pkg", "detectors"),
+		GLPath:         filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml"),
+		Mode:           "gondolin",
+		Force:          true,
+		PublicSuffixes: "reject",
+		StatsHistory:   history,
+	}
+	for i := 0; i < 2; i++ {
+		cfg.OutPath = filepath.Join(dir, "gondolin.json")
+		if err := runExport(context.Background(), cfg); err != nil {
+			t.Fatalf("runExport: %v", err)
+		}
+	}
+
+	f, err := os.Open(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var records []StatsHistoryRecord
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r StatsHistoryRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("line %d: %v", len(records)+1, err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want one per run", len(records))
+	}
+	r := records[1]
+	if r.Time.IsZero() || r.ContentHashes["gondolin"] == "" || r.Gondolin == nil || r.Combined.TotalServices == 0 {
+		t.Errorf("record = %+v", r)
+	}
+	if r.ContentHashes["gondolin"] != records[0].ContentHashes["gondolin"] {
+		t.Error("identical runs recorded different content hashes")
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"secret-detector-export/pkg/export"
+)
+
+// Checks -strict can turn into failures, in the order they run.
+const (
+	strictWarnings   = "warnings"   // TruffleHog extraction produced warnings
+	strictValidation = "validation" // an output fails `hogwash validate`
+	strictConflicts  = "conflicts"  // a host is mapped from more services than lint allows
+	strictCoverage   = "coverage"   // an output covers fewer services, hosts or patterns than -previous
+)
+
+var strictChecks = []string{strictWarnings, strictValidation, strictConflicts, strictCoverage}
+
+// strictFlag is -strict: the set of strictChecks that fail the run. A bare
+// -strict (or true) selects warnings, its meaning before checks could be
+// picked; repeating the flag adds checks.
+type strictFlag map[string]bool
+
+func (f strictFlag) String() string {
+	var names []string
+	for _, c := range strictChecks {
+		if f[c] {
+			names = append(names, c)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func (f strictFlag) Set(v string) error {
+	switch v {
+	case "true":
+		f[strictWarnings] = true
+		return nil
+	case "false":
+		clear(f)
+		return nil
+	}
+	for _, c := range strings.Split(v, ",") {
+		c = strings.TrimSpace(c)
+		if !isStrictCheck(c) {
+			return fmt.Errorf("unknown check %q (want %s)", c, strings.Join(strictChecks, ", "))
+		}
+		f[c] = true
+	}
+	return nil
+}
+
+func (f strictFlag) IsBoolFlag() bool { return true }
+
+func isStrictCheck(c string) bool {
+	for _, s := range strictChecks {
+		if c == s {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStrict runs the -strict checks on built outputs; warnings are
+// enforced during extraction. prev is the -previous export, needed by
+// coverage. Every failed check is logged before the first is returned.
+func checkStrict(cfg exportConfig, out exportOutput, prev previousExport) error {
+	var failed []string
+	modes := make([]string, 0, 2)
+	for _, mode := range []string{"full", "gondolin"} {
+		if cfg.wantsMode(mode) {
+			modes = append(modes, mode)
+		}
+	}
+
+	if cfg.Strict[strictValidation] {
+		for _, mode := range modes {
+			errs := validateRendered(out, mode, export.ValidateOptions{AllowIPHosts: cfg.AllowIPHosts})
+			warnEach(len(errs), 10, func(i int) {
+				logger.Warn(fmt.Sprintf("%s: %v", mode, errs[i]), "check", strictValidation, "mode", mode)
+			})
+			if len(errs) > 0 {
+				failed = append(failed, fmt.Sprintf("%s: %s export has %d validation problems", strictValidation, mode, len(errs)))
+			}
+		}
+	}
+
+	if cfg.Strict[strictConflicts] {
+		for _, mode := range modes {
+			var conflicts []export.LintIssue
+			for _, issue := range lintRendered(out, mode) {
+				if issue.Check == export.LintSharedHost {
+					conflicts = append(conflicts, issue)
+				}
+			}
+			warnEach(len(conflicts), 10, func(i int) {
+				logger.Warn(fmt.Sprintf("%s: %s", mode, conflicts[i]), "check", strictConflicts, "mode", mode, "host", conflicts[i].Subject)
+			})
+			if len(conflicts) > 0 {
+				failed = append(failed, fmt.Sprintf("%s: %s export has %d shared hosts", strictConflicts, mode, len(conflicts)))
+			}
+		}
+	}
+
+	if cfg.Strict[strictCoverage] {
+		for _, mode := range modes {
+			var before, after export.Coverage
+			if mode == "gondolin" {
+				before, after = export.CoverageGondolin(*prev.gondolin, 0), export.CoverageGondolin(*out.gondolin, 0)
+			} else {
+				before, after = export.CoverageFull(*prev.full, 0), export.CoverageFull(out.full, 0)
+			}
+			for _, d := range []struct {
+				what          string
+				before, after int
+			}{
+				{"services", before.Services, after.Services},
+				{"distinct hosts", before.DistinctHosts, after.DistinctHosts},
+				{"patterns", before.Patterns, after.Patterns},
+			} {
+				if d.after < d.before {
+					failed = append(failed, fmt.Sprintf("%s: %s export covers %d %s, down from %d in -previous", strictCoverage, mode, d.after, d.what, d.before))
+				}
+			}
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	for _, f := range failed {
+		logger.Warn(f, "check", "strict")
+	}
+	return withExitCode(exitStrict, fmt.Errorf("-strict: %s (%d checks failed)", failed[0], len(failed)))
+}
+
+// lintRendered lints the mode output of out with the lint subcommand's
+// thresholds.
+func lintRendered(out exportOutput, mode string) []export.LintIssue {
+	if mode == "gondolin" {
+		return export.LintGondolin(*out.gondolin, export.DefaultLintOptions())
+	}
+	return export.LintFull(out.full, export.DefaultLintOptions())
+}
+
+// checkBudgets fails the run when TruffleHog extraction degraded beyond
+// -max-warnings or -max-skipped, which usually means an upstream refactor
+// broke the assumptions of the AST walk.
+func checkBudgets(cfg exportConfig, skipped, warnings int) error {
+	var over []string
+	if cfg.MaxWarnings > 0 && warnings > cfg.MaxWarnings {
+		over = append(over, fmt.Sprintf("%d warnings (> -max-warnings %d)", warnings, cfg.MaxWarnings))
+	}
+	if cfg.MaxSkipped > 0 && skipped > cfg.MaxSkipped {
+		over = append(over, fmt.Sprintf("%d skipped detectors (> -max-skipped %d)", skipped, cfg.MaxSkipped))
+	}
+	if len(over) == 0 {
+		return nil
+	}
+	return withExitCode(exitStrict, fmt.Errorf("trufflehog extraction over budget: %s", strings.Join(over, ", ")))
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestStrictFlag(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"-strict"}, "warnings"},
+		{[]string{"-strict=coverage,conflicts"}, "conflicts,coverage"},
+		{[]string{"-strict=validation", "-strict=warnings"}, "warnings,validation"},
+		{[]string{"-strict", "-strict=false"}, ""},
+	} {
+		var cfg exportConfig
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		cfg.registerFlags(fs)
+		if err := fs.Parse(tc.args); err != nil {
+			t.Fatalf("%v: %v", tc.args, err)
+		}
+		if got := cfg.Strict.String(); got != tc.want {
+			t.Errorf("%v: -strict = %q, want %q", tc.args, got, tc.want)
+		}
+	}
+
+	var cfg exportConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(new(strings.Builder))
+	cfg.registerFlags(fs)
+	if err := fs.Parse([]string{"-strict=warnings,typos"}); err == nil {
+		t.Error("unknown check: no error")
+	}
+	if err := fs.Parse([]string{"-gitleaks", "gl.toml", "-strict=coverage"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "requires -previous") {
+		t.Errorf("coverage without -previous: validate = %v", err)
+	}
+}
+
+func TestStrictChecks(t *testing.T) {
+	dir := t.TempDir()
+	writeFull := func(name string, services []combine.Service) string {
+		t.Helper()
+		data, err := json.Marshal(combine.Export{Services: services}.WithContentHash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	service := func(keyword string, hosts ...string) combine.Service {
+		return combine.Service{Keyword: keyword, Hosts: hosts, Rules: []combine.Rule{{ID: keyword + "-token", Regex: keyword + `_[a-z0-9]{32}`}}}
+	}
+
+	var shared []combine.Service
+	for _, k := range []string{"alpha", "bravo", "charlie", "delta"} {
+		shared = append(shared, service(k, "api.shared.example", "api."+k+".example"))
+	}
+	current := writeFull("current.json", shared)
+	bigger := writeFull("bigger.json", append(shared, service("echo", "api.echo.example")))
+
+	for _, tc := range []struct {
+		strict   strictFlag
+		previous string
+		fail     string
+	}{
+		{strictFlag{}, "", ""},
+		{strictFlag{strictValidation: true}, "", ""},
+		{strictFlag{strictConflicts: true}, "", "conflicts: full export has 1 shared hosts"},
+		{strictFlag{strictCoverage: true}, current, ""},
+		{strictFlag{strictCoverage: true}, bigger, "coverage: full export covers 4 services, down from 5"},
+	} {
+		cfg := exportConfig{FromFull: current, Mode: "full", Strict: tc.strict, Previous: tc.previous}
+		_, err := buildExport(context.Background(), cfg)
+		switch {
+		case tc.fail == "" && err != nil:
+			t.Errorf("-strict=%s: %v", tc.strict, err)
+		case tc.fail != "" && (err == nil || !strings.Contains(err.Error(), tc.fail) || exitCode(err) != exitStrict):
+			t.Errorf("-strict=%s: err = %v, want %q (exit %d)", tc.strict, err, tc.fail, exitStrict)
+		}
+	}
+}
+
+func TestCheckBudgets(t *testing.T) {
+	for _, tc := range []struct {
+		maxWarnings, maxSkipped int
+		fail                    bool
+	}{
+		{0, 0, false},
+		{5, 40, false},
+		{4, 40, true},
+		{5, 39, true},
+	} {
+		cfg := exportConfig{MaxWarnings: tc.maxWarnings, MaxSkipped: tc.maxSkipped}
+		err := checkBudgets(cfg, 40, 5)
+		if (err != nil) != tc.fail || (err != nil && exitCode(err) != exitStrict) {
+			t.Errorf("-max-warnings %d -max-skipped %d: err = %v", tc.maxWarnings, tc.maxSkipped, err)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+#!/bin/sh
+# Example extractor for -extractor: reads the request from stdin and answers
+# with one detector and one rule (see pkg/extractor for the contract).
+cat >/dev/null
+cat <<'JSON'
+{
+  "protocol": 1,
+  "name": "acme",
+  "detectors": [{"dir_name": "acmepay", "hosts": ["api.acmepay.example"]}],
+  "rules": [{"id": "acmepay-api-key", "regex": "acme_[a-z0-9]{32}", "keywords": ["acme_"]}]
+}
+JSON
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// previousExport is the -previous export in the forms the outputs need.
+type previousExport struct {
+	full     *combine.Export  // nil for a gondolin -previous
+	gondolin *export.Gondolin // nil unless an output is in gondolin mode
+}
+
+// loadPrevious reads -previous. A full previous export serves both modes:
+// its gondolin form is derived with opts, the options of this run, so only
+// real removals show up. A gondolin previous export can only serve gondolin
+// outputs.
+func loadPrevious(cfg exportConfig, opts export.Options) (previousExport, error) {
+	var raw json.RawMessage
+	if err := readJSONInput(cfg.Previous, &raw); err != nil {
+		return previousExport{}, fmt.Errorf("read -previous: %w", err)
+	}
+	kind, err := export.DetectKind(raw)
+	if err != nil {
+		return previousExport{}, fmt.Errorf("-previous %s: %w", cfg.Previous, err)
+	}
+
+	var prev previousExport
+	if kind == "full" {
+		var full combine.Export
+		if err := json.Unmarshal(raw, &full); err != nil {
+			return previousExport{}, fmt.Errorf("decode -previous: %w", err)
+		}
+		prev.full = &full
+		if cfg.wantsMode("gondolin") {
+			g := export.ToGondolin(full, opts)
+			prev.gondolin = &g
+		}
+		return prev, nil
+	}
+	if cfg.wantsMode("full") {
+		return previousExport{}, fmt.Errorf("-previous %s is a gondolin export; full outputs need a full one", cfg.Previous)
+	}
+	var g export.Gondolin
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return previousExport{}, fmt.Errorf("decode -previous: %w", err)
+	}
+	prev.gondolin = &g
+	return prev, nil
+}
+
+// addTombstones sets the removed section of every output against prev.
+func addTombstones(out *exportOutput, cfg exportConfig, prev previousExport, opts export.Options) {
+	if cfg.wantsMode("full") {
+		out.full.Removed = export.Tombstones(export.DiffFull(*prev.full, out.full), prev.full.ContentHash, out.full, nil)
+		logRemoved("full", out.full.Removed)
+	}
+	if out.gondolin != nil {
+		out.gondolin.Removed = export.Tombstones(export.DiffGondolin(*prev.gondolin, *out.gondolin), prev.gondolin.ContentHash, out.full, &opts)
+		logRemoved("gondolin", out.gondolin.Removed)
+	}
+}
+
+func logRemoved(mode string, r *combine.Removed) {
+	logger.Info(fmt.Sprintf("Removed since -previous (%s): %d services, %d hosts, %d rules", mode, len(r.Services), len(r.Hosts), len(r.Rules)),
+		"mode", mode, "removed_services", len(r.Services), "removed_hosts", len(r.Hosts), "removed_rules", len(r.Rules))
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"secret-detector-export/pkg/combine"
+)
+
+func TestPreviousTombstones(t *testing.T) {
+	cfg := exportConfig{
+		THDir:          filepath.Join("testdata", "trufflehog", "pkg", "detectors"),
+		GLPath:         filepath.Join("testdata", "gitleaks", "config", "gitleaks.toml"),
+		Outputs:        []outputSpec{{Mode: "full", Path: "-"}, {Mode: "gondolin", Path: "-"}},
+		PublicSuffixes: "reject",
+	}
+	base, err := buildExport(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prev := base.full
+	prev.Services = append(append([]combine.Service(nil), prev.Services...), combine.Service{
+		Keyword: "retired", MatchType: "exact", Hosts: []string{"api.retired.example"},
+		Rules: []combine.Rule{{ID: "retired-token", Regex: `rt_[a-z0-9]{32}`}},
+	})
+	prev = prev.WithContentHash()
+	data, err := json.Marshal(prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Previous = filepath.Join(t.TempDir(), "previous.json")
+	if err := os.WriteFile(cfg.Previous, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := buildExport(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for mode, r := range map[string]*combine.Removed{"full": out.full.Removed, "gondolin": out.gondolin.Removed} {
+		if r == nil || len(r.Services) != 1 || r.Services[0].Keyword != "retired" || len(r.Rules) != 1 || len(r.Hosts) != 0 {
+			t.Errorf("%s removed = %+v", mode, r)
+		}
+	}
+	if out.full.Removed.Previous != prev.ContentHash {
+		t.Errorf("full removed.previous = %q, want %q", out.full.Removed.Previous, prev.ContentHash)
+	}
+	if out.full.ContentHash != base.full.ContentHash || out.gondolin.ContentHash != base.gondolin.ContentHash {
+		t.Error("removed changed the content hash")
+	}
+
+	cfg.Outputs = []outputSpec{{Mode: "full", Path: "-"}}
+	data, _ = json.Marshal(base.gondolin)
+	if err := os.WriteFile(cfg.Previous, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buildExport(context.Background(), cfg); err == nil {
+		t.Error("gondolin -previous for a full output: no error")
+	}
+}
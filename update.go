@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// upstreamSource is one repository the update daemon fetches.
+type upstreamSource struct {
+	name   string // directory under -workdir
+	repo   string
+	ref    string // branch, tag or commit; empty for the remote's HEAD
+	subdir string // extraction input inside the checkout
+}
+
+// runUpdate implements `hogwash update [export flags] -out <file> [flags]`:
+// fetch the upstream sources, regenerate, and replace -out when the dataset
+// changed and passes validation. With -interval it keeps doing so.
+func runUpdate(args []string) error {
+	var cfg exportConfig
+	fs := flag.NewFlagSet("update", flag.ExitOnError)
+	cfg.registerFlags(fs)
+	workdir := fs.String("workdir", "upstream", "Directory holding the upstream checkouts; reused between runs")
+	thRepo := fs.String("trufflehog-repo", "https://github.com/trufflesecurity/trufflehog.git", "TruffleHog repository to fetch")
+	thRef := fs.String("trufflehog-ref", "", "TruffleHog branch, tag or commit to pin (default: the repository's HEAD)")
+	glRepo := fs.String("gitleaks-repo", "https://github.com/gitleaks/gitleaks.git", "Gitleaks repository to fetch")
+	glRef := fs.String("gitleaks-ref", "", "Gitleaks branch, tag or commit to pin (default: the repository's HEAD)")
+	interval := fs.Duration("interval", 0, "Fetch and regenerate this often, until interrupted (0: once)")
+	dryRun := fs.Bool("dry-run", false, "Report what would change without writing -out")
+	addr := fs.String("addr", "", "Also serve the latest validated dataset on this address, with the endpoints of serve")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s update [export flags] -out <file> [-interval 6h] [-addr :8080]\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Fetches TruffleHog and Gitleaks, regenerates, and replaces -out only when the\ndataset changed and passes validation.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("update: unexpected arguments %v", fs.Args()))
+	}
+	if err := cfg.applyConfigFile(fs); err != nil {
+		return withExitCode(exitUsage, fmt.Errorf("update: %w", err))
+	}
+	cfg.recordFlags(fs)
+	if cfg.THDir != "" || cfg.GLPath != "" || cfg.FromFull != "" {
+		return withExitCode(exitUsage, errors.New("update: sources are fetched; -trufflehog, -gitleaks and -from-full don't apply"))
+	}
+	if *interval < 0 {
+		return withExitCode(exitUsage, fmt.Errorf("update: invalid -interval %s: must be >= 0", *interval))
+	}
+	sources := []upstreamSource{
+		{name: "trufflehog", repo: *thRepo, ref: *thRef, subdir: "pkg/detectors"},
+		{name: "gitleaks", repo: *glRepo, ref: *glRef, subdir: "config/gitleaks.toml"},
+	}
+	cfg.THDir = filepath.Join(*workdir, sources[0].name, sources[0].subdir)
+	cfg.GLPath = filepath.Join(*workdir, sources[1].name, sources[1].subdir)
+	if err := cfg.validate(); err != nil {
+		return withExitCode(exitUsage, fmt.Errorf("update: %w", err))
+	}
+	if len(cfg.outputPaths()) != len(cfg.outputs()) {
+		return withExitCode(exitUsage, errors.New("update: -out must be a file"))
+	}
+	// -out is the dataset being kept current, so it is always replaced.
+	cfg.Force = true
+	cfg.startLogging()
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	var live atomic.Pointer[http.Handler]
+	if *addr != "" {
+		server := &http.Server{
+			Addr: *addr,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h := live.Load()
+				if h == nil {
+					http.Error(w, "no validated dataset yet", http.StatusServiceUnavailable)
+					return
+				}
+				(*h).ServeHTTP(w, r)
+			}),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		logger.Info("update: serving on http://"+*addr, "addr", *addr)
+		go func() {
+			if err := server.ListenAndServe(); err != nil {
+				logger.Error("update: serve: " + err.Error())
+			}
+		}()
+	}
+
+	var lastRevs string
+	for {
+		revs, out, err := updateOnce(ctx, cfg, *workdir, sources, lastRevs, *dryRun)
+		switch {
+		case err != nil && *interval == 0:
+			return fmt.Errorf("update: %w", err)
+		case err != nil:
+			logger.Error("update: " + err.Error())
+		default:
+			lastRevs = revs
+			if out != nil && *addr != "" {
+				srv, err := liveDatasetServer(*out)
+				if err != nil {
+					logger.Error("update: serve: " + err.Error())
+				} else {
+					h := srv.handler()
+					live.Store(&h)
+				}
+			}
+		}
+		if *interval == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// updateOnce fetches every source and, unless their revisions equal
+// lastRevs, regenerates and validates the dataset and writes it if its
+// content changed. It returns the fetched revisions and the validated
+// export (nil when sources were unchanged).
+func updateOnce(ctx context.Context, cfg exportConfig, workdir string, sources []upstreamSource, lastRevs string, dryRun bool) (string, *exportOutput, error) {
+	started := time.Now()
+	ctx, cancel := cfg.withTimeout(ctx)
+	defer cancel()
+
+	var revs []string
+	for _, src := range sources {
+		rev, err := fetchUpstream(ctx, filepath.Join(workdir, src.name), src.repo, src.ref)
+		if err != nil {
+			return "", nil, withExitCode(exitExtraction, &sourceError{Source: src.name, Err: fmt.Errorf("fetch %s: %w", src.name, err)})
+		}
+		revs = append(revs, src.name+"@"+rev)
+	}
+	revList := strings.Join(revs, " ")
+	if revList == lastRevs {
+		logger.Info("update: upstream unchanged ("+revList+")", "revisions", revList)
+		return revList, nil, nil
+	}
+	logger.Info("update: regenerating from "+revList, "revisions", revList)
+
+	out, err := buildExport(ctx, cfg)
+	if err != nil {
+		return "", nil, err
+	}
+	changed := false
+	for _, o := range cfg.outputs() {
+		if errs := validateRendered(out, o.Mode, export.ValidateOptions{AllowIPHosts: cfg.AllowIPHosts}); len(errs) > 0 {
+			for _, e := range errs {
+				logger.Warn(fmt.Sprintf("%s: %v", o.Path, e))
+			}
+			return "", nil, withExitCode(exitValidation, fmt.Errorf("regenerated %s export fails validation (%d problems); keeping %s", o.Mode, len(errs), o.Path))
+		}
+		c, err := reportUpdate(out, o)
+		if err != nil {
+			return "", nil, err
+		}
+		changed = changed || c
+	}
+	switch {
+	case !changed:
+		logger.Info("update: dataset unchanged")
+	case dryRun:
+		logger.Info("update: dry run, not writing")
+	default:
+		if err := writeExport(ctx, cfg, out, started); err != nil {
+			return "", nil, err
+		}
+	}
+	return revList, &out, nil
+}
+
+// liveDatasetServer serves out as validated: its gondolin export when an
+// output is in gondolin mode, so /gondolin.json matches -out, and otherwise
+// the full export reduced with the default options.
+func liveDatasetServer(out exportOutput) (*datasetServer, error) {
+	if out.gondolin != nil {
+		return newDatasetServerFor(out.full, *out.gondolin)
+	}
+	return newDatasetServer(out.full, export.DefaultOptions())
+}
+
+// validateRendered validates the mode output of out.
+func validateRendered(out exportOutput, mode string, opts export.ValidateOptions) []error {
+	if mode == "gondolin" {
+		return export.ValidateGondolin(*out.gondolin, opts)
+	}
+	return export.ValidateCombined(out.full, opts)
+}
+
+// reportUpdate compares the regenerated output o with the file on disk,
+// logs what changed, and reports whether it did.
+func reportUpdate(out exportOutput, o outputSpec) (bool, error) {
+	_, hash := out.render(o.Mode)
+	data, err := os.ReadFile(o.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Info(fmt.Sprintf("update: %s does not exist yet (%s)", o.Path, hash), "path", o.Path, "content_hash", hash)
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var d export.Diff
+	var current string
+	if o.Mode == "gondolin" {
+		var g export.Gondolin
+		if err := json.Unmarshal(data, &g); err != nil {
+			return false, fmt.Errorf("decode %s: %w", o.Path, err)
+		}
+		current = g.WithContentHash().ContentHash
+		d = export.DiffGondolin(g, *out.gondolin)
+	} else {
+		var e combine.Export
+		if err := json.Unmarshal(data, &e); err != nil {
+			return false, fmt.Errorf("decode %s: %w", o.Path, err)
+		}
+		current = e.WithContentHash().ContentHash
+		d = export.DiffFull(e, out.full)
+	}
+	if current == hash {
+		return false, nil
+	}
+	logger.Info(fmt.Sprintf("update: %s changes %s → %s: %d services added, %d removed, %d host changes, %d rules added, %d removed, %d regexes changed",
+		o.Path, current, hash, len(d.AddedServices), len(d.RemovedServices), len(d.HostChanges), len(d.AddedRules), len(d.RemovedRules), len(d.ChangedRegexes)),
+		"path", o.Path, "content_hash", hash)
+	return true, nil
+}
+
+// fetchUpstream makes dir a shallow checkout of ref (the remote's HEAD when
+// empty) of repo and returns the checked-out commit. The clone is kept, so
+// later fetches only transfer what changed.
+func fetchUpstream(ctx context.Context, dir, repo, ref string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); errors.Is(err, os.ErrNotExist) {
+		if _, err := git(ctx, "", "init", "--quiet", dir); err != nil {
+			return "", err
+		}
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := git(ctx, dir, "fetch", "--quiet", "--depth=1", repo, ref); err != nil {
+		return "", err
+	}
+	if _, err := git(ctx, dir, "checkout", "--quiet", "--force", "--detach", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	return git(ctx, dir, "rev-parse", "HEAD")
+}
+
+// git runs a git command in dir and returns its trimmed stdout.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/export"
+)
+
+func TestUpdateOnce(t *testing.T) {
+	if _, err := git(context.Background(), "", "--version"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Local stand-ins for the upstream repositories, laid out like them.
+	thUpstream := filepath.Join(dir, "th-upstream")
+	glUpstream := filepath.Join(dir, "gl-upstream")
+	copyTree(t, filepath.Join("testdata", "trufflehog", "pkg"), filepath.Join(thUpstream, "pkg"))
+	copyTree(t, filepath.Join("testdata", "gitleaks", "config"), filepath.Join(glUpstream, "config"))
+	commitAll(t, thUpstream)
+	commitAll(t, glUpstream)
+
+	workdir := filepath.Join(dir, "work")
+	sources := []upstreamSource{
+		{name: "trufflehog", repo: thUpstream, subdir: "pkg/detectors"},
+		{name: "gitleaks", repo: glUpstream, subdir: "config/gitleaks.toml"},
+	}
+	out := filepath.Join(dir, "gondolin.json")
+	cfg := exportConfig{
+		THDir:          filepath.Join(workdir, "trufflehog", "pkg", "detectors"),
+		GLPath:         filepath.Join(workdir, "gitleaks", "config", "gitleaks.toml"),
+		OutPath:        out,
+		Mode:           "gondolin",
+		PublicSuffixes: "reject",
+		Force:          true,
+		KeepRegexes:    true, // not the default, so -addr must serve what was written
+	}
+
+	revs, built, err := updateOnce(ctx, cfg, workdir, sources, "", false)
+	if err != nil {
+		t.Fatalf("first update: %v", err)
+	}
+	if built == nil || !strings.Contains(revs, "trufflehog@") || !strings.Contains(revs, "gitleaks@") {
+		t.Fatalf("first update: revisions %q, export %v", revs, built)
+	}
+	first, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("first update did not write -out: %v", err)
+	}
+	srv, err := liveDatasetServer(*built)
+	if err != nil {
+		t.Fatalf("liveDatasetServer: %v", err)
+	}
+	var written export.Gondolin
+	if err := json.Unmarshal(first, &written); err != nil {
+		t.Fatal(err)
+	}
+	if srv.g.ContentHash != written.ContentHash {
+		t.Errorf("served gondolin hash %s, -out has %s", srv.g.ContentHash, written.ContentHash)
+	}
+
+	if again, built, err := updateOnce(ctx, cfg, workdir, sources, revs, false); err != nil || again != revs || built != nil {
+		t.Errorf("unchanged upstream: revisions %q, export %v, err %v", again, built, err)
+	}
+
+	// Dropping the Meraki rule upstream changes the dataset.
+	toml := filepath.Join(glUpstream, "config", "gitleaks.toml")
+	data, err := os.ReadFile(toml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := strings.Index(string(data), "[[rules]]\nid = \"cisco-meraki")
+	if i < 0 {
+		t.Fatalf("fixture has no cisco-meraki rule:\n%s", data)
+	}
+	if err := os.WriteFile(toml, data[:i], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	commitAll(t, glUpstream)
+
+	if _, _, err := updateOnce(ctx, cfg, workdir, sources, revs, true); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != string(first) {
+		t.Error("dry run wrote -out")
+	}
+	newRevs, _, err := updateOnce(ctx, cfg, workdir, sources, revs, false)
+	if err != nil {
+		t.Fatalf("update after upstream change: %v", err)
+	}
+	if newRevs == revs {
+		t.Errorf("revisions did not change: %q", newRevs)
+	}
+	if got, _ := os.ReadFile(out); string(got) == string(first) || strings.Contains(string(got), "cisco-meraki") {
+		t.Error("-out was not replaced with the regenerated dataset")
+	}
+}
+
+func commitAll(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "update"},
+	} {
+		if _, err := git(context.Background(), dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func copyTree(t *testing.T, src, dst string) {
+	t.Helper()
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, p)
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
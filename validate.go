@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+)
+
+// runValidate implements `hogwash validate [flags] <export.json>`.
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	allowIPHosts := fs.Bool("allow-ip-hosts", false, "Accept IP-literal hosts (for exports built with -allow-ip-hosts)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s validate [flags] <export.json | ->\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return withExitCode(exitUsage, fmt.Errorf("validate: expected exactly one input file, got %d", fs.NArg()))
+	}
+	path := fs.Arg(0)
+	opts := export.ValidateOptions{AllowIPHosts: *allowIPHosts}
+
+	var raw json.RawMessage
+	if err := readJSONInput(path, &raw); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	kind, err := export.DetectKind(raw)
+	if err != nil {
+		return fmt.Errorf("validate: %s: %w", path, err)
+	}
+
+	var errs []error
+	var summary string
+	switch kind {
+	case "gondolin":
+		var g export.Gondolin
+		if err := json.Unmarshal(raw, &g); err != nil {
+			return fmt.Errorf("validate: decode %s: %w", path, err)
+		}
+		errs = export.ValidateGondolin(g, opts)
+		summary = fmt.Sprintf("gondolin schema v%d, %d keywords, %d patterns", g.SchemaVersion, len(g.KeywordHostMap), len(g.ValuePatterns))
+	default:
+		var e combine.Export
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return fmt.Errorf("validate: decode %s: %w", path, err)
+		}
+		errs = export.ValidateCombined(e, opts)
+		summary = fmt.Sprintf("full export, %d services, %d TH-only", len(e.Services), len(e.THOnlyHosts))
+	}
+
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "  - %v\n", err)
+	}
+	if len(errs) > 0 {
+		return withExitCode(exitValidation, fmt.Errorf("validate: %s: %d problems (%s)", path, len(errs), summary))
+	}
+	fmt.Fprintf(os.Stderr, "validate: %s: OK (%s)\n", path, summary)
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/hostcheck"
+)
+
+// dnsReport is written by -dns-report: every host that didn't resolve, with
+// the keywords that reference it.
+type dnsReport struct {
+	Checked int             `json:"checked"`
+	Dead    []dnsReportHost `json:"dead"`
+	Errors  []dnsReportHost `json:"errors,omitempty"` // transient failures; hosts are kept
+	Dropped bool            `json:"dropped"`          // dead hosts were removed from the export
+}
+
+type dnsReportHost struct {
+	hostcheck.DNSResult
+	Keywords []string `json:"keywords"`
+}
+
+// exportHostOwners maps every upstream-derived host in e (service and
+// TH-only hosts; curated regional hosts are not checked) to the keywords
+// referencing it.
+func exportHostOwners(e combine.Export) map[string][]string {
+	owners := make(map[string][]string)
+	for _, svc := range e.Services {
+		for _, h := range svc.Hosts {
+			owners[h] = append(owners[h], svc.Keyword)
+		}
+	}
+	for _, th := range e.THOnlyHosts {
+		for _, h := range th.Hosts {
+			owners[h] = append(owners[h], th.Keyword)
+		}
+	}
+	for _, keywords := range owners {
+		sort.Strings(keywords)
+	}
+	return owners
+}
+
+// verifyDNS resolves every host in full, then annotates (or, with
+// -drop-dead-hosts, removes) those that don't exist.
+func verifyDNS(ctx context.Context, full combine.Export, cfg exportConfig) (combine.Export, error) {
+	owners := exportHostOwners(full)
+	results := hostcheck.ResolveAll(ctx, sortedKeys(owners), hostcheck.DNSOptions{
+		Concurrency: cfg.DNSConcurrency,
+		Timeout:     cfg.DNSTimeout,
+	})
+	// Lookups cut short by cancellation would look like dead hosts.
+	if err := checkCanceled(ctx); err != nil {
+		return full, err
+	}
+
+	report := dnsReport{Checked: len(results), Dead: []dnsReportHost{}, Dropped: cfg.DropDeadHosts}
+	dead := make(map[string]bool)
+	for _, r := range results {
+		switch r.Status {
+		case hostcheck.StatusDead:
+			dead[r.Host] = true
+			report.Dead = append(report.Dead, dnsReportHost{DNSResult: r, Keywords: owners[r.Host]})
+		case hostcheck.StatusError:
+			report.Errors = append(report.Errors, dnsReportHost{DNSResult: r, Keywords: owners[r.Host]})
+		}
+	}
+	logger.Info(fmt.Sprintf("DNS: %d hosts checked, %d dead, %d lookup errors", report.Checked, len(report.Dead), len(report.Errors)),
+		"checked", report.Checked, "dead", len(report.Dead), "errors", len(report.Errors))
+
+	if cfg.DNSReport != "" {
+		if err := writeJSONAtomic(cfg.DNSReport, true, cfg.SyncDir, false, report); err != nil {
+			return full, fmt.Errorf("write -dns-report: %w", err)
+		}
+	}
+	isDead := func(h string) bool { return dead[h] }
+	if cfg.DropDeadHosts {
+		return full.WithoutHosts(isDead), nil
+	}
+	return full.WithUnresolvedHosts(isDead), nil
+}
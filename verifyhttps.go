@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/hostcheck"
+)
+
+// httpsReport is written by -https-report: status and latency for every
+// probed host, plus the keywords referencing hosts that failed.
+type httpsReport struct {
+	Checked int                     `json:"checked"`
+	NoTLS   []httpsReportHost       `json:"no_tls"`
+	Errors  []httpsReportHost       `json:"errors,omitempty"`
+	Results []hostcheck.HTTPSResult `json:"results"`
+}
+
+type httpsReportHost struct {
+	hostcheck.HTTPSResult
+	Keywords []string `json:"keywords"`
+}
+
+// verifyHTTPS probes every host in full and reports those that no longer
+// serve TLS. The export itself is not modified.
+func verifyHTTPS(ctx context.Context, full combine.Export, cfg exportConfig) error {
+	owners := exportHostOwners(full)
+	results := hostcheck.ProbeAll(ctx, sortedKeys(owners), hostcheck.HTTPSOptions{
+		Rate:      cfg.HTTPSRate,
+		Timeout:   cfg.HTTPSTimeout,
+		UserAgent: cfg.HTTPSUserAgent,
+	})
+	if err := checkCanceled(ctx); err != nil {
+		return err
+	}
+
+	report := httpsReport{Checked: len(results), NoTLS: []httpsReportHost{}, Results: results}
+	for _, r := range results {
+		switch r.Status {
+		case hostcheck.StatusNoTLS:
+			report.NoTLS = append(report.NoTLS, httpsReportHost{HTTPSResult: r, Keywords: owners[r.Host]})
+		case hostcheck.StatusError:
+			report.Errors = append(report.Errors, httpsReportHost{HTTPSResult: r, Keywords: owners[r.Host]})
+		}
+	}
+	logger.Info(fmt.Sprintf("HTTPS: %d hosts probed, %d without TLS, %d errors", report.Checked, len(report.NoTLS), len(report.Errors)),
+		"checked", report.Checked, "no_tls", len(report.NoTLS), "errors", len(report.Errors))
+	warnEach(len(report.NoTLS), 5, func(i int) {
+		h := report.NoTLS[i]
+		logger.Warn(fmt.Sprintf("%s (%v): %s", h.Host, h.Keywords, h.Error), "host", h.Host, "keywords", h.Keywords, "error", h.Error)
+	})
+
+	if cfg.HTTPSReport != "" {
+		if err := writeJSONAtomic(cfg.HTTPSReport, true, cfg.SyncDir, false, report); err != nil {
+			return fmt.Errorf("write -https-report: %w", err)
+		}
+	}
+	return nil
+}
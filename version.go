@@ -0,0 +1,58 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+
+	"secret-detector-export/data"
+	"secret-detector-export/pkg/combine"
+)
+
+// version is the release tag, set at build time with
+// -ldflags "-X main.version=v1.2.3".
+var version = "dev"
+
+// generatorInfo describes this binary and its embedded data files. It is
+// printed by -version and recorded in every export.
+func generatorInfo() combine.Generator {
+	g := combine.Generator{Version: version, GoVersion: runtime.Version(), DataFiles: make(map[string]string)}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		modified := false
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				g.Commit = s.Value
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+		if g.Commit != "" && modified {
+			g.Commit += "-dirty"
+		}
+		if g.Version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			g.Version = info.Main.Version // go install module@version
+		}
+	}
+	for name, content := range data.Files() {
+		sum := sha256.Sum256(content)
+		g.DataFiles[name] = "sha256:" + hex.EncodeToString(sum[:6])
+	}
+	return g
+}
+
+func printVersion(w io.Writer) {
+	g := generatorInfo()
+	fmt.Fprintf(w, "hogwash %s\n", g.Version)
+	if g.Commit != "" {
+		fmt.Fprintf(w, "commit:  %s\n", g.Commit)
+	}
+	fmt.Fprintf(w, "go:      %s\n", g.GoVersion)
+	fmt.Fprintf(w, "data files:\n")
+	for _, name := range sortedKeys(g.DataFiles) {
+		fmt.Fprintf(w, "  %-32s %s\n", name, g.DataFiles[name])
+	}
+}
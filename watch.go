@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// watchedInputs lists the files and directories an export reads: sources,
+// every file-path flag, the config file, and extractor executables given
+// as paths. Outputs are left out so a run doesn't retrigger itself. The
+// curated data/*.json files are embedded at build time, so editing them
+// needs a rebuild, not a regeneration.
+func (cfg exportConfig) watchedInputs() []string {
+	var paths []string
+	for _, p := range []string{
+		cfg.THDir, cfg.GLPath, cfg.FromFull, cfg.Previous, cfg.PatternDenylist, cfg.Popularity,
+		cfg.AuditList, cfg.Golden, cfg.SignKey, cfg.ConfigPath,
+	} {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	for _, command := range cfg.Extractors {
+		if args := strings.Fields(command); len(args) > 0 && strings.ContainsRune(args[0], filepath.Separator) {
+			paths = append(paths, args[0])
+		}
+	}
+	return paths
+}
+
+// inputFingerprint summarizes the path, size, and modification time of every
+// regular file under paths. Any edit, addition, or removal changes it.
+// Missing paths are fingerprinted as missing rather than failing, so a
+// checkout being swapped out mid-watch is just another change.
+func inputFingerprint(paths []string) string {
+	h := sha256.New()
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" && p != root {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00%d\n", p, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(h, "%s\x00error\x00%v\n", root, err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// configFingerprint fingerprints the config file, if one was read.
+func configFingerprint(cfg exportConfig) string {
+	if cfg.ConfigPath == "" {
+		return ""
+	}
+	return inputFingerprint([]string{cfg.ConfigPath})
+}
+
+// runWatch runs the export once, then polls its inputs every interval and
+// regenerates after a change once the inputs have been stable for a full
+// interval (so a multi-file checkout or editor save is picked up as one
+// change). Output is always written atomically. Failed regenerations are
+// reported and leave the previous output in place. With metricsAddr set,
+// regenerations are exposed as Prometheus metrics there. It returns when
+// interrupted.
+func runWatch(ctx context.Context, cfg exportConfig, interval time.Duration, metricsAddr string) error {
+	paths := cfg.outputPaths()
+	if len(paths) != len(cfg.outputs()) {
+		return errors.New("-watch requires -out to be a file")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("invalid -watch-interval %s: must be > 0", interval)
+	}
+
+	var metrics *watchMetrics
+	if metricsAddr != "" {
+		metrics = newWatchMetrics()
+		serveMetrics(metricsAddr, metrics)
+	}
+
+	inputs := cfg.watchedInputs()
+	last := inputFingerprint(inputs)
+	lastConfig := configFingerprint(cfg)
+	started := time.Now()
+	out, err := exportOnce(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	metrics.observe(started, out, nil)
+	// Later runs replace the output we just wrote.
+	cfg.Force = true
+	logger.Info(fmt.Sprintf("watch: watching %d inputs every %s (Ctrl-C to stop)", len(inputs), interval), "inputs", len(inputs), "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	pending := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		fp := inputFingerprint(inputs)
+		switch {
+		case fp == last:
+			pending = ""
+		case fp != pending:
+			// Still changing; wait for it to settle.
+			pending = fp
+		default:
+			logger.Info(fmt.Sprintf("watch: inputs changed at %s, regenerating %s", time.Now().Format(time.TimeOnly), strings.Join(paths, ", ")), "out", paths)
+			if c := configFingerprint(cfg); c != lastConfig {
+				// Flags were resolved at startup; only inputs it names are re-read.
+				logger.Warn("watch: " + cfg.ConfigPath + " changed; restart to apply its flags")
+				lastConfig = c
+			}
+			started := time.Now()
+			out, err := exportOnce(ctx, cfg)
+			if err != nil {
+				logger.Error("watch: " + err.Error())
+			}
+			metrics.observe(started, out, err)
+			last, pending = fp, ""
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInputFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "gitleaks.toml")
+	if err := os.WriteFile(file, []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	inputs := []string{dir}
+
+	fp := inputFingerprint(inputs)
+	if fp != inputFingerprint(inputs) {
+		t.Fatal("fingerprint should be stable when nothing changes")
+	}
+
+	if err := os.WriteFile(file, []byte("ab"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changed := inputFingerprint(inputs)
+	if changed == fp {
+		t.Error("fingerprint should change when a file is edited")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".git"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, time.Now(), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if inputFingerprint(inputs) == changed {
+		t.Error("fingerprint should change when a file is touched or added")
+	}
+
+	missing := inputFingerprint([]string{filepath.Join(dir, "missing")})
+	if missing == "" || missing == inputFingerprint(nil) {
+		t.Error("missing inputs should still produce a distinct fingerprint")
+	}
+}
+
+func TestWatchedInputs(t *testing.T) {
+	cfg := exportConfig{
+		THDir:      "th",
+		GLPath:     "gitleaks.toml",
+		Previous:   "prev.json",
+		Popularity: "weights.json",
+		AuditList:  "must-have.json",
+		Golden:     "golden.json",
+		ConfigPath: "secret-mapping.toml",
+		Extractors: []string{filepath.Join("bin", "extract") + " -v", "extract-on-path"},
+		StatsOut:   "stats.json",
+		Manifest:   "manifest.json",
+	}
+	want := []string{"th", "gitleaks.toml", "prev.json", "weights.json", "must-have.json", "golden.json", "secret-mapping.toml", filepath.Join("bin", "extract")}
+	if got := cfg.watchedInputs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("watchedInputs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"secret-detector-export/pkg/combine"
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/hostcheck"
+)
+
+// runWorklist implements `hogwash worklist -from-full <full.json>`: the
+// Gitleaks services without hosts, ranked for curation of
+// data/well_known_hosts.json.
+func runWorklist(args []string) error {
+	fs := flag.NewFlagSet("worklist", flag.ExitOnError)
+	fromFull := fs.String("from-full", "", "Full export to rank services without hosts from (required)")
+	popularity := fs.String("popularity", "", "JSON `file` of service keyword → popularity weight, as the export flag of the same name")
+	limit := fs.Int("limit", 0, "List only the N highest-ranked services (0: all)")
+	probe := fs.Bool("probe", false, "Resolve candidate hosts (api.<keyword>.com, <keyword>.com, api.<keyword>.io) and suggest those that exist (needs network)")
+	concurrency := fs.Int("dns-concurrency", 16, "With -probe: parallel lookups")
+	timeout := fs.Duration("dns-timeout", 5*time.Second, "With -probe: timeout per lookup")
+	asJSON := fs.Bool("json", false, "Write the worklist as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s worklist -from-full <full.json> [flags]\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Ranks the Gitleaks services exported without hosts by rule count, then\n")
+		fmt.Fprintf(fs.Output(), "popularity, as a worklist for data/well_known_hosts.json.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *fromFull == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return withExitCode(exitUsage, errors.New("worklist: -from-full is required"))
+	}
+	if *limit < 0 {
+		return withExitCode(exitUsage, fmt.Errorf("worklist: invalid -limit %d: must be >= 0", *limit))
+	}
+
+	var full combine.Export
+	if err := readJSONInput(*fromFull, &full); err != nil {
+		return fmt.Errorf("worklist: %w", err)
+	}
+	var weights map[string]float64
+	if *popularity != "" {
+		data, err := os.ReadFile(*popularity)
+		if err != nil {
+			return fmt.Errorf("worklist: read -popularity: %w", err)
+		}
+		if weights, err = export.ParsePopularityWeights(data); err != nil {
+			return fmt.Errorf("worklist: decode -popularity JSON: %w", err)
+		}
+	}
+	entries := export.NoHostWorklist(full.Services, export.DefaultOptions().Popularity, weights)
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[:*limit]
+	}
+	if *probe {
+		ctx, stop := interruptContext()
+		defer stop()
+		probeCandidates(ctx, entries, hostcheck.DNSOptions{Concurrency: *concurrency, Timeout: *timeout})
+		if err := checkCanceled(ctx); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		if entries == nil {
+			entries = []export.WorklistEntry{}
+		}
+		return export.EncodeJSON(os.Stdout, entries, false)
+	}
+	return printWorklist(os.Stdout, entries, *probe)
+}
+
+// probeCandidates resolves the candidate hosts of every entry and keeps
+// those that resolve as its candidates.
+func probeCandidates(ctx context.Context, entries []export.WorklistEntry, opts hostcheck.DNSOptions) {
+	var hosts []string
+	for _, e := range entries {
+		hosts = append(hosts, export.CandidateHosts(e.Keyword)...)
+	}
+	ok := make(map[string]bool)
+	for _, r := range hostcheck.ResolveAll(ctx, hosts, opts) {
+		if r.Status == hostcheck.StatusOK {
+			ok[r.Host] = true
+		}
+	}
+	for i := range entries {
+		for _, h := range export.CandidateHosts(entries[i].Keyword) {
+			if ok[h] {
+				entries[i].Candidates = append(entries[i].Candidates, h)
+			}
+		}
+	}
+}
+
+func printWorklist(out io.Writer, entries []export.WorklistEntry, probed bool) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	header := "#\tKEYWORD\tRULES\tWEIGHT\tPOPULARITY"
+	if probed {
+		header += "\tCANDIDATES"
+	}
+	fmt.Fprintln(w, header)
+	for i, e := range entries {
+		pop := "-"
+		if e.Popularity > 0 {
+			pop = fmt.Sprint(e.Popularity)
+		}
+		line := fmt.Sprintf("%d\t%s\t%d\t%g\t%s", i+1, e.Keyword, e.Rules, e.Weight, pop)
+		if probed {
+			line += "\t" + strings.Join(e.Candidates, ", ")
+		}
+		fmt.Fprintln(w, line)
+	}
+	return w.Flush()
+}
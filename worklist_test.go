@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"secret-detector-export/pkg/export"
+	"secret-detector-export/pkg/hostcheck"
+)
+
+type mapResolver map[string]bool
+
+func (m mapResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if m[host] {
+		return []string{"192.0.2.1"}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestWorklistProbe(t *testing.T) {
+	entries := []export.WorklistEntry{{Keyword: "planet-scale", Rules: 2}, {Keyword: "nowhere", Rules: 1}}
+	resolver := mapResolver{"api.planetscale.com": true, "planet-scale.com": true, "nowhere.example": true}
+	probeCandidates(context.Background(), entries, hostcheck.DNSOptions{Resolver: resolver})
+	if want := []string{"planet-scale.com", "api.planetscale.com"}; !reflect.DeepEqual(entries[0].Candidates, want) {
+		t.Errorf("candidates = %v, want %v", entries[0].Candidates, want)
+	}
+	if entries[1].Candidates != nil {
+		t.Errorf("nowhere: candidates = %v", entries[1].Candidates)
+	}
+
+	var out strings.Builder
+	if err := printWorklist(&out, entries, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "CANDIDATES") || !strings.Contains(out.String(), "planet-scale.com, api.planetscale.com") {
+		t.Errorf("worklist:\n%s", out.String())
+	}
+}